@@ -43,6 +43,7 @@ const (
 	AnalysisReportFileRoot       = AnalysisReportIssueRoot + "/files"
 	//
 	AppAnalysesRoot       = ApplicationRoot + "/analyses"
+	AppAnalysesIDRoot     = AppAnalysesRoot + "/:" + ID2
 	AppAnalysisRoot       = ApplicationRoot + "/analysis"
 	AppAnalysisReportRoot = AppAnalysisRoot + "/report"
 	AppAnalysisDepsRoot   = AppAnalysisRoot + "/dependencies"
@@ -65,24 +66,35 @@ func (h AnalysisHandler) AddRoutes(e *gin.Engine) {
 	routeGroup := e.Group("/")
 	routeGroup.Use(Required("analyses"))
 	routeGroup.GET(AnalysisRoot, h.Get)
+	routeGroup.HEAD(AnalysisRoot, h.Head)
 	routeGroup.DELETE(AnalysisRoot, h.Delete)
 	routeGroup.GET(AnalysesDepsRoot, h.Deps)
 	routeGroup.GET(AnalysesIssuesRoot, h.Issues)
 	routeGroup.GET(AnalysesIssueRoot, h.Issue)
 	routeGroup.GET(AnalysisIncidentsRoot, h.Incidents)
+	routeGroup.GET(AnalysisIncidentSnippetRoot, h.IncidentSnippet)
 	routeGroup.GET(AnalysisReportRuleRoot, h.RuleReports)
+	routeGroup.GET(AnalysisReportTrendRoot, h.TrendReport)
+	routeGroup.GET(AnalysisReportIssuesRoot, h.IssueSummaryReports)
 	routeGroup.GET(AnalysisReportAppsIssuesRoot, h.AppIssueReports)
 	routeGroup.GET(AnalysisReportIssuesAppsRoot, h.IssueAppReports)
 	routeGroup.GET(AnalysisReportFileRoot, h.FileReports)
 	routeGroup.GET(AnalysisReportDepsRoot, h.DepReports)
+	routeGroup.GET(AnalysisReportDepsGraphRoot, h.DepGraphReport)
 	routeGroup.GET(AnalysisReportDepsAppsRoot, h.DepAppReports)
+	routeGroup.POST(AnalysisReportExportRoot, h.Export)
 	// Application
 	routeGroup = e.Group("/")
 	routeGroup.Use(Required("applications.analyses"))
 	routeGroup.POST(AppAnalysesRoot, h.AppCreate)
+	routeGroup.POST(AppAnalysesStreamRoot, h.AppStream)
 	routeGroup.GET(AppAnalysesRoot, h.AppList)
+	routeGroup.GET(AppAnalysesDiffRoot, h.AppDiff)
+	routeGroup.GET(AppAnalysesTrendRoot, h.AppTrend)
+	routeGroup.DELETE(AppAnalysesIDRoot, h.AppDelete)
 	routeGroup.GET(AppAnalysisRoot, h.AppLatest)
 	routeGroup.GET(AppAnalysisReportRoot, h.AppLatestReport)
+	routeGroup.GET(AppAnalysisSarifRoot, h.AppLatestSarif)
 	routeGroup.GET(AppAnalysisDepsRoot, h.AppDeps)
 	routeGroup.GET(AppAnalysisIssuesRoot, h.AppIssues)
 }
@@ -97,6 +109,14 @@ func (h AnalysisHandler) AddRoutes(e *gin.Engine) {
 // @param id path int true "Analysis ID"
 func (h AnalysisHandler) Get(ctx *gin.Context) {
 	id := h.pk(ctx)
+	m := &model.Analysis{}
+	db := h.DB(ctx).Select("ID", "UpdateTime")
+	result := db.First(m, id)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	h.Validators(ctx, &m.Model)
 	writer := AnalysisWriter{ctx: ctx}
 	path, err := writer.Create(id)
 	if err != nil {
@@ -110,6 +130,19 @@ func (h AnalysisHandler) Get(ctx *gin.Context) {
 	ctx.File(path)
 }
 
+// Head godoc
+// @summary Check an analysis exists.
+// @description Check an analysis exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags analyses
+// @success 200
+// @success 304
+// @router /analyses/{id} [head]
+// @param id path int true "Analysis ID"
+func (h AnalysisHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.Analysis{})
+}
+
 // AppLatest godoc
 // @summary Get the latest analysis.
 // @description Get the latest analysis for an application.
@@ -224,18 +257,41 @@ func (h AnalysisHandler) AppList(ctx *gin.Context) {
 // @description   - file: file that contains the api.Analysis resource.
 // @description   - issues: file that multiple api.Issue resources.
 // @description   - dependencies: file that multiple api.TechDependency resources.
+// @description When ?merge= (comma-separated rulesets) is specified, the issues of the
+// @description (re-run) rulesets are merged into the latest analysis and 200 is returned.
+// @description Issues are matched by ruleset/rule and only issues of the listed rulesets
+// @description may be posted. Issues of the rulesets not posted are deleted.
+// @description Dependencies are not merged (ignored).
 // @tags analyses
 // @produce json
 // @success 201 {object} api.Analysis
+// @success 200 {object} api.Analysis
 // @router /application/{id}/analyses [post]
 // @param id path int true "Application ID"
+// @param merge query string false "Merged rulesets"
 func (h AnalysisHandler) AppCreate(ctx *gin.Context) {
 	id := h.pk(ctx)
-	result := h.DB(ctx).First(&model.Application{}, id)
+	application := &model.Application{}
+	result := h.DB(ctx).First(application, id)
 	if result.Error != nil {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	if application.Archived {
+		_ = ctx.Error(&BadRequestError{"application is archived."})
+		return
+	}
+	if s := ctx.Query(MergeParam); s != "" {
+		rulesets := []string{}
+		for _, name := range strings.Split(s, ",") {
+			name = strings.TrimSpace(name)
+			if name != "" {
+				rulesets = append(rulesets, name)
+			}
+		}
+		h.merge(ctx, rulesets)
+		return
+	}
 	err := h.archive(ctx)
 	if err != nil {
 		_ = ctx.Error(err)
@@ -282,6 +338,9 @@ func (h AnalysisHandler) AppCreate(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
+	if r.Provenance != nil {
+		analysis.Provenance, _ = json.Marshal(r.Provenance)
+	}
 	//
 	// Issues
 	input, err = ctx.FormFile(IssueField)
@@ -424,6 +483,52 @@ func (h AnalysisHandler) Delete(ctx *gin.Context) {
 	h.Status(ctx, http.StatusNoContent)
 }
 
+// AppDelete godoc
+// @summary Delete an application analysis by ID.
+// @description Delete an application analysis by ID.
+// @description The latest analysis cannot be deleted.
+// @tags analyses
+// @success 204
+// @router /applications/{id}/analyses/{sid} [delete]
+// @param id path int true "Application ID"
+// @param sid path int true "Analysis ID"
+func (h AnalysisHandler) AppDelete(ctx *gin.Context) {
+	id := h.pk(ctx)
+	id2, err := strconv.ParseUint(ctx.Param(ID2), 10, 0)
+	if err != nil {
+		_ = ctx.Error(&BadRequestError{"analysis ID must be an integer."})
+		return
+	}
+	m := &model.Analysis{}
+	db := h.DB(ctx).Select("ID")
+	db = db.Where("ApplicationID", id)
+	err = db.First(m, id2).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	latest := &model.Analysis{}
+	db = h.DB(ctx).Select("ID")
+	db = db.Where("ApplicationID", id)
+	err = db.Last(latest).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if latest.ID == m.ID {
+		err = &Conflict{Reason: "the latest analysis cannot be deleted.", ID: m.ID}
+		_ = ctx.Error(err)
+		return
+	}
+	err = h.DB(ctx).Delete(m).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	h.Status(ctx, http.StatusNoContent)
+}
+
 // AppDeps godoc
 // @summary List application dependencies.
 // @description List application dependencies.
@@ -433,8 +538,9 @@ func (h AnalysisHandler) Delete(ctx *gin.Context) {
 // @description - sha
 // @description - indirect
 // @description - labels
+// @description CSV is rendered when Accept=text/csv. Columns are selected using: ?columns=name,version.
 // @tags dependencies
-// @produce json
+// @produce json,text/csv
 // @success 200 {object} []api.TechDependency
 // @router /application/{id}/analysis/dependencies [get]
 // @param id path int true "Application ID"
@@ -502,6 +608,15 @@ func (h AnalysisHandler) AppDeps(ctx *gin.Context) {
 		resources = append(resources, r)
 	}
 
+	if h.Accepted(ctx, CSV) {
+		table := &CsvTable{Columns: DepColumns}
+		for i := range resources {
+			table.Add(resources[i].csvRow())
+		}
+		h.writeCSV(ctx, table)
+		return
+	}
+
 	h.Respond(ctx, http.StatusOK, resources)
 }
 
@@ -515,11 +630,20 @@ func (h AnalysisHandler) AppDeps(ctx *gin.Context) {
 // @description - category
 // @description - effort
 // @description - labels
+// @description - file
+// @description The file is the (incident) file. Example: file~*/src/main/*.java.
+// @description Ranges are supported. Example: effort>1,effort<5
+// @description Paginated using ?limit= and ?offset= (applied in SQL). The X-Total-Count header
+// @description reports the total number matched. Sorted using: ?sort=category,desc:effort.
+// @description CSV is rendered when Accept=text/csv. Columns are selected using: ?columns=ruleset,rule.
+// @description Suppressed issues are flagged. See: /suppressions.
+// @description Use ?suppressed=(true|false) to list only (or exclude) suppressed issues.
 // @tags issues
-// @produce json
+// @produce json,text/csv
 // @success 200 {object} []api.Issue
 // @router /application/{id}/analysis/issues [get]
 // @param id path int true "Application ID"
+// @param suppressed query bool false "Suppressed"
 func (h AnalysisHandler) AppIssues(ctx *gin.Context) {
 	resources := []Issue{}
 	// Latest
@@ -540,6 +664,7 @@ func (h AnalysisHandler) AppIssues(ctx *gin.Context) {
 			{Field: "category", Kind: qf.STRING},
 			{Field: "effort", Kind: qf.LITERAL},
 			{Field: "labels", Kind: qf.STRING, And: true},
+			{Field: "file", Kind: qf.STRING},
 		})
 	if err != nil {
 		_ = ctx.Error(err)
@@ -557,36 +682,46 @@ func (h AnalysisHandler) AppIssues(ctx *gin.Context) {
 	db = db.Model(&model.Issue{})
 	db = db.Where("AnalysisID = ?", analysis.ID)
 	db = db.Where("ID IN (?)", h.issueIDs(ctx, filter))
+	db, err = h.suppressed(ctx, db, "ID", h.suppressedIssueIDs(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	db = sort.Sorted(db)
+	db = db.Order("ID")
 	var list []model.Issue
-	var m model.Issue
-	page := Page{}
-	page.With(ctx)
-	cursor := Cursor{}
-	cursor.With(db, page)
-	defer func() {
-		cursor.Close()
-	}()
-	for cursor.Next(&m) {
-		if cursor.Error != nil {
-			_ = ctx.Error(cursor.Error)
-			return
-		}
-		list = append(list, m)
-	}
-	err = h.WithCount(ctx, cursor.Count())
+	err = h.FindPage(ctx, db, &list)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
 	// Render
+	ids := []uint{}
+	for i := range list {
+		ids = append(ids, list[i].ID)
+	}
+	suppressed, err := h.suppressedIn(ctx, h.suppressedIssueIDs(ctx), ids)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	for i := range list {
 		m := &list[i]
 		r := Issue{}
 		r.With(m)
+		r.Suppressed = suppressed[m.ID]
 		resources = append(resources, r)
 	}
 
+	if h.Accepted(ctx, CSV) {
+		table := &CsvTable{Columns: IssueColumns}
+		for i := range resources {
+			table.Add(resources[i].csvRow())
+		}
+		h.writeCSV(ctx, table)
+		return
+	}
+
 	h.Respond(ctx, http.StatusOK, resources)
 }
 
@@ -600,13 +735,22 @@ func (h AnalysisHandler) AppIssues(ctx *gin.Context) {
 // @description - category
 // @description - effort
 // @description - labels
+// @description - file
 // @description - application.id
 // @description - application.name
 // @description - tag.id
+// @description The file is the (incident) file. Example: file~*/src/main/*.java.
+// @description Ranges are supported. Example: effort>1,effort<5
+// @description Paginated using ?limit= and ?offset= (applied in SQL). The X-Total-Count header
+// @description reports the total number matched. Sorted using: ?sort=category,desc:effort.
+// @description CSV is rendered when Accept=text/csv. Columns are selected using: ?columns=ruleset,rule.
+// @description Suppressed issues are flagged. See: /suppressions.
+// @description Use ?suppressed=(true|false) to list only (or exclude) suppressed issues.
 // @tags issues
-// @produce json
+// @produce json,text/csv
 // @success 200 {object} []api.Issue
 // @router /analyses/issues [get]
+// @param suppressed query bool false "Suppressed"
 func (h AnalysisHandler) Issues(ctx *gin.Context) {
 	resources := []Issue{}
 	// Filter
@@ -618,6 +762,7 @@ func (h AnalysisHandler) Issues(ctx *gin.Context) {
 			{Field: "category", Kind: qf.STRING},
 			{Field: "effort", Kind: qf.LITERAL},
 			{Field: "labels", Kind: qf.STRING, And: true},
+			{Field: "file", Kind: qf.STRING},
 			{Field: "application.id", Kind: qf.LITERAL},
 			{Field: "application.name", Kind: qf.STRING},
 			{Field: "tag.id", Kind: qf.LITERAL, And: true},
@@ -635,48 +780,56 @@ func (h AnalysisHandler) Issues(ctx *gin.Context) {
 	}
 	// Find
 	db := h.DB(ctx)
-	db = db.Table("Issue i")
-	db = db.Joins(",Analysis a")
-	db = db.Where("a.ID = i.AnalysisID")
-	db = db.Where("a.ID IN (?)", h.analysisIDs(ctx, filter))
-	db = db.Where("i.ID IN (?)", h.issueIDs(ctx, filter))
-	db = db.Group("i.ID")
+	db = db.Model(&model.Issue{})
+	db = db.Where("AnalysisID IN (?)", h.analysisIDs(ctx, filter))
+	db = db.Where("ID IN (?)", h.issueIDs(ctx, filter))
+	db, err = h.suppressed(ctx, db, "ID", h.suppressedIssueIDs(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	db = sort.Sorted(db)
+	db = db.Order("ID")
 	var list []model.Issue
-	var m model.Issue
-	page := Page{}
-	page.With(ctx)
-	cursor := Cursor{}
-	cursor.With(db, page)
-	defer func() {
-		cursor.Close()
-	}()
-	for cursor.Next(&m) {
-		if cursor.Error != nil {
-			_ = ctx.Error(cursor.Error)
-			return
-		}
-		list = append(list, m)
-	}
-	err = h.WithCount(ctx, cursor.Count())
+	err = h.FindPage(ctx, db, &list)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
 	// Render
+	ids := []uint{}
+	for i := range list {
+		ids = append(ids, list[i].ID)
+	}
+	suppressed, err := h.suppressedIn(ctx, h.suppressedIssueIDs(ctx), ids)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	for i := range list {
 		m := &list[i]
 		r := Issue{}
 		r.With(m)
+		r.Suppressed = suppressed[m.ID]
 		resources = append(resources, r)
 	}
 
+	if h.Accepted(ctx, CSV) {
+		table := &CsvTable{Columns: IssueColumns}
+		for i := range resources {
+			table.Add(resources[i].csvRow())
+		}
+		h.writeCSV(ctx, table)
+		return
+	}
+
 	h.Respond(ctx, http.StatusOK, resources)
 }
 
 // Issue godoc
 // @summary Get an issue.
 // @description Get an issue.
+// @description Suppressed issues are flagged. See: /suppressions.
 // @tags issue
 // @produce json
 // @success 200 {object} api.Issue
@@ -692,8 +845,14 @@ func (h AnalysisHandler) Issue(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
+	suppressed, err := h.suppressedIn(ctx, h.suppressedIssueIDs(ctx), []uint{m.ID})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	r := Issue{}
 	r.With(m)
+	r.Suppressed = suppressed[m.ID]
 
 	h.Respond(ctx, http.StatusOK, r)
 }
@@ -703,17 +862,25 @@ func (h AnalysisHandler) Issue(ctx *gin.Context) {
 // @description List incidents for an issue.
 // @description filters:
 // @description - file
+// @description - line
+// @description Paginated using ?limit= and ?offset= (applied in SQL). The X-Total-Count header
+// @description reports the total number matched. Sorted using: ?sort=file,line.
+// @description CSV is rendered when Accept=text/csv. Columns are selected using: ?columns=file,line.
+// @description Suppressed incidents are flagged. See: /suppressions.
+// @description Use ?suppressed=(true|false) to list only (or exclude) suppressed incidents.
 // @tags incidents
-// @produce json
+// @produce json,text/csv
 // @success 200 {object} []api.Incident
 // @router /analyses/issues/{id}/incidents [get]
 // @param id path int true "Issue ID"
+// @param suppressed query bool false "Suppressed"
 func (h AnalysisHandler) Incidents(ctx *gin.Context) {
 	issueId := ctx.Param(ID)
 	// Filter
 	filter, err := qf.New(ctx,
 		[]qf.Assert{
 			{Field: "file", Kind: qf.STRING},
+			{Field: "line", Kind: qf.LITERAL},
 		})
 	if err != nil {
 		_ = ctx.Error(err)
@@ -731,38 +898,48 @@ func (h AnalysisHandler) Incidents(ctx *gin.Context) {
 	db = db.Model(&model.Incident{})
 	db = db.Where("IssueID", issueId)
 	db = filter.Where(db)
+	db, err = h.suppressed(ctx, db, "ID", h.suppressedIncidentIDs(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	db = sort.Sorted(db)
+	db = db.Order("ID")
 	var list []model.Incident
-	var m model.Incident
-	cursor := Cursor{}
-	defer func() {
-		cursor.Close()
-	}()
-	page := Page{}
-	page.With(ctx)
-	cursor.With(db, page)
-	for cursor.Next(&m) {
-		if cursor.Error != nil {
-			_ = ctx.Error(cursor.Error)
-			return
-		}
-		list = append(list, m)
-	}
-	err = h.WithCount(ctx, cursor.Count())
+	err = h.FindPage(ctx, db, &list)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
 	// Render
+	ids := []uint{}
+	for i := range list {
+		ids = append(ids, list[i].ID)
+	}
+	suppressed, err := h.suppressedIn(ctx, h.suppressedIncidentIDs(ctx), ids)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	resources := []Incident{}
 	for _, m := range list {
 		r := Incident{}
 		r.With(&m)
+		r.Suppressed = suppressed[m.ID]
 		resources = append(
 			resources,
 			r)
 	}
 
+	if h.Accepted(ctx, CSV) {
+		table := &CsvTable{Columns: IncidentColumns}
+		for i := range resources {
+			table.Add(resources[i].csvRow())
+		}
+		h.writeCSV(ctx, table)
+		return
+	}
+
 	h.Respond(ctx, http.StatusOK, resources)
 }
 
@@ -786,7 +963,10 @@ func (h AnalysisHandler) Incidents(ctx *gin.Context) {
 // @description - rule
 // @description - category
 // @description - effort
+// @description - incidents
+// @description - totalEffort
 // @description - applications
+// @description The totalEffort is the effort of all incidents.
 // @tags rulereports
 // @produce json
 // @success 200 {object} []api.RuleReport
@@ -795,6 +975,8 @@ func (h AnalysisHandler) RuleReports(ctx *gin.Context) {
 	resources := []*RuleReport{}
 	type M struct {
 		model.Issue
+		Incidents    int
+		TotalEffort  int
 		Applications int
 	}
 	// Filter
@@ -834,9 +1016,12 @@ func (h AnalysisHandler) RuleReports(ctx *gin.Context) {
 		"i.Effort",
 		"i.Labels",
 		"i.Links",
+		"SUM(IFNULL(n.Count,0)) Incidents",
+		"SUM(i.Effort*IFNULL(n.Count,0)) TotalEffort",
 		"COUNT(distinct a.ID) Applications")
 	q = q.Table("Issue i,")
 	q = q.Joins("Analysis a")
+	q = q.Joins("LEFT JOIN (?) n ON n.IssueID = i.ID", h.incidentCounts(ctx))
 	q = q.Where("a.ID = i.AnalysisID")
 	q = q.Where("a.ID in (?)", h.analysisIDs(ctx, filter))
 	q = q.Where("i.ID IN (?)", h.issueIDs(ctx, filter))
@@ -871,6 +1056,8 @@ func (h AnalysisHandler) RuleReports(ctx *gin.Context) {
 	for i := range list {
 		m := list[i]
 		r := &RuleReport{
+			Incidents:    m.Incidents,
+			TotalEffort:  m.TotalEffort,
 			Applications: m.Applications,
 			Description:  m.Description,
 			Category:     m.Category,
@@ -891,6 +1078,67 @@ func (h AnalysisHandler) RuleReports(ctx *gin.Context) {
 	h.Respond(ctx, http.StatusOK, resources)
 }
 
+// IssueSummaryReports godoc
+// @summary List issue summary reports.
+// @description Each report aggregates the issues (of the latest analyses) by category.
+// @description filters:
+// @description - ruleset
+// @description - rule
+// @description - category
+// @description - effort
+// @description - labels
+// @description - application.id
+// @description - application.name
+// @description - businessService.id
+// @description - businessService.name
+// @description - tag.id
+// @description The effort is the effort of all incidents.
+// @tags rulereports
+// @produce json
+// @success 200 {object} []api.IssueSummary
+// @router /analyses/report/issues [get]
+func (h AnalysisHandler) IssueSummaryReports(ctx *gin.Context) {
+	resources := []IssueSummary{}
+	// Filter
+	filter, err := qf.New(ctx,
+		[]qf.Assert{
+			{Field: "ruleset", Kind: qf.STRING},
+			{Field: "rule", Kind: qf.STRING},
+			{Field: "category", Kind: qf.STRING},
+			{Field: "effort", Kind: qf.LITERAL},
+			{Field: "labels", Kind: qf.STRING, And: true},
+			{Field: "application.id", Kind: qf.STRING},
+			{Field: "application.name", Kind: qf.STRING},
+			{Field: "businessService.id", Kind: qf.LITERAL},
+			{Field: "businessService.name", Kind: qf.STRING},
+			{Field: "tag.id", Kind: qf.LITERAL, And: true},
+		})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	db := h.DB(ctx)
+	db = db.Select(
+		"i.Category",
+		"COUNT(distinct i.ID) Issues",
+		"SUM(IFNULL(n.Count,0)) Incidents",
+		"SUM(i.Effort*IFNULL(n.Count,0)) Effort",
+		"COUNT(distinct i.AnalysisID) Applications")
+	db = db.Table("Issue i")
+	db = db.Joins("LEFT JOIN (?) n ON n.IssueID = i.ID", h.incidentCounts(ctx))
+	db = db.Where("i.AnalysisID IN (?)", h.analysisIDs(ctx, filter))
+	db = db.Where("i.ID IN (?)", h.issueIDs(ctx, filter))
+	db = db.Group("i.Category")
+	db = db.Order("i.Category")
+	err = db.Scan(&resources).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	h.Respond(ctx, http.StatusOK, resources)
+}
+
 // AppIssueReports godoc
 // @summary List application issue reports.
 // @description Each report collates issues by ruleset/rule.
@@ -1299,8 +1547,9 @@ func (h AnalysisHandler) FileReports(ctx *gin.Context) {
 // @description - application.id
 // @description - application.name
 // @description - tag.id
+// @description CSV is rendered when Accept=text/csv. Columns are selected using: ?columns=name,version.
 // @tags dependencies
-// @produce json
+// @produce json,text/csv
 // @success 200 {object} []api.TechDependency
 // @router /analyses/dependencies [get]
 func (h AnalysisHandler) Deps(ctx *gin.Context) {
@@ -1362,6 +1611,15 @@ func (h AnalysisHandler) Deps(ctx *gin.Context) {
 		resources = append(resources, r)
 	}
 
+	if h.Accepted(ctx, CSV) {
+		table := &CsvTable{Columns: DepColumns}
+		for i := range resources {
+			table.Add(resources[i].csvRow())
+		}
+		h.writeCSV(ctx, table)
+		return
+	}
+
 	h.Respond(ctx, http.StatusOK, resources)
 }
 
@@ -1638,7 +1896,7 @@ func (h AnalysisHandler) DepAppReports(ctx *gin.Context) {
 	h.Respond(ctx, http.StatusOK, resources)
 }
 
-// appIDs provides application IDs.
+// appIDs provides (unarchived) application IDs.
 // filter:
 // - application.(id|name)
 // - tag.id
@@ -1646,6 +1904,7 @@ func (h *AnalysisHandler) appIDs(ctx *gin.Context, f qf.Filter) (q *gorm.DB) {
 	q = h.DB(ctx)
 	q = q.Model(&model.Application{})
 	q = q.Select("ID")
+	q = q.Where("Archived", false)
 	appFilter := f.Resource("application")
 	q = appFilter.Where(q)
 	tagFilter := f.Resource("tag")
@@ -1696,12 +1955,20 @@ func (h *AnalysisHandler) analysisIDs(ctx *gin.Context, f qf.Filter) (q *gorm.DB
 // Filter:
 //
 //	issue.*
+//	file (incident)
 func (h *AnalysisHandler) issueIDs(ctx *gin.Context, f qf.Filter) (q *gorm.DB) {
 	q = h.DB(ctx)
 	q = q.Model(&model.Issue{})
 	q = q.Select("ID")
-	q = f.Where(q, "-Labels")
+	q = f.Where(q, "-Labels", "-File")
 	filter := f
+	if f, found := filter.Field("file"); found {
+		iq := h.DB(ctx)
+		iq = iq.Model(&model.Incident{})
+		iq = iq.Select("IssueID")
+		iq = f.Where(iq)
+		q = q.Where("ID IN (?)", iq)
+	}
 	if f, found := filter.Field("labels"); found {
 		if f.Value.Operator(qf.AND) {
 			var qs []*gorm.DB
@@ -1728,6 +1995,15 @@ func (h *AnalysisHandler) issueIDs(ctx *gin.Context, f qf.Filter) (q *gorm.DB) {
 	return
 }
 
+// incidentCounts returns the incident count by issue.
+func (h *AnalysisHandler) incidentCounts(ctx *gin.Context) (q *gorm.DB) {
+	q = h.DB(ctx)
+	q = q.Model(&model.Incident{})
+	q = q.Select("IssueID", "COUNT(*) Count")
+	q = q.Group("IssueID")
+	return
+}
+
 // depIDs returns issue filtered issue IDs.
 // Filter:
 //
@@ -1833,6 +2109,7 @@ type Analysis struct {
 	Issues       []Issue          `json:"issues,omitempty" yaml:",omitempty"`
 	Dependencies []TechDependency `json:"dependencies,omitempty" yaml:",omitempty"`
 	Summary      []ArchivedIssue  `json:"summary,omitempty" yaml:",omitempty" swaggertype:"object"`
+	Provenance   *Provenance      `json:"provenance,omitempty" yaml:",omitempty"`
 }
 
 // With updates the resource with the model.
@@ -1859,12 +2136,19 @@ func (r *Analysis) With(m *model.Analysis) {
 	if m.Summary != nil {
 		_ = json.Unmarshal(m.Summary, &r.Summary)
 	}
+	if m.Provenance != nil {
+		r.Provenance = &Provenance{}
+		_ = json.Unmarshal(m.Provenance, r.Provenance)
+	}
 }
 
 // Model builds a model.
 func (r *Analysis) Model() (m *model.Analysis) {
 	m = &model.Analysis{}
 	m.Effort = r.Effort
+	if r.Provenance != nil {
+		m.Provenance, _ = json.Marshal(r.Provenance)
+	}
 	m.Issues = []model.Issue{}
 	for i := range r.Issues {
 		n := r.Issues[i].Model()
@@ -1882,6 +2166,23 @@ func (r *Analysis) Model() (m *model.Analysis) {
 	return
 }
 
+// Provenance analysis provenance.
+// Reported by the addon so that results can be reproduced.
+type Provenance struct {
+	Addon    string          `json:"addon,omitempty" yaml:",omitempty"`
+	Image    string          `json:"image,omitempty" yaml:",omitempty"`
+	Digest   string          `json:"digest,omitempty" yaml:",omitempty"`
+	RuleSets []ProvenanceRef `json:"rulesets,omitempty" yaml:",omitempty"`
+	Targets  []ProvenanceRef `json:"targets,omitempty" yaml:",omitempty"`
+	Flags    []string        `json:"flags,omitempty" yaml:",omitempty"`
+}
+
+// ProvenanceRef versioned (ruleset|target) reference.
+type ProvenanceRef struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty" yaml:",omitempty"`
+}
+
 // Issue REST resource.
 type Issue struct {
 	Resource    `yaml:",inline"`
@@ -1895,6 +2196,7 @@ type Issue struct {
 	Links       []Link     `json:"links,omitempty" yaml:",omitempty"`
 	Facts       FactMap    `json:"facts,omitempty" yaml:",omitempty"`
 	Labels      []string   `json:"labels"`
+	Suppressed  bool       `json:"suppressed,omitempty" yaml:",omitempty"`
 }
 
 // With updates the resource with the model.
@@ -1986,12 +2288,13 @@ func (r *TechDependency) Model() (m *model.TechDependency) {
 
 // Incident REST resource.
 type Incident struct {
-	Resource `yaml:",inline"`
-	File     string  `json:"file"`
-	Line     int     `json:"line"`
-	Message  string  `json:"message"`
-	CodeSnip string  `json:"codeSnip" yaml:"codeSnip"`
-	Facts    FactMap `json:"facts"`
+	Resource   `yaml:",inline"`
+	File       string  `json:"file"`
+	Line       int     `json:"line"`
+	Message    string  `json:"message"`
+	CodeSnip   string  `json:"codeSnip" yaml:"codeSnip"`
+	Facts      FactMap `json:"facts"`
+	Suppressed bool    `json:"suppressed,omitempty" yaml:",omitempty"`
 }
 
 // With updates the resource with the model.
@@ -2036,9 +2339,20 @@ type RuleReport struct {
 	Effort       int      `json:"effort"`
 	Labels       []string `json:"labels"`
 	Links        []Link   `json:"links"`
+	Incidents    int      `json:"incidents"`
+	TotalEffort  int      `json:"totalEffort"`
 	Applications int      `json:"applications"`
 }
 
+// IssueSummary REST resource.
+type IssueSummary struct {
+	Category     string `json:"category"`
+	Issues       int    `json:"issues"`
+	Incidents    int    `json:"incidents"`
+	Effort       int    `json:"effort"`
+	Applications int    `json:"applications"`
+}
+
 // IssueReport REST resource.
 type IssueReport struct {
 	ID          uint     `json:"id"`
@@ -2111,10 +2425,16 @@ type FactMap map[string]interface{}
 type AnalysisWriter struct {
 	encoder
 	ctx *gin.Context
+	// client (optional) used instead of the context DB.
+	client *gorm.DB
 }
 
 // db returns a db client.
 func (r *AnalysisWriter) db() (db *gorm.DB) {
+	if r.client != nil {
+		db = r.client
+		return
+	}
 	rtx := WithContext(r.ctx)
 	db = rtx.DB.Debug()
 	return
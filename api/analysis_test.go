@@ -0,0 +1,887 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/konveyor/tackle2-hub/model"
+	tasking "github.com/konveyor/tackle2-hub/task"
+	"github.com/onsi/gomega"
+)
+
+func TestAnalysisIssueReports(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	service := &model.BusinessService{Name: "finance"}
+	g.Expect(db.Create(service).Error).To(gomega.BeNil())
+	apps := []model.Application{
+		{Name: "a", BusinessServiceID: &service.ID},
+		{Name: "b"},
+	}
+	g.Expect(db.Create(&apps).Error).To(gomega.BeNil())
+	incidents := func(n int) (list []model.Incident) {
+		for i := 0; i < n; i++ {
+			list = append(list, model.Incident{File: "f", Line: i})
+		}
+		return
+	}
+	analyses := []model.Analysis{
+		// Superseded.
+		{
+			ApplicationID: apps[0].ID,
+			Issues: []model.Issue{
+				{RuleSet: "rs", Rule: "r1", Category: "mandatory", Effort: 9, Incidents: incidents(9)},
+			},
+		},
+		{
+			ApplicationID: apps[0].ID,
+			Issues: []model.Issue{
+				{RuleSet: "rs", Rule: "r1", Category: "mandatory", Effort: 2, Incidents: incidents(3)},
+				{RuleSet: "rs", Rule: "r2", Category: "optional", Effort: 1, Incidents: incidents(1)},
+			},
+		},
+		{
+			ApplicationID: apps[1].ID,
+			Issues: []model.Issue{
+				{RuleSet: "rs", Rule: "r1", Category: "mandatory", Effort: 2, Incidents: incidents(1)},
+				{RuleSet: "rs", Rule: "r3", Category: "mandatory", Effort: 5},
+			},
+		},
+	}
+	g.Expect(db.Create(&analyses).Error).To(gomega.BeNil())
+
+	h := AnalysisHandler{}
+	e := newEngine(db)
+	e.GET(AnalysisReportIssuesRoot, h.IssueSummaryReports)
+	e.GET(AnalysisReportRuleRoot, h.RuleReports)
+	get := func(path, filter string, r interface{}) {
+		w := httptest.NewRecorder()
+		if filter != "" {
+			path += "?filter=" + url.QueryEscape(filter)
+		}
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		e.ServeHTTP(w, req)
+		g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+		g.Expect(json.Unmarshal(w.Body.Bytes(), r)).To(gomega.BeNil())
+	}
+
+	summary := []IssueSummary{}
+	get(AnalysisReportIssuesRoot, "", &summary)
+	g.Expect(summary).To(gomega.Equal([]IssueSummary{
+		{Category: "mandatory", Issues: 3, Incidents: 4, Effort: 8, Applications: 2},
+		{Category: "optional", Issues: 1, Incidents: 1, Effort: 1, Applications: 1},
+	}))
+	summary = []IssueSummary{}
+	get(AnalysisReportIssuesRoot, "businessService.name=finance", &summary)
+	g.Expect(summary).To(gomega.Equal([]IssueSummary{
+		{Category: "mandatory", Issues: 1, Incidents: 3, Effort: 6, Applications: 1},
+		{Category: "optional", Issues: 1, Incidents: 1, Effort: 1, Applications: 1},
+	}))
+	summary = []IssueSummary{}
+	get(AnalysisReportIssuesRoot, "category=optional", &summary)
+	g.Expect(summary).To(gomega.HaveLen(1))
+
+	rules := []RuleReport{}
+	get(AnalysisReportRuleRoot+"?sort=rule", "", &rules)
+	g.Expect(rules).To(gomega.HaveLen(3))
+	g.Expect(rules[0].Rule).To(gomega.Equal("r1"))
+	g.Expect(rules[0].Incidents).To(gomega.Equal(4))
+	g.Expect(rules[0].TotalEffort).To(gomega.Equal(8))
+	g.Expect(rules[0].Applications).To(gomega.Equal(2))
+	g.Expect(rules[2].Incidents).To(gomega.Equal(0))
+}
+
+func TestAnalysisDiff(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	apps := []model.Application{{Name: "a"}, {Name: "b"}}
+	g.Expect(db.Create(&apps).Error).To(gomega.BeNil())
+	incidents := func(n int) (list []model.Incident) {
+		for i := 0; i < n; i++ {
+			list = append(list, model.Incident{File: "f", Line: i})
+		}
+		return
+	}
+	analyses := []model.Analysis{
+		{
+			ApplicationID: apps[0].ID,
+			Issues: []model.Issue{
+				{RuleSet: "rs", Rule: "r1", Category: "mandatory", Incidents: incidents(5)},
+				{RuleSet: "rs", Rule: "r2", Category: "mandatory", Incidents: incidents(1)},
+			},
+		},
+		{
+			ApplicationID: apps[0].ID,
+			Issues: []model.Issue{
+				{RuleSet: "rs", Rule: "r1", Category: "mandatory", Incidents: incidents(2)},
+				{RuleSet: "rs", Rule: "r3", Category: "optional", Incidents: incidents(1)},
+			},
+		},
+		{ApplicationID: apps[1].ID},
+	}
+	g.Expect(db.Create(&analyses).Error).To(gomega.BeNil())
+
+	h := AnalysisHandler{}
+	e := newEngine(db)
+	e.GET(AppAnalysesDiffRoot, h.AppDiff)
+	get := func(path string) (w *httptest.ResponseRecorder, r AnalysisDiff) {
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		e.ServeHTTP(w, req)
+		_ = json.Unmarshal(w.Body.Bytes(), &r)
+		return
+	}
+
+	w, r := get("/applications/1/analyses/diff")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(r.Base.ID).To(gomega.Equal(uint(1)))
+	g.Expect(r.Base.Incidents).To(gomega.Equal(6))
+	g.Expect(r.Target.ID).To(gomega.Equal(uint(2)))
+	g.Expect(r.Target.Incidents).To(gomega.Equal(3))
+	g.Expect(r.Introduced).To(gomega.Equal([]IssueDiff{
+		{RuleSet: "rs", Rule: "r3", Category: "optional", Target: 1},
+	}))
+	g.Expect(r.Resolved).To(gomega.Equal([]IssueDiff{
+		{RuleSet: "rs", Rule: "r2", Category: "mandatory", Base: 1},
+	}))
+	g.Expect(r.Unchanged).To(gomega.Equal([]IssueDiff{
+		{RuleSet: "rs", Rule: "r1", Category: "mandatory", Base: 5, Target: 2},
+	}))
+	// Reversed.
+	w, r = get("/applications/1/analyses/diff?base=2&target=1")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(r.Introduced[0].Rule).To(gomega.Equal("r2"))
+	// Analysis of another application.
+	w, _ = get("/applications/1/analyses/diff?base=3")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNotFound))
+	w, _ = get("/applications/1/analyses/diff?base=2&target=2")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	// Single analysis.
+	w, _ = get("/applications/2/analyses/diff")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNotFound))
+}
+
+func TestAnalysisSarif(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	app := &model.Application{Name: "a"}
+	g.Expect(db.Create(app).Error).To(gomega.BeNil())
+	analysis := &model.Analysis{
+		ApplicationID: app.ID,
+		Issues: []model.Issue{
+			{
+				RuleSet:     "rs",
+				Rule:        "r1",
+				Description: "Replace javax",
+				Category:    "mandatory",
+				Effort:      3,
+				Links:       []byte(`[{"url":"https://x"}]`),
+				Labels:      []byte(`["konveyor.io/target=quarkus"]`),
+				Incidents: []model.Incident{
+					{File: "file:///opt/input/source/src/A.java", Line: 7, Message: "javax.ejb"},
+					{File: "file:///opt/input/source/pom.xml"},
+				},
+			},
+			{RuleSet: "rs", Rule: "r2", Category: "potential"},
+		},
+	}
+	g.Expect(db.Create(analysis).Error).To(gomega.BeNil())
+
+	h := AnalysisHandler{}
+	e := newEngine(db)
+	e.GET(AppAnalysisSarifRoot, h.AppLatestSarif)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/applications/1/analysis/sarif?root=/opt/input/source", nil)
+	e.ServeHTTP(w, req)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(w.Header().Get("Content-Type")).To(gomega.Equal(MIMESARIF))
+	r := SarifLog{}
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+	g.Expect(r.Version).To(gomega.Equal(SarifVersion))
+	g.Expect(r.Runs).To(gomega.HaveLen(1))
+	run := r.Runs[0]
+	g.Expect(run.Tool.Driver.Rules).To(gomega.HaveLen(2))
+	rule := run.Tool.Driver.Rules[0]
+	g.Expect(rule.ID).To(gomega.Equal("rs/r1"))
+	g.Expect(rule.HelpURI).To(gomega.Equal("https://x"))
+	g.Expect(rule.Properties.Tags).To(gomega.Equal([]string{"konveyor.io/target=quarkus"}))
+	g.Expect(run.Results).To(gomega.HaveLen(2))
+	result := run.Results[0]
+	g.Expect(result.Level).To(gomega.Equal(SarifError))
+	g.Expect(result.Message.Text).To(gomega.Equal("javax.ejb"))
+	g.Expect(result.Locations[0].Physical.Artifact.URI).To(gomega.Equal("src/A.java"))
+	g.Expect(result.Locations[0].Physical.Region.StartLine).To(gomega.Equal(7))
+	result = run.Results[1]
+	g.Expect(result.Message.Text).To(gomega.Equal("Replace javax"))
+	g.Expect(result.Locations[0].Physical.Artifact.URI).To(gomega.Equal("pom.xml"))
+	g.Expect(result.Locations[0].Physical.Region).To(gomega.BeNil())
+
+	// Not analyzed.
+	g.Expect(db.Create(&model.Application{Name: "b"}).Error).To(gomega.BeNil())
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/applications/2/analysis/sarif", nil)
+	e.ServeHTTP(w, req)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNotFound))
+}
+
+func TestAnalysisCsv(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	app := &model.Application{Name: "a"}
+	g.Expect(db.Create(app).Error).To(gomega.BeNil())
+	analysis := &model.Analysis{
+		ApplicationID: app.ID,
+		Issues: []model.Issue{
+			{
+				RuleSet:     "rs",
+				Rule:        "r1",
+				Description: "Replace javax, jakarta",
+				Category:    "mandatory",
+				Effort:      3,
+				Labels:      []byte(`["a","b"]`),
+			},
+		},
+		Dependencies: []model.TechDependency{
+			{Provider: "java", Name: "log4j", Version: "1.2"},
+		},
+	}
+	g.Expect(db.Create(analysis).Error).To(gomega.BeNil())
+
+	h := AnalysisHandler{}
+	e := newEngine(db)
+	e.GET(AnalysesIssuesRoot, h.Issues)
+	e.GET(AnalysesDepsRoot, h.Deps)
+	get := func(path string) (w *httptest.ResponseRecorder) {
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(Accept, CSV)
+		e.ServeHTTP(w, req)
+		return
+	}
+
+	w := get(AnalysesIssuesRoot)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(w.Header().Get("Content-Type")).To(gomega.Equal(CSV))
+	g.Expect(w.Body.String()).To(gomega.Equal(
+		"id,ruleset,rule,name,description,category,effort,labels,links\n" +
+			"1,rs,r1,,\"Replace javax, jakarta\",mandatory,3,a;b,\n"))
+	w = get(AnalysesIssuesRoot + "?columns=rule,Effort")
+	g.Expect(w.Body.String()).To(gomega.Equal("rule,effort\nr1,3\n"))
+	w = get(AnalysesIssuesRoot + "?columns=rule,x")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	w = get(AnalysesDepsRoot + "?columns=name,version")
+	g.Expect(w.Body.String()).To(gomega.Equal("name,version\nlog4j,1.2\n"))
+}
+
+func TestAnalysisRetention(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	retention := Settings.Hub.Analysis.Retention
+	defer func() {
+		Settings.Hub.Analysis.Retention = retention
+	}()
+	apps := []model.Application{{Name: "a"}, {Name: "b"}}
+	g.Expect(db.Create(&apps).Error).To(gomega.BeNil())
+	for _, m := range []model.Analysis{
+		{ApplicationID: apps[0].ID},
+		{ApplicationID: apps[0].ID},
+		{ApplicationID: apps[0].ID},
+		{ApplicationID: apps[1].ID},
+	} {
+		g.Expect(db.Create(&m).Error).To(gomega.BeNil())
+	}
+	// Aged.
+	g.Expect(db.Exec("UPDATE Analysis SET CreateTime = ?", time.Now().Add(-48*time.Hour)).Error).To(gomega.BeNil())
+
+	e := newEngine(db)
+	h := AnalysisHandler{}
+	mh := MaintenanceHandler{}
+	e.DELETE(AppAnalysesIDRoot, h.AppDelete)
+	e.GET(ReaperDryRunRoot, mh.ReaperDryRun)
+	dryRun := func() (ids []uint) {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ReaperDryRunRoot, nil))
+		actions := []ReaperAction{}
+		_ = json.Unmarshal(w.Body.Bytes(), &actions)
+		for _, action := range actions {
+			if action.Kind == "Analysis" {
+				ids = append(ids, action.ID)
+			}
+		}
+		return
+	}
+
+	Settings.Hub.Analysis.Retention.Count = 2
+	g.Expect(dryRun()).To(gomega.Equal([]uint{1}))
+	Settings.Hub.Analysis.Retention.Count = 0
+	Settings.Hub.Analysis.Retention.Age = 1
+	// The latest is kept.
+	g.Expect(dryRun()).To(gomega.Equal([]uint{2, 1}))
+
+	// Delete.
+	del := func(path string) int {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, path, nil))
+		return w.Code
+	}
+	g.Expect(del("/applications/1/analyses/3")).To(gomega.Equal(http.StatusConflict))
+	// Analysis of another application (not found).
+	g.Expect(del("/applications/2/analyses/1")).To(gomega.Equal(http.StatusNoContent))
+	var n int64
+	db.Model(&model.Analysis{}).Count(&n)
+	g.Expect(n).To(gomega.Equal(int64(4)))
+	g.Expect(del("/applications/1/analyses/1")).To(gomega.Equal(http.StatusNoContent))
+	db.Model(&model.Analysis{}).Count(&n)
+	g.Expect(n).To(gomega.Equal(int64(3)))
+}
+
+func TestAnalysisStream(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	app := &model.Application{Name: "a"}
+	g.Expect(db.Create(app).Error).To(gomega.BeNil())
+
+	h := AnalysisHandler{}
+	e := newEngine(db)
+	e.POST(AppAnalysesStreamRoot, h.AppStream)
+	post := func(encoding, body string) (w *httptest.ResponseRecorder) {
+		w = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/applications/1/analyses/stream", strings.NewReader(body))
+		req.Header.Set(ContentType, encoding)
+		e.ServeHTTP(w, req)
+		return
+	}
+	ndjson := strings.Join(
+		[]string{
+			`{"issue":{"ruleset":"rs","rule":"r1","name":"n","category":"mandatory","effort":2,"incidents":[{"file":"a","line":1}]}}`,
+			`{"incident":{"file":"b","line":2}}`,
+			`{"incident":{"file":"c","line":3}}`,
+			`{"dependency":{"name":"d","version":"1","indirect":true}}`,
+			`{"dependency":{"name":"d","version":"1"}}`,
+			`{"dependency":{"name":"e","version":"1","indirect":true}}`,
+		},
+		"\n")
+	w := post(MIMENDJSON, ndjson)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusCreated))
+	r := Analysis{}
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+	g.Expect(r.Effort).To(gomega.Equal(6))
+	var n int64
+	db.Model(&model.Incident{}).Count(&n)
+	g.Expect(n).To(gomega.Equal(int64(3)))
+	deps := []model.TechDependency{}
+	db.Order("Name").Find(&deps)
+	g.Expect(len(deps)).To(gomega.Equal(2))
+	g.Expect(deps[0].Indirect).To(gomega.BeFalse())
+	g.Expect(deps[1].Indirect).To(gomega.BeTrue())
+	// YAML.
+	yml := "issue:\n  ruleset: rs\n  rule: r2\n  category: optional\n  effort: 1\n---\nincident:\n  file: a\n"
+	w = post(binding.MIMEYAML, yml)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusCreated))
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+	g.Expect(r.Effort).To(gomega.Equal(1))
+	// Invalid (analysis deleted).
+	db.Model(&model.Analysis{}).Count(&n)
+	g.Expect(n).To(gomega.Equal(int64(2)))
+	w = post(MIMENDJSON, `{"incident":{"file":"a"}}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	db.Model(&model.Analysis{}).Count(&n)
+	g.Expect(n).To(gomega.Equal(int64(2)))
+	w = post("text/plain", "")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+}
+
+func TestIncidentSnippet(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	bucket := &model.Bucket{}
+	g.Expect(db.Create(bucket).Error).To(gomega.BeNil())
+	g.Expect(os.MkdirAll(path.Join(bucket.Path, "src"), 0777)).To(gomega.BeNil())
+	lines := []string{}
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, "line"+strconv.Itoa(i))
+	}
+	content := strings.Join(lines, "\n")
+	g.Expect(os.WriteFile(path.Join(bucket.Path, "src", "a.java"), []byte(content), 0666)).To(gomega.BeNil())
+	app := &model.Application{Name: "a"}
+	app.BucketID = &bucket.ID
+	g.Expect(db.Create(app).Error).To(gomega.BeNil())
+	analysis := &model.Analysis{
+		ApplicationID: app.ID,
+		Issues: []model.Issue{
+			{
+				RuleSet:  "rs",
+				Rule:     "r1",
+				Category: "mandatory",
+				Incidents: []model.Incident{
+					{File: "file:///opt/input/source/src/a.java", Line: 10},
+					{File: "b.java", Line: 3, CodeSnip: " 1  one\n 2  two\n 3  three\n 4  four\n"},
+					{File: "c.java", Line: 3},
+				},
+			},
+		},
+	}
+	g.Expect(db.Create(analysis).Error).To(gomega.BeNil())
+
+	h := AnalysisHandler{}
+	e := newEngine(db)
+	e.GET(AnalysisIncidentSnippetRoot, h.IncidentSnippet)
+	get := func(path string) (w *httptest.ResponseRecorder, r Snippet) {
+		w = httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code == http.StatusOK {
+			g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+		}
+		return
+	}
+	// Bucket.
+	w, r := get("/analyses/incidents/1/snippet?context=2&root=/opt/input/source")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(r.Source).To(gomega.Equal(SnippetBucket))
+	g.Expect(r.Lines).To(gomega.Equal([]SnippetLine{
+		{Number: 8, Text: "line8"},
+		{Number: 9, Text: "line9"},
+		{Number: 10, Text: "line10"},
+		{Number: 11, Text: "line11"},
+		{Number: 12, Text: "line12"},
+	}))
+	// Code snip.
+	w, r = get("/analyses/incidents/2/snippet?context=1")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(r.Source).To(gomega.Equal(SnippetCodeSnip))
+	g.Expect(r.Lines).To(gomega.Equal([]SnippetLine{
+		{Number: 2, Text: "two"},
+		{Number: 3, Text: "three"},
+		{Number: 4, Text: "four"},
+	}))
+	// Not found.
+	w, _ = get("/analyses/incidents/3/snippet")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNotFound))
+	w, _ = get("/analyses/incidents/1/snippet?context=x")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+}
+
+func TestDepGraphReport(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	apps := []model.Application{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	g.Expect(db.Create(&apps).Error).To(gomega.BeNil())
+	dep := func(name, version string, indirect bool) model.TechDependency {
+		return model.TechDependency{Provider: "java", Name: name, Version: version, Indirect: indirect}
+	}
+	analyses := []model.Analysis{
+		// Superseded.
+		{
+			ApplicationID: apps[0].ID,
+			Dependencies: []model.TechDependency{
+				dep("log4j", "1.0", false),
+			},
+		},
+		{
+			ApplicationID: apps[0].ID,
+			Dependencies: []model.TechDependency{
+				dep("log4j", "2.9", false),
+				dep("spring", "5.0", false),
+			},
+		},
+		{
+			ApplicationID: apps[1].ID,
+			Dependencies: []model.TechDependency{
+				dep("log4j", "2.17.1", true),
+			},
+		},
+		{
+			ApplicationID: apps[2].ID,
+			Dependencies: []model.TechDependency{
+				dep("log4j", "2.9", true),
+			},
+		},
+	}
+	g.Expect(db.Create(&analyses).Error).To(gomega.BeNil())
+
+	h := AnalysisHandler{}
+	e := newEngine(db)
+	e.GET(AnalysisReportDepsGraphRoot, h.DepGraphReport)
+	get := func(filter string) (r DepGraph) {
+		w := httptest.NewRecorder()
+		path := AnalysisReportDepsGraphRoot
+		if filter != "" {
+			path += "?filter=" + url.QueryEscape(filter)
+		}
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+		g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+		return
+	}
+	r := get("")
+	g.Expect(len(r.Libraries)).To(gomega.Equal(2))
+	g.Expect(len(r.Applications)).To(gomega.Equal(3))
+	log4j := r.Libraries[0]
+	g.Expect(log4j.Name).To(gomega.Equal("log4j"))
+	g.Expect(log4j.Applications).To(gomega.Equal(3))
+	g.Expect(len(log4j.Versions)).To(gomega.Equal(2))
+	r = get("name=log4j,version<'2.17'")
+	g.Expect(r.Libraries).To(gomega.Equal([]DepGraphLibrary{
+		{
+			Provider:     "java",
+			Name:         "log4j",
+			Applications: 2,
+			Versions: []DepGraphVersion{
+				{
+					Version: "2.9",
+					Applications: []DepGraphEdge{
+						{Application: Ref{ID: apps[0].ID, Name: "a"}},
+						{Application: Ref{ID: apps[2].ID, Name: "c"}, Indirect: true},
+					},
+				},
+			},
+		},
+	}))
+	g.Expect(r.Applications).To(gomega.Equal([]Ref{
+		{ID: apps[0].ID, Name: "a"},
+		{ID: apps[2].ID, Name: "c"},
+	}))
+	r = get("version>='2.17'")
+	g.Expect(len(r.Libraries)).To(gomega.Equal(2))
+	g.Expect(r.Libraries[0].Versions[0].Version).To(gomega.Equal("2.17.1"))
+	// Version comparison.
+	g.Expect(versionCompare("2.9", "2.17")).To(gomega.Equal(-1))
+	g.Expect(versionCompare("2.0-beta", "2.0")).To(gomega.Equal(-1))
+	g.Expect(versionCompare("2.0.0", "2.0")).To(gomega.Equal(0))
+	g.Expect(versionCompare("2.0.1", "2.0")).To(gomega.Equal(1))
+}
+
+func TestAnalysisTrend(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	apps := []model.Application{{Name: "a"}, {Name: "b"}}
+	g.Expect(db.Create(&apps).Error).To(gomega.BeNil())
+	day := func(n int) time.Time {
+		return time.Date(2024, 1, n, 12, 0, 0, 0, time.UTC)
+	}
+	summary, _ := json.Marshal([]ArchivedIssue{
+		{RuleSet: "rs", Rule: "r1", Category: "mandatory", Effort: 1, Incidents: 4},
+	})
+	analyses := []model.Analysis{
+		{
+			ApplicationID: apps[0].ID,
+			Effort:        4,
+			Archived:      true,
+			Summary:       summary,
+		},
+		{
+			ApplicationID: apps[1].ID,
+			Effort:        3,
+			Issues: []model.Issue{
+				{RuleSet: "rs", Rule: "r1", Category: "optional", Effort: 3, Incidents: []model.Incident{{File: "f"}}},
+			},
+		},
+		{
+			ApplicationID: apps[0].ID,
+			Effort:        2,
+			Issues: []model.Issue{
+				{RuleSet: "rs", Rule: "r1", Category: "mandatory", Effort: 1, Incidents: []model.Incident{{File: "f"}, {File: "g"}}},
+			},
+			Dependencies: []model.TechDependency{{Name: "d"}},
+		},
+	}
+	for i, n := range []int{1, 1, 2} {
+		analyses[i].CreateTime = day(n)
+	}
+	g.Expect(db.Create(&analyses).Error).To(gomega.BeNil())
+
+	h := AnalysisHandler{}
+	e := newEngine(db)
+	e.GET(AppAnalysesTrendRoot, h.AppTrend)
+	e.GET(AnalysisReportTrendRoot, h.TrendReport)
+	get := func(path string, r interface{}) {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+		g.Expect(json.Unmarshal(w.Body.Bytes(), r)).To(gomega.BeNil())
+	}
+	points := []AnalysisTrend{}
+	get("/applications/1/analyses/trend", &points)
+	g.Expect(len(points)).To(gomega.Equal(2))
+	g.Expect(points[0].Archived).To(gomega.BeTrue())
+	g.Expect(points[0].Effort).To(gomega.Equal(4))
+	g.Expect(points[0].Incidents).To(gomega.Equal(4))
+	g.Expect(points[0].Categories).To(gomega.Equal(map[string]int{"mandatory": 4}))
+	g.Expect(points[0].Dependencies).To(gomega.BeNil())
+	g.Expect(points[1].Issues).To(gomega.Equal(1))
+	g.Expect(points[1].Incidents).To(gomega.Equal(2))
+	g.Expect(*points[1].Dependencies).To(gomega.Equal(1))
+	portfolio := []PortfolioTrend{}
+	get(AnalysisReportTrendRoot, &portfolio)
+	g.Expect(portfolio).To(gomega.Equal([]PortfolioTrend{
+		{
+			Date:         "2024-01-01",
+			Applications: 2,
+			Effort:       7,
+			Issues:       2,
+			Incidents:    5,
+			Categories:   map[string]int{"mandatory": 4, "optional": 1},
+		},
+		{
+			Date:         "2024-01-02",
+			Applications: 2,
+			Effort:       5,
+			Issues:       2,
+			Incidents:    3,
+			Categories:   map[string]int{"mandatory": 2, "optional": 1},
+			Dependencies: 1,
+		},
+	}))
+}
+
+func TestAnalysisProvenance(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	app := &model.Application{Name: "a"}
+	g.Expect(db.Create(app).Error).To(gomega.BeNil())
+
+	h := AnalysisHandler{}
+	e := newEngine(db)
+	e.POST(AppAnalysesStreamRoot, h.AppStream)
+	e.GET(AppAnalysesDiffRoot, h.AppDiff)
+	post := func(body string) (r Analysis) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/applications/1/analyses/stream", strings.NewReader(body))
+		req.Header.Set(ContentType, MIMENDJSON)
+		e.ServeHTTP(w, req)
+		g.Expect(w.Code).To(gomega.Equal(http.StatusCreated))
+		g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+		return
+	}
+	r := post(`{"provenance":{"addon":"analyzer","digest":"sha256:1","rulesets":[{"name":"rs","version":"1"}],"flags":["--mode=full"]}}`)
+	g.Expect(r.Provenance).To(gomega.Equal(&Provenance{
+		Addon:    "analyzer",
+		Digest:   "sha256:1",
+		RuleSets: []ProvenanceRef{{Name: "rs", Version: "1"}},
+		Flags:    []string{"--mode=full"},
+	}))
+	_ = post(`{"provenance":{"addon":"analyzer","digest":"sha256:2"}}`)
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/applications/1/analyses/diff", nil))
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	diff := AnalysisDiff{}
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &diff)).To(gomega.BeNil())
+	g.Expect(diff.Base.Provenance.Digest).To(gomega.Equal("sha256:1"))
+	g.Expect(diff.Target.Provenance.Digest).To(gomega.Equal("sha256:2"))
+}
+
+func TestAnalysisMerge(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	app := &model.Application{Name: "a"}
+	g.Expect(db.Create(app).Error).To(gomega.BeNil())
+	analysis := &model.Analysis{ApplicationID: app.ID}
+	analysis.Provenance = []byte(`{"rulesets":[{"name":"rs1","version":"1"},{"name":"rs2","version":"1"}]}`)
+	g.Expect(db.Create(analysis).Error).To(gomega.BeNil())
+	issues := []model.Issue{
+		{RuleSet: "rs1", Rule: "r1", Category: "mandatory", Effort: 1, AnalysisID: analysis.ID},
+		{RuleSet: "rs1", Rule: "r2", Category: "mandatory", Effort: 1, AnalysisID: analysis.ID},
+		{RuleSet: "rs2", Rule: "r1", Category: "optional", Effort: 3, AnalysisID: analysis.ID},
+	}
+	for i := range issues {
+		issues[i].Incidents = []model.Incident{{File: "a.java"}}
+	}
+	g.Expect(db.Create(&issues).Error).To(gomega.BeNil())
+
+	h := AnalysisHandler{}
+	e := newEngine(db)
+	e.POST(AppAnalysesRoot, h.AppCreate)
+	post := func(merge string, issues string) (w *httptest.ResponseRecorder) {
+		body := &bytes.Buffer{}
+		mp := multipart.NewWriter(body)
+		part := func(field, content string) {
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Disposition", `form-data; name="`+field+`"; filename="`+field+`"`)
+			header.Set(ContentType, binding.MIMEJSON)
+			w, err := mp.CreatePart(header)
+			g.Expect(err).To(gomega.BeNil())
+			_, _ = w.Write([]byte(content))
+		}
+		part(FileField, `{"provenance":{"rulesets":[{"name":"rs1","version":"2"}]}}`)
+		part(IssueField, issues)
+		part(DepField, "")
+		_ = mp.Close()
+		w = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/applications/1/analyses?merge="+merge, body)
+		req.Header.Set(ContentType, mp.FormDataContentType())
+		e.ServeHTTP(w, req)
+		return
+	}
+	// ruleset not listed.
+	w := post("rs1", `{"ruleset":"rs2","rule":"r1","category":"optional"}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	// merged.
+	w = post("rs1", `{"ruleset":"rs1","rule":"r1","category":"potential","effort":2,"incidents":[{"file":"a.java"},{"file":"b.java"}]}
+{"ruleset":"rs1","rule":"r3","category":"mandatory","effort":1,"incidents":[{"file":"c.java"}]}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	r := Analysis{}
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+	g.Expect(r.ID).To(gomega.Equal(analysis.ID))
+	g.Expect(r.Effort).To(gomega.Equal(2*2 + 1 + 3))
+	g.Expect(r.Provenance.RuleSets).To(gomega.Equal([]ProvenanceRef{
+		{Name: "rs1", Version: "2"},
+		{Name: "rs2", Version: "1"},
+	}))
+	var count int64
+	g.Expect(db.Model(&model.Analysis{}).Count(&count).Error).To(gomega.BeNil())
+	g.Expect(count).To(gomega.Equal(int64(1)))
+	var list []model.Issue
+	g.Expect(db.Preload("Incidents").Order("ID").Find(&list).Error).To(gomega.BeNil())
+	g.Expect(list).To(gomega.HaveLen(3))
+	g.Expect(list[0].ID).To(gomega.Equal(issues[0].ID))
+	g.Expect(list[0].Category).To(gomega.Equal("potential"))
+	g.Expect(list[0].Incidents).To(gomega.HaveLen(2))
+	g.Expect(list[1].ID).To(gomega.Equal(issues[2].ID))
+	g.Expect(list[2].Rule).To(gomega.Equal("r3"))
+}
+
+func TestIssuePagination(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	app := &model.Application{Name: "a"}
+	g.Expect(db.Create(app).Error).To(gomega.BeNil())
+	analysis := &model.Analysis{ApplicationID: app.ID}
+	g.Expect(db.Create(analysis).Error).To(gomega.BeNil())
+	for i := 0; i < 5; i++ {
+		m := &model.Issue{
+			RuleSet:    "rs",
+			Rule:       "r" + strconv.Itoa(i),
+			Category:   "mandatory",
+			Effort:     i,
+			AnalysisID: analysis.ID,
+		}
+		for n := 0; n < 3; n++ {
+			m.Incidents = append(
+				m.Incidents,
+				model.Incident{
+					File: "/src/" + strconv.Itoa(i) + "/f" + strconv.Itoa(n) + ".java",
+					Line: n,
+				})
+		}
+		g.Expect(db.Create(m).Error).To(gomega.BeNil())
+	}
+
+	h := AnalysisHandler{}
+	e := newEngine(db)
+	e.GET(AnalysesIssuesRoot, h.Issues)
+	e.GET(AnalysisIncidentsRoot, h.Incidents)
+	get := func(path string, r interface{}) (w *httptest.ResponseRecorder) {
+		w = httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+		g.Expect(json.Unmarshal(w.Body.Bytes(), r)).To(gomega.BeNil())
+		return
+	}
+	var issues []Issue
+	w := get("/analyses/issues?sort=desc:effort&limit=2&offset=1", &issues)
+	g.Expect(w.Header().Get(TotalCount)).To(gomega.Equal("5"))
+	g.Expect(issues).To(gomega.HaveLen(2))
+	g.Expect(issues[0].Effort).To(gomega.Equal(3))
+	g.Expect(issues[1].Effort).To(gomega.Equal(2))
+	w = get("/analyses/issues?filter="+url.QueryEscape("effort>1,effort<4,file~/src/3/*"), &issues)
+	g.Expect(w.Header().Get(TotalCount)).To(gomega.Equal("1"))
+	g.Expect(issues[0].Rule).To(gomega.Equal("r3"))
+	var incidents []Incident
+	w = get("/analyses/issues/1/incidents?sort=desc:line&limit=1&filter=line>0", &incidents)
+	g.Expect(w.Header().Get(TotalCount)).To(gomega.Equal("2"))
+	g.Expect(incidents).To(gomega.HaveLen(1))
+	g.Expect(incidents[0].Line).To(gomega.Equal(2))
+}
+
+func TestReportExport(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	reportPath := Settings.Analysis.ReportPath
+	Settings.Analysis.ReportPath = t.TempDir()
+	t.Cleanup(func() {
+		Settings.Analysis.ReportPath = reportPath
+	})
+	index := path.Join(Settings.Analysis.ReportPath, "index.html")
+	g.Expect(os.WriteFile(index, []byte("<html/>"), 0644)).To(gomega.BeNil())
+	for _, name := range []string{"a", "b", "c"} {
+		g.Expect(db.Create(&model.Application{Name: name}).Error).To(gomega.BeNil())
+	}
+	for _, id := range []uint{1, 2, 2} {
+		analysis := &model.Analysis{ApplicationID: id}
+		g.Expect(db.Create(analysis).Error).To(gomega.BeNil())
+		issue := &model.Issue{
+			RuleSet:    "rs",
+			Rule:       "r" + strconv.Itoa(int(analysis.ID)),
+			Category:   "mandatory",
+			AnalysisID: analysis.ID,
+		}
+		g.Expect(db.Create(issue).Error).To(gomega.BeNil())
+	}
+
+	h := AnalysisHandler{}
+	e := newEngine(db)
+	e.POST(AnalysisReportExportRoot, h.Export)
+	post := func(body string) (w *httptest.ResponseRecorder) {
+		w = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, AnalysisReportExportRoot, strings.NewReader(body))
+		req.Header.Set(ContentType, binding.MIMEJSON)
+		e.ServeHTTP(w, req)
+		return
+	}
+	// not analyzed.
+	w := post(`{"applications":[{"id":1},{"id":3}]}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	// exported.
+	w = post(`{"applications":[{"id":1},{"id":2}]}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusAccepted))
+	r := Task{}
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+	g.Expect(r.Name).To(gomega.Equal(ReportExportTask))
+	g.Expect(r.State).To(gomega.Equal(tasking.Running))
+	task := &model.Task{}
+	g.Eventually(func() string {
+		_ = db.First(task, r.ID).Error
+		return task.State
+	}, 5*time.Second, 10*time.Millisecond).Should(gomega.Equal(tasking.Succeeded))
+	bucket := &model.Bucket{}
+	g.Expect(db.First(bucket, *task.BucketID).Error).To(gomega.BeNil())
+	reader, err := zip.OpenReader(path.Join(bucket.Path, ReportBundle))
+	g.Expect(err).To(gomega.BeNil())
+	defer func() {
+		_ = reader.Close()
+	}()
+	content := map[string]string{}
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		g.Expect(err).To(gomega.BeNil())
+		b, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		content[f.Name] = string(b)
+	}
+	g.Expect(content).To(gomega.HaveKey("index.html"))
+	output := strings.TrimPrefix(content["output.js"], `window["apps"]=`)
+	var apps []struct {
+		ID       string  `json:"id"`
+		Analysis string  `json:"analysis"`
+		Issues   []Issue `json:"issues"`
+	}
+	g.Expect(json.Unmarshal([]byte(output), &apps)).To(gomega.BeNil())
+	g.Expect(apps).To(gomega.HaveLen(2))
+	g.Expect(apps[0].ID).To(gomega.Equal("1"))
+	g.Expect(apps[1].Analysis).To(gomega.Equal("3"))
+	g.Expect(apps[1].Issues).To(gomega.HaveLen(1))
+}
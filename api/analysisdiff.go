@@ -0,0 +1,227 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+// Routes
+const (
+	AppAnalysesDiffRoot = AppAnalysesRoot + "/diff"
+)
+
+// Params
+const (
+	BaseParam   = "base"
+	TargetParam = "target"
+)
+
+// AppDiff godoc
+// @summary Compare two analyses.
+// @description Compare (diff) the issues of two analyses of the application.
+// @description Issues are matched by ruleset/rule and reported as:
+// @description - introduced: found only in the target.
+// @description - resolved: found only in the base.
+// @description - unchanged: found in both (incident counts may differ).
+// @description The target defaults to the latest analysis and the base
+// @description defaults to the analysis preceding the target.
+// @description The provenance of each is included to explain differences.
+// @tags analyses
+// @produce json
+// @success 200 {object} api.AnalysisDiff
+// @router /applications/{id}/analyses/diff [get]
+// @param id path int true "Application ID"
+// @param base query int false "Base analysis ID"
+// @param target query int false "Target analysis ID"
+func (h AnalysisHandler) AppDiff(ctx *gin.Context) {
+	id := h.pk(ctx)
+	app := &model.Application{}
+	err := h.DB(ctx).Select("ID").First(app, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	incidents := map[uint]int{}
+	target, err := h.diffed(ctx, id, TargetParam, 0, incidents)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	base, err := h.diffed(ctx, id, BaseParam, target.ID, incidents)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if base.ID == target.ID {
+		err = &BadRequestError{"base and target must be different analyses."}
+		_ = ctx.Error(err)
+		return
+	}
+	r := AnalysisDiff{}
+	r.With(base, target, incidents)
+
+	h.Respond(ctx, http.StatusOK, r)
+}
+
+// diffed returns the analysis (with issues) specified by the parameter.
+// When not specified, the latest analysis created before the (before)
+// analysis ID is returned. The incident counts are added by issue ID.
+func (h *AnalysisHandler) diffed(ctx *gin.Context, appId uint, param string, before uint, incidents map[uint]int) (m *model.Analysis, err error) {
+	m = &model.Analysis{}
+	db := h.DB(ctx).Select("ID", "Effort", "Provenance", "CreateTime")
+	db = db.Where("ApplicationID", appId)
+	s := ctx.Query(param)
+	if s != "" {
+		n, pErr := strconv.ParseUint(s, 10, 0)
+		if pErr != nil {
+			err = &BadRequestError{param + ": '" + s + "' must be an integer."}
+			return
+		}
+		db = db.Where("ID", n)
+	} else if before > 0 {
+		db = db.Where("ID < ?", before)
+	}
+	err = db.Order("ID DESC").First(m).Error
+	if err != nil {
+		return
+	}
+	db = h.DB(ctx).Select(
+		"ID",
+		"RuleSet",
+		"Rule",
+		"Name",
+		"Category",
+		"Effort")
+	db = db.Where("AnalysisID", m.ID)
+	err = db.Find(&m.Issues).Error
+	if err != nil {
+		return
+	}
+	var counts []struct {
+		IssueID uint
+		Count   int
+	}
+	iq := h.DB(ctx).Model(&model.Issue{})
+	iq = iq.Select("ID")
+	iq = iq.Where("AnalysisID", m.ID)
+	q := h.incidentCounts(ctx)
+	q = q.Where("IssueID IN (?)", iq)
+	err = q.Scan(&counts).Error
+	if err != nil {
+		return
+	}
+	for _, c := range counts {
+		incidents[c.IssueID] = c.Count
+	}
+	return
+}
+
+// AnalysisDiff REST resource.
+type AnalysisDiff struct {
+	Base       AnalysisDiffed `json:"base"`
+	Target     AnalysisDiffed `json:"target"`
+	Introduced []IssueDiff    `json:"introduced"`
+	Resolved   []IssueDiff    `json:"resolved"`
+	Unchanged  []IssueDiff    `json:"unchanged"`
+}
+
+// With updates the resource with the (base and target) models
+// and the incident counts by issue ID.
+func (r *AnalysisDiff) With(base, target *model.Analysis, incidents map[uint]int) {
+	r.Base.With(base, incidents)
+	r.Target.With(target, incidents)
+	r.Introduced = []IssueDiff{}
+	r.Resolved = []IssueDiff{}
+	r.Unchanged = []IssueDiff{}
+	key := func(m *model.Issue) string {
+		return m.RuleSet + "/" + m.Rule
+	}
+	found := map[string]*model.Issue{}
+	for i := range base.Issues {
+		m := &base.Issues[i]
+		found[key(m)] = m
+	}
+	matched := map[string]bool{}
+	for i := range target.Issues {
+		m := &target.Issues[i]
+		d := IssueDiff{}
+		d.With(m)
+		d.Target = incidents[m.ID]
+		if b, exists := found[key(m)]; exists {
+			matched[key(m)] = true
+			d.Base = incidents[b.ID]
+			r.Unchanged = append(r.Unchanged, d)
+		} else {
+			r.Introduced = append(r.Introduced, d)
+		}
+	}
+	for i := range base.Issues {
+		m := &base.Issues[i]
+		if matched[key(m)] {
+			continue
+		}
+		d := IssueDiff{}
+		d.With(m)
+		d.Base = incidents[m.ID]
+		r.Resolved = append(r.Resolved, d)
+	}
+	for _, list := range [][]IssueDiff{r.Introduced, r.Resolved, r.Unchanged} {
+		sort.Slice(
+			list,
+			func(i, j int) bool {
+				if list[i].RuleSet != list[j].RuleSet {
+					return list[i].RuleSet < list[j].RuleSet
+				}
+				return list[i].Rule < list[j].Rule
+			})
+	}
+}
+
+// AnalysisDiffed the compared analysis.
+type AnalysisDiffed struct {
+	ID         uint        `json:"id"`
+	Effort     int         `json:"effort"`
+	Issues     int         `json:"issues"`
+	Incidents  int         `json:"incidents"`
+	Provenance *Provenance `json:"provenance,omitempty" yaml:",omitempty"`
+}
+
+// With updates the resource with the model.
+func (r *AnalysisDiffed) With(m *model.Analysis, incidents map[uint]int) {
+	r.ID = m.ID
+	r.Effort = m.Effort
+	r.Issues = len(m.Issues)
+	if m.Provenance != nil {
+		r.Provenance = &Provenance{}
+		_ = json.Unmarshal(m.Provenance, r.Provenance)
+	}
+	for i := range m.Issues {
+		r.Incidents += incidents[m.Issues[i].ID]
+	}
+}
+
+// IssueDiff the compared issue.
+// Base and Target are the incident counts.
+type IssueDiff struct {
+	RuleSet  string `json:"ruleset"`
+	Rule     string `json:"rule"`
+	Name     string `json:"name,omitempty" yaml:",omitempty"`
+	Category string `json:"category"`
+	Effort   int    `json:"effort"`
+	Base     int    `json:"base"`
+	Target   int    `json:"target"`
+}
+
+// With updates the resource with the model.
+func (r *IssueDiff) With(m *model.Issue) {
+	r.RuleSet = m.RuleSet
+	r.Rule = m.Rule
+	r.Name = m.Name
+	r.Category = m.Category
+	r.Effort = m.Effort
+}
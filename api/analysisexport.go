@@ -0,0 +1,286 @@
+package api
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	pathlib "path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/model"
+	tasking "github.com/konveyor/tackle2-hub/task"
+	"gorm.io/gorm"
+)
+
+// Routes
+const (
+	AnalysisReportExportRoot = AnalysesReportRoot + "/export"
+)
+
+// Report export.
+const (
+	// ReportExportTask the name of the (hub) export task.
+	ReportExportTask = "analysis-report-export"
+	// ReportBundle the bundle (artifact) path in the task bucket.
+	ReportBundle = "report.zip"
+)
+
+// Export godoc
+// @summary Export the (static) analysis report.
+// @description Export a (static) HTML report bundle for the applications using the
+// @description latest analysis of each. The bundle is built asynchronously by a (hub) task
+// @description which is returned. When the task has succeeded, the (zip) bundle may be
+// @description downloaded from the task bucket: /tasks/{id}/bucket/report.zip.
+// @description The task is failed when the hub is restarted before the bundle is built.
+// @tags analyses
+// @accept json
+// @produce json
+// @success 202 {object} api.Task
+// @router /analyses/report/export [post]
+// @param export body api.ReportExport true "Export data"
+func (h AnalysisHandler) Export(ctx *gin.Context) {
+	r := &ReportExport{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if len(r.Applications) == 0 {
+		err = &BadRequestError{"applications: at least 1 required."}
+		_ = ctx.Error(err)
+		return
+	}
+	ids := []uint{}
+	seen := map[uint]bool{}
+	for _, ref := range r.Applications {
+		if !seen[ref.ID] {
+			seen[ref.ID] = true
+			ids = append(ids, ref.ID)
+		}
+	}
+	var latest []struct {
+		ApplicationID uint
+		ID            uint
+	}
+	db := h.DB(ctx)
+	db = db.Model(&model.Analysis{})
+	db = db.Select("ApplicationID", "MAX(ID) ID")
+	db = db.Where("ApplicationID IN ?", ids)
+	db = db.Group("ApplicationID")
+	err = db.Scan(&latest).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	found := map[uint]uint{}
+	for _, m := range latest {
+		found[m.ApplicationID] = m.ID
+	}
+	analyses := []uint{}
+	for _, id := range ids {
+		analysis, matched := found[id]
+		if !matched {
+			err = &BadRequestError{
+				"application: (id=" + strconv.Itoa(int(id)) + ") not found or not analyzed.",
+			}
+			_ = ctx.Error(err)
+			return
+		}
+		analyses = append(analyses, analysis)
+	}
+	mark := time.Now()
+	task := &model.Task{}
+	task.Name = ReportExportTask
+	task.Addon = tasking.HubAddon
+	task.State = tasking.Running
+	task.Started = &mark
+	task.Data, _ = json.Marshal(r)
+	task.CreateUser = h.CurrentUser(ctx)
+	task.Event(tasking.Running, "")
+	err = h.DB(ctx).Create(task).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	exporter := ReportExporter{
+		DB:       h.DB(ctx).WithContext(context.Background()),
+		task:     task,
+		analyses: analyses,
+	}
+	go exporter.Run()
+	rt := Task{}
+	rt.With(task)
+
+	h.Respond(ctx, http.StatusAccepted, rt)
+}
+
+// ReportExport REST resource.
+type ReportExport struct {
+	Applications []Ref `json:"applications" binding:"required"`
+}
+
+// ReportExporter builds the (static) report bundle.
+type ReportExporter struct {
+	DB       *gorm.DB
+	task     *model.Task
+	analyses []uint
+}
+
+// Run builds the bundle and updates the task.
+func (r *ReportExporter) Run() {
+	err := r.build()
+	mark := time.Now()
+	r.task.Terminated = &mark
+	if err != nil {
+		r.task.State = tasking.Failed
+		r.task.Error("Error", err.Error())
+	} else {
+		r.task.State = tasking.Succeeded
+	}
+	r.task.Event(r.task.State, "")
+	db := r.DB.Model(r.task)
+	db = db.Select("State", "Terminated", "Errors", "Events")
+	err = db.Updates(r.task).Error
+	if err != nil {
+		Log.Error(err, "")
+	}
+}
+
+// build the bundle in the task bucket.
+// The bundle contains the report (static) content
+// and the output.js built for the analyses. The bundle
+// is (renamed) in place only when complete.
+func (r *ReportExporter) build() (err error) {
+	bucket := &model.Bucket{}
+	err = r.DB.First(bucket, *r.task.BucketID).Error
+	if err != nil {
+		return
+	}
+	output, err := r.output()
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = os.Remove(output)
+	}()
+	path := pathlib.Join(bucket.Path, ReportBundle)
+	file, err := os.Create(path + ".partial")
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = file.Close()
+		if err != nil {
+			_ = os.Remove(file.Name())
+		}
+	}()
+	writer := zip.NewWriter(file)
+	reportDir := Settings.Analysis.ReportPath
+	err = filepath.WalkDir(
+		reportDir,
+		func(path string, entry fs.DirEntry, wErr error) (err error) {
+			if wErr != nil {
+				err = wErr
+				return
+			}
+			if !entry.Type().IsRegular() {
+				return
+			}
+			name, err := filepath.Rel(reportDir, path)
+			if err != nil {
+				return
+			}
+			if name == "output.js" {
+				return
+			}
+			err = r.add(writer, path, filepath.ToSlash(name))
+			return
+		})
+	if err != nil {
+		return
+	}
+	err = r.add(writer, output, "output.js")
+	if err != nil {
+		return
+	}
+	err = writer.Close()
+	if err != nil {
+		return
+	}
+	err = os.Rename(file.Name(), path)
+	return
+}
+
+// add a file to the bundle.
+func (r *ReportExporter) add(writer *zip.Writer, path, name string) (err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	w, err := writer.Create(name)
+	if err != nil {
+		return
+	}
+	_, err = io.Copy(w, f)
+	return
+}
+
+// output creates the report output.js file.
+func (r *ReportExporter) output() (path string, err error) {
+	file, err := os.CreateTemp("", "output-*.js")
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	path = file.Name()
+	_, _ = file.WriteString("window[\"apps\"]=[")
+	for i, id := range r.analyses {
+		if i > 0 {
+			_, _ = file.WriteString(",")
+		}
+		m := &model.Analysis{}
+		db := r.DB
+		db = db.Preload("Application")
+		db = db.Preload("Application.Tags")
+		db = db.Preload("Application.Tags.Category")
+		err = db.First(m, id).Error
+		if err != nil {
+			return
+		}
+		writer := ReportWriter{}
+		writer.encoder = &jsonEncoder{output: file}
+		writer.begin()
+		writer.field("id").writeStr(strconv.Itoa(int(m.Application.ID)))
+		writer.field("name").writeStr(m.Application.Name)
+		writer.field("analysis").writeStr(strconv.Itoa(int(m.ID)))
+		aWriter := AnalysisWriter{client: r.DB}
+		aWriter.encoder = writer.encoder
+		err = aWriter.addIssues(m)
+		if err != nil {
+			return
+		}
+		err = aWriter.addDeps(m)
+		if err != nil {
+			return
+		}
+		err = writer.addTags(m)
+		if err != nil {
+			return
+		}
+		writer.end()
+	}
+	_, _ = file.WriteString("]")
+	return
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Params
+const (
+	MergeParam = "merge"
+)
+
+// merge the (re-run) rulesets into the latest analysis.
+// Issues are matched by ruleset/rule. Matched issues are updated
+// (incidents replaced) so that the issue ID is stable. Unmatched issues
+// are created. Issues (of the rulesets) not reported are deleted.
+// Dependencies are not merged.
+func (h *AnalysisHandler) merge(ctx *gin.Context, rulesets []string) {
+	id := h.pk(ctx)
+	analysis := &model.Analysis{}
+	db := h.DB(ctx)
+	db = db.Where("ApplicationID", id)
+	db = db.Where("Archived", false)
+	err := db.Last(analysis).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = &BadRequestError{"merge: analysis (to be merged) not found."}
+		}
+		_ = ctx.Error(err)
+		return
+	}
+	//
+	// Analysis
+	d, closer, err := h.formDecoder(ctx, FileField)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	defer closer()
+	r := Analysis{}
+	err = d.Decode(&r)
+	if err != nil {
+		err = &BadRequestError{err.Error()}
+		_ = ctx.Error(err)
+		return
+	}
+	//
+	// Issues
+	d, closer, err = h.formDecoder(ctx, IssueField)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	defer closer()
+	selected := map[string]bool{}
+	for _, name := range rulesets {
+		selected[name] = true
+	}
+	err = h.DB(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		var existing []model.Issue
+		db := tx.Select("ID", "RuleSet", "Rule")
+		db = db.Where("AnalysisID", analysis.ID)
+		db = db.Where("RuleSet IN ?", rulesets)
+		err = db.Find(&existing).Error
+		if err != nil {
+			return
+		}
+		found := map[string]uint{}
+		for _, m := range existing {
+			found[m.RuleSet+"/"+m.Rule] = m.ID
+		}
+		kept := map[uint]bool{}
+		for {
+			issue := &Issue{}
+			err = d.Decode(issue)
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+					break
+				} else {
+					err = &BadRequestError{err.Error()}
+					return
+				}
+			}
+			if !selected[issue.RuleSet] {
+				err = &BadRequestError{
+					"issue: ruleset '" + issue.RuleSet + "' not in: " + MergeParam + "=" + strings.Join(rulesets, ","),
+				}
+				return
+			}
+			m := issue.Model()
+			m.AnalysisID = analysis.ID
+			issueId, matched := found[m.RuleSet+"/"+m.Rule]
+			if !matched {
+				err = tx.Create(m).Error
+				if err != nil {
+					return
+				}
+				continue
+			}
+			kept[issueId] = true
+			err = h.mergeIssue(tx, issueId, m)
+			if err != nil {
+				return
+			}
+		}
+		resolved := []uint{}
+		for _, m := range existing {
+			if !kept[m.ID] {
+				resolved = append(resolved, m.ID)
+			}
+		}
+		if len(resolved) > 0 {
+			err = tx.Delete(&model.Issue{}, resolved).Error
+			if err != nil {
+				return
+			}
+		}
+		var effort int
+		q := tx.Table("Issue i")
+		q = q.Select("IFNULL(SUM(i.Effort * n.Count), 0)")
+		q = q.Joins("JOIN (?) n ON n.IssueID = i.ID", h.incidentCounts(ctx))
+		q = q.Where("i.AnalysisID", analysis.ID)
+		err = q.Scan(&effort).Error
+		if err != nil {
+			return
+		}
+		provenance := &Provenance{}
+		if analysis.Provenance != nil {
+			_ = json.Unmarshal(analysis.Provenance, provenance)
+		}
+		provenance.Merge(r.Provenance)
+		db = tx.Model(analysis)
+		db = db.Omit(clause.Associations)
+		analysis.Effort = effort
+		analysis.Provenance, _ = json.Marshal(provenance)
+		analysis.UpdateUser = h.CurrentUser(ctx)
+		err = db.Updates(map[string]interface{}{
+			"Effort":     analysis.Effort,
+			"Provenance": analysis.Provenance,
+			"UpdateUser": analysis.UpdateUser,
+		}).Error
+		return
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	db = h.DB(ctx)
+	db = db.Preload(clause.Associations)
+	err = db.First(analysis).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	r = Analysis{}
+	r.With(analysis)
+
+	h.Respond(ctx, http.StatusOK, r)
+}
+
+// mergeIssue updates the (matched) issue and replaces the incidents.
+func (h *AnalysisHandler) mergeIssue(tx *gorm.DB, id uint, m *model.Issue) (err error) {
+	db := tx.Model(&model.Issue{})
+	db = db.Where("ID", id)
+	err = db.Updates(map[string]interface{}{
+		"Name":        m.Name,
+		"Description": m.Description,
+		"Category":    m.Category,
+		"Effort":      m.Effort,
+		"Links":       m.Links,
+		"Facts":       m.Facts,
+		"Labels":      m.Labels,
+	}).Error
+	if err != nil {
+		return
+	}
+	err = tx.Where("IssueID", id).Delete(&model.Incident{}).Error
+	if err != nil {
+		return
+	}
+	if len(m.Incidents) == 0 {
+		return
+	}
+	for i := range m.Incidents {
+		m.Incidents[i].IssueID = id
+	}
+	err = tx.Create(&m.Incidents).Error
+	return
+}
+
+// formDecoder returns a decoder for the form (file) field.
+func (h *AnalysisHandler) formDecoder(ctx *gin.Context, field string) (d Decoder, closer func(), err error) {
+	closer = func() {}
+	input, err := ctx.FormFile(field)
+	if err != nil {
+		err = &BadRequestError{err.Error()}
+		return
+	}
+	reader, err := input.Open()
+	if err != nil {
+		err = &BadRequestError{err.Error()}
+		return
+	}
+	closer = func() {
+		_ = reader.Close()
+	}
+	encoding := input.Header.Get(ContentType)
+	d, err = h.Decoder(ctx, encoding, reader)
+	if err != nil {
+		err = &BadRequestError{err.Error()}
+		return
+	}
+	return
+}
+
+// Merge the (re-run) provenance.
+// Rulesets and targets are replaced by name. The addon,
+// image, digest and flags are replaced when specified.
+func (r *Provenance) Merge(other *Provenance) {
+	if other == nil {
+		return
+	}
+	if other.Addon != "" {
+		r.Addon = other.Addon
+	}
+	if other.Image != "" {
+		r.Image = other.Image
+	}
+	if other.Digest != "" {
+		r.Digest = other.Digest
+	}
+	if len(other.Flags) > 0 {
+		r.Flags = other.Flags
+	}
+	merge := func(in, with []ProvenanceRef) (out []ProvenanceRef) {
+		out = in
+		for _, ref := range with {
+			replaced := false
+			for i := range out {
+				if out[i].Name == ref.Name {
+					out[i] = ref
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				out = append(out, ref)
+			}
+		}
+		return
+	}
+	r.RuleSets = merge(r.RuleSets, other.RuleSets)
+	r.Targets = merge(r.Targets, other.Targets)
+}
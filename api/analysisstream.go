@@ -0,0 +1,271 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// Routes
+const (
+	AppAnalysesStreamRoot = AppAnalysesRoot + "/stream"
+)
+
+// MIMENDJSON newline delimited JSON mime.
+const MIMENDJSON = "application/x-ndjson"
+
+// StreamBatch the number of (streamed) records inserted per batch.
+const StreamBatch = 500
+
+// AppStream godoc
+// @summary Create an analysis (streamed).
+// @description Create an analysis from a stream of api.AnalysisRecord.
+// @description The body is either newline delimited JSON (application/x-ndjson)
+// @description or a multi-document YAML (application/x-yaml) stream.
+// @description Each record contains exactly one of:
+// @description - provenance: the api.Provenance.
+// @description - issue: an api.Issue.
+// @description - incident: an api.Incident of the preceding issue.
+// @description - dependency: an api.TechDependency.
+// @description Records are inserted in batches as they are read. When the same
+// @description dependency is reported as both direct and indirect, it is stored as direct.
+// @description The analysis is deleted when the stream is not valid.
+// @tags analyses
+// @accept application/x-ndjson,application/x-yaml
+// @produce json
+// @success 201 {object} api.Analysis
+// @router /applications/{id}/analyses/stream [post]
+// @param id path int true "Application ID"
+// @param records body api.AnalysisRecord true "Analysis records"
+func (h AnalysisHandler) AppStream(ctx *gin.Context) {
+	id := h.pk(ctx)
+	application := &model.Application{}
+	err := h.DB(ctx).First(application, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if application.Archived {
+		_ = ctx.Error(&BadRequestError{"application is archived."})
+		return
+	}
+	encoding := ctx.ContentType()
+	switch encoding {
+	case MIMENDJSON:
+		encoding = binding.MIMEJSON
+	case binding.MIMEJSON, binding.MIMEYAML:
+	default:
+		err = &BadRequestError{"Content-Type must be (" + MIMENDJSON + "|" + binding.MIMEYAML + ")."}
+		_ = ctx.Error(err)
+		return
+	}
+	d, err := h.Decoder(ctx, encoding, nil)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = h.archive(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	analysis := &model.Analysis{}
+	analysis.ApplicationID = id
+	analysis.CreateUser = h.BaseHandler.CurrentUser(ctx)
+	db := h.DB(ctx)
+	db.Logger = db.Logger.LogMode(logger.Error)
+	err = db.Create(analysis).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ingest := AnalysisIngest{DB: db, Analysis: analysis}
+	err = ingest.Read(d)
+	if err != nil {
+		_ = ctx.Error(err)
+		_ = db.Delete(analysis).Error
+		return
+	}
+	err = db.Save(analysis).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	r := Analysis{}
+	r.With(analysis)
+
+	h.Respond(ctx, http.StatusCreated, r)
+}
+
+// AnalysisRecord streamed analysis record.
+type AnalysisRecord struct {
+	Provenance *Provenance     `json:"provenance,omitempty" yaml:",omitempty"`
+	Issue      *Issue          `json:"issue,omitempty" yaml:",omitempty"`
+	Incident   *Incident       `json:"incident,omitempty" yaml:",omitempty"`
+	Dependency *TechDependency `json:"dependency,omitempty" yaml:",omitempty"`
+}
+
+// AnalysisIngest ingests streamed analysis records.
+// Incidents and dependencies are inserted in batches. The
+// analysis effort is updated as issues and incidents are read.
+type AnalysisIngest struct {
+	DB        *gorm.DB
+	Analysis  *model.Analysis
+	issue     *model.Issue
+	incidents []model.Incident
+	deps      []model.TechDependency
+}
+
+// Read (and ingest) all of the records.
+func (r *AnalysisIngest) Read(d Decoder) (err error) {
+	for {
+		record := &AnalysisRecord{}
+		err = d.Decode(record)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+				break
+			} else {
+				err = &BadRequestError{err.Error()}
+				return
+			}
+		}
+		err = r.Add(record)
+		if err != nil {
+			return
+		}
+	}
+	err = r.Flush()
+	return
+}
+
+// Add a record.
+func (r *AnalysisIngest) Add(record *AnalysisRecord) (err error) {
+	switch {
+	case record.Provenance != nil:
+		r.Analysis.Provenance, err = json.Marshal(record.Provenance)
+	case record.Issue != nil:
+		err = r.addIssue(record.Issue)
+	case record.Incident != nil:
+		err = r.addIncident(record.Incident)
+	case record.Dependency != nil:
+		err = r.addDep(record.Dependency)
+	default:
+		err = &BadRequestError{"record must contain (provenance|issue|incident|dependency)."}
+	}
+	return
+}
+
+// Flush inserts the pending incidents and dependencies.
+func (r *AnalysisIngest) Flush() (err error) {
+	if len(r.incidents) > 0 {
+		err = r.DB.Create(&r.incidents).Error
+		if err != nil {
+			return
+		}
+		r.incidents = nil
+	}
+	if len(r.deps) > 0 {
+		db := r.DB.Clauses(clause.OnConflict{
+			Columns: []clause.Column{
+				{Name: "Provider"},
+				{Name: "Name"},
+				{Name: "Version"},
+				{Name: "SHA"},
+				{Name: "AnalysisID"},
+			},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"Indirect": gorm.Expr("Indirect AND excluded.Indirect"),
+			}),
+		})
+		err = db.Create(&r.deps).Error
+		if err != nil {
+			return
+		}
+		r.deps = nil
+	}
+	return
+}
+
+// addIssue inserts the issue.
+// Embedded incidents are queued.
+func (r *AnalysisIngest) addIssue(issue *Issue) (err error) {
+	err = r.validate(issue)
+	if err != nil {
+		return
+	}
+	m := issue.Model()
+	m.AnalysisID = r.Analysis.ID
+	incidents := m.Incidents
+	m.Incidents = nil
+	err = r.DB.Create(m).Error
+	if err != nil {
+		return
+	}
+	r.issue = m
+	for i := range incidents {
+		err = r.queueIncident(&incidents[i])
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// addIncident queues an incident of the preceding issue.
+func (r *AnalysisIngest) addIncident(incident *Incident) (err error) {
+	if r.issue == nil {
+		err = &BadRequestError{"incident must follow an issue."}
+		return
+	}
+	err = r.queueIncident(incident.Model())
+	return
+}
+
+// queueIncident queues the incident and flushes the
+// (full) batch.
+func (r *AnalysisIngest) queueIncident(m *model.Incident) (err error) {
+	m.IssueID = r.issue.ID
+	r.incidents = append(r.incidents, *m)
+	r.Analysis.Effort += r.issue.Effort
+	if len(r.incidents) >= StreamBatch {
+		err = r.Flush()
+	}
+	return
+}
+
+// addDep queues a dependency and flushes the (full) batch.
+func (r *AnalysisIngest) addDep(dep *TechDependency) (err error) {
+	if dep.Name == "" {
+		err = &BadRequestError{"dependency: name required."}
+		return
+	}
+	m := dep.Model()
+	m.AnalysisID = r.Analysis.ID
+	r.deps = append(r.deps, *m)
+	if len(r.deps) >= StreamBatch {
+		err = r.Flush()
+	}
+	return
+}
+
+// validate the issue required fields.
+func (r *AnalysisIngest) validate(issue *Issue) (err error) {
+	switch {
+	case issue.RuleSet == "":
+		err = &BadRequestError{"issue: ruleset required."}
+	case issue.Rule == "":
+		err = &BadRequestError{"issue: rule required."}
+	case issue.Category == "":
+		err = &BadRequestError{"issue: category required."}
+	}
+	return
+}
@@ -0,0 +1,256 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	qf "github.com/konveyor/tackle2-hub/api/filter"
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/gorm"
+)
+
+// Routes
+const (
+	AppAnalysesTrendRoot    = AppAnalysesRoot + "/trend"
+	AnalysisReportTrendRoot = AnalysesReportRoot + "/trend"
+)
+
+// AppTrend godoc
+// @summary Get the application analysis trend.
+// @description Get the effort, issue, incident (by category) and dependency
+// @description counts of each analysis (run) of the application ordered by creation.
+// @description The counts of archived analyses are based on the archived summary.
+// @description Dependencies are not retained (omitted) for archived analyses.
+// @tags analyses
+// @produce json
+// @success 200 {object} []api.AnalysisTrend
+// @router /applications/{id}/analyses/trend [get]
+// @param id path int true "Application ID"
+func (h AnalysisHandler) AppTrend(ctx *gin.Context) {
+	id := h.pk(ctx)
+	app := &model.Application{}
+	err := h.DB(ctx).Select("ID").First(app, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	db := h.DB(ctx)
+	db = db.Where("ApplicationID", id)
+	resources, err := h.trend(ctx, db)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	h.Respond(ctx, http.StatusOK, resources)
+}
+
+// TrendReport godoc
+// @summary Get the portfolio analysis trend.
+// @description Get the (daily) portfolio trend. Each point totals the counts
+// @description of the latest analysis of each application as of the (UTC) date.
+// @description Points are reported for each date on which an analysis was created.
+// @description Dependencies are not retained for archived analyses and are counted
+// @description only for (unarchived) latest analyses.
+// @description filters:
+// @description - application.id
+// @description - application.name
+// @description - businessService.id
+// @description - businessService.name
+// @description - tag.id
+// @tags analyses
+// @produce json
+// @success 200 {object} []api.PortfolioTrend
+// @router /analyses/report/trend [get]
+func (h AnalysisHandler) TrendReport(ctx *gin.Context) {
+	filter, err := qf.New(ctx,
+		[]qf.Assert{
+			{Field: "application.id", Kind: qf.LITERAL},
+			{Field: "application.name", Kind: qf.STRING},
+			{Field: "businessService.id", Kind: qf.LITERAL},
+			{Field: "businessService.name", Kind: qf.STRING},
+			{Field: "tag.id", Kind: qf.LITERAL, And: true},
+		})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	db := h.DB(ctx)
+	db = db.Where("ApplicationID IN (?)", h.appIDs(ctx, filter))
+	points, err := h.trend(ctx, db)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	sort.SliceStable(
+		points,
+		func(i, j int) bool {
+			return points[i].CreateTime.Before(points[j].CreateTime)
+		})
+	resources := []PortfolioTrend{}
+	latest := map[uint]*AnalysisTrend{}
+	for i := range points {
+		p := &points[i]
+		latest[p.application] = p
+		date := p.CreateTime.UTC().Format(time.DateOnly)
+		if i+1 < len(points) {
+			next := points[i+1].CreateTime.UTC().Format(time.DateOnly)
+			if next == date {
+				continue
+			}
+		}
+		r := PortfolioTrend{Date: date}
+		for _, p := range latest {
+			r.add(p)
+		}
+		resources = append(resources, r)
+	}
+
+	h.Respond(ctx, http.StatusOK, resources)
+}
+
+// trend returns the trend points of the (selected) analyses.
+func (h *AnalysisHandler) trend(ctx *gin.Context, db *gorm.DB) (points []AnalysisTrend, err error) {
+	points = []AnalysisTrend{}
+	var list []model.Analysis
+	db = db.Select(
+		"ID",
+		"ApplicationID",
+		"CreateTime",
+		"Effort",
+		"Archived",
+		"Summary")
+	db = db.Order("ID")
+	err = db.Find(&list).Error
+	if err != nil {
+		return
+	}
+	unarchived := []uint{}
+	for i := range list {
+		m := &list[i]
+		if !m.Archived {
+			unarchived = append(unarchived, m.ID)
+		}
+	}
+	var issues []struct {
+		AnalysisID uint
+		Category   string
+		Issues     int
+		Incidents  int
+	}
+	iq := h.DB(ctx)
+	iq = iq.Select(
+		"i.AnalysisID",
+		"i.Category",
+		"COUNT(distinct i.ID) Issues",
+		"COUNT(n.ID) Incidents")
+	iq = iq.Table("Issue i")
+	iq = iq.Joins("LEFT JOIN Incident n ON n.IssueID = i.ID")
+	iq = iq.Where("i.AnalysisID IN ?", unarchived)
+	iq = iq.Group("i.AnalysisID, i.Category")
+	err = iq.Scan(&issues).Error
+	if err != nil {
+		return
+	}
+	var deps []struct {
+		AnalysisID uint
+		Count      int
+	}
+	dq := h.DB(ctx)
+	dq = dq.Model(&model.TechDependency{})
+	dq = dq.Select("AnalysisID", "COUNT(*) Count")
+	dq = dq.Where("AnalysisID IN ?", unarchived)
+	dq = dq.Group("AnalysisID")
+	err = dq.Scan(&deps).Error
+	if err != nil {
+		return
+	}
+	for i := range list {
+		m := &list[i]
+		points = append(points, AnalysisTrend{})
+		p := &points[len(points)-1]
+		p.With(m)
+		if m.Archived {
+			summary := []ArchivedIssue{}
+			_ = json.Unmarshal(m.Summary, &summary)
+			for _, s := range summary {
+				p.count(s.Category, 1, s.Incidents)
+			}
+		} else {
+			p.Dependencies = new(int)
+		}
+	}
+	found := map[uint]*AnalysisTrend{}
+	for i := range points {
+		found[points[i].ID] = &points[i]
+	}
+	for _, n := range issues {
+		found[n.AnalysisID].count(n.Category, n.Issues, n.Incidents)
+	}
+	for _, n := range deps {
+		*found[n.AnalysisID].Dependencies = n.Count
+	}
+	return
+}
+
+// AnalysisTrend (analysis) trend point.
+// Categories are the incident counts by issue category.
+type AnalysisTrend struct {
+	ID           uint           `json:"id"`
+	CreateTime   time.Time      `json:"createTime"`
+	Archived     bool           `json:"archived,omitempty" yaml:",omitempty"`
+	Effort       int            `json:"effort"`
+	Issues       int            `json:"issues"`
+	Incidents    int            `json:"incidents"`
+	Categories   map[string]int `json:"categories"`
+	Dependencies *int           `json:"dependencies,omitempty" yaml:",omitempty"`
+	application  uint
+}
+
+// With updates the resource with the model.
+func (r *AnalysisTrend) With(m *model.Analysis) {
+	r.ID = m.ID
+	r.CreateTime = m.CreateTime
+	r.Archived = m.Archived
+	r.Effort = m.Effort
+	r.Categories = map[string]int{}
+	r.application = m.ApplicationID
+}
+
+// count adds issues and incidents.
+func (r *AnalysisTrend) count(category string, issues, incidents int) {
+	r.Issues += issues
+	r.Incidents += incidents
+	r.Categories[category] += incidents
+}
+
+// PortfolioTrend (portfolio) trend point.
+type PortfolioTrend struct {
+	Date         string         `json:"date"`
+	Applications int            `json:"applications"`
+	Effort       int            `json:"effort"`
+	Issues       int            `json:"issues"`
+	Incidents    int            `json:"incidents"`
+	Categories   map[string]int `json:"categories"`
+	Dependencies int            `json:"dependencies"`
+}
+
+// add the (latest) analysis of an application.
+func (r *PortfolioTrend) add(p *AnalysisTrend) {
+	if r.Categories == nil {
+		r.Categories = map[string]int{}
+	}
+	r.Applications++
+	r.Effort += p.Effort
+	r.Issues += p.Issues
+	r.Incidents += p.Incidents
+	for category, n := range p.Categories {
+		r.Categories[category] += n
+	}
+	if p.Dependencies != nil {
+		r.Dependencies += *p.Dependencies
+	}
+}
@@ -1,13 +1,52 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/database"
+	v13 "github.com/konveyor/tackle2-hub/migration/v13"
+	"github.com/konveyor/tackle2-hub/model"
 	"github.com/onsi/gomega"
+	"gorm.io/gorm"
 )
 
+// newDB returns a (temporary) migrated DB.
+func newDB(t *testing.T) (db *gorm.DB) {
+	g := gomega.NewGomegaWithT(t)
+	Settings.DB.Path = path.Join(t.TempDir(), "hub.db")
+	Settings.Hub.Bucket.Path = t.TempDir()
+	db, err := database.Open(true)
+	g.Expect(err).To(gomega.BeNil())
+	err = v13.Migration{}.Apply(db)
+	g.Expect(err).To(gomega.BeNil())
+	t.Cleanup(func() {
+		_ = database.Close(db)
+	})
+	return
+}
+
+// newEngine returns an engine using the DB with the
+// (render and error) middleware installed.
+func newEngine(db *gorm.DB) (e *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	e = gin.New()
+	e.Use(func(ctx *gin.Context) {
+		rtx := WithContext(ctx)
+		rtx.DB = db
+		ctx.Next()
+	})
+	e.Use(Render(), ErrorHandler())
+	return
+}
+
 func TestAccepted(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 	h := BaseHandler{}
@@ -73,3 +112,165 @@ func TestFactKey(t *testing.T) {
 	g.Expect(key.Source()).To(gomega.Equal("test"))
 	g.Expect(key.Name()).To(gomega.Equal(""))
 }
+
+func TestHead(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	category := &model.TagCategory{Name: "c"}
+	g.Expect(db.Create(category).Error).To(gomega.BeNil())
+	tag := &model.Tag{Name: "t", CategoryID: category.ID}
+	g.Expect(db.Create(tag).Error).To(gomega.BeNil())
+
+	h := TagHandler{}
+	e := newEngine(db)
+	e.GET(TagRoot, h.Get)
+	e.HEAD(TagRoot, h.Head)
+	send := func(method, path, etag string) (w *httptest.ResponseRecorder) {
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		if etag != "" {
+			req.Header.Set(IfNoneMatch, etag)
+		}
+		e.ServeHTTP(w, req)
+		return
+	}
+	path := "/tags/" + strconv.Itoa(int(tag.ID))
+
+	// Get.
+	w := send(http.MethodGet, path, "")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	etag := w.Header().Get(ETag)
+	g.Expect(etag).ToNot(gomega.BeEmpty())
+	g.Expect(w.Header().Get(LastModified)).ToNot(gomega.BeEmpty())
+
+	// Head.
+	w = send(http.MethodHead, path, "")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(w.Body.Len()).To(gomega.Equal(0))
+	g.Expect(w.Header().Get(ETag)).To(gomega.Equal(etag))
+
+	// Not modified.
+	w = send(http.MethodHead, path, etag)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNotModified))
+
+	// Modified.
+	time.Sleep(time.Millisecond)
+	g.Expect(db.Model(tag).Update("Name", "u").Error).To(gomega.BeNil())
+	w = send(http.MethodHead, path, etag)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(w.Header().Get(ETag)).ToNot(gomega.Equal(etag))
+
+	// Not found.
+	w = send(http.MethodHead, "/tags/99", "")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNotFound))
+}
+
+func TestJSONPath(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	cases := []struct {
+		namespace string
+		path      string
+	}{
+		{namespace: "Tracker.Name", path: "name"},
+		{namespace: "Tracker.Identity.ID", path: "identity.id"},
+		{namespace: "Tracker.URL", path: "url"},
+		{namespace: "Tracker.TunnelIdentity.Name", path: "tunnelIdentity.name"},
+		{namespace: "TrackerTagging.Add[1].ID", path: "add[1].id"},
+		{namespace: "Tracker", path: ""},
+	}
+	h := BaseHandler{}
+	for _, c := range cases {
+		g.Expect(h.jsonPath(c.namespace)).To(gomega.Equal(c.path), c.namespace)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	category := &model.TagCategory{Name: "c"}
+	g.Expect(db.Create(category).Error).To(gomega.BeNil())
+	for _, name := range []string{"a", "b"} {
+		g.Expect(db.Create(&model.Tag{Name: name, CategoryID: category.ID}).Error).To(gomega.BeNil())
+	}
+	e := newEngine(db)
+	var r TrackerTagging
+	var err error
+	e.POST("/resolve", func(ctx *gin.Context) {
+		h := BaseHandler{}
+		r = TrackerTagging{}
+		err = h.BindJSON(ctx, &r)
+	})
+	send := func(body string) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/resolve", strings.NewReader(body))
+		e.ServeHTTP(w, req)
+	}
+	// Slice resolved by name.
+	send(`{"add":[{"id":1},{"name":"b"}],"remove":[{"name":"a"}]}`)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(r.Add).To(gomega.Equal([]Ref{{ID: 1}, {ID: 2, Name: "b"}}))
+	g.Expect(r.Remove).To(gomega.Equal([]Ref{{ID: 1, Name: "a"}}))
+	// Not found.
+	send(`{"add":[{"id":1},{"name":"x"}]}`)
+	fErr := &FieldError{}
+	g.Expect(errors.As(err, &fErr)).To(gomega.BeTrue())
+	g.Expect(fErr.Field).To(gomega.Equal("add.1.name"))
+}
+
+func TestTimeout(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	maxTimeout := Settings.Hub.DB.MaxTimeout
+	defer func() {
+		Settings.Hub.DB.MaxTimeout = maxTimeout
+	}()
+	Settings.Hub.DB.MaxTimeout = 300
+	e := newEngine(db)
+	e.Use(Timeout)
+	var deadline time.Duration
+	e.GET("/slow", func(ctx *gin.Context) {
+		h := BaseHandler{}
+		db := h.DB(ctx)
+		if t, found := db.Statement.Context.Deadline(); found {
+			deadline = time.Until(t)
+		}
+		var n int
+		limit, _ := strconv.Atoi(ctx.Query("n"))
+		err := db.Raw(
+			"WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x+1 FROM c WHERE x < ?)"+
+				" SELECT count(*) FROM c",
+			limit).Scan(&n).Error
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		h.Status(ctx, http.StatusOK)
+	})
+	get := func(timeout, n string) (w *httptest.ResponseRecorder) {
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/slow?n="+n, nil)
+		if timeout != "" {
+			req.Header.Set(QueryTimeout, timeout)
+		}
+		e.ServeHTTP(w, req)
+		return
+	}
+
+	// Not specified.
+	w := get("", "10")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+
+	// Exceeded.
+	w = get("10ms", "100000000")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusGatewayTimeout))
+	g.Expect(w.Body.String()).To(gomega.ContainSubstring(QueryTimeout))
+
+	// Bounded.
+	w = get("3600", "10")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(deadline > 299*time.Second && deadline <= 300*time.Second).To(gomega.BeTrue())
+
+	// Invalid.
+	w = get("soon", "10")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	pathlib "path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/konveyor/tackle2-hub/nas"
+)
+
+// Binary (upload) stored in the application bucket.
+const (
+	// BinaryDir the bucket directory.
+	BinaryDir = ".binary"
+	// BinaryBucket the binary coordinates prefix
+	// indicating the binary is stored in the bucket.
+	BinaryBucket = "bucket://"
+)
+
+// BinaryUpload godoc
+// @summary Upload the application binary.
+// @description Upload the application binary (WAR|EAR|JAR) using the `file` form field.
+// @description The binary is stored in the application bucket (replacing any
+// @description previously uploaded) and the application binary coordinates
+// @description are set to: bucket://.binary/<name>.
+// @description The upload size is limited by the hub settings (MB).
+// @tags applications
+// @accept multipart/form-data
+// @produce json
+// @success 201 {object} api.AppBinary
+// @router /applications/{id}/binary [post]
+// @param id path int true "Application ID"
+func (h ApplicationHandler) BinaryUpload(ctx *gin.Context) {
+	m := &model.Application{}
+	id := h.pk(ctx)
+	err := h.DB(ctx).Preload("Bucket").First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if !m.HasBucket() || m.Bucket == nil {
+		h.Status(ctx, http.StatusNotFound)
+		return
+	}
+	limit := int64(Settings.Hub.Application.BinaryLimit) << 20
+	if limit > 0 {
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, limit)
+	}
+	input, err := ctx.FormFile(FileField)
+	if err != nil {
+		mErr := &http.MaxBytesError{}
+		if errors.As(err, &mErr) {
+			h.Status(ctx, http.StatusRequestEntityTooLarge)
+			return
+		}
+		_ = ctx.Error(&BadRequestError{err.Error()})
+		return
+	}
+	name := pathlib.Base(strings.ReplaceAll(input.Filename, "\\", "/"))
+	switch strings.ToLower(pathlib.Ext(name)) {
+	case ".war", ".ear", ".jar":
+	default:
+		_ = ctx.Error(&BadRequestError{"file: '" + name + "' must be (war|ear|jar)."})
+		return
+	}
+	reader, err := input.Open()
+	if err != nil {
+		_ = ctx.Error(&BadRequestError{err.Error()})
+		return
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	dir := pathlib.Join(m.Bucket.Path, BinaryDir)
+	err = nas.RmDir(dir)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = os.MkdirAll(dir, 0777)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	path := pathlib.Join(dir, name)
+	writer, err := os.Create(path)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	defer func() {
+		_ = writer.Close()
+	}()
+	digest := sha256.New()
+	size, err := io.Copy(io.MultiWriter(writer, digest), reader)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = os.Chmod(path, 0666)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	r := AppBinary{
+		Name:     name,
+		Path:     pathlib.Join(BinaryDir, name),
+		Size:     size,
+		Checksum: "sha256:" + hex.EncodeToString(digest.Sum(nil)),
+	}
+	r.Binary = BinaryBucket + r.Path
+	db := h.DB(ctx).Model(m)
+	err = db.UpdateColumn("Binary", r.Binary).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	h.Respond(ctx, http.StatusCreated, r)
+}
+
+// AppBinary REST resource.
+type AppBinary struct {
+	// Name the (file) name.
+	Name string `json:"name"`
+	// Path the bucket relative path.
+	Path string `json:"path"`
+	// Binary the application binary coordinates.
+	Binary   string `json:"binary"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
@@ -0,0 +1,219 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/assessment"
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Params
+const (
+	IdsParam = "ids"
+)
+
+// Compare godoc
+// @summary Compare applications.
+// @description Compare (side-by-side) the tags, technologies, assessment risk,
+// @description review and latest analysis of the applications.
+// @description Technologies are the tags discovered by addons (source not empty).
+// @description The applications are specified using: ?ids=1,2,3.
+// @tags applications
+// @produce json
+// @success 200 {object} api.AppComparison
+// @router /applications/compare [get]
+// @param ids query string true "Application IDs"
+func (h ApplicationHandler) Compare(ctx *gin.Context) {
+	ids, err := h.compareIDs(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	questionnaire, err := assessment.NewQuestionnaireResolver(h.DB(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	membership := assessment.NewMembershipResolver(h.DB(ctx))
+	tagsResolver, err := assessment.NewTagResolver(h.DB(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	resource := AppComparison{}
+	for _, id := range ids {
+		m := &model.Application{}
+		db := h.preLoad(h.DB(ctx), clause.Associations)
+		err = db.First(m, id).Error
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		tags := []model.ApplicationTag{}
+		db = h.preLoad(h.DB(ctx), clause.Associations)
+		err = db.Find(&tags, "ApplicationID = ?", id).Error
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		resolver := assessment.NewApplicationResolver(m, tagsResolver, membership, questionnaire)
+		r := Application{}
+		r.With(m, tags)
+		err = r.WithResolver(resolver)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		compared := AppCompared{}
+		compared.With(&r, m.Review)
+		analysis := &model.Analysis{}
+		db = h.DB(ctx).Select("ID", "Effort", "CreateTime")
+		db = db.Where("ApplicationID", id)
+		err = db.Order("ID DESC").First(analysis).Error
+		if err == nil {
+			compared.Analysis = &AnalysisCompared{
+				ID:      analysis.ID,
+				Effort:  analysis.Effort,
+				Created: analysis.CreateTime,
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = ctx.Error(err)
+			return
+		}
+		resource.Applications = append(resource.Applications, compared)
+	}
+	resource.tagMatrix()
+
+	h.Respond(ctx, http.StatusOK, resource)
+}
+
+// compareIDs returns the (unique) application IDs specified
+// by the ids parameter (comma separated). At least 2 are required.
+func (h ApplicationHandler) compareIDs(ctx *gin.Context) (ids []uint, err error) {
+	seen := map[uint]bool{}
+	for _, param := range ctx.QueryArray(IdsParam) {
+		for _, s := range strings.Split(param, ",") {
+			s = strings.TrimSpace(s)
+			if s == "" {
+				continue
+			}
+			n, pErr := strconv.ParseUint(s, 10, 0)
+			if pErr != nil {
+				err = &BadRequestError{"ids: '" + s + "' must be an integer."}
+				return
+			}
+			id := uint(n)
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) < 2 {
+		err = &BadRequestError{"ids: at least 2 required."}
+	}
+	return
+}
+
+// AppComparison REST resource.
+type AppComparison struct {
+	Applications []AppCompared `json:"applications"`
+	// Tags matrix.
+	Tags []TagCompared `json:"tags"`
+}
+
+// tagMatrix builds the tag matrix sorted by name.
+func (r *AppComparison) tagMatrix() {
+	r.Tags = []TagCompared{}
+	index := map[uint]int{}
+	for col, app := range r.Applications {
+		for _, tags := range [][]TagRef{app.Tags, app.Technologies} {
+			for _, ref := range tags {
+				row, found := index[ref.ID]
+				if !found {
+					row = len(r.Tags)
+					index[ref.ID] = row
+					r.Tags = append(
+						r.Tags,
+						TagCompared{
+							Tag:          Ref{ID: ref.ID, Name: ref.Name},
+							Applications: make([]bool, len(r.Applications)),
+						})
+				}
+				r.Tags[row].Applications[col] = true
+			}
+		}
+	}
+	sort.SliceStable(
+		r.Tags,
+		func(i, j int) bool {
+			return r.Tags[i].Tag.Name < r.Tags[j].Tag.Name
+		})
+}
+
+// AppCompared the compared application (column).
+type AppCompared struct {
+	Application  Ref               `json:"application"`
+	Tags         []TagRef          `json:"tags"`
+	Technologies []TagRef          `json:"technologies"`
+	Assessed     bool              `json:"assessed"`
+	Risk         string            `json:"risk"`
+	Confidence   int               `json:"confidence"`
+	Review       *ReviewCompared   `json:"review,omitempty" yaml:",omitempty"`
+	Analysis     *AnalysisCompared `json:"analysis,omitempty" yaml:",omitempty"`
+}
+
+// With updates the resource.
+func (r *AppCompared) With(app *Application, review *model.Review) {
+	r.Application = Ref{ID: app.ID, Name: app.Name}
+	r.Tags = []TagRef{}
+	r.Technologies = []TagRef{}
+	for _, ref := range app.Tags {
+		if ref.Source != "" && !ref.Virtual {
+			r.Technologies = append(r.Technologies, ref)
+		} else {
+			r.Tags = append(r.Tags, ref)
+		}
+	}
+	r.Assessed = app.Assessed
+	r.Risk = app.Risk
+	r.Confidence = app.Confidence
+	if review != nil {
+		r.Review = &ReviewCompared{
+			ProposedAction:      review.ProposedAction,
+			EffortEstimate:      review.EffortEstimate,
+			BusinessCriticality: review.BusinessCriticality,
+			WorkPriority:        review.WorkPriority,
+		}
+	}
+}
+
+// ReviewCompared the compared review.
+type ReviewCompared struct {
+	ProposedAction      string `json:"proposedAction"`
+	EffortEstimate      string `json:"effortEstimate"`
+	BusinessCriticality uint   `json:"businessCriticality"`
+	WorkPriority        uint   `json:"workPriority"`
+}
+
+// AnalysisCompared the compared (latest) analysis.
+type AnalysisCompared struct {
+	ID      uint      `json:"id"`
+	Effort  int       `json:"effort"`
+	Created time.Time `json:"createTime"`
+}
+
+// TagCompared the tag (row) indicating which
+// applications (columns) have the tag.
+type TagCompared struct {
+	Tag          Ref    `json:"tag"`
+	Applications []bool `json:"applications"`
+}
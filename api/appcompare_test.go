@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestApplicationCompare(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	category := &model.TagCategory{Name: "c"}
+	g.Expect(db.Create(category).Error).To(gomega.BeNil())
+	tags := []model.Tag{
+		{Name: "Java", CategoryID: category.ID},
+		{Name: "Critical", CategoryID: category.ID},
+	}
+	g.Expect(db.Create(&tags).Error).To(gomega.BeNil())
+	for _, name := range []string{"a", "b"} {
+		g.Expect(db.Create(&model.Application{Name: name}).Error).To(gomega.BeNil())
+	}
+	for _, m := range []model.ApplicationTag{
+		{ApplicationID: 1, TagID: 1, Source: "language-discovery"},
+		{ApplicationID: 2, TagID: 1, Source: "language-discovery"},
+		{ApplicationID: 2, TagID: 2},
+	} {
+		g.Expect(db.Create(&m).Error).To(gomega.BeNil())
+	}
+	id := uint(1)
+	g.Expect(db.Create(&model.Review{ApplicationID: &id, ProposedAction: "rehost", EffortEstimate: "small"}).Error).To(gomega.BeNil())
+	for _, effort := range []int{10, 20} {
+		g.Expect(db.Create(&model.Analysis{ApplicationID: 2, Effort: effort}).Error).To(gomega.BeNil())
+	}
+
+	h := ApplicationHandler{}
+	e := newEngine(db)
+	e.GET(AppCompareRoot, h.Compare)
+	get := func(query string) (w *httptest.ResponseRecorder, r AppComparison) {
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, AppCompareRoot+"?"+query, nil)
+		e.ServeHTTP(w, req)
+		_ = json.Unmarshal(w.Body.Bytes(), &r)
+		return
+	}
+
+	w, r := get("ids=1,2")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(r.Applications).To(gomega.HaveLen(2))
+	a, b := r.Applications[0], r.Applications[1]
+	g.Expect(a.Application).To(gomega.Equal(Ref{ID: 1, Name: "a"}))
+	g.Expect(a.Tags).To(gomega.BeEmpty())
+	g.Expect(a.Technologies).To(gomega.HaveLen(1))
+	g.Expect(a.Review.ProposedAction).To(gomega.Equal("rehost"))
+	g.Expect(a.Analysis).To(gomega.BeNil())
+	g.Expect(b.Tags).To(gomega.HaveLen(1))
+	g.Expect(b.Review).To(gomega.BeNil())
+	g.Expect(b.Analysis.Effort).To(gomega.Equal(20))
+	g.Expect(r.Tags).To(gomega.Equal([]TagCompared{
+		{Tag: Ref{ID: 2, Name: "Critical"}, Applications: []bool{false, true}},
+		{Tag: Ref{ID: 1, Name: "Java"}, Applications: []bool{true, true}},
+	}))
+
+	w, _ = get("ids=1")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	w, _ = get("ids=1,x")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	w, _ = get("ids=1&ids=9")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNotFound))
+}
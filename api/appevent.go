@@ -0,0 +1,284 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/gorm"
+)
+
+// Application event kinds.
+const (
+	AppEventCreated       = "Created"
+	AppEventTagAdded      = "TagAdded"
+	AppEventTagRemoved    = "TagRemoved"
+	AppEventWaveAssigned  = "WaveAssigned"
+	AppEventWaveRemoved   = "WaveRemoved"
+	AppEventArchived      = "Archived"
+	AppEventRestored      = "Restored"
+	AppEventAssessed      = "Assessed"
+	AppEventReviewed      = "Reviewed"
+	AppEventAnalyzed      = "Analyzed"
+	AppEventTicketCreated = "TicketCreated"
+)
+
+// EventList godoc
+// @summary List application events.
+// @description List the application activity (timeline) sorted by time.
+// @description Includes: tag and wave membership changes, archive/restore
+// @description and the assessments, reviews, analyses and tickets created.
+// @tags applications
+// @produce json
+// @success 200 {object} []api.AppEvent
+// @router /applications/{id}/events [get]
+// @param id path int true "Application ID"
+func (h ApplicationHandler) EventList(ctx *gin.Context) {
+	id := h.pk(ctx)
+	m := &model.Application{}
+	err := h.DB(ctx).First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	events := []AppEvent{
+		{
+			Kind:  AppEventCreated,
+			Actor: m.CreateUser,
+			Time:  m.CreateTime,
+		},
+	}
+	var recorded []model.ApplicationEvent
+	err = h.DB(ctx).Find(&recorded, "ApplicationID", id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	for i := range recorded {
+		r := AppEvent{}
+		r.With(&recorded[i])
+		events = append(events, r)
+	}
+	var assessments []model.Assessment
+	db := h.DB(ctx).Select("ID", "CreateUser", "CreateTime", "QuestionnaireID")
+	db = db.Preload("Questionnaire", func(db *gorm.DB) *gorm.DB {
+		return db.Select("ID", "Name")
+	})
+	err = db.Find(&assessments, "ApplicationID", id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	for _, a := range assessments {
+		events = append(
+			events,
+			AppEvent{
+				Kind:        AppEventAssessed,
+				Description: "Questionnaire: " + a.Questionnaire.Name,
+				Actor:       a.CreateUser,
+				Time:        a.CreateTime,
+				Ref:         &Ref{ID: a.ID},
+			})
+	}
+	var reviews []model.Review
+	db = h.DB(ctx).Select("ID", "CreateUser", "CreateTime", "ProposedAction")
+	err = db.Find(&reviews, "ApplicationID", id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	for _, r := range reviews {
+		events = append(
+			events,
+			AppEvent{
+				Kind:        AppEventReviewed,
+				Description: "Proposed action: " + r.ProposedAction,
+				Actor:       r.CreateUser,
+				Time:        r.CreateTime,
+				Ref:         &Ref{ID: r.ID},
+			})
+	}
+	var analyses []model.Analysis
+	db = h.DB(ctx).Select("ID", "CreateUser", "CreateTime", "Effort")
+	err = db.Find(&analyses, "ApplicationID", id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	for _, a := range analyses {
+		events = append(
+			events,
+			AppEvent{
+				Kind:        AppEventAnalyzed,
+				Description: fmt.Sprintf("Effort: %d", a.Effort),
+				Actor:       a.CreateUser,
+				Time:        a.CreateTime,
+				Ref:         &Ref{ID: a.ID},
+			})
+	}
+	var tickets []model.Ticket
+	db = h.DB(ctx).Select("ID", "CreateUser", "CreateTime", "Kind", "Parent")
+	err = db.Find(&tickets, "ApplicationID", id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	for _, t := range tickets {
+		events = append(
+			events,
+			AppEvent{
+				Kind:        AppEventTicketCreated,
+				Description: "Kind: " + t.Kind + " Parent: " + t.Parent,
+				Actor:       t.CreateUser,
+				Time:        t.CreateTime,
+				Ref:         &Ref{ID: t.ID},
+			})
+	}
+	sort.SliceStable(
+		events,
+		func(i, j int) bool {
+			return events[i].Time.Before(events[j].Time)
+		})
+
+	h.Respond(ctx, http.StatusOK, events)
+}
+
+// appEvent records an application event.
+func (h *BaseHandler) appEvent(ctx *gin.Context, id uint, kind, description string) (err error) {
+	m := &model.ApplicationEvent{
+		ApplicationID: id,
+		Kind:          kind,
+		Description:   description,
+	}
+	m.CreateUser = h.CurrentUser(ctx)
+	err = h.DB(ctx).Create(m).Error
+	return
+}
+
+// tagChanged records the tag events for the difference
+// between the (before and after) tag associations.
+func (h *BaseHandler) tagChanged(ctx *gin.Context, before, after []model.ApplicationTag) (err error) {
+	key := func(m *model.ApplicationTag) string {
+		return fmt.Sprintf("%d/%d/%s", m.ApplicationID, m.TagID, m.Source)
+	}
+	matched := map[string]bool{}
+	for i := range before {
+		matched[key(&before[i])] = false
+	}
+	var added []model.ApplicationTag
+	for i := range after {
+		m := &after[i]
+		k := key(m)
+		if _, found := matched[k]; found {
+			matched[k] = true
+			continue
+		}
+		added = append(added, *m)
+	}
+	var removed []model.ApplicationTag
+	for i := range before {
+		m := &before[i]
+		if !matched[key(m)] {
+			removed = append(removed, *m)
+		}
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+	ids := []uint{}
+	for _, m := range append(added, removed...) {
+		ids = append(ids, m.TagID)
+	}
+	var tags []model.Tag
+	err = h.DB(ctx).Preload("Category").Find(&tags, ids).Error
+	if err != nil {
+		return
+	}
+	names := map[uint]string{}
+	for _, m := range tags {
+		names[m.ID] = m.Category.Name + "/" + m.Name
+	}
+	describe := func(m *model.ApplicationTag) (d string) {
+		d = "Tag: " + names[m.TagID]
+		if m.Source != "" {
+			d += " (source: " + m.Source + ")"
+		}
+		return
+	}
+	for i := range added {
+		m := &added[i]
+		err = h.appEvent(ctx, m.ApplicationID, AppEventTagAdded, describe(m))
+		if err != nil {
+			return
+		}
+	}
+	for i := range removed {
+		m := &removed[i]
+		err = h.appEvent(ctx, m.ApplicationID, AppEventTagRemoved, describe(m))
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// waveChanged records the wave events for a change
+// of (application) migration wave.
+func (h *BaseHandler) waveChanged(ctx *gin.Context, id uint, before, after *uint) (err error) {
+	if before != nil && after != nil && *before == *after {
+		return
+	}
+	describe := func(waveID uint) (d string, err error) {
+		m := &model.MigrationWave{}
+		err = h.DB(ctx).Select("ID", "Name").First(m, waveID).Error
+		if err != nil {
+			return
+		}
+		d = fmt.Sprintf("Wave: (%d) %s", m.ID, m.Name)
+		return
+	}
+	if before != nil {
+		d, nErr := describe(*before)
+		if nErr != nil {
+			err = nErr
+			return
+		}
+		err = h.appEvent(ctx, id, AppEventWaveRemoved, d)
+		if err != nil {
+			return
+		}
+	}
+	if after != nil {
+		d, nErr := describe(*after)
+		if nErr != nil {
+			err = nErr
+			return
+		}
+		err = h.appEvent(ctx, id, AppEventWaveAssigned, d)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// AppEvent REST resource.
+type AppEvent struct {
+	Kind        string    `json:"kind"`
+	Description string    `json:"description,omitempty"`
+	Actor       string    `json:"actor,omitempty"`
+	Time        time.Time `json:"time"`
+	// Ref to the (related) resource.
+	Ref *Ref `json:"ref,omitempty"`
+}
+
+// With updates the resource with the model.
+func (r *AppEvent) With(m *model.ApplicationEvent) {
+	r.Kind = m.Kind
+	r.Description = m.Description
+	r.Actor = m.CreateUser
+	r.Time = m.CreateTime
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/gorm"
+)
+
+// Application identity kinds.
+// The kind determines how the identity is used by analysis tasks:
+// source - repository credentials.
+// maven - maven settings.
+const (
+	IdentitySource = "source"
+	IdentityMaven  = "maven"
+)
+
+// IdentityList godoc
+// @summary List the application identities.
+// @description List the application identities and whether each is resolvable
+// @description (used) by analysis tasks. An identity is not resolvable when:
+// @description - the kind is not (source|maven).
+// @description - another identity of the same kind is listed first.
+// @description - source: the application repository is not defined or the
+// @description   identity has neither user/password nor key.
+// @description - maven: the identity settings are empty.
+// @tags applications
+// @produce json
+// @success 200 {object} []api.AppIdentity
+// @router /applications/{id}/identities [get]
+// @param id path int true "Application ID"
+func (h ApplicationHandler) IdentityList(ctx *gin.Context) {
+	m := &model.Application{}
+	id := h.pk(ctx)
+	db := h.DB(ctx).Preload("Identities", func(db *gorm.DB) *gorm.DB {
+		return db.Order("ID")
+	})
+	err := db.First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	repository := Repository{}
+	_ = json.Unmarshal(m.Repository, &repository)
+	resources := []AppIdentity{}
+	resolved := map[string]bool{}
+	for i := range m.Identities {
+		identity := &m.Identities[i]
+		r := AppIdentity{}
+		r.With(identity)
+		switch {
+		case identity.Kind != IdentitySource && identity.Kind != IdentityMaven:
+			r.Reason = "kind must be (source|maven)."
+		case resolved[identity.Kind]:
+			r.Reason = "another identity (kind=" + identity.Kind + ") is resolved."
+		case identity.Kind == IdentitySource && strings.TrimSpace(repository.URL) == "":
+			r.Reason = "application repository not defined."
+		case identity.Kind == IdentitySource && identity.User == "" && identity.Key == "":
+			r.Reason = "user or key required."
+		case identity.Kind == IdentityMaven && identity.Settings == "":
+			r.Reason = "settings required."
+		default:
+			r.Resolvable = true
+			resolved[identity.Kind] = true
+		}
+		resources = append(resources, r)
+	}
+
+	h.Respond(ctx, http.StatusOK, resources)
+}
+
+// identities validates the application identities.
+// The identities must exist and the kinds must be (source|maven)
+// with at most one of each kind.
+func (h *BaseHandler) identities(ctx *gin.Context, refs []Ref) (err error) {
+	if len(refs) == 0 {
+		return
+	}
+	ids := []uint{}
+	for _, ref := range refs {
+		ids = append(ids, ref.ID)
+	}
+	var list []model.Identity
+	err = h.DB(ctx).Select("ID", "Name", "Kind").Find(&list, ids).Error
+	if err != nil {
+		return
+	}
+	found := map[uint]*model.Identity{}
+	for i := range list {
+		found[list[i].ID] = &list[i]
+	}
+	kinds := map[string]string{}
+	for _, ref := range refs {
+		m, exists := found[ref.ID]
+		if !exists {
+			err = &BadRequestError{"identity: (id=" + strconv.Itoa(int(ref.ID)) + ") not found."}
+			return
+		}
+		switch m.Kind {
+		case IdentitySource, IdentityMaven:
+		default:
+			err = &BadRequestError{
+				"identity: '" + m.Name + "' kind must be (source|maven).",
+			}
+			return
+		}
+		if name, exists := kinds[m.Kind]; exists && name != m.Name {
+			err = &BadRequestError{
+				"identity: '" + m.Name + "' conflicts with '" + name + "' (kind=" + m.Kind + ").",
+			}
+			return
+		}
+		kinds[m.Kind] = m.Name
+	}
+	return
+}
+
+// AppIdentity REST resource.
+type AppIdentity struct {
+	Identity   Ref    `json:"identity"`
+	Kind       string `json:"kind"`
+	Resolvable bool   `json:"resolvable"`
+	Reason     string `json:"reason,omitempty" yaml:",omitempty"`
+}
+
+// With updates the resource with the model.
+func (r *AppIdentity) With(m *model.Identity) {
+	r.Identity = Ref{ID: m.ID, Name: m.Name}
+	r.Kind = m.Kind
+}
@@ -2,20 +2,29 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/konveyor/tackle2-hub/assessment"
 	"github.com/konveyor/tackle2-hub/metrics"
 	"github.com/konveyor/tackle2-hub/model"
+	"github.com/konveyor/tackle2-hub/scm"
+	"github.com/konveyor/tackle2-hub/tracker"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 // Routes
 const (
 	ApplicationsRoot     = "/applications"
+	AppDuplicatesRoot    = ApplicationsRoot + "/duplicates"
+	AppCompareRoot       = ApplicationsRoot + "/compare"
+	AppReportRoot        = ApplicationsRoot + "/report"
 	ApplicationRoot      = ApplicationsRoot + "/:" + ID
 	ApplicationTagsRoot  = ApplicationRoot + "/tags"
 	ApplicationTagRoot   = ApplicationTagsRoot + "/:" + ID2
@@ -26,11 +35,43 @@ const (
 	AppStakeholdersRoot  = ApplicationRoot + "/stakeholders"
 	AppAssessmentsRoot   = ApplicationRoot + "/assessments"
 	AppAssessmentRoot    = AppAssessmentsRoot + "/:" + ID2
+	AppTrackerRoot       = ApplicationRoot + "/tracker"
+	AppTrackerHealthRoot = ApplicationRoot + "/tracker-health"
+	AppArchiveRoot       = ApplicationRoot + "/archive"
+	AppRestoreRoot       = ApplicationRoot + "/restore"
+	AppEventsRoot        = ApplicationRoot + "/events"
+	AppCopyRoot          = ApplicationRoot + "/copy"
+	AppRepositoryRoot    = ApplicationRoot + "/repository"
+	AppRepoValidateRoot  = AppRepositoryRoot + "/validate"
+	AppBinaryRoot        = ApplicationRoot + "/binary"
+	AppIdentitiesRoot    = ApplicationRoot + "/identities"
 )
 
 // Params
 const (
-	Source = "source"
+	Source         = "source"
+	QualifiedParam = "qualified"
+	ArchivedParam  = "archived"
+	UniqueParam    = "unique"
+	SearchParam    = "search"
+	OwnerParam     = "owner.id"
+	ContribParam   = "contributor.id"
+)
+
+// Application uniqueness policies.
+//
+// name: the application name is unique (default). Always
+// enforced by the unique index.
+//
+// repository: the (normalized) repository URL and path are unique.
+// Applications without a repository are not constrained.
+//
+// binary: the (non-empty) binary coordinates are unique.
+// Binaries uploaded to the application bucket are not constrained.
+const (
+	AppUniqueName       = "name"
+	AppUniqueRepository = "repository"
+	AppUniqueBinary     = "binary"
 )
 
 // Tag Sources
@@ -50,18 +91,28 @@ func (h ApplicationHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.Use(Required("applications"), Transaction)
 	routeGroup.GET(ApplicationsRoot, h.List)
 	routeGroup.GET(ApplicationsRoot+"/", h.List)
+	routeGroup.GET(AppDuplicatesRoot, h.Duplicates)
+	routeGroup.GET(AppCompareRoot, h.Compare)
+	routeGroup.GET(AppReportRoot, h.Report)
 	routeGroup.POST(ApplicationsRoot, h.Create)
 	routeGroup.GET(ApplicationRoot, h.Get)
+	routeGroup.HEAD(ApplicationRoot, h.Head)
 	routeGroup.PUT(ApplicationRoot, h.Update)
+	routeGroup.PATCH(ApplicationsRoot, h.Patch)
 	routeGroup.DELETE(ApplicationsRoot, h.DeleteList)
 	routeGroup.DELETE(ApplicationRoot, h.Delete)
+	routeGroup.PUT(AppArchiveRoot, h.Archive)
+	routeGroup.PUT(AppRestoreRoot, h.Restore)
+	routeGroup.GET(AppEventsRoot, h.EventList)
+	routeGroup.POST(AppCopyRoot, h.Copy)
+	routeGroup.GET(AppIdentitiesRoot, h.IdentityList)
 	// Tags
 	routeGroup = e.Group("/")
 	routeGroup.Use(Required("applications"))
 	routeGroup.GET(ApplicationTagsRoot, h.TagList)
 	routeGroup.GET(ApplicationTagsRoot+"/", h.TagList)
-	routeGroup.POST(ApplicationTagsRoot, h.TagAdd)
-	routeGroup.DELETE(ApplicationTagRoot, h.TagDelete)
+	routeGroup.POST(ApplicationTagsRoot, h.TagAdd, Transaction)
+	routeGroup.DELETE(ApplicationTagRoot, h.TagDelete, Transaction)
 	routeGroup.PUT(ApplicationTagsRoot, h.TagReplace, Transaction)
 	// Facts
 	routeGroup = e.Group("/")
@@ -70,7 +121,7 @@ func (h ApplicationHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(ApplicationFactsRoot+"/", h.FactGet)
 	routeGroup.POST(ApplicationFactsRoot, h.FactCreate)
 	routeGroup.GET(ApplicationFactRoot, h.FactGet)
-	routeGroup.PUT(ApplicationFactRoot, h.FactPut)
+	routeGroup.PUT(ApplicationFactRoot, h.FactPut, Transaction)
 	routeGroup.DELETE(ApplicationFactRoot, h.FactDelete)
 	routeGroup.PUT(ApplicationFactsRoot, h.FactPut, Transaction)
 	// Bucket
@@ -81,6 +132,10 @@ func (h ApplicationHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.POST(AppBucketContentRoot, h.BucketPut)
 	routeGroup.PUT(AppBucketContentRoot, h.BucketPut)
 	routeGroup.DELETE(AppBucketContentRoot, h.BucketDelete)
+	// Binary
+	routeGroup = e.Group("/")
+	routeGroup.Use(Required("applications.bucket"))
+	routeGroup.POST(AppBinaryRoot, h.BinaryUpload)
 	// Stakeholders
 	routeGroup = e.Group("/")
 	routeGroup.Use(Required("applications.stakeholders"))
@@ -90,6 +145,16 @@ func (h ApplicationHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.Use(Required("applications.assessments"))
 	routeGroup.GET(AppAssessmentsRoot, h.AssessmentList)
 	routeGroup.POST(AppAssessmentsRoot, h.AssessmentCreate)
+	// Repository
+	routeGroup = e.Group("/")
+	routeGroup.Use(Required("applications"))
+	routeGroup.POST(AppRepoValidateRoot, h.RepositoryValidate)
+	// Tracker
+	routeGroup = e.Group("/")
+	routeGroup.Use(Required("applications"))
+	routeGroup.GET(AppTrackerRoot, h.TrackerGet)
+	routeGroup.PUT(AppTrackerRoot, h.TrackerPut)
+	routeGroup.GET(AppTrackerHealthRoot, h.TrackerHealth)
 }
 
 // Get godoc
@@ -109,6 +174,7 @@ func (h ApplicationHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 
 	tags := []model.ApplicationTag{}
 	db = h.preLoad(h.DB(ctx), clause.Associations)
@@ -140,16 +206,63 @@ func (h ApplicationHandler) Get(ctx *gin.Context) {
 	h.Respond(ctx, http.StatusOK, r)
 }
 
+// Head godoc
+// @summary Check an application exists.
+// @description Check an application exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags applications
+// @success 200
+// @success 304
+// @router /applications/{id} [head]
+// @param id path int true "Application ID"
+func (h ApplicationHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.Application{})
+}
+
 // List godoc
 // @summary List all applications.
 // @description List all applications.
+// @description Archived applications are listed only when ?archived=true.
+// @description Free-text search using ?search=terms matches (each term) the name, description,
+// @description comments, tags and repository URL. Results are sorted by rank.
+// @description Filtered by owner using ?owner.id=1&owner.id=2.
+// @description Filtered by contributor using ?contributor.id=1&contributor.id=2.
 // @tags applications
 // @produce json
 // @success 200 {object} []api.Application
 // @router /applications [get]
+// @param archived query bool false "Include archived"
+// @param search query string false "Search terms"
+// @param owner.id query int false "Owner (stakeholder) ID"
+// @param contributor.id query int false "Contributor (stakeholder) ID"
 func (h ApplicationHandler) List(ctx *gin.Context) {
 	var list []model.Application
 	db := h.preLoad(h.DB(ctx), clause.Associations)
+	archived, _ := strconv.ParseBool(ctx.Query(ArchivedParam))
+	if !archived {
+		db = db.Where("Archived", false)
+	}
+	search := AppSearch{Terms: strings.Fields(ctx.Query(SearchParam))}
+	db = search.Where(h.DB(ctx), db)
+	owners, err := h.queryIDs(ctx, OwnerParam)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if owners != nil {
+		db = db.Where("OwnerID IN ?", owners)
+	}
+	contributors, err := h.queryIDs(ctx, ContribParam)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if contributors != nil {
+		iq := h.DB(ctx).Table("ApplicationContributors")
+		iq = iq.Select("ApplicationID")
+		iq = iq.Where("StakeholderID IN ?", contributors)
+		db = db.Where("ID IN (?)", iq)
+	}
 	result := db.Find(&list)
 	if result.Error != nil {
 		_ = ctx.Error(result.Error)
@@ -169,6 +282,7 @@ func (h ApplicationHandler) List(ctx *gin.Context) {
 	}
 
 	resources := []Application{}
+	rank := map[uint]int{}
 	for i := range list {
 		tags := []model.ApplicationTag{}
 		db = h.preLoad(h.DB(ctx), clause.Associations)
@@ -185,8 +299,16 @@ func (h ApplicationHandler) List(ctx *gin.Context) {
 			_ = ctx.Error(err)
 			return
 		}
+		rank[r.ID] = search.Rank(&list[i], tags)
 		resources = append(resources, r)
 	}
+	if len(search.Terms) > 0 {
+		sort.SliceStable(
+			resources,
+			func(i, j int) bool {
+				return rank[resources[i].ID] > rank[resources[j].ID]
+			})
+	}
 
 	h.Respond(ctx, http.StatusOK, resources)
 }
@@ -207,7 +329,22 @@ func (h ApplicationHandler) Create(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
+	err = h.extensions(ctx, r.Extensions)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = h.identities(ctx, r.Identities)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	m := r.Model()
+	err = h.unique(ctx, m)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	m.CreateUser = h.BaseHandler.CurrentUser(ctx)
 	result := h.DB(ctx).Omit("Tags").Create(m)
 	if result.Error != nil {
@@ -300,6 +437,134 @@ func (h ApplicationHandler) DeleteList(ctx *gin.Context) {
 	h.Status(ctx, http.StatusNoContent)
 }
 
+// Patch godoc
+// @summary Update (patch) the selected applications.
+// @description Update the applications selected by the filter.
+// @description The filter criteria (ids, tags, business service) are ANDed.
+// @description Tags (any) and contributors are added. Other fields are replaced.
+// @description Archived applications are not selected.
+// @description Returns the IDs of the updated applications.
+// @tags applications
+// @accept json
+// @produce json
+// @success 200 {object} api.ApplicationPatched
+// @router /applications [patch]
+// @param patch body api.ApplicationPatch true "Filter and patch"
+func (h ApplicationHandler) Patch(ctx *gin.Context) {
+	r := &ApplicationPatch{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = r.Validate()
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	db := h.DB(ctx).Model(&model.Application{})
+	db = db.Where("Archived", false)
+	filter := r.Filter
+	if len(filter.IDs) > 0 {
+		db = db.Where("ID IN ?", filter.IDs)
+	}
+	if len(filter.Tags) > 0 {
+		iq := h.DB(ctx).Model(&model.ApplicationTag{})
+		iq = iq.Select("ApplicationID")
+		iq = iq.Where("TagID IN ?", r.ids(filter.Tags))
+		db = db.Where("ID IN (?)", iq)
+	}
+	if filter.BusinessService != nil {
+		db = db.Where("BusinessServiceID", filter.BusinessService.ID)
+	}
+	var matched []model.Application
+	err = db.Select("ID", "MigrationWaveID").Order("ID").Find(&matched).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	patched := ApplicationPatched{Updated: []uint{}}
+	for _, m := range matched {
+		patched.Updated = append(patched.Updated, m.ID)
+	}
+	if len(patched.Updated) == 0 {
+		h.Respond(ctx, http.StatusOK, patched)
+		return
+	}
+	patch := r.Patch
+	fields := map[string]interface{}{
+		"UpdateUser": h.CurrentUser(ctx),
+	}
+	if patch.Owner != nil {
+		fields["OwnerID"] = patch.Owner.ID
+	}
+	if patch.BusinessService != nil {
+		fields["BusinessServiceID"] = patch.BusinessService.ID
+	}
+	if patch.MigrationWave != nil {
+		fields["MigrationWaveID"] = patch.MigrationWave.ID
+	}
+	db = h.DB(ctx).Model(&model.Application{})
+	db = db.Where("ID IN ?", patched.Updated)
+	err = db.Updates(fields).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	for _, app := range matched {
+		id := app.ID
+		if patch.MigrationWave != nil {
+			err = h.waveChanged(ctx, id, app.MigrationWaveID, &patch.MigrationWave.ID)
+			if err != nil {
+				_ = ctx.Error(err)
+				return
+			}
+		}
+		added := []model.ApplicationTag{}
+		for _, ref := range patch.Tags {
+			tag := &model.ApplicationTag{
+				ApplicationID: id,
+				TagID:         ref.ID,
+				Source:        ref.Source,
+			}
+			db = h.DB(ctx).Clauses(clause.OnConflict{DoNothing: true})
+			result := db.Create(tag)
+			if result.Error != nil {
+				_ = ctx.Error(result.Error)
+				return
+			}
+			if result.RowsAffected > 0 {
+				added = append(added, *tag)
+			}
+		}
+		err = h.tagChanged(ctx, nil, added)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		if len(patch.Contributors) > 0 {
+			contributors := []model.Stakeholder{}
+			for _, ref := range patch.Contributors {
+				contributors = append(
+					contributors,
+					model.Stakeholder{
+						Model: model.Model{ID: ref.ID},
+					})
+			}
+			m := &model.Application{}
+			m.ID = id
+			db = h.DB(ctx).Model(m).Omit("Contributors.*")
+			err = db.Association("Contributors").Append(contributors)
+			if err != nil {
+				_ = ctx.Error(err)
+				return
+			}
+		}
+	}
+
+	h.Respond(ctx, http.StatusOK, patched)
+}
+
 // Update godoc
 // @summary Update an application.
 // @description Update an application.
@@ -317,6 +582,16 @@ func (h ApplicationHandler) Update(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
+	err = h.extensions(ctx, r.Extensions)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = h.identities(ctx, r.Identities)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	//
 	// Delete unwanted facts.
 	m := &model.Application{}
@@ -326,15 +601,27 @@ func (h ApplicationHandler) Update(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	wave := m.MigrationWaveID
 	//
 	// Update the application.
 	m = r.Model()
 	m.Tags = nil
 	m.ID = id
+	err = h.unique(ctx, m)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	m.UpdateUser = h.BaseHandler.CurrentUser(ctx)
+	fields := h.fields(m)
+	delete(fields, "DefaultTrackerID")
+	delete(fields, "Archived")
+	if r.TicketDefaults == nil {
+		delete(fields, "TicketDefaults")
+	}
 	db = h.DB(ctx).Model(m)
 	db = db.Omit(clause.Associations, "BucketID")
-	result = db.Updates(h.fields(m))
+	result = db.Updates(fields)
 	if result.Error != nil {
 		_ = ctx.Error(result.Error)
 		return
@@ -352,14 +639,26 @@ func (h ApplicationHandler) Update(ctx *gin.Context) {
 		return
 	}
 
+	err = h.waveChanged(ctx, id, wave, m.MigrationWaveID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
 	// delete existing tag associations and create new ones
+	before := []model.ApplicationTag{}
+	err = h.DB(ctx).Find(&before, "ApplicationID = ?", id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	err = h.DB(ctx).Delete(&model.ApplicationTag{}, "ApplicationID = ?", id).Error
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
+	tags := []model.ApplicationTag{}
 	if len(r.Tags) > 0 {
-		tags := []model.ApplicationTag{}
 		for _, t := range r.Tags {
 			if !t.Virtual {
 				tags = append(tags, model.ApplicationTag{TagID: t.ID, ApplicationID: m.ID, Source: t.Source})
@@ -371,6 +670,330 @@ func (h ApplicationHandler) Update(ctx *gin.Context) {
 			return
 		}
 	}
+	err = h.tagChanged(ctx, before, tags)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	h.Status(ctx, http.StatusNoContent)
+}
+
+// Copy godoc
+// @summary Copy the assessments, review and tags to other applications.
+// @description Copy (replicate) the assessments, review and tags of the application
+// @description to the target applications. Copied assessments replace the target
+// @description assessments for the same questionnaire. The copied review replaces
+// @description the target review. Tags are added.
+// @tags applications
+// @accept json
+// @success 204
+// @router /applications/{id}/copy [post]
+// @param id path int true "Application id"
+// @param copy body api.AppCopy true "Targets and flags"
+func (h ApplicationHandler) Copy(ctx *gin.Context) {
+	id := h.pk(ctx)
+	r := &AppCopy{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = r.Validate(id)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m := &model.Application{}
+	err = h.DB(ctx).First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	targets := r.targets()
+	var n int64
+	err = h.DB(ctx).Model(&model.Application{}).Where("ID IN ?", targets).Count(&n).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if int(n) != len(targets) {
+		_ = ctx.Error(&BadRequestError{"target applications not found."})
+		return
+	}
+	user := h.CurrentUser(ctx)
+	if r.Assessment {
+		var list []model.Assessment
+		db := h.preLoad(h.DB(ctx), "Stakeholders", "StakeholderGroups")
+		err = db.Find(&list, "ApplicationID", id).Error
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		if len(list) == 0 {
+			_ = ctx.Error(&BadRequestError{"application not assessed."})
+			return
+		}
+		for _, target := range targets {
+			for _, a := range list {
+				db = h.DB(ctx).Where("ApplicationID", target)
+				db = db.Where("QuestionnaireID", a.QuestionnaireID)
+				err = db.Delete(&model.Assessment{}).Error
+				if err != nil {
+					_ = ctx.Error(err)
+					return
+				}
+				applicationID := target
+				copied := &model.Assessment{
+					ApplicationID:     &applicationID,
+					QuestionnaireID:   a.QuestionnaireID,
+					Sections:          a.Sections,
+					Thresholds:        a.Thresholds,
+					RiskMessages:      a.RiskMessages,
+					Stakeholders:      a.Stakeholders,
+					StakeholderGroups: a.StakeholderGroups,
+				}
+				copied.CreateUser = user
+				db = h.DB(ctx).Omit("Stakeholders.*", "StakeholderGroups.*")
+				err = db.Create(copied).Error
+				if err != nil {
+					_ = ctx.Error(err)
+					return
+				}
+			}
+		}
+	}
+	if r.Review {
+		review := &model.Review{}
+		err = h.DB(ctx).First(review, "ApplicationID", id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = &BadRequestError{"application not reviewed."}
+			}
+			_ = ctx.Error(err)
+			return
+		}
+		for _, target := range targets {
+			err = h.DB(ctx).Delete(&model.Review{}, "ApplicationID", target).Error
+			if err != nil {
+				_ = ctx.Error(err)
+				return
+			}
+			applicationID := target
+			copied := &model.Review{
+				BusinessCriticality: review.BusinessCriticality,
+				EffortEstimate:      review.EffortEstimate,
+				ProposedAction:      review.ProposedAction,
+				WorkPriority:        review.WorkPriority,
+				Comments:            review.Comments,
+				ApplicationID:       &applicationID,
+			}
+			copied.CreateUser = user
+			err = h.DB(ctx).Create(copied).Error
+			if err != nil {
+				_ = ctx.Error(err)
+				return
+			}
+		}
+	}
+	if r.Tags {
+		var list []model.ApplicationTag
+		err = h.DB(ctx).Find(&list, "ApplicationID", id).Error
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		for _, target := range targets {
+			added := []model.ApplicationTag{}
+			for _, tag := range list {
+				tag.ApplicationID = target
+				db := h.DB(ctx).Clauses(clause.OnConflict{DoNothing: true})
+				result := db.Create(&tag)
+				if result.Error != nil {
+					_ = ctx.Error(result.Error)
+					return
+				}
+				if result.RowsAffected > 0 {
+					added = append(added, tag)
+				}
+			}
+			err = h.tagChanged(ctx, nil, added)
+			if err != nil {
+				_ = ctx.Error(err)
+				return
+			}
+		}
+	}
+
+	h.Status(ctx, http.StatusNoContent)
+}
+
+// Duplicates godoc
+// @summary List duplicate applications.
+// @description List the applications that would violate the uniqueness
+// @description policy (repository|binary). Both are reported by default.
+// @tags applications
+// @produce json
+// @success 200 {object} []api.AppDuplicate
+// @router /applications/duplicates [get]
+// @param unique query string false "Policy (repository|binary)"
+func (h ApplicationHandler) Duplicates(ctx *gin.Context) {
+	policies := []string{AppUniqueRepository, AppUniqueBinary}
+	if policy := ctx.Query(UniqueParam); policy != "" {
+		switch policy {
+		case AppUniqueRepository,
+			AppUniqueBinary:
+			policies = []string{policy}
+		default:
+			_ = ctx.Error(&BadRequestError{"unique must be (repository|binary)."})
+			return
+		}
+	}
+	var list []model.Application
+	db := h.DB(ctx).Select("ID", "Name", "Repository", "Binary")
+	err := db.Order("ID").Find(&list).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	resources := []AppDuplicate{}
+	for _, policy := range policies {
+		keys := []string{}
+		found := map[string][]Ref{}
+		for i := range list {
+			m := &list[i]
+			key := AppUniqueKey(policy, m)
+			if key == "" {
+				continue
+			}
+			if _, seen := found[key]; !seen {
+				keys = append(keys, key)
+			}
+			found[key] = append(found[key], Ref{ID: m.ID, Name: m.Name})
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if len(found[key]) < 2 {
+				continue
+			}
+			resources = append(
+				resources,
+				AppDuplicate{
+					Unique:       policy,
+					Key:          key,
+					Applications: found[key],
+				})
+		}
+	}
+
+	h.Respond(ctx, http.StatusOK, resources)
+}
+
+// queryIDs returns the IDs specified by the query parameter.
+// Returns nil when the parameter is not specified.
+func (h ApplicationHandler) queryIDs(ctx *gin.Context, param string) (ids []uint, err error) {
+	values, found := ctx.GetQueryArray(param)
+	if !found {
+		return
+	}
+	ids = []uint{}
+	for _, v := range values {
+		n, pErr := strconv.ParseUint(v, 10, 0)
+		if pErr != nil {
+			err = &BadRequestError{param + " must be an integer."}
+			return
+		}
+		ids = append(ids, uint(n))
+	}
+	return
+}
+
+// unique enforces the (configured) uniqueness policy.
+func (h ApplicationHandler) unique(ctx *gin.Context, m *model.Application) (err error) {
+	policy := Settings.Hub.Application.Unique
+	conflict, err := AppConflict(h.DB(ctx), policy, m)
+	if err != nil || conflict == nil {
+		return
+	}
+	err = &Conflict{
+		Reason: "application: conflicts with '" + conflict.Name + "' (unique: " + policy + ").",
+		ID:     conflict.ID,
+	}
+	return
+}
+
+// Archive godoc
+// @summary Archive an application.
+// @description Archive (retire) an application.
+// @description The application is removed from its migration wave and
+// @description excluded from the (default) list and analysis reports.
+// @description The assessments, reviews and analyses are retained.
+// @tags applications
+// @success 204
+// @router /applications/{id}/archive [put]
+// @param id path int true "Application id"
+func (h ApplicationHandler) Archive(ctx *gin.Context) {
+	h.archive(ctx, true)
+}
+
+// Restore godoc
+// @summary Restore an archived application.
+// @description Restore an archived application.
+// @tags applications
+// @success 204
+// @router /applications/{id}/restore [put]
+// @param id path int true "Application id"
+func (h ApplicationHandler) Restore(ctx *gin.Context) {
+	h.archive(ctx, false)
+}
+
+// archive sets the archived flag.
+func (h ApplicationHandler) archive(ctx *gin.Context, archived bool) {
+	id := h.pk(ctx)
+	m := &model.Application{}
+	result := h.DB(ctx).First(m, id)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	wasArchived := m.Archived
+	wave := m.MigrationWaveID
+	fields := map[string]interface{}{
+		"Archived":   archived,
+		"UpdateUser": h.CurrentUser(ctx),
+	}
+	if archived {
+		fields["MigrationWaveID"] = nil
+	}
+	db := h.DB(ctx).Model(m)
+	db = db.Omit(clause.Associations)
+	result = db.Updates(fields)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	if wasArchived == archived {
+		h.Status(ctx, http.StatusNoContent)
+		return
+	}
+	if archived {
+		err := h.waveChanged(ctx, id, wave, nil)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		err = h.appEvent(ctx, id, AppEventArchived, "")
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	} else {
+		err := h.appEvent(ctx, id, AppEventRestored, "")
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
 
 	h.Status(ctx, http.StatusNoContent)
 }
@@ -567,6 +1190,11 @@ func (h ApplicationHandler) TagAdd(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
+	err = h.tagChanged(ctx, nil, []model.ApplicationTag{*tag})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	h.Respond(ctx, http.StatusCreated, ref)
 }
 
@@ -597,6 +1225,12 @@ func (h ApplicationHandler) TagReplace(ctx *gin.Context) {
 		condition := h.DB(ctx).Where("source = ?", source)
 		db = db.Where(condition)
 	}
+	before := []model.ApplicationTag{}
+	err = db.Session(&gorm.Session{}).Find(&before).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	err = db.Delete(&model.ApplicationTag{}).Error
 	if err != nil {
 		_ = ctx.Error(err)
@@ -604,8 +1238,8 @@ func (h ApplicationHandler) TagReplace(ctx *gin.Context) {
 	}
 
 	// create new associations
+	appTags := []model.ApplicationTag{}
 	if len(refs) > 0 {
-		appTags := []model.ApplicationTag{}
 		for _, ref := range refs {
 			if !ref.Virtual {
 				appTags = append(appTags, model.ApplicationTag{
@@ -621,6 +1255,11 @@ func (h ApplicationHandler) TagReplace(ctx *gin.Context) {
 			return
 		}
 	}
+	err = h.tagChanged(ctx, before, appTags)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 
 	h.Status(ctx, http.StatusNoContent)
 }
@@ -649,7 +1288,18 @@ func (h ApplicationHandler) TagDelete(ctx *gin.Context) {
 		condition := h.DB(ctx).Where("source = ?", source)
 		db = db.Where(condition)
 	}
-	err := db.Delete(&model.ApplicationTag{}).Error
+	deleted := []model.ApplicationTag{}
+	err := db.Session(&gorm.Session{}).Find(&deleted).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = db.Delete(&model.ApplicationTag{}).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = h.tagChanged(ctx, deleted, nil)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
@@ -690,6 +1340,64 @@ func (h ApplicationHandler) FactList(ctx *gin.Context, key FactKey) {
 	h.Respond(ctx, http.StatusOK, facts)
 }
 
+// FactQuery godoc
+// @summary List facts.
+// @description List the anonymous (unqualified) facts.
+// @description When ?qualified=true, list facts (all sources).
+// @description The keys are qualified by source. See api.FactKey for details.
+// @description Filtered by source using: ?source=analysis&source=discovery.
+// @description Use ?source= for anonymous (unqualified) facts.
+// @tags applications
+// @produce json
+// @success 200 {object} api.FactMap
+// @router /applications/{id}/facts [get]
+// @param id path int true "Application ID"
+// @param qualified query bool false "All sources (qualified keys)"
+// @param source query string false "Source (when qualified)"
+func (h ApplicationHandler) FactQuery(ctx *gin.Context) {
+	qualified := false
+	s := ctx.Query(QualifiedParam)
+	if s != "" {
+		var err error
+		qualified, err = strconv.ParseBool(s)
+		if err != nil {
+			err = &BadRequestError{QualifiedParam + ": '" + s + "' must be a boolean."}
+			_ = ctx.Error(err)
+			return
+		}
+	}
+	if !qualified {
+		h.FactList(ctx, FactKey(""))
+		return
+	}
+	id := h.pk(ctx)
+	list := []model.Fact{}
+	db := h.DB(ctx)
+	db = db.Where("ApplicationID", id)
+	sources, found := ctx.GetQueryArray(Source)
+	if found {
+		db = db.Where("Source IN ?", sources)
+	}
+	result := db.Find(&list)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+
+	facts := FactMap{}
+	for i := range list {
+		fact := &list[i]
+		key := FactKey(fact.Key)
+		if fact.Source != "" {
+			key.Qualify(fact.Source)
+		}
+		var v interface{}
+		_ = json.Unmarshal(fact.Value, &v)
+		facts[string(key)] = v
+	}
+	h.Respond(ctx, http.StatusOK, facts)
+}
+
 // FactGet godoc
 // @summary Get fact by name.
 // @description Get fact by name.
@@ -710,6 +1418,10 @@ func (h ApplicationHandler) FactGet(ctx *gin.Context) {
 	}
 
 	key := FactKey(ctx.Param(Key))
+	if key == "" {
+		h.FactQuery(ctx)
+		return
+	}
 	if key.Name() == "" {
 		h.FactList(ctx, key)
 		return
@@ -855,6 +1567,8 @@ func (h ApplicationHandler) FactDelete(ctx *gin.Context) {
 // FactReplace godoc
 // @summary Replace all facts from a source.
 // @description Replace all facts from a source.
+// @description Facts from other sources are not affected.
+// @description The (factmap) keys may be qualified only by the same source.
 // @description see api.FactKey for details on key parameter format.
 // @tags applications
 // @success 204
@@ -870,6 +1584,16 @@ func (h ApplicationHandler) FactReplace(ctx *gin.Context, key FactKey) {
 		_ = ctx.Error(err)
 		return
 	}
+	for k := range facts {
+		source := FactKey(k).Source()
+		if source != "" && source != key.Source() {
+			err = &BadRequestError{
+				"fact: '" + k + "' not in source: '" + key.Source() + "'.",
+			}
+			_ = ctx.Error(err)
+			return
+		}
+	}
 
 	// remove all the existing Facts for that source and app id.
 	db := h.DB(ctx)
@@ -1061,6 +1785,280 @@ func (h ApplicationHandler) AssessmentCreate(ctx *gin.Context) {
 	h.Respond(ctx, http.StatusCreated, r)
 }
 
+// RepositoryValidate godoc
+// @summary Validate the repository.
+// @description Validate the repository URL, branch, tag and path exist using
+// @description the (source) identity of the application.
+// @description The repository (body) is optional and validated instead of the
+// @description application repository when specified.
+// @description Git URLs must be (http|https|ssh|git) or scp-like (user@host:path).
+// @description Local (file) URLs and paths are not supported.
+// @description The ssh host key is verified only when SCM_KNOWN_HOSTS is defined.
+// @tags applications
+// @accept json
+// @produce json
+// @success 200 {object} api.RepositoryValidation
+// @router /applications/{id}/repository/validate [post]
+// @param id path int true "Application id"
+// @param repository body api.Repository false "Repository"
+func (h ApplicationHandler) RepositoryValidate(ctx *gin.Context) {
+	id := h.pk(ctx)
+	m := &model.Application{}
+	err := h.DB(ctx).Preload("Identities").First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	r := Repository{}
+	if ctx.Request.ContentLength > 0 {
+		err = h.Bind(ctx, &r)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	} else {
+		_ = json.Unmarshal(m.Repository, &r)
+	}
+	resource := RepositoryValidation{Errors: []RepositoryError{}}
+	var identity *model.Identity
+	for i := range m.Identities {
+		if m.Identities[i].Kind == IdentitySource {
+			identity = &m.Identities[i]
+			break
+		}
+	}
+	if identity != nil {
+		err = identity.Decrypt()
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+	validator, err := scm.New(r.Kind, identity)
+	if err != nil {
+		resource.Errors = append(
+			resource.Errors,
+			RepositoryError{Field: scm.FieldKind, Reason: err.Error()})
+		h.Respond(ctx, http.StatusOK, resource)
+		return
+	}
+	failed, err := validator.Validate(
+		&scm.Remote{
+			Kind:   r.Kind,
+			URL:    r.URL,
+			Branch: r.Branch,
+			Tag:    r.Tag,
+			Path:   r.Path,
+		})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	for _, e := range failed {
+		resource.Errors = append(
+			resource.Errors,
+			RepositoryError{Field: e.Field, Reason: e.Reason})
+	}
+	resource.Valid = len(resource.Errors) == 0
+
+	h.Respond(ctx, http.StatusOK, resource)
+}
+
+// TrackerGet godoc
+// @summary Get the default tracker.
+// @description Get the default tracker used for tickets created for the application.
+// @tags applications
+// @produce json
+// @success 200 {object} api.AppTracker
+// @router /applications/{id}/tracker [get]
+// @param id path int true "Application ID"
+func (h ApplicationHandler) TrackerGet(ctx *gin.Context) {
+	id := h.pk(ctx)
+	m := &model.Application{}
+	db := h.preLoad(h.DB(ctx), "DefaultTracker")
+	err := db.First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	r := AppTracker{}
+	if m.DefaultTracker != nil {
+		r.Tracker = &Ref{}
+		r.Tracker.With(m.DefaultTracker.ID, m.DefaultTracker.Name)
+	}
+	h.Respond(ctx, http.StatusOK, r)
+}
+
+// TrackerPut godoc
+// @summary Set the default tracker.
+// @description Set the default tracker used for tickets created for the application.
+// @description The tracker must be connected and of a supported kind.
+// @description A null tracker unsets the default.
+// @tags applications
+// @accept json
+// @success 204
+// @router /applications/{id}/tracker [put]
+// @param id path int true "Application ID"
+// @param tracker body api.AppTracker true "Tracker reference"
+func (h ApplicationHandler) TrackerPut(ctx *gin.Context) {
+	id := h.pk(ctx)
+	r := &AppTracker{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m := &model.Application{}
+	err = h.DB(ctx).First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var trackerID *uint
+	if r.Tracker != nil {
+		trackerID = &r.Tracker.ID
+		t := &model.Tracker{}
+		err = h.DB(ctx).First(t, r.Tracker.ID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = &BadRequestError{"tracker: not found."}
+			}
+			_ = ctx.Error(err)
+			return
+		}
+		if _, found := tracker.Schemas[t.Kind]; !found {
+			err = &BadRequestError{"tracker: kind not supported."}
+			_ = ctx.Error(err)
+			return
+		}
+		if !t.Connected {
+			err = &BadRequestError{"tracker: not connected."}
+			_ = ctx.Error(err)
+			return
+		}
+	}
+	db := h.DB(ctx).Model(m)
+	err = db.UpdateColumn("DefaultTrackerID", trackerID).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	h.Status(ctx, http.StatusNoContent)
+}
+
+// TrackerHealth godoc
+// @summary Get the tracker health.
+// @description Get the health of the trackers linked to the application
+// @description (the default tracker and the trackers of its tickets).
+// @description The status is the worst-of the linked trackers:
+// @description Healthy < Unknown (not tested) < Unhealthy.
+// @description The status is Unknown when no trackers are linked.
+// @tags applications
+// @produce json
+// @success 200 {object} api.AppTrackerHealth
+// @router /applications/{id}/tracker-health [get]
+// @param id path int true "Application ID"
+func (h ApplicationHandler) TrackerHealth(ctx *gin.Context) {
+	id := h.pk(ctx)
+	m := &model.Application{}
+	err := h.DB(ctx).First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	db := h.DB(ctx).Model(&model.Ticket{})
+	db = db.Where("ApplicationID", m.ID)
+	var ids []uint
+	err = db.Pluck("TrackerID", &ids).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if m.DefaultTrackerID != nil {
+		ids = append(ids, *m.DefaultTrackerID)
+	}
+	var list []model.Tracker
+	if len(ids) > 0 {
+		err = h.DB(ctx).Order("ID").Find(&list, ids).Error
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+	r := AppTrackerHealth{}
+	r.With(list)
+	h.Respond(ctx, http.StatusOK, r)
+}
+
+// AppTracker REST resource.
+// The default tracker for an application.
+type AppTracker struct {
+	Tracker *Ref `json:"tracker"`
+}
+
+// Tracker health.
+const (
+	TrackerHealthy   = "Healthy"
+	TrackerUnknown   = "Unknown"
+	TrackerUnhealthy = "Unhealthy"
+)
+
+// AppTrackerHealth REST resource.
+// The health of the trackers linked to an application.
+type AppTrackerHealth struct {
+	Status   string          `json:"status"`
+	Trackers []TrackerHealth `json:"trackers"`
+}
+
+// With updates the resource with the (linked) trackers.
+func (r *AppTrackerHealth) With(list []model.Tracker) {
+	severity := map[string]int{
+		TrackerHealthy:   0,
+		TrackerUnknown:   1,
+		TrackerUnhealthy: 2,
+	}
+	r.Status = TrackerUnknown
+	r.Trackers = []TrackerHealth{}
+	for i := range list {
+		health := TrackerHealth{}
+		health.With(&list[i])
+		if i == 0 || severity[health.Status] > severity[r.Status] {
+			r.Status = health.Status
+		}
+		r.Trackers = append(r.Trackers, health)
+	}
+}
+
+// TrackerHealth REST resource.
+type TrackerHealth struct {
+	Tracker       Ref       `json:"tracker"`
+	Status        string    `json:"status"`
+	Connected     bool      `json:"connected"`
+	Message       string    `json:"message,omitempty" yaml:",omitempty"`
+	StatusReason  string    `json:"statusReason,omitempty" yaml:"statusReason,omitempty"`
+	ErrorCategory string    `json:"errorCategory,omitempty" yaml:"errorCategory,omitempty"`
+	LastUpdated   time.Time `json:"lastUpdated" yaml:"lastUpdated"`
+}
+
+// With updates the resource with the model.
+func (r *TrackerHealth) With(m *model.Tracker) {
+	r.Tracker.With(m.ID, m.Name)
+	r.Connected = m.Connected
+	r.Message = m.Message
+	r.StatusReason = m.StatusReason
+	r.ErrorCategory = m.ErrorCategory
+	r.LastUpdated = m.LastUpdated
+	switch {
+	case m.Connected:
+		r.Status = TrackerHealthy
+	case m.LastUpdated.IsZero():
+		r.Status = TrackerUnknown
+	default:
+		r.Status = TrackerUnhealthy
+	}
+}
+
 // Application REST resource.
 type Application struct {
 	Resource        `yaml:",inline"`
@@ -1083,6 +2081,250 @@ type Application struct {
 	Risk            string      `json:"risk"`
 	Confidence      int         `json:"confidence"`
 	Effort          int         `json:"effort"`
+	TicketDefaults  Fields      `json:"ticketDefaults,omitempty" yaml:"ticketDefaults,omitempty"`
+	// DefaultTracker (read-only) set using /applications/{id}/tracker.
+	DefaultTracker *Ref `json:"defaultTracker,omitempty" yaml:"defaultTracker,omitempty"`
+	// Archived (read-only) set using /applications/{id}/archive.
+	Archived bool `json:"archived,omitempty" yaml:",omitempty"`
+	// Extensions (custom field) values validated by the schema.
+	// See: /schemas/application.
+	Extensions Extensions `json:"extensions,omitempty" yaml:",omitempty"`
+}
+
+// AppCopy copy request.
+// The flags select what is copied.
+type AppCopy struct {
+	Applications []Ref `json:"applications" binding:"required"`
+	Assessment   bool  `json:"assessment,omitempty" yaml:",omitempty"`
+	Review       bool  `json:"review,omitempty" yaml:",omitempty"`
+	Tags         bool  `json:"tags,omitempty" yaml:",omitempty"`
+}
+
+// Validate the request.
+func (r *AppCopy) Validate(id uint) (err error) {
+	if len(r.Applications) == 0 {
+		err = &BadRequestError{"applications: at least one required."}
+		return
+	}
+	if !r.Assessment && !r.Review && !r.Tags {
+		err = &BadRequestError{"at least one of (assessment|review|tags) required."}
+		return
+	}
+	for _, ref := range r.Applications {
+		if ref.ID == id {
+			err = &BadRequestError{"applications: cannot include the source."}
+			return
+		}
+	}
+	return
+}
+
+// targets returns the (unique) target application IDs.
+func (r *AppCopy) targets() (ids []uint) {
+	seen := map[uint]bool{}
+	for _, ref := range r.Applications {
+		if !seen[ref.ID] {
+			seen[ref.ID] = true
+			ids = append(ids, ref.ID)
+		}
+	}
+	return
+}
+
+// AppConflict returns the application conflicting with the
+// specified application by the policy.
+func AppConflict(db *gorm.DB, policy string, m *model.Application) (conflict *model.Application, err error) {
+	key := AppUniqueKey(policy, m)
+	if key == "" {
+		return
+	}
+	db = db.Select("ID", "Name", "Repository", "Binary")
+	db = db.Where("ID != ?", m.ID)
+	switch policy {
+	case AppUniqueRepository:
+		db = db.Where("Repository IS NOT NULL")
+	case AppUniqueBinary:
+		db = db.Where("Binary != ''")
+	}
+	var list []model.Application
+	err = db.Find(&list).Error
+	if err != nil {
+		return
+	}
+	for i := range list {
+		if AppUniqueKey(policy, &list[i]) == key {
+			conflict = &list[i]
+			return
+		}
+	}
+	return
+}
+
+// AppSearch free-text application search.
+// Each term must match (case-insensitive) at least one of:
+// name, description, comments, tag and repository URL.
+type AppSearch struct {
+	Terms []string
+}
+
+// Where returns the query with the search (terms) conditions.
+func (r *AppSearch) Where(db, in *gorm.DB) (out *gorm.DB) {
+	out = in
+	for _, term := range r.Terms {
+		like := "%" + r.escape(term) + "%"
+		tq := db.Model(&model.Tag{})
+		tq = tq.Select("ID")
+		tq = tq.Where("Name LIKE ? ESCAPE '\\'", like)
+		iq := db.Model(&model.ApplicationTag{})
+		iq = iq.Select("ApplicationID")
+		iq = iq.Where("TagID IN (?)", tq)
+		q := db.Where("Name LIKE ? ESCAPE '\\'", like)
+		q = q.Or("Description LIKE ? ESCAPE '\\'", like)
+		q = q.Or("Comments LIKE ? ESCAPE '\\'", like)
+		q = q.Or("json_extract(Repository, '$.url') LIKE ? ESCAPE '\\'", like)
+		q = q.Or("ID IN (?)", iq)
+		out = out.Where(q)
+	}
+	return
+}
+
+// Rank returns the rank (relevance) of the application.
+// Name matches rank highest (exact, prefix, contains) followed
+// by tags, repository URL, description and comments.
+func (r *AppSearch) Rank(m *model.Application, tags []model.ApplicationTag) (rank int) {
+	name := strings.ToLower(m.Name)
+	description := strings.ToLower(m.Description)
+	comments := strings.ToLower(m.Comments)
+	repository := Repository{}
+	_ = json.Unmarshal(m.Repository, &repository)
+	url := strings.ToLower(repository.URL)
+	for _, term := range r.Terms {
+		term = strings.ToLower(term)
+		switch {
+		case name == term:
+			rank += 100
+		case strings.HasPrefix(name, term):
+			rank += 50
+		case strings.Contains(name, term):
+			rank += 25
+		}
+		for _, tag := range tags {
+			if strings.Contains(strings.ToLower(tag.Tag.Name), term) {
+				rank += 10
+				break
+			}
+		}
+		if strings.Contains(url, term) {
+			rank += 5
+		}
+		if strings.Contains(description, term) {
+			rank += 3
+		}
+		if strings.Contains(comments, term) {
+			rank++
+		}
+	}
+	return
+}
+
+// escape the LIKE wildcards.
+func (r *AppSearch) escape(term string) (escaped string) {
+	escaped = strings.NewReplacer(
+		"\\", "\\\\",
+		"%", "\\%",
+		"_", "\\_").Replace(term)
+	return
+}
+
+// AppUniqueKey returns the (normalized) key used to determine
+// uniqueness by the policy. Returns "" when not constrained.
+func AppUniqueKey(policy string, m *model.Application) (key string) {
+	switch policy {
+	case AppUniqueRepository:
+		repository := Repository{}
+		_ = json.Unmarshal(m.Repository, &repository)
+		url := strings.ToLower(strings.TrimSpace(repository.URL))
+		url = strings.TrimSuffix(url, "/")
+		url = strings.TrimSuffix(url, ".git")
+		if url == "" {
+			return
+		}
+		key = url
+		path := strings.Trim(strings.TrimSpace(repository.Path), "/")
+		if path != "" {
+			key += "#" + path
+		}
+	case AppUniqueBinary:
+		key = strings.TrimSpace(m.Binary)
+		if strings.HasPrefix(key, BinaryBucket) {
+			key = ""
+		}
+	}
+	return
+}
+
+// AppDuplicate REST resource.
+type AppDuplicate struct {
+	Unique       string `json:"unique"`
+	Key          string `json:"key"`
+	Applications []Ref  `json:"applications"`
+}
+
+// ApplicationPatch REST resource.
+// The patch is applied to the applications selected by the filter.
+type ApplicationPatch struct {
+	Filter struct {
+		IDs             []uint `json:"ids,omitempty" yaml:",omitempty"`
+		Tags            []Ref  `json:"tags,omitempty" yaml:",omitempty"`
+		BusinessService *Ref   `json:"businessService,omitempty" yaml:"businessService,omitempty"`
+	} `json:"filter"`
+	Patch struct {
+		Owner           *Ref     `json:"owner,omitempty" yaml:",omitempty"`
+		BusinessService *Ref     `json:"businessService,omitempty" yaml:"businessService,omitempty"`
+		MigrationWave   *Ref     `json:"migrationWave,omitempty" yaml:"migrationWave,omitempty"`
+		Contributors    []Ref    `json:"contributors,omitempty" yaml:",omitempty"`
+		Tags            []TagRef `json:"tags,omitempty" yaml:",omitempty"`
+	} `json:"patch"`
+}
+
+// Validate the patch.
+func (r *ApplicationPatch) Validate() (err error) {
+	filter := r.Filter
+	if len(filter.IDs) == 0 &&
+		len(filter.Tags) == 0 &&
+		filter.BusinessService == nil {
+		err = &BadRequestError{"filter (ids, tags, businessService) required."}
+		return
+	}
+	patch := r.Patch
+	if patch.Owner == nil &&
+		patch.BusinessService == nil &&
+		patch.MigrationWave == nil &&
+		len(patch.Contributors) == 0 &&
+		len(patch.Tags) == 0 {
+		err = &BadRequestError{"patch is empty."}
+		return
+	}
+	for _, ref := range patch.Tags {
+		if ref.Virtual {
+			err = &BadRequestError{"cannot add virtual tags"}
+			return
+		}
+	}
+	return
+}
+
+// ids returns the referenced IDs.
+func (r *ApplicationPatch) ids(refs []Ref) (ids []uint) {
+	for _, ref := range refs {
+		ids = append(ids, ref.ID)
+	}
+	return
+}
+
+// ApplicationPatched REST resource.
+type ApplicationPatched struct {
+	Updated []uint `json:"updated"`
 }
 
 // With updates the resource using the model.
@@ -1093,7 +2335,10 @@ func (r *Application) With(m *model.Application, tags []model.ApplicationTag) {
 	r.Bucket = r.refPtr(m.BucketID, m.Bucket)
 	r.Comments = m.Comments
 	r.Binary = m.Binary
+	r.Archived = m.Archived
+	_ = json.Unmarshal(m.Extensions, &r.Extensions)
 	_ = json.Unmarshal(m.Repository, &r.Repository)
+	_ = json.Unmarshal(m.TicketDefaults, &r.TicketDefaults)
 	if m.Review != nil {
 		ref := &Ref{}
 		ref.With(m.Review.ID, "")
@@ -1123,6 +2368,7 @@ func (r *Application) With(m *model.Application, tags []model.ApplicationTag) {
 			ref)
 	}
 	r.MigrationWave = r.refPtr(m.MigrationWaveID, m.MigrationWave)
+	r.DefaultTracker = r.refPtr(m.DefaultTrackerID, m.DefaultTracker)
 	r.Assessments = []Ref{}
 	for _, a := range m.Assessments {
 		ref := Ref{}
@@ -1195,6 +2441,12 @@ func (r *Application) Model() (m *model.Application) {
 	if r.Repository != nil {
 		m.Repository, _ = json.Marshal(r.Repository)
 	}
+	if r.Extensions != nil {
+		m.Extensions, _ = json.Marshal(r.Extensions)
+	}
+	if r.TicketDefaults != nil {
+		m.TicketDefaults, _ = json.Marshal(r.TicketDefaults)
+	}
 	if r.BusinessService != nil {
 		m.BusinessServiceID = &r.BusinessService.ID
 	}
@@ -1244,6 +2496,19 @@ type Repository struct {
 	Path   string `json:"path"`
 }
 
+// RepositoryValidation REST resource.
+type RepositoryValidation struct {
+	Valid  bool              `json:"valid"`
+	Errors []RepositoryError `json:"errors"`
+}
+
+// RepositoryError a repository validation error.
+// Field: (kind|url|branch|tag|path).
+type RepositoryError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
 // Fact REST nested resource.
 type Fact struct {
 	Key    string      `json:"key"`
@@ -0,0 +1,709 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/konveyor/tackle2-hub/tracker"
+	"github.com/onsi/gomega"
+)
+
+func TestApplicationUpdateTrackerDefaults(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	identity := &model.Identity{Name: "jira", Kind: tracker.BasicAuth}
+	g.Expect(db.Create(identity).Error).To(gomega.BeNil())
+	for _, name := range []string{"a", "b"} {
+		m := &model.Tracker{
+			Name:       name,
+			URL:        "https://" + name,
+			Kind:       tracker.JiraCloud,
+			IdentityID: identity.ID,
+		}
+		g.Expect(db.Create(m).Error).To(gomega.BeNil())
+	}
+	trackerID := uint(1)
+	app := &model.Application{
+		Name:             "app",
+		TicketDefaults:   []byte(`{"priority":"high"}`),
+		DefaultTrackerID: &trackerID,
+	}
+	g.Expect(db.Create(app).Error).To(gomega.BeNil())
+
+	h := ApplicationHandler{}
+	e := newEngine(db)
+	e.PUT(ApplicationRoot, h.Update)
+	put := func(body string) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, "/applications/1", bytes.NewBufferString(body))
+		e.ServeHTTP(w, req)
+		g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	}
+	get := func() (m *model.Application) {
+		m = &model.Application{}
+		g.Expect(db.First(m, app.ID).Error).To(gomega.BeNil())
+		return
+	}
+
+	// Not supplied.
+	put(`{"name":"app"}`)
+	m := get()
+	g.Expect(*m.DefaultTrackerID).To(gomega.Equal(trackerID))
+	g.Expect(string(m.TicketDefaults)).To(gomega.Equal(`{"priority":"high"}`))
+
+	// Default tracker is read-only.
+	put(`{"name":"app","defaultTracker":{"id":2}}`)
+	m = get()
+	g.Expect(*m.DefaultTrackerID).To(gomega.Equal(trackerID))
+
+	// Ticket defaults supplied.
+	put(`{"name":"app","ticketDefaults":{"priority":"low"}}`)
+	m = get()
+	g.Expect(string(m.TicketDefaults)).To(gomega.Equal(`{"priority":"low"}`))
+	g.Expect(*m.DefaultTrackerID).To(gomega.Equal(trackerID))
+}
+
+func TestApplicationTrackerHealth(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	a := newTracker(g, db, "a")
+	g.Expect(db.Model(a).Update("Connected", true).Error).To(gomega.BeNil())
+	b := newTracker(g, db, "b")
+	c := newTracker(g, db, "c")
+	g.Expect(db.Model(c).Updates(map[string]interface{}{
+		"LastUpdated":   time.Now(),
+		"ErrorCategory": tracker.ErrorAuth,
+	}).Error).To(gomega.BeNil())
+	app := &model.Application{Name: "app", DefaultTrackerID: &a.ID}
+	g.Expect(db.Create(app).Error).To(gomega.BeNil())
+	other := &model.Application{Name: "other"}
+	g.Expect(db.Create(other).Error).To(gomega.BeNil())
+
+	h := ApplicationHandler{}
+	e := newEngine(db)
+	e.GET(AppTrackerHealthRoot, h.TrackerHealth)
+	get := func(id uint) (r AppTrackerHealth) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/applications/"+strconv.Itoa(int(id))+"/tracker-health", nil)
+		e.ServeHTTP(w, req)
+		g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+		g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+		return
+	}
+
+	// Not linked.
+	r := get(other.ID)
+	g.Expect(r.Status).To(gomega.Equal(TrackerUnknown))
+	g.Expect(len(r.Trackers)).To(gomega.Equal(0))
+
+	// Default tracker.
+	r = get(app.ID)
+	g.Expect(r.Status).To(gomega.Equal(TrackerHealthy))
+	g.Expect(len(r.Trackers)).To(gomega.Equal(1))
+
+	// Worst-of the ticket trackers.
+	for _, m := range []*model.Tracker{a, b} {
+		ticket := &model.Ticket{Kind: "1", Parent: "1", ApplicationID: app.ID, TrackerID: m.ID}
+		g.Expect(db.Create(ticket).Error).To(gomega.BeNil())
+	}
+	r = get(app.ID)
+	g.Expect(r.Status).To(gomega.Equal(TrackerUnknown))
+	g.Expect(len(r.Trackers)).To(gomega.Equal(2))
+	ticket := &model.Ticket{Kind: "1", Parent: "1", ApplicationID: app.ID, TrackerID: c.ID}
+	g.Expect(db.Create(ticket).Error).To(gomega.BeNil())
+	r = get(app.ID)
+	g.Expect(r.Status).To(gomega.Equal(TrackerUnhealthy))
+	g.Expect(len(r.Trackers)).To(gomega.Equal(3))
+	g.Expect(r.Trackers[2].ErrorCategory).To(gomega.Equal(tracker.ErrorAuth))
+}
+
+func TestApplicationArchive(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	wave := &model.MigrationWave{Name: "wave"}
+	g.Expect(db.Create(wave).Error).To(gomega.BeNil())
+	app := &model.Application{Name: "app", MigrationWaveID: &wave.ID}
+	g.Expect(db.Create(app).Error).To(gomega.BeNil())
+	g.Expect(db.Create(&model.Application{Name: "other"}).Error).To(gomega.BeNil())
+
+	h := ApplicationHandler{}
+	e := newEngine(db)
+	e.GET(ApplicationsRoot, h.List)
+	e.PUT(AppArchiveRoot, h.Archive)
+	e.PUT(AppRestoreRoot, h.Restore)
+	put := func(url string) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, url, nil)
+		e.ServeHTTP(w, req)
+		g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	}
+	list := func(url string) (names []string) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		e.ServeHTTP(w, req)
+		g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+		resources := []Application{}
+		_ = json.Unmarshal(w.Body.Bytes(), &resources)
+		for _, r := range resources {
+			names = append(names, r.Name)
+		}
+		return
+	}
+
+	put("/applications/1/archive")
+	m := &model.Application{}
+	g.Expect(db.First(m, app.ID).Error).To(gomega.BeNil())
+	g.Expect(m.Archived).To(gomega.BeTrue())
+	g.Expect(m.MigrationWaveID).To(gomega.BeNil())
+	g.Expect(list(ApplicationsRoot)).To(gomega.Equal([]string{"other"}))
+	g.Expect(list(ApplicationsRoot + "?archived=true")).To(gomega.Equal([]string{"app", "other"}))
+
+	put("/applications/1/restore")
+	g.Expect(list(ApplicationsRoot)).To(gomega.Equal([]string{"app", "other"}))
+}
+
+func TestApplicationPatch(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	category := &model.TagCategory{Name: "c"}
+	g.Expect(db.Create(category).Error).To(gomega.BeNil())
+	tags := []model.Tag{
+		{Name: "t1", CategoryID: category.ID},
+		{Name: "t2", CategoryID: category.ID},
+	}
+	g.Expect(db.Create(&tags).Error).To(gomega.BeNil())
+	owner := &model.Stakeholder{Name: "owner", Email: "owner@x"}
+	g.Expect(db.Create(owner).Error).To(gomega.BeNil())
+	for _, name := range []string{"a", "b", "c"} {
+		g.Expect(db.Create(&model.Application{Name: name}).Error).To(gomega.BeNil())
+	}
+	for _, id := range []uint{1, 2} {
+		appTag := &model.ApplicationTag{ApplicationID: id, TagID: tags[0].ID}
+		g.Expect(db.Create(appTag).Error).To(gomega.BeNil())
+	}
+
+	h := ApplicationHandler{}
+	e := newEngine(db)
+	e.PATCH(ApplicationsRoot, h.Patch)
+	patch := func(body string) (w *httptest.ResponseRecorder, r ApplicationPatched) {
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPatch, ApplicationsRoot, bytes.NewBufferString(body))
+		e.ServeHTTP(w, req)
+		_ = json.Unmarshal(w.Body.Bytes(), &r)
+		return
+	}
+
+	body := `{"filter":{"tags":[{"id":1}]},"patch":{"owner":{"id":1},"tags":[{"id":2}],"contributors":[{"id":1}]}}`
+	for i := 0; i < 2; i++ {
+		w, r := patch(body)
+		g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+		g.Expect(r.Updated).To(gomega.Equal([]uint{1, 2}))
+	}
+	for _, id := range []uint{1, 2, 3} {
+		m := &model.Application{}
+		g.Expect(db.First(m, id).Error).To(gomega.BeNil())
+		var n int64
+		db.Model(&model.ApplicationTag{}).Where("ApplicationID", id).Where("TagID", tags[1].ID).Count(&n)
+		if id == 3 {
+			g.Expect(m.OwnerID).To(gomega.BeNil())
+			g.Expect(n).To(gomega.BeZero())
+		} else {
+			g.Expect(*m.OwnerID).To(gomega.Equal(owner.ID))
+			g.Expect(n).To(gomega.Equal(int64(1)))
+			n = db.Model(m).Association("Contributors").Count()
+			g.Expect(n).To(gomega.Equal(int64(1)))
+		}
+	}
+	// IDs ANDed with tags.
+	w, r := patch(`{"filter":{"ids":[2,3],"tags":[{"id":1}]},"patch":{"owner":{"id":1}}}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(r.Updated).To(gomega.Equal([]uint{2}))
+	// Filter required.
+	w, _ = patch(`{"patch":{"owner":{"id":1}}}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	// Patch required.
+	w, _ = patch(`{"filter":{"ids":[1]}}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+}
+
+func TestApplicationUnique(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	policy := Settings.Hub.Application.Unique
+	defer func() {
+		Settings.Hub.Application.Unique = policy
+	}()
+	Settings.Hub.Application.Unique = AppUniqueRepository
+
+	h := ApplicationHandler{}
+	e := newEngine(db)
+	e.POST(ApplicationsRoot, h.Create)
+	e.GET(AppDuplicatesRoot, h.Duplicates)
+	post := func(body string) (w *httptest.ResponseRecorder) {
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, ApplicationsRoot, bytes.NewBufferString(body))
+		e.ServeHTTP(w, req)
+		return
+	}
+
+	w := post(`{"name":"a","repository":{"url":"https://github.com/x/y.git"}}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusCreated))
+	w = post(`{"name":"b","repository":{"url":"https://GitHub.com/x/y/"}}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusConflict))
+	conflict := map[string]interface{}{}
+	_ = json.Unmarshal(w.Body.Bytes(), &conflict)
+	g.Expect(conflict["id"]).To(gomega.Equal(float64(1)))
+	// Path (monorepo) distinguished.
+	w = post(`{"name":"c","repository":{"url":"https://github.com/x/y","path":"svc"}}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusCreated))
+
+	// Report existing duplicates.
+	for _, name := range []string{"d", "e"} {
+		m := &model.Application{Name: name, Binary: "g:a:1"}
+		g.Expect(db.Create(m).Error).To(gomega.BeNil())
+	}
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, AppDuplicatesRoot, nil))
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	report := []AppDuplicate{}
+	_ = json.Unmarshal(w.Body.Bytes(), &report)
+	g.Expect(report).To(gomega.Equal(
+		[]AppDuplicate{
+			{
+				Unique: AppUniqueBinary,
+				Key:    "g:a:1",
+				Applications: []Ref{
+					{ID: 3, Name: "d"},
+					{ID: 4, Name: "e"},
+				},
+			},
+		}))
+}
+
+func TestApplicationEvents(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	category := &model.TagCategory{Name: "Language"}
+	g.Expect(db.Create(category).Error).To(gomega.BeNil())
+	tag := &model.Tag{Name: "Java", CategoryID: category.ID}
+	g.Expect(db.Create(tag).Error).To(gomega.BeNil())
+	wave := &model.MigrationWave{Name: "wave"}
+	g.Expect(db.Create(wave).Error).To(gomega.BeNil())
+	app := &model.Application{Name: "app", MigrationWaveID: &wave.ID}
+	g.Expect(db.Create(app).Error).To(gomega.BeNil())
+	review := &model.Review{ProposedAction: "rehost", EffortEstimate: "small", ApplicationID: &app.ID}
+	g.Expect(db.Create(review).Error).To(gomega.BeNil())
+
+	h := ApplicationHandler{}
+	e := newEngine(db)
+	e.POST(ApplicationTagsRoot, h.TagAdd)
+	e.DELETE(ApplicationTagRoot, h.TagDelete)
+	e.PUT(AppArchiveRoot, h.Archive)
+	e.GET(AppEventsRoot, h.EventList)
+	send := func(method, url string, r interface{}) (w *httptest.ResponseRecorder) {
+		b, _ := json.Marshal(r)
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(method, url, bytes.NewReader(b))
+		req.Header.Set("Content-Type", "application/json")
+		e.ServeHTTP(w, req)
+		return
+	}
+
+	w := send(http.MethodPost, "/applications/1/tags", TagRef{ID: tag.ID})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusCreated))
+	w = send(http.MethodDelete, "/applications/1/tags/1", nil)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	w = send(http.MethodPut, "/applications/1/archive", nil)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+
+	w = send(http.MethodGet, "/applications/1/events", nil)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	events := []AppEvent{}
+	_ = json.Unmarshal(w.Body.Bytes(), &events)
+	kinds := []string{}
+	for _, r := range events {
+		kinds = append(kinds, r.Kind)
+	}
+	g.Expect(kinds).To(gomega.Equal([]string{
+		AppEventCreated,
+		AppEventReviewed,
+		AppEventTagAdded,
+		AppEventTagRemoved,
+		AppEventWaveRemoved,
+		AppEventArchived,
+	}))
+	g.Expect(events[1].Ref).To(gomega.Equal(&Ref{ID: review.ID}))
+	g.Expect(events[2].Description).To(gomega.Equal("Tag: Language/Java"))
+	g.Expect(events[4].Description).To(gomega.Equal("Wave: (1) wave"))
+
+	w = send(http.MethodGet, "/applications/9/events", nil)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNotFound))
+}
+
+func TestApplicationFacts(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	g.Expect(db.Create(&model.Application{Name: "app"}).Error).To(gomega.BeNil())
+
+	h := ApplicationHandler{}
+	e := newEngine(db)
+	e.GET(ApplicationFactsRoot, h.FactGet)
+	e.GET(ApplicationFactRoot, h.FactGet)
+	e.PUT(ApplicationFactRoot, h.FactPut)
+	send := func(method, url string, r interface{}) (w *httptest.ResponseRecorder, facts FactMap) {
+		b, _ := json.Marshal(r)
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(method, url, bytes.NewReader(b))
+		req.Header.Set("Content-Type", "application/json")
+		e.ServeHTTP(w, req)
+		_ = json.Unmarshal(w.Body.Bytes(), &facts)
+		return
+	}
+
+	w, _ := send(http.MethodPut, "/applications/1/facts/analysis:", FactMap{"a": 1.0, "b": 2.0})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	w, _ = send(http.MethodPut, "/applications/1/facts/discovery:", FactMap{"a": 3.0})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	w, _ = send(http.MethodPut, "/applications/1/facts/plain", "x")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	// Replace (source) does not clobber other sources.
+	w, _ = send(http.MethodPut, "/applications/1/facts/analysis:", FactMap{"analysis:c": 4.0})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	w, _ = send(http.MethodPut, "/applications/1/facts/analysis:", FactMap{"discovery:a": 4.0})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+
+	// Anonymous (unqualified).
+	w, facts := send(http.MethodGet, "/applications/1/facts", nil)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(facts).To(gomega.Equal(FactMap{"plain": "x"}))
+	// All sources.
+	w, facts = send(http.MethodGet, "/applications/1/facts?qualified=true", nil)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(facts).To(gomega.Equal(FactMap{"analysis:c": 4.0, "discovery:a": 3.0, "plain": "x"}))
+	w, _ = send(http.MethodGet, "/applications/1/facts?qualified=x", nil)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	_, facts = send(http.MethodGet, "/applications/1/facts?qualified=true&source=discovery&source=", nil)
+	g.Expect(facts).To(gomega.Equal(FactMap{"discovery:a": 3.0, "plain": "x"}))
+	_, facts = send(http.MethodGet, "/applications/1/facts/analysis:", nil)
+	g.Expect(facts).To(gomega.Equal(FactMap{"c": 4.0}))
+}
+
+func TestApplicationSearch(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	category := &model.TagCategory{Name: "Domain"}
+	g.Expect(db.Create(category).Error).To(gomega.BeNil())
+	tag := &model.Tag{Name: "Payments", CategoryID: category.ID}
+	g.Expect(db.Create(tag).Error).To(gomega.BeNil())
+	apps := []model.Application{
+		{Name: "billing", Comments: "talks to payments"},
+		{Name: "ledger"},
+		{Name: "payments-api"},
+		{Name: "checkout", Repository: []byte(`{"url":"https://git/acme/payments.git"}`)},
+		{Name: "payments"},
+		{Name: "store", Description: "on_sale 100%"},
+	}
+	g.Expect(db.Create(&apps).Error).To(gomega.BeNil())
+	g.Expect(db.Create(&model.ApplicationTag{ApplicationID: apps[1].ID, TagID: tag.ID}).Error).To(gomega.BeNil())
+
+	h := ApplicationHandler{}
+	e := newEngine(db)
+	e.GET(ApplicationsRoot, h.List)
+	search := func(terms string) (names []string) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, ApplicationsRoot, nil)
+		q := req.URL.Query()
+		q.Set(SearchParam, terms)
+		req.URL.RawQuery = q.Encode()
+		e.ServeHTTP(w, req)
+		g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+		resources := []Application{}
+		_ = json.Unmarshal(w.Body.Bytes(), &resources)
+		for _, r := range resources {
+			names = append(names, r.Name)
+		}
+		return
+	}
+
+	g.Expect(search("PAYMENTS")).To(gomega.Equal(
+		[]string{"payments", "payments-api", "ledger", "checkout", "billing"}))
+	g.Expect(search("payments api")).To(gomega.Equal([]string{"payments-api"}))
+	g.Expect(search("100%")).To(gomega.Equal([]string{"store"}))
+	g.Expect(search("n_s")).To(gomega.Equal([]string{"store"}))
+	g.Expect(search("0%s")).To(gomega.BeNil())
+	g.Expect(search("")).To(gomega.HaveLen(6))
+}
+
+func TestApplicationCopy(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	for _, name := range []string{"source", "a", "b"} {
+		g.Expect(db.Create(&model.Application{Name: name}).Error).To(gomega.BeNil())
+	}
+	category := &model.TagCategory{Name: "c"}
+	g.Expect(db.Create(category).Error).To(gomega.BeNil())
+	tag := &model.Tag{Name: "t", CategoryID: category.ID}
+	g.Expect(db.Create(tag).Error).To(gomega.BeNil())
+	g.Expect(db.Create(&model.ApplicationTag{ApplicationID: 1, TagID: tag.ID}).Error).To(gomega.BeNil())
+	stakeholder := &model.Stakeholder{Name: "s", Email: "s@x"}
+	g.Expect(db.Create(stakeholder).Error).To(gomega.BeNil())
+	questionnaire := &model.Questionnaire{Name: "q"}
+	g.Expect(db.Create(questionnaire).Error).To(gomega.BeNil())
+	source := uint(1)
+	g.Expect(db.Create(&model.Assessment{
+		ApplicationID:   &source,
+		QuestionnaireID: questionnaire.ID,
+		Sections:        []byte(`[{"order":1}]`),
+		Stakeholders:    []model.Stakeholder{*stakeholder},
+	}).Error).To(gomega.BeNil())
+	existing := uint(2)
+	g.Expect(db.Create(&model.Assessment{
+		ApplicationID:   &existing,
+		QuestionnaireID: questionnaire.ID,
+	}).Error).To(gomega.BeNil())
+	g.Expect(db.Create(&model.Review{
+		ApplicationID:  &source,
+		ProposedAction: "rehost",
+		EffortEstimate: "small",
+		WorkPriority:   3,
+	}).Error).To(gomega.BeNil())
+
+	h := ApplicationHandler{}
+	e := newEngine(db)
+	e.POST(AppCopyRoot, h.Copy)
+	post := func(url string, r interface{}) (w *httptest.ResponseRecorder) {
+		b, _ := json.Marshal(r)
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+		req.Header.Set("Content-Type", "application/json")
+		e.ServeHTTP(w, req)
+		return
+	}
+
+	targets := []Ref{{ID: 2}, {ID: 3}}
+	w := post("/applications/1/copy", AppCopy{Applications: targets, Assessment: true, Review: true, Tags: true})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	for _, id := range []uint{2, 3} {
+		var assessments []model.Assessment
+		g.Expect(db.Preload("Stakeholders").Find(&assessments, "ApplicationID", id).Error).To(gomega.BeNil())
+		g.Expect(assessments).To(gomega.HaveLen(1))
+		g.Expect(string(assessments[0].Sections)).To(gomega.Equal(`[{"order":1}]`))
+		g.Expect(assessments[0].Stakeholders).To(gomega.HaveLen(1))
+		review := &model.Review{}
+		g.Expect(db.First(review, "ApplicationID", id).Error).To(gomega.BeNil())
+		g.Expect(review.ProposedAction).To(gomega.Equal("rehost"))
+		g.Expect(review.WorkPriority).To(gomega.Equal(uint(3)))
+		var n int64
+		db.Model(&model.ApplicationTag{}).Where("ApplicationID", id).Count(&n)
+		g.Expect(n).To(gomega.Equal(int64(1)))
+	}
+	// Again (replaced).
+	w = post("/applications/1/copy", AppCopy{Applications: targets, Assessment: true, Review: true, Tags: true})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	var n int64
+	db.Model(&model.Review{}).Count(&n)
+	g.Expect(n).To(gomega.Equal(int64(3)))
+	// Invalid.
+	w = post("/applications/1/copy", AppCopy{Applications: targets})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	w = post("/applications/1/copy", AppCopy{Applications: []Ref{{ID: 1}}, Tags: true})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	w = post("/applications/1/copy", AppCopy{Applications: []Ref{{ID: 9}}, Tags: true})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	w = post("/applications/2/copy", AppCopy{Applications: []Ref{{ID: 3}}, Review: true})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	w = post("/applications/9/copy", AppCopy{Applications: []Ref{{ID: 3}}, Review: true})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNotFound))
+}
+
+func TestApplicationOwnership(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	owner := &model.Stakeholder{Name: "owner", Email: "owner@x"}
+	g.Expect(db.Create(owner).Error).To(gomega.BeNil())
+	other := &model.Stakeholder{Name: "other", Email: "other@x"}
+	g.Expect(db.Create(other).Error).To(gomega.BeNil())
+	apps := []model.Application{
+		{Name: "a", OwnerID: &owner.ID, Contributors: []model.Stakeholder{*other}},
+		{Name: "b", OwnerID: &other.ID},
+		{Name: "c"},
+	}
+	g.Expect(db.Omit("Contributors.*").Create(&apps).Error).To(gomega.BeNil())
+
+	h := ApplicationHandler{}
+	sh := StakeholderHandler{}
+	e := newEngine(db)
+	e.GET(ApplicationsRoot, h.List)
+	e.DELETE(StakeholderRoot, sh.Delete)
+	list := func(query string) (code int, names []string) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, ApplicationsRoot+"?"+query, nil)
+		e.ServeHTTP(w, req)
+		resources := []Application{}
+		_ = json.Unmarshal(w.Body.Bytes(), &resources)
+		for _, r := range resources {
+			names = append(names, r.Name)
+		}
+		code = w.Code
+		return
+	}
+
+	_, names := list("owner.id=1")
+	g.Expect(names).To(gomega.Equal([]string{"a"}))
+	_, names = list("owner.id=1&owner.id=2")
+	g.Expect(names).To(gomega.Equal([]string{"a", "b"}))
+	_, names = list("contributor.id=2")
+	g.Expect(names).To(gomega.Equal([]string{"a"}))
+	_, names = list("owner.id=2&contributor.id=2")
+	g.Expect(names).To(gomega.BeNil())
+	code, _ := list("owner.id=x")
+	g.Expect(code).To(gomega.Equal(http.StatusBadRequest))
+
+	// Stakeholder deleted.
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/stakeholders/2", nil)
+	e.ServeHTTP(w, req)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	m := &model.Application{}
+	g.Expect(db.Preload("Contributors").First(m, apps[0].ID).Error).To(gomega.BeNil())
+	g.Expect(m.Contributors).To(gomega.BeEmpty())
+	m = &model.Application{}
+	g.Expect(db.First(m, apps[1].ID).Error).To(gomega.BeNil())
+	g.Expect(m.OwnerID).To(gomega.BeNil())
+	_, names = list("owner.id=1")
+	g.Expect(names).To(gomega.Equal([]string{"a"}))
+}
+
+func TestApplicationBinary(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	app := &model.Application{Name: "app"}
+	g.Expect(db.Create(app).Error).To(gomega.BeNil())
+	limit := Settings.Hub.Application.BinaryLimit
+	Settings.Hub.Application.BinaryLimit = 1
+	t.Cleanup(func() {
+		Settings.Hub.Application.BinaryLimit = limit
+	})
+
+	h := ApplicationHandler{}
+	e := newEngine(db)
+	e.POST(AppBinaryRoot, h.BinaryUpload)
+	upload := func(name string, content []byte) (w *httptest.ResponseRecorder) {
+		body := &bytes.Buffer{}
+		mp := multipart.NewWriter(body)
+		part, _ := mp.CreateFormFile(FileField, name)
+		_, _ = part.Write(content)
+		_ = mp.Close()
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/applications/1/binary", body)
+		req.Header.Set("Content-Type", mp.FormDataContentType())
+		e.ServeHTTP(w, req)
+		return
+	}
+
+	content := []byte("archive")
+	w := upload("app.war", content)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusCreated))
+	r := AppBinary{}
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+	digest := sha256.Sum256(content)
+	g.Expect(r.Checksum).To(gomega.Equal("sha256:" + hex.EncodeToString(digest[:])))
+	g.Expect(r.Size).To(gomega.Equal(int64(len(content))))
+	g.Expect(r.Binary).To(gomega.Equal("bucket://.binary/app.war"))
+	m := &model.Application{}
+	g.Expect(db.Preload("Bucket").First(m, app.ID).Error).To(gomega.BeNil())
+	g.Expect(m.Binary).To(gomega.Equal(r.Binary))
+	stored, err := os.ReadFile(path.Join(m.Bucket.Path, r.Path))
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(stored).To(gomega.Equal(content))
+
+	// Replaced.
+	w = upload("app.ear", content)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusCreated))
+	_, err = os.Stat(path.Join(m.Bucket.Path, r.Path))
+	g.Expect(os.IsNotExist(err)).To(gomega.BeTrue())
+
+	// Invalid.
+	w = upload("app.zip", content)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	w = upload("app.jar", make([]byte, 2<<20))
+	g.Expect(w.Code).To(gomega.Equal(http.StatusRequestEntityTooLarge))
+}
+
+func TestApplicationIdentities(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	for _, m := range []model.Identity{
+		{Name: "git", Kind: IdentitySource, User: "u", Password: "p"},
+		{Name: "maven", Kind: IdentityMaven},
+		{Name: "jira", Kind: tracker.BasicAuth},
+		{Name: "svn", Kind: IdentitySource, Key: "k"},
+	} {
+		g.Expect(db.Create(&m).Error).To(gomega.BeNil())
+	}
+
+	h := ApplicationHandler{}
+	e := newEngine(db)
+	e.POST(ApplicationsRoot, h.Create)
+	e.GET(AppIdentitiesRoot, h.IdentityList)
+	post := func(body string) (w *httptest.ResponseRecorder) {
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, ApplicationsRoot, bytes.NewBufferString(body))
+		e.ServeHTTP(w, req)
+		return
+	}
+
+	w := post(`{"name":"a","identities":[{"id":3}]}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	w = post(`{"name":"a","identities":[{"id":1},{"id":4}]}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	w = post(`{"name":"a","identities":[{"id":9}]}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	w = post(`{"name":"a","identities":[{"id":1},{"id":2}]}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusCreated))
+	r := Application{}
+	_ = json.Unmarshal(w.Body.Bytes(), &r)
+
+	// Misconfigured (existing) identities are reported.
+	db.Exec("INSERT INTO ApplicationIdentity (ApplicationID, IdentityID) VALUES (?, 3), (?, 4)", r.ID, r.ID)
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/applications/"+strconv.Itoa(int(r.ID))+"/identities", nil)
+	e.ServeHTTP(w, req)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	list := []AppIdentity{}
+	_ = json.Unmarshal(w.Body.Bytes(), &list)
+	g.Expect(list).To(gomega.HaveLen(4))
+	resolvable := []bool{}
+	for _, identity := range list {
+		resolvable = append(resolvable, identity.Resolvable)
+	}
+	// source: no repository; maven: no settings.
+	g.Expect(resolvable).To(gomega.Equal([]bool{false, false, false, false}))
+	g.Expect(list[0].Reason).To(gomega.Equal("application repository not defined."))
+	g.Expect(list[1].Reason).To(gomega.Equal("settings required."))
+	g.Expect(list[2].Reason).To(gomega.Equal("kind must be (source|maven)."))
+
+	db.Model(&model.Application{}).Where("ID", r.ID).Update("Repository", `{"url":"https://x"}`)
+	db.Model(&model.Identity{}).Where("ID", 2).Update("Settings", "<settings/>")
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+	list = []AppIdentity{}
+	_ = json.Unmarshal(w.Body.Bytes(), &list)
+	resolvable = []bool{}
+	for _, identity := range list {
+		resolvable = append(resolvable, identity.Resolvable)
+	}
+	g.Expect(resolvable).To(gomega.Equal([]bool{true, true, false, false}))
+	g.Expect(list[3].Reason).To(gomega.Equal("another identity (kind=source) is resolved."))
+}
@@ -0,0 +1,229 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/assessment"
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+// AnalyzerAddon the addon which performs analysis.
+const AnalyzerAddon = "analyzer"
+
+// Report godoc
+// @summary Get the application portfolio report.
+// @description Get application counts by business service, risk, review
+// @description decision (proposed action), tag category and analysis state.
+// @description Applications without a business service are counted with business service ID=0.
+// @description The analysis state is the state of the latest analyzer task.
+// @description Archived applications are included with: ?archived=true.
+// @tags applications
+// @produce json
+// @success 200 {object} api.AppReport
+// @router /applications/report [get]
+// @param archived query bool false "Include archived"
+func (h ApplicationHandler) Report(ctx *gin.Context) {
+	var list []model.Application
+	db := h.preLoad(
+		h.DB(ctx),
+		"Tags",
+		"Assessments",
+		"Review",
+		"BusinessService")
+	archived, _ := strconv.ParseBool(ctx.Query(ArchivedParam))
+	if !archived {
+		db = db.Where("Archived", false)
+	}
+	err := db.Find(&list).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var categories []model.TagCategory
+	err = h.DB(ctx).Find(&categories).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var analyzed []uint
+	err = h.DB(ctx).Model(&model.Analysis{}).Distinct().Pluck("ApplicationID", &analyzed).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var tasks []model.Task
+	db = h.DB(ctx).Select("ID", "ApplicationID", "State")
+	db = db.Where("Addon", AnalyzerAddon)
+	db = db.Where("ApplicationID IS NOT NULL")
+	err = db.Order("ID").Find(&tasks).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	questionnaire, err := assessment.NewQuestionnaireResolver(h.DB(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	membership := assessment.NewMembershipResolver(h.DB(ctx))
+	tagsResolver, err := assessment.NewTagResolver(h.DB(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	r := AppReport{}
+	r.With(categories, analyzed, tasks)
+	for i := range list {
+		m := &list[i]
+		resolver := assessment.NewApplicationResolver(m, tagsResolver, membership, questionnaire)
+		assessed, err := resolver.Assessed()
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		risk := assessment.RiskUnknown
+		if assessed {
+			risk, err = resolver.Risk()
+			if err != nil {
+				_ = ctx.Error(err)
+				return
+			}
+		}
+		r.add(m, assessed, risk)
+	}
+	r.sorted()
+
+	h.Respond(ctx, http.StatusOK, r)
+}
+
+// AppReport REST resource.
+type AppReport struct {
+	Total            int               `json:"total"`
+	BusinessServices []AppReportCount  `json:"businessServices"`
+	Assessment       AppReportAssessed `json:"assessment"`
+	Review           AppReportReview   `json:"review"`
+	TagCategories    []AppReportCount  `json:"tagCategories"`
+	Analysis         AppReportAnalysis `json:"analysis"`
+	// indexes.
+	services   map[uint]int
+	categories map[uint]int
+	analyzed   map[uint]bool
+	tasks      map[uint]string
+}
+
+// AppReportCount application count.
+type AppReportCount struct {
+	Ref
+	Count int `json:"count"`
+}
+
+// AppReportAssessed assessment counts.
+type AppReportAssessed struct {
+	Assessed    int            `json:"assessed"`
+	NotAssessed int            `json:"notAssessed"`
+	Risks       map[string]int `json:"risks"`
+}
+
+// AppReportReview review counts.
+type AppReportReview struct {
+	Reviewed    int            `json:"reviewed"`
+	NotReviewed int            `json:"notReviewed"`
+	Decisions   map[string]int `json:"decisions"`
+}
+
+// AppReportAnalysis analysis counts.
+type AppReportAnalysis struct {
+	Analyzed    int            `json:"analyzed"`
+	NotAnalyzed int            `json:"notAnalyzed"`
+	States      map[string]int `json:"states"`
+}
+
+// With initializes the report.
+// The tasks are ordered by ID.
+func (r *AppReport) With(categories []model.TagCategory, analyzed []uint, tasks []model.Task) {
+	r.BusinessServices = []AppReportCount{}
+	r.TagCategories = []AppReportCount{}
+	r.Assessment.Risks = map[string]int{}
+	r.Review.Decisions = map[string]int{}
+	r.Analysis.States = map[string]int{}
+	r.services = map[uint]int{}
+	r.categories = map[uint]int{}
+	r.analyzed = map[uint]bool{}
+	r.tasks = map[uint]string{}
+	for i := range categories {
+		m := &categories[i]
+		r.categories[m.ID] = len(r.TagCategories)
+		r.TagCategories = append(
+			r.TagCategories,
+			AppReportCount{Ref: Ref{ID: m.ID, Name: m.Name}})
+	}
+	for _, id := range analyzed {
+		r.analyzed[id] = true
+	}
+	for i := range tasks {
+		m := &tasks[i]
+		r.tasks[*m.ApplicationID] = m.State
+	}
+}
+
+// add the application.
+func (r *AppReport) add(m *model.Application, assessed bool, risk string) {
+	r.Total++
+	ref := Ref{}
+	if m.BusinessService != nil {
+		ref.With(m.BusinessService.ID, m.BusinessService.Name)
+	}
+	n, found := r.services[ref.ID]
+	if !found {
+		n = len(r.BusinessServices)
+		r.services[ref.ID] = n
+		r.BusinessServices = append(r.BusinessServices, AppReportCount{Ref: ref})
+	}
+	r.BusinessServices[n].Count++
+	if assessed {
+		r.Assessment.Assessed++
+	} else {
+		r.Assessment.NotAssessed++
+	}
+	r.Assessment.Risks[risk]++
+	if m.Review != nil {
+		r.Review.Reviewed++
+		r.Review.Decisions[m.Review.ProposedAction]++
+	} else {
+		r.Review.NotReviewed++
+	}
+	seen := map[uint]bool{}
+	for _, tag := range m.Tags {
+		if seen[tag.CategoryID] {
+			continue
+		}
+		seen[tag.CategoryID] = true
+		n, found = r.categories[tag.CategoryID]
+		if found {
+			r.TagCategories[n].Count++
+		}
+	}
+	if r.analyzed[m.ID] {
+		r.Analysis.Analyzed++
+	} else {
+		r.Analysis.NotAnalyzed++
+	}
+	if state, found := r.tasks[m.ID]; found {
+		r.Analysis.States[state]++
+	}
+}
+
+// sorted sorts the (named) counts by name.
+func (r *AppReport) sorted() {
+	for _, list := range [][]AppReportCount{r.BusinessServices, r.TagCategories} {
+		sort.SliceStable(
+			list,
+			func(i, j int) bool {
+				return list[i].Name < list[j].Name
+			})
+	}
+}
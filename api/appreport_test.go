@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/assessment"
+	"github.com/konveyor/tackle2-hub/model"
+	tasking "github.com/konveyor/tackle2-hub/task"
+	"github.com/onsi/gomega"
+)
+
+func TestApplicationReport(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	service := &model.BusinessService{Name: "finance"}
+	g.Expect(db.Create(service).Error).To(gomega.BeNil())
+	category := &model.TagCategory{Name: "Language"}
+	g.Expect(db.Create(category).Error).To(gomega.BeNil())
+	tags := []model.Tag{
+		{Name: "Java", CategoryID: category.ID},
+		{Name: "Go", CategoryID: category.ID},
+	}
+	g.Expect(db.Create(&tags).Error).To(gomega.BeNil())
+	apps := []model.Application{
+		{Name: "a", BusinessServiceID: &service.ID},
+		{Name: "b", BusinessServiceID: &service.ID},
+		{Name: "c"},
+		{Name: "d", Archived: true},
+	}
+	g.Expect(db.Create(&apps).Error).To(gomega.BeNil())
+	for _, m := range []model.ApplicationTag{
+		{ApplicationID: apps[0].ID, TagID: tags[0].ID},
+		{ApplicationID: apps[0].ID, TagID: tags[1].ID, Source: "language-discovery"},
+	} {
+		g.Expect(db.Create(&m).Error).To(gomega.BeNil())
+	}
+	g.Expect(db.Create(&model.Review{ApplicationID: &apps[0].ID, ProposedAction: "rehost"}).Error).To(gomega.BeNil())
+	g.Expect(db.Create(&model.Analysis{ApplicationID: apps[0].ID}).Error).To(gomega.BeNil())
+	for _, m := range []model.Task{
+		{Name: "1", Addon: AnalyzerAddon, State: tasking.Failed, ApplicationID: &apps[0].ID},
+		{Name: "2", Addon: AnalyzerAddon, State: tasking.Succeeded, ApplicationID: &apps[0].ID},
+		{Name: "3", Addon: AnalyzerAddon, State: tasking.Running, ApplicationID: &apps[1].ID},
+		{Name: "4", Addon: "other", State: tasking.Failed, ApplicationID: &apps[2].ID},
+	} {
+		g.Expect(db.Create(&m).Error).To(gomega.BeNil())
+	}
+
+	h := ApplicationHandler{}
+	e := newEngine(db)
+	e.GET(AppReportRoot, h.Report)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, AppReportRoot, nil)
+	e.ServeHTTP(w, req)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	r := AppReport{}
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+	g.Expect(r.Total).To(gomega.Equal(3))
+	g.Expect(r.BusinessServices).To(gomega.Equal([]AppReportCount{
+		{Ref: Ref{}, Count: 1},
+		{Ref: Ref{ID: service.ID, Name: "finance"}, Count: 2},
+	}))
+	g.Expect(r.Assessment.NotAssessed).To(gomega.Equal(3))
+	g.Expect(r.Assessment.Risks).To(gomega.Equal(map[string]int{assessment.RiskUnknown: 3}))
+	g.Expect(r.Review.Reviewed).To(gomega.Equal(1))
+	g.Expect(r.Review.Decisions).To(gomega.Equal(map[string]int{"rehost": 1}))
+	g.Expect(r.TagCategories).To(gomega.Equal([]AppReportCount{
+		{Ref: Ref{ID: category.ID, Name: "Language"}, Count: 1},
+	}))
+	g.Expect(r.Analysis.Analyzed).To(gomega.Equal(1))
+	g.Expect(r.Analysis.NotAnalyzed).To(gomega.Equal(2))
+	g.Expect(r.Analysis.States).To(gomega.Equal(map[string]int{
+		tasking.Succeeded: 1,
+		tasking.Running:   1,
+	}))
+}
@@ -0,0 +1,269 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+// Routes
+const (
+	SchemasRoot   = "/schemas"
+	AppSchemaRoot = SchemasRoot + "/application"
+)
+
+// Custom field types.
+const (
+	FieldString = "string"
+	FieldEnum   = "enum"
+	FieldNumber = "number"
+	FieldDate   = "date"
+)
+
+// AppSchemaHandler handles the application (custom field) schema routes.
+type AppSchemaHandler struct {
+	BaseHandler
+}
+
+// AddRoutes adds routes.
+func (h AppSchemaHandler) AddRoutes(e *gin.Engine) {
+	routeGroup := e.Group("/")
+	routeGroup.Use(Required("schemas"))
+	routeGroup.GET(AppSchemaRoot, h.Get)
+	routeGroup.PUT(AppSchemaRoot, h.Update, Transaction)
+}
+
+// Get godoc
+// @summary Get the application schema.
+// @description Get the application (custom field) schema.
+// @tags schemas
+// @produce json
+// @success 200 {object} api.AppSchema
+// @router /schemas/application [get]
+func (h AppSchemaHandler) Get(ctx *gin.Context) {
+	var list []model.ApplicationField
+	err := h.DB(ctx).Order("ID").Find(&list).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	r := AppSchema{}
+	r.With(list)
+
+	h.Respond(ctx, http.StatusOK, r)
+}
+
+// Update godoc
+// @summary Update the application schema.
+// @description Replace the application (custom field) schema.
+// @description Field types: (string|enum|number|date). Enum fields must define values.
+// @description The extensions of existing applications are validated on their next update.
+// @tags schemas
+// @accept json
+// @success 204
+// @router /schemas/application [put]
+// @param schema body api.AppSchema true "Schema data"
+func (h AppSchemaHandler) Update(ctx *gin.Context) {
+	r := &AppSchema{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = r.Validate()
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = h.DB(ctx).Where("ID > 0").Delete(&model.ApplicationField{}).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	user := h.CurrentUser(ctx)
+	for _, f := range r.Fields {
+		m := f.Model()
+		m.CreateUser = user
+		err = h.DB(ctx).Create(m).Error
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+
+	h.Status(ctx, http.StatusNoContent)
+}
+
+// extensions validates the application extensions against the schema.
+func (h *BaseHandler) extensions(ctx *gin.Context, extensions Extensions) (err error) {
+	var list []model.ApplicationField
+	err = h.DB(ctx).Find(&list).Error
+	if err != nil {
+		return
+	}
+	schema := AppSchema{}
+	schema.With(list)
+	err = schema.Check(extensions)
+	return
+}
+
+// Extensions custom field values.
+type Extensions map[string]interface{}
+
+// AppSchema REST resource.
+type AppSchema struct {
+	Fields []AppField `json:"fields"`
+}
+
+// With updates the resource with the model.
+func (r *AppSchema) With(list []model.ApplicationField) {
+	r.Fields = []AppField{}
+	for i := range list {
+		f := AppField{}
+		f.With(&list[i])
+		r.Fields = append(r.Fields, f)
+	}
+}
+
+// Validate the schema.
+func (r *AppSchema) Validate() (err error) {
+	names := map[string]bool{}
+	for _, f := range r.Fields {
+		if names[f.Name] {
+			err = &BadRequestError{"field: '" + f.Name + "' must be unique."}
+			return
+		}
+		names[f.Name] = true
+		err = f.Validate()
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Check the extensions (values) against the schema.
+func (r *AppSchema) Check(extensions Extensions) (err error) {
+	fields := map[string]AppField{}
+	for _, f := range r.Fields {
+		fields[f.Name] = f
+	}
+	for name, v := range extensions {
+		f, found := fields[name]
+		if !found {
+			err = &BadRequestError{"extension: '" + name + "' not defined."}
+			return
+		}
+		err = f.Check(v)
+		if err != nil {
+			return
+		}
+	}
+	for _, f := range r.Fields {
+		if !f.Required {
+			continue
+		}
+		if _, found := extensions[f.Name]; !found {
+			err = &BadRequestError{"extension: '" + f.Name + "' required."}
+			return
+		}
+	}
+	return
+}
+
+// AppField REST resource.
+type AppField struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty" yaml:",omitempty"`
+	Required    bool     `json:"required,omitempty" yaml:",omitempty"`
+	Values      []string `json:"values,omitempty" yaml:",omitempty"`
+}
+
+// With updates the resource with the model.
+func (r *AppField) With(m *model.ApplicationField) {
+	r.Name = m.Name
+	r.Type = m.Type
+	r.Description = m.Description
+	r.Required = m.Required
+	_ = json.Unmarshal(m.Values, &r.Values)
+}
+
+// Model builds a model.
+func (r *AppField) Model() (m *model.ApplicationField) {
+	m = &model.ApplicationField{
+		Name:        r.Name,
+		Type:        r.Type,
+		Description: r.Description,
+		Required:    r.Required,
+	}
+	if len(r.Values) > 0 {
+		m.Values, _ = json.Marshal(r.Values)
+	}
+	return
+}
+
+// Validate the field definition.
+func (r *AppField) Validate() (err error) {
+	if r.Name == "" {
+		err = &BadRequestError{"field: name required."}
+		return
+	}
+	switch r.Type {
+	case FieldEnum:
+		if len(r.Values) == 0 {
+			err = &BadRequestError{"field: '" + r.Name + "' (enum) values required."}
+		}
+	case FieldString,
+		FieldNumber,
+		FieldDate:
+		if len(r.Values) > 0 {
+			err = &BadRequestError{"field: '" + r.Name + "' values only valid for enum."}
+		}
+	default:
+		err = &BadRequestError{"field: '" + r.Name + "' type must be (string|enum|number|date)."}
+	}
+	return
+}
+
+// Check the value matches the field type.
+// Dates are: YYYY-MM-DD or RFC3339.
+func (r *AppField) Check(v interface{}) (err error) {
+	valid := false
+	switch r.Type {
+	case FieldString:
+		_, valid = v.(string)
+	case FieldEnum:
+		s, isString := v.(string)
+		for _, allowed := range r.Values {
+			if isString && s == allowed {
+				valid = true
+				break
+			}
+		}
+	case FieldNumber:
+		switch v.(type) {
+		case int, int64, uint64, float64:
+			valid = true
+		}
+	case FieldDate:
+		s, isString := v.(string)
+		if isString {
+			_, dErr := time.Parse(time.DateOnly, s)
+			if dErr != nil {
+				_, dErr = time.Parse(time.RFC3339, s)
+			}
+			valid = dErr == nil
+		}
+	}
+	if !valid {
+		err = &BadRequestError{
+			fmt.Sprintf("extension: '%s' must be (%s).", r.Name, r.Type),
+		}
+	}
+	return
+}
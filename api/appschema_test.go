@@ -0,0 +1,87 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestAppSchema(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	h := AppSchemaHandler{}
+	ah := ApplicationHandler{}
+	e := newEngine(db)
+	e.GET(AppSchemaRoot, h.Get)
+	e.PUT(AppSchemaRoot, h.Update)
+	e.POST(ApplicationsRoot, ah.Create)
+	e.PUT(ApplicationRoot, ah.Update)
+	send := func(method, url string, r interface{}) (w *httptest.ResponseRecorder) {
+		b, _ := json.Marshal(r)
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(method, url, bytes.NewReader(b))
+		req.Header.Set("Content-Type", "application/json")
+		e.ServeHTTP(w, req)
+		return
+	}
+
+	schema := AppSchema{
+		Fields: []AppField{
+			{Name: "costCenter", Type: FieldString, Required: true},
+			{Name: "region", Type: FieldEnum, Values: []string{"east", "west"}},
+			{Name: "users", Type: FieldNumber},
+			{Name: "sunset", Type: FieldDate},
+		},
+	}
+	w := send(http.MethodPut, AppSchemaRoot, schema)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	w = send(http.MethodGet, AppSchemaRoot, nil)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	got := AppSchema{}
+	_ = json.Unmarshal(w.Body.Bytes(), &got)
+	g.Expect(got).To(gomega.Equal(schema))
+	// Invalid schema.
+	for _, f := range []AppField{
+		{Name: "x", Type: FieldEnum},
+		{Name: "x", Type: FieldString, Values: []string{"a"}},
+		{Name: "x", Type: "bool"},
+		{Type: FieldString},
+	} {
+		w = send(http.MethodPut, AppSchemaRoot, AppSchema{Fields: []AppField{f}})
+		g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	}
+
+	app := Application{
+		Name: "a",
+		Extensions: Extensions{
+			"costCenter": "cc-1",
+			"region":     "east",
+			"users":      10,
+			"sunset":     "2027-01-31",
+		},
+	}
+	w = send(http.MethodPost, ApplicationsRoot, app)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusCreated))
+	created := Application{}
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	g.Expect(created.Extensions["region"]).To(gomega.Equal("east"))
+	// Invalid extensions.
+	for _, ext := range []Extensions{
+		{"region": "east"},
+		{"costCenter": "cc-1", "region": "north"},
+		{"costCenter": "cc-1", "users": "many"},
+		{"costCenter": "cc-1", "sunset": "soon"},
+		{"costCenter": "cc-1", "other": "x"},
+	} {
+		app.Extensions = ext
+		w = send(http.MethodPut, "/applications/1", app)
+		g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	}
+	app.Extensions = Extensions{"costCenter": "cc-2"}
+	w = send(http.MethodPut, "/applications/1", app)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+}
@@ -29,6 +29,7 @@ func (h ArchetypeHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(ArchetypesRoot, h.List)
 	routeGroup.POST(ArchetypesRoot, h.Create)
 	routeGroup.GET(ArchetypeRoot, h.Get)
+	routeGroup.HEAD(ArchetypeRoot, h.Head)
 	routeGroup.PUT(ArchetypeRoot, h.Update)
 	routeGroup.DELETE(ArchetypeRoot, h.Delete)
 	// Assessments
@@ -55,6 +56,7 @@ func (h ArchetypeHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 	membership := assessment.NewMembershipResolver(h.DB(ctx))
 	questionnaires, err := assessment.NewQuestionnaireResolver(h.DB(ctx))
 	if err != nil {
@@ -76,6 +78,19 @@ func (h ArchetypeHandler) Get(ctx *gin.Context) {
 	h.Respond(ctx, http.StatusOK, r)
 }
 
+// Head godoc
+// @summary Check an archetype exists.
+// @description Check an archetype exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags archetypes
+// @success 200
+// @success 304
+// @router /archetypes/{id} [head]
+// @param id path int true "Archetype ID"
+func (h ArchetypeHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.Archetype{})
+}
+
 // List godoc
 // @summary List all archetypes.
 // @description List all archetypes.
@@ -28,6 +28,7 @@ func (h AssessmentHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(AssessmentsRoot, h.List)
 	routeGroup.GET(AssessmentsRoot+"/", h.List)
 	routeGroup.GET(AssessmentRoot, h.Get)
+	routeGroup.HEAD(AssessmentRoot, h.Head)
 	routeGroup.PUT(AssessmentRoot, h.Update)
 	routeGroup.DELETE(AssessmentRoot, h.Delete)
 }
@@ -49,12 +50,26 @@ func (h AssessmentHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 	r := Assessment{}
 	r.With(m)
 
 	h.Respond(ctx, http.StatusOK, r)
 }
 
+// Head godoc
+// @summary Check an assessment exists.
+// @description Check an assessment exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags questionnaires
+// @success 200
+// @success 304
+// @router /assessments/{id} [head]
+// @param id path int true "Assessment ID"
+func (h AssessmentHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.Assessment{})
+}
+
 // List godoc
 // @summary List all assessments.
 // @description List all assessments.
@@ -150,7 +165,7 @@ type Assessment struct {
 	Resource          `yaml:",inline"`
 	Application       *Ref                 `json:"application,omitempty" yaml:",omitempty" binding:"excluded_with=Archetype"`
 	Archetype         *Ref                 `json:"archetype,omitempty" yaml:",omitempty" binding:"excluded_with=Application"`
-	Questionnaire     Ref                  `json:"questionnaire" binding:"required"`
+	Questionnaire     Ref                  `json:"questionnaire" binding:"required" ref:"questionnaire"`
 	Sections          []assessment.Section `json:"sections" binding:"dive"`
 	Stakeholders      []Ref                `json:"stakeholders"`
 	StakeholderGroups []Ref                `json:"stakeholderGroups" yaml:"stakeholderGroups"`
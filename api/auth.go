@@ -100,10 +100,15 @@ func Required(scope string) func(*gin.Context) {
 	return func(ctx *gin.Context) {
 		rtx := WithContext(ctx)
 		token := ctx.GetHeader(Authorization)
+		method := ctx.Request.Method
+		if method == http.MethodHead {
+			// authorized as GET.
+			method = http.MethodGet
+		}
 		request := &auth.Request{
 			Token:  token,
 			Scope:  scope,
-			Method: ctx.Request.Method,
+			Method: method,
 			DB:     rtx.DB,
 		}
 		result, err := request.Permit()
@@ -7,12 +7,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	liberr "github.com/jortel/go-utils/error"
 	"github.com/jortel/go-utils/logr"
 	"github.com/konveyor/tackle2-hub/api/reflect"
@@ -75,6 +77,41 @@ func (h *BaseHandler) WithCount(ctx *gin.Context, count int64) (err error) {
 	return
 }
 
+// FindPage finds the (requested) page of models.
+// The limit and offset are applied in SQL. The X-Total and
+// X-Total-Count headers are set to the (SQL) count of all
+// matched models. Returns an error when the count exceeds
+// the limit and is not constrained by pagination.
+func (h *BaseHandler) FindPage(ctx *gin.Context, db *gorm.DB, list interface{}) (err error) {
+	p := Page{}
+	p.With(ctx)
+	if p.Offset < 0 || p.Limit < 0 {
+		err = &BadRequestError{"?offset and ?limit must be >= 0."}
+		return
+	}
+	var n int64
+	err = h.DB(ctx).Table("(?) q", db).Count(&n).Error
+	if err != nil {
+		return
+	}
+	if n > MaxPage {
+		if p.Limit == 0 || p.Limit > MaxPage {
+			err = &BadRequestError{
+				fmt.Sprintf(
+					"Found=%d, ?Limit <= %d required.",
+					n,
+					MaxPage)}
+			return
+		}
+	}
+	s := strconv.FormatInt(n, 10)
+	mp := ctx.Writer.Header()
+	mp[Total] = []string{s}
+	mp[TotalCount] = []string{s}
+	err = p.Paginated(db).Find(list).Error
+	return
+}
+
 // preLoad update DB to pre-load fields.
 func (h *BaseHandler) preLoad(db *gorm.DB, fields ...string) (tx *gorm.DB) {
 	tx = db
@@ -164,7 +201,7 @@ func (h *BaseHandler) Bind(ctx *gin.Context, r interface{}) (err error) {
 	default:
 		err = &BadRequestError{"Bind: MIME not supported."}
 	}
-	if err != nil {
+	if err != nil && !errors.Is(err, &FieldError{}) {
 		err = &BadRequestError{err.Error()}
 	}
 	return
@@ -184,6 +221,10 @@ func (h *BaseHandler) BindJSON(ctx *gin.Context, r interface{}) (err error) {
 		err = liberr.Wrap(err)
 		return
 	}
+	err = h.resolve(ctx, r)
+	if err != nil {
+		return
+	}
 	err = h.Validate(r)
 	return
 }
@@ -202,6 +243,10 @@ func (h *BaseHandler) BindYAML(ctx *gin.Context, r interface{}) (err error) {
 		err = liberr.Wrap(err)
 		return
 	}
+	err = h.resolve(ctx, r)
+	if err != nil {
+		return
+	}
 	err = h.Validate(r)
 	return
 }
@@ -213,11 +258,148 @@ func (h *BaseHandler) Validate(r interface{}) (err error) {
 	}
 	err = binding.Validator.ValidateStruct(r)
 	if err != nil {
+		vErr := validator.ValidationErrors{}
+		if errors.As(err, &vErr) {
+			for _, fe := range vErr {
+				if fe.Field() == "ID" && strings.HasSuffix(fe.StructNamespace(), ".ID") {
+					err = &FieldError{
+						Field: h.jsonPath(fe.StructNamespace()),
+						Rule:  fe.Tag(),
+					}
+					return
+				}
+			}
+		}
 		err = liberr.Wrap(err)
 	}
 	return
 }
 
+// jsonPath returns the (json) field path for the struct namespace.
+// Example: Tracker.Identity.ID => identity.id
+func (h *BaseHandler) jsonPath(namespace string) (path string) {
+	part := strings.Split(namespace, ".")[1:]
+	for i := range part {
+		p := part[i]
+		if strings.ToUpper(p) == p {
+			part[i] = strings.ToLower(p)
+		} else {
+			part[i] = strings.ToLower(p[:1]) + p[1:]
+		}
+	}
+	path = strings.Join(part, ".")
+	return
+}
+
+// Resolvable models by (ref tag) kind.
+var Resolvable = map[string]func() interface{}{
+	"application":   func() interface{} { return &model.Application{} },
+	"identity":      func() interface{} { return &model.Identity{} },
+	"questionnaire": func() interface{} { return &model.Questionnaire{} },
+	"tag":           func() interface{} { return &model.Tag{} },
+	"tagCategory":   func() interface{} { return &model.TagCategory{} },
+	"tracker":       func() interface{} { return &model.Tracker{} },
+}
+
+// resolve Ref fields tagged with `ref:"<kind>"` by name.
+// Refs specified by name (ID = 0) are resolved to the ID
+// of the named resource.
+func (h *BaseHandler) resolve(ctx *gin.Context, r interface{}) (err error) {
+	for _, f := range reflect.Tagged(r, "ref") {
+		m, found := Resolvable[f.Tag]
+		if !found {
+			continue
+		}
+		var paths []string
+		var refs []*Ref
+		switch fv := f.Value.(type) {
+		case *Ref:
+			paths = append(paths, f.Path)
+			refs = append(refs, fv)
+		case **Ref:
+			if *fv != nil {
+				paths = append(paths, f.Path)
+				refs = append(refs, *fv)
+			}
+		case *[]Ref:
+			for i := range *fv {
+				paths = append(paths, f.Path+"."+strconv.Itoa(i))
+				refs = append(refs, &(*fv)[i])
+			}
+		}
+		for i, ref := range refs {
+			if ref.ID != 0 || ref.Name == "" {
+				continue
+			}
+			row := struct{ ID uint }{}
+			err = h.DB(ctx).Model(m()).Select("ID").Where("Name", ref.Name).Take(&row).Error
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					err = &FieldError{
+						Field: h.jsonPath(paths[i] + ".Name"),
+						Rule:  "exists",
+					}
+				}
+				return
+			}
+			ref.ID = row.ID
+		}
+	}
+	return
+}
+
+// VerifyRefs verifies that the Ref fields tagged with `ref:"<kind>"`
+// reference resources that exist. All of the fields are verified
+// and the fields not found are reported as FieldErrors.
+func (h *BaseHandler) VerifyRefs(ctx *gin.Context, r interface{}) (err error) {
+	fErrs := FieldErrors{}
+	for _, f := range reflect.Tagged(r, "ref") {
+		m, found := Resolvable[f.Tag]
+		if !found {
+			continue
+		}
+		var paths []string
+		var refs []*Ref
+		switch fv := f.Value.(type) {
+		case *Ref:
+			paths = append(paths, f.Path)
+			refs = append(refs, fv)
+		case **Ref:
+			if *fv != nil {
+				paths = append(paths, f.Path)
+				refs = append(refs, *fv)
+			}
+		case *[]Ref:
+			for i := range *fv {
+				paths = append(paths, f.Path+"."+strconv.Itoa(i))
+				refs = append(refs, &(*fv)[i])
+			}
+		}
+		for i, ref := range refs {
+			if ref.ID == 0 {
+				continue
+			}
+			var count int64
+			err = h.DB(ctx).Model(m()).Where("ID", ref.ID).Count(&count).Error
+			if err != nil {
+				return
+			}
+			if count == 0 {
+				fErrs = append(
+					fErrs,
+					FieldError{
+						Field: h.jsonPath(paths[i] + ".ID"),
+						Rule:  "exists",
+					})
+			}
+		}
+	}
+	if len(fErrs) > 0 {
+		err = fErrs
+	}
+	return
+}
+
 // Decoder returns a decoder based on encoding.
 // Opinionated towards json.
 func (h *BaseHandler) Decoder(ctx *gin.Context, encoding string, r io.Reader) (d Decoder, err error) {
@@ -253,6 +435,46 @@ func (h *BaseHandler) Respond(ctx *gin.Context, code int, r interface{}) {
 	rtx.Respond(code, r)
 }
 
+// Head responds to HEAD requests for the resource (by ID).
+// The ETag and Last-Modified headers are set using the
+// update time. Responds 304 when If-None-Match matches.
+func (h *BaseHandler) Head(ctx *gin.Context, m interface{}) {
+	id := h.pk(ctx)
+	row := struct {
+		ID         uint
+		UpdateTime time.Time
+	}{}
+	db := h.DB(ctx).Model(m)
+	db = db.Select("ID", "UpdateTime")
+	err := db.Take(&row, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	matched := h.Validators(
+		ctx,
+		&model.Model{
+			ID:         row.ID,
+			UpdateTime: row.UpdateTime,
+		})
+	if matched {
+		h.Status(ctx, http.StatusNotModified)
+		return
+	}
+	h.Status(ctx, http.StatusOK)
+}
+
+// Validators sets the ETag and Last-Modified headers using
+// the update time. Returns true when If-None-Match matches.
+func (h *BaseHandler) Validators(ctx *gin.Context, m *model.Model) (matched bool) {
+	tag := fmt.Sprintf("\"%d-%d\"", m.ID, m.UpdateTime.UnixNano())
+	header := ctx.Writer.Header()
+	header.Set(ETag, tag)
+	header.Set(LastModified, m.UpdateTime.UTC().Format(http.TimeFormat))
+	matched = ctx.GetHeader(IfNoneMatch) == tag
+	return
+}
+
 // Accepted determines if the mime is accepted.
 // Wildcards ignored.
 func (h *BaseHandler) Accepted(ctx *gin.Context, mimes ...string) (b bool) {
@@ -5,15 +5,23 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // Routes
 const (
-	BatchRoot        = "/batch"
-	BatchTicketsRoot = BatchRoot + TicketsRoot
-	BatchTagsRoot    = BatchRoot + TagsRoot
+	BatchRoot         = "/batch"
+	BatchTicketsRoot  = BatchRoot + TicketsRoot
+	BatchTagsRoot     = BatchRoot + TagsRoot
+	BatchTrackersRoot = BatchRoot + TrackersRoot
+)
+
+// Params
+const (
+	Atomic = "atomic"
 )
 
 // BatchHandler handles batch resource creation routes.
@@ -24,18 +32,24 @@ type BatchHandler struct {
 // AddRoutes adds routes.
 func (h BatchHandler) AddRoutes(e *gin.Engine) {
 	routeGroup := e.Group("/")
-	routeGroup.POST(BatchTicketsRoot, Required("tickets"), Transaction, h.TicketsCreate)
-	routeGroup.POST(BatchTagsRoot, Required("tags"), Transaction, h.TagsCreate)
+	routeGroup.POST(BatchTicketsRoot, Required("tickets"), BatchTransaction, h.TicketsCreate)
+	routeGroup.POST(BatchTagsRoot, Required("tags"), BatchTransaction, h.TagsCreate)
+	routeGroup.POST(BatchTrackersRoot, Required("trackers"), BatchTransaction, h.TrackersCreate)
+	routeGroup.DELETE(BatchTrackersRoot, Required("trackers"), BatchTransaction, h.TrackersDelete)
 }
 
 // TicketsCreate godoc
 // @summary Batch-create Tickets.
 // @description Batch-create Tickets.
+// @description When ?atomic=false, each ticket is created independently
+// @description and the result is reported using 207 (Multi-Status).
 // @tags batch, tickets
 // @produce json
 // @success 200 {object} []api.Ticket
+// @success 207 {object} []api.BatchResult
 // @router /batch/tickets [post]
 // @param tickets body []api.Ticket true "Tickets data"
+// @param atomic query bool false "All-or-nothing (default: true)"
 func (h BatchHandler) TicketsCreate(ctx *gin.Context) {
 	handler := TicketHandler{}
 	h.create(ctx, handler.Create)
@@ -44,16 +58,54 @@ func (h BatchHandler) TicketsCreate(ctx *gin.Context) {
 // TagsCreate godoc
 // @summary Batch-create Tags.
 // @description Batch-create Tags.
+// @description When ?atomic=false, each tag is created independently
+// @description and the result is reported using 207 (Multi-Status).
 // @tags batch, tags
 // @produce json
 // @success 200 {object} []api.Tag
+// @success 207 {object} []api.BatchResult
 // @router /batch/tags [post]
 // @param tags body []api.Tag true "Tags data"
+// @param atomic query bool false "All-or-nothing (default: true)"
 func (h BatchHandler) TagsCreate(ctx *gin.Context) {
 	handler := TagHandler{}
 	h.create(ctx, handler.Create)
 }
 
+// TrackersCreate godoc
+// @summary Batch-create Trackers.
+// @description Batch-create Trackers.
+// @description When ?atomic=false, each tracker is created independently
+// @description and the result is reported using 207 (Multi-Status).
+// @tags batch, trackers
+// @produce json
+// @success 200 {object} []api.Tracker
+// @success 207 {object} []api.BatchResult
+// @router /batch/trackers [post]
+// @param trackers body []api.Tracker true "Trackers data"
+// @param atomic query bool false "All-or-nothing (default: true)"
+func (h BatchHandler) TrackersCreate(ctx *gin.Context) {
+	handler := TrackerHandler{}
+	h.create(ctx, handler.Create)
+}
+
+// TrackersDelete godoc
+// @summary Batch-delete Trackers.
+// @description Batch-delete Trackers.
+// @description When ?atomic=false, each tracker is deleted independently
+// @description and the result is reported using 207 (Multi-Status).
+// @tags batch, trackers
+// @success 204
+// @success 207 {object} []api.BatchResult
+// @router /batch/trackers [delete]
+// @param ids body []uint true "Tracker IDs"
+// @param atomic query bool false "All-or-nothing (default: true)"
+func (h BatchHandler) TrackersDelete(ctx *gin.Context) {
+	handler := TrackerHandler{}
+	h.delete(ctx, handler.Delete)
+}
+
+// create resources using the create handler.
 func (h BatchHandler) create(ctx *gin.Context, create gin.HandlerFunc) {
 	var resources []interface{}
 	err := h.Bind(ctx, &resources)
@@ -61,24 +113,30 @@ func (h BatchHandler) create(ctx *gin.Context, create gin.HandlerFunc) {
 		_ = ctx.Error(err)
 		return
 	}
-
-	rtx := WithContext(ctx)
+	results := h.each(
+		ctx,
+		len(resources),
+		func(i int) {
+			b, _ := json.Marshal(resources[i])
+			bfr := bytes.NewBuffer(b)
+			ctx.Request.Body = io.NopCloser(bfr)
+		},
+		create)
+	if !h.atomic(ctx) {
+		h.Respond(ctx, http.StatusMultiStatus, results)
+		return
+	}
 	bErr := BatchError{Message: "Create failed."}
-	for i := range resources {
-		b, _ := json.Marshal(resources[i])
-		bfr := bytes.NewBuffer(b)
-		ctx.Request.Body = io.NopCloser(bfr)
-		create(ctx)
-		if len(ctx.Errors) > 0 {
-			err = ctx.Errors[0]
+	for i := range results {
+		result := &results[i]
+		if result.err != nil {
 			bErr.Items = append(bErr.Items, BatchErrorItem{
-				Error:    err,
+				Error:    result.err,
 				Resource: resources[i],
 			})
-			ctx.Errors = nil
 			continue
 		}
-		resources[i] = rtx.Response.Body
+		resources[i] = result.Body
 	}
 	if len(bErr.Items) == 0 {
 		h.Respond(ctx, http.StatusCreated, resources)
@@ -86,3 +144,136 @@ func (h BatchHandler) create(ctx *gin.Context, create gin.HandlerFunc) {
 		_ = ctx.Error(bErr)
 	}
 }
+
+// delete resources (by id) using the delete handler.
+func (h BatchHandler) delete(ctx *gin.Context, delete gin.HandlerFunc) {
+	var ids []uint
+	err := h.Bind(ctx, &ids)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	params := ctx.Params
+	results := h.each(
+		ctx,
+		len(ids),
+		func(i int) {
+			ctx.Params = append(
+				params,
+				gin.Param{
+					Key:   ID,
+					Value: strconv.Itoa(int(ids[i])),
+				})
+		},
+		delete)
+	ctx.Params = params
+	if !h.atomic(ctx) {
+		h.Respond(ctx, http.StatusMultiStatus, results)
+		return
+	}
+	bErr := BatchError{Message: "Delete failed."}
+	for i := range results {
+		result := &results[i]
+		if result.err != nil {
+			bErr.Items = append(bErr.Items, BatchErrorItem{
+				Error:    result.err,
+				Resource: ids[i],
+			})
+		}
+	}
+	if len(bErr.Items) == 0 {
+		h.Status(ctx, http.StatusNoContent)
+	} else {
+		_ = ctx.Error(bErr)
+	}
+}
+
+// each calls the handler for each of (n) items.
+// The prepare function updates the request for the item
+// before the handler is called. When not atomic, each item
+// is processed within its own transaction.
+func (h BatchHandler) each(
+	ctx *gin.Context,
+	n int,
+	prepare func(int),
+	handler gin.HandlerFunc) (results []BatchResult) {
+	//
+	rtx := WithContext(ctx)
+	atomic := h.atomic(ctx)
+	results = []BatchResult{}
+	for i := 0; i < n; i++ {
+		prepare(i)
+		rtx.Response = Response{}
+		if atomic {
+			handler(ctx)
+		} else {
+			h.transaction(ctx, handler)
+		}
+		result := BatchResult{}
+		if len(ctx.Errors) > 0 {
+			err := ctx.Errors[0]
+			result.Status, result.Body = ErrorResponse(ctx, err)
+			if result.Status >= http.StatusBadRequest {
+				result.err = err
+			}
+			ctx.Errors = nil
+		} else {
+			result.Status = rtx.Response.Status
+			result.Body = rtx.Response.Body
+		}
+		results = append(results, result)
+	}
+	rtx.Response = Response{}
+	return
+}
+
+// transaction calls the handler within a transaction.
+// The transaction is rolled back when the handler reports
+// an error. The errors are retained for reporting.
+func (h BatchHandler) transaction(ctx *gin.Context, handler gin.HandlerFunc) {
+	rtx := WithContext(ctx)
+	db := rtx.DB
+	_ = db.Transaction(func(tx *gorm.DB) (err error) {
+		rtx.DB = tx
+		rtx.Transaction = true
+		handler(ctx)
+		if len(ctx.Errors) > 0 {
+			err = ctx.Errors[0]
+		}
+		return
+	})
+	rtx.Transaction = false
+	rtx.DB = db
+}
+
+// atomic returns true unless ?atomic=false.
+func (h BatchHandler) atomic(ctx *gin.Context) (b bool) {
+	b = true
+	s := ctx.Query(Atomic)
+	if s != "" {
+		parsed, err := strconv.ParseBool(s)
+		if err == nil {
+			b = parsed
+		}
+	}
+	return
+}
+
+// BatchTransaction handler.
+// Batch operations are performed within a transaction
+// unless ?atomic=false.
+func BatchTransaction(ctx *gin.Context) {
+	h := BatchHandler{}
+	if h.atomic(ctx) {
+		Transaction(ctx)
+	}
+}
+
+// BatchResult REST resource.
+// Reports the status of an individual item in a
+// (non-atomic) batch operation.
+type BatchResult struct {
+	Status int         `json:"status"`
+	Body   interface{} `json:"body,omitempty"`
+	err    error
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestBatchAtomic(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	cases := []struct {
+		query  string
+		atomic bool
+	}{
+		{query: "", atomic: true},
+		{query: "?atomic=true", atomic: true},
+		{query: "?atomic=1", atomic: true},
+		{query: "?atomic=false", atomic: false},
+		{query: "?atomic=0", atomic: false},
+		{query: "?atomic=invalid", atomic: true},
+	}
+	h := BatchHandler{}
+	for _, c := range cases {
+		req, _ := http.NewRequest(http.MethodPost, BatchTagsRoot+c.query, nil)
+		ctx := &gin.Context{Request: req}
+		g.Expect(h.atomic(ctx)).To(gomega.Equal(c.atomic), c.query)
+	}
+}
+
+func TestBatchEach(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	category := &model.TagCategory{Name: "c"}
+	g.Expect(db.Create(category).Error).To(gomega.BeNil())
+	names := []string{"a", "b", "c"}
+	var transaction []bool
+	var name string
+	// Creates the tag and then fails for "b".
+	handler := func(ctx *gin.Context) {
+		rtx := WithContext(ctx)
+		transaction = append(transaction, rtx.Transaction)
+		err := rtx.DB.Create(&model.Tag{Name: name, CategoryID: category.ID}).Error
+		if err == nil && name == "b" {
+			err = &BadRequestError{"failed."}
+		}
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		rtx.Respond(http.StatusCreated, nil)
+	}
+
+	h := BatchHandler{}
+	e := newEngine(db)
+	var results []BatchResult
+	e.POST(BatchTagsRoot, func(ctx *gin.Context) {
+		results = h.each(
+			ctx,
+			len(names),
+			func(i int) {
+				name = names[i]
+			},
+			handler)
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, BatchTagsRoot+"?atomic=false", nil)
+	e.ServeHTTP(w, req)
+	g.Expect(len(results)).To(gomega.Equal(3))
+	g.Expect(results[0].Status).To(gomega.Equal(http.StatusCreated))
+	g.Expect(results[1].Status).To(gomega.Equal(http.StatusBadRequest))
+	g.Expect(errors.Is(results[1].err, &BadRequestError{})).To(gomega.BeTrue())
+	g.Expect(results[2].Status).To(gomega.Equal(http.StatusCreated))
+	g.Expect(transaction).To(gomega.Equal([]bool{true, true, true}))
+	var created []model.Tag
+	g.Expect(db.Order("Name").Find(&created).Error).To(gomega.BeNil())
+	g.Expect(len(created)).To(gomega.Equal(2))
+	g.Expect(created[0].Name).To(gomega.Equal("a"))
+	g.Expect(created[1].Name).To(gomega.Equal("c"))
+}
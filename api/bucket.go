@@ -2,9 +2,14 @@ package api
 
 import (
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
 	pathlib "path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -21,6 +26,19 @@ const (
 	BucketContentRoot = BucketRoot + "/*" + Wildcard
 )
 
+// Params
+const (
+	RecursiveParam = "recursive"
+	ArchiveParam   = "archive"
+)
+
+// Bucket entry types.
+const (
+	BucketEntryFile = "file"
+	BucketEntryDir  = "dir"
+	BucketEntryLink = "link"
+)
+
 // BucketHandler handles bucket routes.
 type BucketHandler struct {
 	BucketOwner
@@ -34,6 +52,7 @@ func (h BucketHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(BucketsRoot+"/", h.List)
 	routeGroup.POST(BucketsRoot, h.Create)
 	routeGroup.GET(BucketRoot, h.Get)
+	routeGroup.HEAD(BucketRoot, h.Head)
 	routeGroup.DELETE(BucketRoot, h.Delete)
 	routeGroup.POST(BucketContentRoot, h.BucketPut)
 	routeGroup.PUT(BucketContentRoot, h.BucketPut)
@@ -106,6 +125,7 @@ func (h BucketHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 	if h.Accepted(ctx, BindMIMEs...) {
 		r := Bucket{}
 		r.With(m)
@@ -115,6 +135,19 @@ func (h BucketHandler) Get(ctx *gin.Context) {
 	h.bucketGet(ctx, id)
 }
 
+// Head godoc
+// @summary Check a bucket exists.
+// @description Check a bucket exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags buckets
+// @success 200
+// @success 304
+// @router /buckets/{id} [head]
+// @param id path int true "Bucket ID"
+func (h BucketHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.Bucket{})
+}
+
 // Delete godoc
 // @summary Delete a bucket.
 // @description Delete a bucket.
@@ -151,29 +184,54 @@ func (h BucketHandler) Delete(ctx *gin.Context) {
 // @description Get bucket content by ID and path.
 // @description When path is FILE, returns file content.
 // @description When path is DIRECTORY and Accept=text/html returns index.html.
+// @description When path is DIRECTORY and ends with '/', returns the directory listing.
+// @description Use ?recursive=true to list the entries of subdirectories.
 // @description ?filter=glob supports directory content filtering.
 // @description Else returns a tarball.
+// @description When ?archive=true, returns a tarball of the DIRECTORY. See: archiveGet().
 // @tags buckets
 // @produce octet-stream
-// @success 200
+// @success 200 {object} []api.BucketEntry
 // @router /buckets/{id}/{wildcard} [get]
 // @param id path int true "Task ID"
 // @param wildcard path string true "Content path"
 // @param filter query string false "Filter"
+// @param recursive query bool false "Recursive listing"
+// @param archive query bool false "Archive mode"
 func (h BucketHandler) BucketGet(ctx *gin.Context) {
+	archive, err := h.archive(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if archive {
+		h.archiveGet(ctx, h.pk(ctx))
+		return
+	}
 	h.bucketGet(ctx, h.pk(ctx))
 }
 
 // BucketPut godoc
 // @summary Upload bucket content by ID and path.
 // @description Upload bucket content by ID and path (handles both [post] and [put] requests).
+// @description When ?archive=true, the tarball (tar.gz) replaces the DIRECTORY content. See: archivePut().
 // @tags buckets
 // @produce json
 // @success 204
 // @router /buckets/{id}/{wildcard} [post]
 // @param id path int true "Bucket ID"
 // @param wildcard path string true "Content path"
+// @param archive query bool false "Archive mode"
 func (h BucketHandler) BucketPut(ctx *gin.Context) {
+	archive, err := h.archive(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if archive {
+		h.archivePut(ctx, h.pk(ctx))
+		return
+	}
 	h.bucketPut(ctx, h.pk(ctx))
 }
 
@@ -204,6 +262,33 @@ func (r *Bucket) With(m *model.Bucket) {
 	r.Expiration = m.Expiration
 }
 
+// BucketEntry (directory listing) REST resource.
+// The path is relative to the listed directory.
+type BucketEntry struct {
+	Path    string    `json:"path"`
+	Name    string    `json:"name"`
+	Type    string    `json:"type"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mtime"`
+}
+
+// With updates the resource with the file info.
+func (r *BucketEntry) With(path string, info fs.FileInfo) {
+	r.Path = filepath.ToSlash(path)
+	r.Name = info.Name()
+	r.Size = info.Size()
+	r.ModTime = info.ModTime()
+	switch {
+	case info.Mode()&fs.ModeSymlink != 0:
+		r.Type = BucketEntryLink
+	case info.IsDir():
+		r.Type = BucketEntryDir
+		r.Size = 0
+	default:
+		r.Type = BucketEntryFile
+	}
+}
+
 type BucketOwner struct {
 	BaseHandler
 }
@@ -212,6 +297,7 @@ type BucketOwner struct {
 // When path is DIRECTORY:
 //
 //	Accept=text/html return body is index.html.
+//	Path ends with '/' returns the listing.
 //	Else streams tarball.
 //
 // When path is FILE:
@@ -236,6 +322,8 @@ func (h *BucketOwner) bucketGet(ctx *gin.Context, id uint) {
 		filter.Include(ctx.Query(Filter))
 		if h.Accepted(ctx, binding.MIMEHTML) {
 			h.getFile(ctx, m)
+		} else if strings.HasSuffix(ctx.Param(Wildcard), "/") {
+			h.listDir(ctx, path, filter)
 		} else {
 			h.getDir(ctx, path, filter)
 		}
@@ -311,6 +399,130 @@ func (h *BucketOwner) putDir(ctx *gin.Context, output string) (err error) {
 	return
 }
 
+// archive returns the (optional) archive mode parameter.
+func (h *BucketOwner) archive(ctx *gin.Context) (b bool, err error) {
+	s := ctx.Query(ArchiveParam)
+	if s == "" {
+		return
+	}
+	b, err = strconv.ParseBool(s)
+	if err != nil {
+		err = &BadRequestError{ArchiveParam + ": '" + s + "' must be a boolean."}
+		return
+	}
+	return
+}
+
+// archiveGet streams a tarball (tar.gz) of the bucket directory.
+// ?filter=glob supports directory content filtering.
+func (h *BucketOwner) archiveGet(ctx *gin.Context, id uint) {
+	m := &model.Bucket{}
+	err := h.DB(ctx).First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	path := h.archivePath(ctx, m)
+	st, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			h.Status(ctx, http.StatusNotFound)
+		} else {
+			_ = ctx.Error(err)
+		}
+		return
+	}
+	if !st.IsDir() {
+		err = &BadRequestError{"path: '" + ctx.Param(Wildcard) + "' must be a directory."}
+		_ = ctx.Error(err)
+		return
+	}
+	filter := tar.NewFilter(path)
+	filter.Include(ctx.Query(Filter))
+	h.getDir(ctx, path, filter)
+}
+
+// archivePut replaces the bucket directory with the content of
+// the uploaded tarball (tar.gz). The tarball is the (multipart) file
+// field or else the request body. The tarball is extracted into a
+// (temporary) sibling directory which replaces the directory only
+// when successfully extracted.
+func (h *BucketOwner) archivePut(ctx *gin.Context, id uint) {
+	m := &model.Bucket{}
+	err := h.DB(ctx).First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var reader io.Reader = ctx.Request.Body
+	if ctx.ContentType() == binding.MIMEMultipartPOSTForm {
+		file, fErr := ctx.FormFile(FileField)
+		if fErr != nil {
+			err = &BadRequestError{fErr.Error()}
+			_ = ctx.Error(err)
+			return
+		}
+		fileReader, fErr := file.Open()
+		if fErr != nil {
+			err = &BadRequestError{fErr.Error()}
+			_ = ctx.Error(err)
+			return
+		}
+		defer func() {
+			_ = fileReader.Close()
+		}()
+		reader = fileReader
+	}
+	path := h.archivePath(ctx, m)
+	parent := pathlib.Dir(path)
+	err = os.MkdirAll(parent, 0777)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	tmpDir, err := os.MkdirTemp(parent, "."+pathlib.Base(path)+"-")
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+	tarReader := tar.NewReader()
+	err = tarReader.Extract(pathlib.Join(tmpDir, "new"), reader)
+	if err != nil {
+		err = &BadRequestError{err.Error()}
+		_ = ctx.Error(err)
+		return
+	}
+	old := pathlib.Join(tmpDir, "old")
+	err = os.Rename(path, old)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			_ = ctx.Error(err)
+			return
+		}
+		old = ""
+	}
+	err = os.Rename(pathlib.Join(tmpDir, "new"), path)
+	if err != nil {
+		if old != "" {
+			_ = os.Rename(old, path)
+		}
+		_ = ctx.Error(err)
+		return
+	}
+
+	h.Status(ctx, http.StatusNoContent)
+}
+
+// archivePath returns the (archive) directory path.
+// The path is constrained to the bucket.
+func (h *BucketOwner) archivePath(ctx *gin.Context, m *model.Bucket) (path string) {
+	path = pathlib.Join(m.Path, pathlib.Clean("/"+ctx.Param(Wildcard)))
+	return
+}
+
 // getDir reads a directory from the bucket.
 func (h *BucketOwner) getDir(ctx *gin.Context, input string, filter tar.Filter) {
 	tarWriter := tar.NewWriter(ctx.Writer)
@@ -330,6 +542,63 @@ func (h *BucketOwner) getDir(ctx *gin.Context, input string, filter tar.Filter)
 	return
 }
 
+// listDir lists the directory entries.
+// Entries of subdirectories are listed when recursive.
+func (h *BucketOwner) listDir(ctx *gin.Context, input string, filter tar.Filter) {
+	recursive := false
+	s := ctx.Query(RecursiveParam)
+	if s != "" {
+		var err error
+		recursive, err = strconv.ParseBool(s)
+		if err != nil {
+			err = &BadRequestError{RecursiveParam + ": '" + s + "' must be a boolean."}
+			_ = ctx.Error(err)
+			return
+		}
+	}
+	resources := []BucketEntry{}
+	err := filepath.WalkDir(
+		input,
+		func(path string, entry fs.DirEntry, wErr error) (err error) {
+			if wErr != nil {
+				err = wErr
+				return
+			}
+			if path == input {
+				return
+			}
+			if entry.IsDir() && !recursive {
+				err = filepath.SkipDir
+			}
+			if !filter.Match(path) {
+				return
+			}
+			info, iErr := entry.Info()
+			if iErr != nil {
+				return
+			}
+			relative, rErr := filepath.Rel(input, path)
+			if rErr != nil {
+				return
+			}
+			r := BucketEntry{}
+			r.With(relative, info)
+			resources = append(resources, r)
+			return
+		})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	sort.Slice(
+		resources,
+		func(i, j int) bool {
+			return resources[i].Path < resources[j].Path
+		})
+
+	h.Respond(ctx, http.StatusOK, resources)
+}
+
 // getFile reads a file from the bucket.
 func (h *BucketOwner) getFile(ctx *gin.Context, m *model.Bucket) {
 	rPath := ctx.Param(Wildcard)
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestBucketList(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	m := &model.Bucket{}
+	g.Expect(db.Create(m).Error).To(gomega.BeNil())
+	g.Expect(os.MkdirAll(path.Join(m.Path, "output", "rules"), 0777)).To(gomega.BeNil())
+	for name, content := range map[string]string{
+		"output/report.yaml":     "issues: []",
+		"output/rules/rule.yaml": "rule",
+		"output/a.json":          "{}",
+	} {
+		g.Expect(os.WriteFile(path.Join(m.Path, name), []byte(content), 0666)).To(gomega.BeNil())
+	}
+
+	h := BucketHandler{}
+	e := newEngine(db)
+	e.GET(BucketContentRoot, h.BucketGet)
+	list := func(path string) (w *httptest.ResponseRecorder, entries []BucketEntry) {
+		w = httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		_ = json.Unmarshal(w.Body.Bytes(), &entries)
+		return
+	}
+	// directory.
+	w, entries := list("/buckets/1/output/")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(entries).To(gomega.HaveLen(3))
+	g.Expect(entries[0].Path).To(gomega.Equal("a.json"))
+	g.Expect(entries[1].Size).To(gomega.Equal(int64(10)))
+	g.Expect(entries[2].Path).To(gomega.Equal("rules"))
+	g.Expect(entries[2].Type).To(gomega.Equal(BucketEntryDir))
+	// recursive.
+	_, entries = list("/buckets/1/?recursive=true")
+	g.Expect(entries).To(gomega.HaveLen(5))
+	g.Expect(entries[4].Path).To(gomega.Equal("output/rules/rule.yaml"))
+	g.Expect(entries[4].Type).To(gomega.Equal(BucketEntryFile))
+	// filtered.
+	_, entries = list("/buckets/1/output/?filter=*.yaml")
+	g.Expect(entries).To(gomega.HaveLen(1))
+	g.Expect(entries[0].Name).To(gomega.Equal("report.yaml"))
+	// invalid.
+	w, _ = list("/buckets/1/output/?recursive=x")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	// not a listing.
+	w, _ = list("/buckets/1/output")
+	g.Expect(w.Header().Get(Directory)).To(gomega.Equal(DirectoryExpand))
+}
+
+func TestBucketArchive(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	m := &model.Bucket{}
+	g.Expect(db.Create(m).Error).To(gomega.BeNil())
+	g.Expect(os.MkdirAll(path.Join(m.Path, "output", "rules"), 0777)).To(gomega.BeNil())
+	g.Expect(os.WriteFile(path.Join(m.Path, "output/rules/rule.yaml"), []byte("rule"), 0666)).To(gomega.BeNil())
+
+	h := BucketHandler{}
+	e := newEngine(db)
+	e.GET(BucketContentRoot, h.BucketGet)
+	e.PUT(BucketContentRoot, h.BucketPut)
+	send := func(method, path string, body []byte) (w *httptest.ResponseRecorder) {
+		w = httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewReader(body)))
+		return
+	}
+	// download.
+	w := send(http.MethodGet, "/buckets/1/output?archive=true", nil)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(w.Header().Get(Directory)).To(gomega.Equal(DirectoryExpand))
+	archive := w.Body.Bytes()
+	// upload.
+	g.Expect(os.MkdirAll(path.Join(m.Path, "copy"), 0777)).To(gomega.BeNil())
+	g.Expect(os.WriteFile(path.Join(m.Path, "copy/stale.yaml"), []byte("stale"), 0666)).To(gomega.BeNil())
+	w = send(http.MethodPut, "/buckets/1/copy?archive=true", archive)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	b, err := os.ReadFile(path.Join(m.Path, "copy/rules/rule.yaml"))
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(string(b)).To(gomega.Equal("rule"))
+	_, err = os.Stat(path.Join(m.Path, "copy/stale.yaml"))
+	g.Expect(os.IsNotExist(err)).To(gomega.BeTrue())
+	// content path named archive not reserved.
+	g.Expect(os.WriteFile(path.Join(m.Path, "archive"), []byte("content"), 0666)).To(gomega.BeNil())
+	w = send(http.MethodGet, "/buckets/1/archive", nil)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(w.Body.String()).To(gomega.Equal("content"))
+	// constrained to the bucket.
+	w = send(http.MethodGet, "/buckets/1/../../copy?archive=true", nil)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	// not found.
+	w = send(http.MethodGet, "/buckets/1/missing?archive=true", nil)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNotFound))
+	// not a directory.
+	w = send(http.MethodGet, "/buckets/1/copy/rules/rule.yaml?archive=true", nil)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	// invalid.
+	w = send(http.MethodGet, "/buckets/1/copy?archive=x", nil)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	// corrupt upload: content preserved.
+	w = send(http.MethodPut, "/buckets/1/copy?archive=true", archive[:len(archive)/2])
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	_, err = os.Stat(path.Join(m.Path, "copy/rules/rule.yaml"))
+	g.Expect(err).To(gomega.BeNil())
+	// entry not within the destination.
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(zw)
+	g.Expect(tw.WriteHeader(&tar.Header{Name: "../escaped", Mode: 0666, Size: 1})).To(gomega.BeNil())
+	_, _ = tw.Write([]byte("x"))
+	_ = tw.Close()
+	_ = zw.Close()
+	w = send(http.MethodPut, "/buckets/1/copy?archive=true", buf.Bytes())
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	_, err = os.Stat(path.Join(m.Path, "escaped"))
+	g.Expect(os.IsNotExist(err)).To(gomega.BeTrue())
+	_, err = os.Stat(path.Join(m.Path, "copy/rules/rule.yaml"))
+	g.Expect(err).To(gomega.BeNil())
+}
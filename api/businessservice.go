@@ -27,6 +27,7 @@ func (h BusinessServiceHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(BusinessServicesRoot+"/", h.List)
 	routeGroup.POST(BusinessServicesRoot, h.Create)
 	routeGroup.GET(BusinessServiceRoot, h.Get)
+	routeGroup.HEAD(BusinessServiceRoot, h.Head)
 	routeGroup.PUT(BusinessServiceRoot, h.Update)
 	routeGroup.DELETE(BusinessServiceRoot, h.Delete)
 }
@@ -48,12 +49,26 @@ func (h BusinessServiceHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 
 	resource := BusinessService{}
 	resource.With(m)
 	h.Respond(ctx, http.StatusOK, resource)
 }
 
+// Head godoc
+// @summary Check a business service exists.
+// @description Check a business service exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags businessservices
+// @success 200
+// @success 304
+// @router /businessservices/{id} [head]
+// @param id path int true "Business Service ID"
+func (h BusinessServiceHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.BusinessService{})
+}
+
 // List godoc
 // @summary List all business services.
 // @description List all business services.
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"context"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/konveyor/tackle2-hub/auth"
@@ -22,6 +25,8 @@ type Context struct {
 	Client client.Client
 	// Response
 	Response Response
+	// Transaction in progress.
+	Transaction bool
 }
 
 // Response values.
@@ -71,7 +76,9 @@ func Transaction(ctx *gin.Context) {
 		err := rtx.DB.Transaction(func(tx *gorm.DB) (err error) {
 			db := rtx.DB
 			rtx.DB = tx
+			rtx.Transaction = true
 			ctx.Next()
+			rtx.Transaction = false
 			rtx.DB = db
 			if len(ctx.Errors) > 0 {
 				err = ctx.Errors[0]
@@ -85,6 +92,38 @@ func Transaction(ctx *gin.Context) {
 	}
 }
 
+// Timeout handler.
+// Sets the DB (context) deadline when the X-Query-Timeout header
+// (seconds or duration) is specified. Bounded by the server max.
+func Timeout(ctx *gin.Context) {
+	header := ctx.GetHeader(QueryTimeout)
+	if header == "" {
+		return
+	}
+	timeout, err := time.ParseDuration(header)
+	if err != nil {
+		n, nErr := strconv.Atoi(header)
+		if nErr != nil || n < 0 {
+			err = &BadRequestError{QueryTimeout + ": must be seconds or a duration."}
+			_ = ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+		timeout = time.Duration(n) * time.Second
+	}
+	maxTimeout := time.Duration(Settings.Hub.DB.MaxTimeout) * time.Second
+	if timeout > maxTimeout {
+		timeout = maxTimeout
+	}
+	rtx := WithContext(ctx)
+	deadline, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
+	defer cancel()
+	db := rtx.DB
+	rtx.DB = db.WithContext(deadline)
+	ctx.Next()
+	rtx.DB = db
+}
+
 // Render renders the response based on the Accept: header.
 // Opinionated towards json.
 func Render() gin.HandlerFunc {
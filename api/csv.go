@@ -0,0 +1,164 @@
+package api
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Params
+const (
+	ColumnsParam = "columns"
+)
+
+// CSV columns.
+var (
+	IssueColumns = []string{
+		"id",
+		"ruleset",
+		"rule",
+		"name",
+		"description",
+		"category",
+		"effort",
+		"labels",
+		"links",
+	}
+	IncidentColumns = []string{
+		"id",
+		"file",
+		"line",
+		"message",
+	}
+	DepColumns = []string{
+		"id",
+		"provider",
+		"name",
+		"version",
+		"sha",
+		"indirect",
+		"labels",
+	}
+)
+
+// CsvTable resources to be rendered as CSV.
+// The rows are keyed by column.
+type CsvTable struct {
+	Columns []string
+	Rows    []map[string]string
+}
+
+// Add a row.
+func (r *CsvTable) Add(row map[string]string) {
+	r.Rows = append(r.Rows, row)
+}
+
+// Select the columns specified by the (optional) columns
+// parameter (comma separated) and in that order.
+func (r *CsvTable) Select(ctx *gin.Context) (err error) {
+	param := ctx.Query(ColumnsParam)
+	if param == "" {
+		return
+	}
+	known := map[string]string{}
+	for _, name := range r.Columns {
+		known[strings.ToLower(name)] = name
+	}
+	selected := []string{}
+	for _, name := range strings.Split(param, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		column, found := known[strings.ToLower(name)]
+		if !found {
+			err = &BadRequestError{
+				"columns: '" + name + "' must be (" + strings.Join(r.Columns, "|") + ").",
+			}
+			return
+		}
+		selected = append(selected, column)
+	}
+	if len(selected) > 0 {
+		r.Columns = selected
+	}
+	return
+}
+
+// writeCSV writes the table as CSV.
+// Columns are selected by the columns parameter. Invalid
+// columns are reported as text since the (error) resource
+// cannot be rendered as CSV.
+func (h *BaseHandler) writeCSV(ctx *gin.Context, table *CsvTable) {
+	err := table.Select(ctx)
+	if err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx.Header(ContentType, CSV)
+	ctx.Status(http.StatusOK)
+	writer := csv.NewWriter(ctx.Writer)
+	_ = writer.Write(table.Columns)
+	for _, row := range table.Rows {
+		record := make([]string, 0, len(table.Columns))
+		for _, name := range table.Columns {
+			record = append(record, row[name])
+		}
+		_ = writer.Write(record)
+	}
+	writer.Flush()
+	err = writer.Error()
+	if err != nil {
+		_ = ctx.Error(err)
+	}
+}
+
+// csvRow returns the issue CSV row.
+// Labels and link URLs are joined using ';'.
+func (r *Issue) csvRow() (row map[string]string) {
+	links := []string{}
+	for _, link := range r.Links {
+		links = append(links, link.URL)
+	}
+	row = map[string]string{
+		"id":          strconv.Itoa(int(r.ID)),
+		"ruleset":     r.RuleSet,
+		"rule":        r.Rule,
+		"name":        r.Name,
+		"description": r.Description,
+		"category":    r.Category,
+		"effort":      strconv.Itoa(r.Effort),
+		"labels":      strings.Join(r.Labels, ";"),
+		"links":       strings.Join(links, ";"),
+	}
+	return
+}
+
+// csvRow returns the incident CSV row.
+func (r *Incident) csvRow() (row map[string]string) {
+	row = map[string]string{
+		"id":      strconv.Itoa(int(r.ID)),
+		"file":    r.File,
+		"line":    strconv.Itoa(r.Line),
+		"message": r.Message,
+	}
+	return
+}
+
+// csvRow returns the dependency CSV row.
+// Labels are joined using ';'.
+func (r *TechDependency) csvRow() (row map[string]string) {
+	row = map[string]string{
+		"id":       strconv.Itoa(int(r.ID)),
+		"provider": r.Provider,
+		"name":     r.Name,
+		"version":  r.Version,
+		"sha":      r.SHA,
+		"indirect": strconv.FormatBool(r.Indirect),
+		"labels":   strings.Join(r.Labels, ";"),
+	}
+	return
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/konveyor/tackle2-hub/model"
@@ -12,6 +13,18 @@ import (
 const (
 	DependenciesRoot = "/dependencies"
 	DependencyRoot   = DependenciesRoot + "/:" + ID
+	AppGraphRoot     = ApplicationRoot + "/graph"
+)
+
+// Params
+const (
+	Depth = "depth"
+)
+
+// Graph directions.
+const (
+	North = "north"
+	South = "south"
 )
 
 // DependencyHandler handles application dependency routes.
@@ -27,7 +40,9 @@ func (h DependencyHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(DependenciesRoot+"/", h.List)
 	routeGroup.POST(DependenciesRoot, h.Create)
 	routeGroup.GET(DependencyRoot, h.Get)
+	routeGroup.HEAD(DependencyRoot, h.Head)
 	routeGroup.DELETE(DependencyRoot, h.Delete)
+	routeGroup.GET(AppGraphRoot, h.Graph)
 }
 
 // Get godoc
@@ -47,12 +62,26 @@ func (h DependencyHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 	r := Dependency{}
 	r.With(m)
 
 	h.Respond(ctx, http.StatusOK, r)
 }
 
+// Head godoc
+// @summary Check a dependency exists.
+// @description Check a dependency exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags dependencies
+// @success 200
+// @success 304
+// @router /dependencies/{id} [head]
+// @param id path int true "Dependency ID"
+func (h DependencyHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.Dependency{})
+}
+
 // List godoc
 // @summary List all dependencies.
 // @description List all dependencies.
@@ -141,6 +170,124 @@ func (h DependencyHandler) Delete(ctx *gin.Context) {
 	h.Status(ctx, http.StatusNoContent)
 }
 
+// Graph godoc
+// @summary Get the application dependency graph.
+// @description Get the transitive dependency graph of an application.
+// @description North: applications that depend on the application.
+// @description South: applications the application depends on.
+// @description The depth limits the levels traversed (0=unlimited).
+// @tags dependencies
+// @produce json
+// @success 200 {object} api.AppGraph
+// @router /applications/{id}/graph [get]
+// @param id path int true "Application ID"
+// @param depth query int false "Depth"
+func (h DependencyHandler) Graph(ctx *gin.Context) {
+	id := h.pk(ctx)
+	depth := 0
+	if s := ctx.Query(Depth); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			_ = ctx.Error(&BadRequestError{"depth must be an integer >= 0."})
+			return
+		}
+		depth = n
+	}
+	app := &model.Application{}
+	result := h.DB(ctx).First(app, id)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	graph := &AppGraph{
+		Nodes: []GraphNode{{ID: app.ID, Name: app.Name}},
+		Edges: []GraphEdge{},
+	}
+	for _, direction := range []string{North, South} {
+		err := h.traverse(ctx, graph, direction, depth)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+
+	h.Respond(ctx, http.StatusOK, graph)
+}
+
+// traverse the dependencies (breadth first) in the specified direction.
+func (h DependencyHandler) traverse(ctx *gin.Context, graph *AppGraph, direction string, depth int) (err error) {
+	near := "ToID"
+	if direction == South {
+		near = "FromID"
+	}
+	visited := map[uint]bool{graph.Nodes[0].ID: true}
+	next := []uint{graph.Nodes[0].ID}
+	for level := 1; len(next) > 0; level++ {
+		if depth > 0 && level > depth {
+			break
+		}
+		var list []model.Dependency
+		db := h.preLoad(h.DB(ctx), clause.Associations)
+		db = db.Order("ID")
+		err = db.Find(&list, near+" IN ?", next).Error
+		if err != nil {
+			return
+		}
+		next = nil
+		for i := range list {
+			m := &list[i]
+			graph.Edges = append(
+				graph.Edges,
+				GraphEdge{
+					ID:   m.ID,
+					From: m.FromID,
+					To:   m.ToID,
+				})
+			app := m.From
+			if direction == South {
+				app = m.To
+			}
+			if visited[app.ID] {
+				continue
+			}
+			visited[app.ID] = true
+			graph.Nodes = append(
+				graph.Nodes,
+				GraphNode{
+					ID:        app.ID,
+					Name:      app.Name,
+					Direction: direction,
+					Depth:     level,
+				})
+			next = append(next, app.ID)
+		}
+	}
+	return
+}
+
+// AppGraph REST resource.
+type AppGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode an application in the graph.
+// The root (requested) application has no direction.
+type GraphNode struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Direction string `json:"direction,omitempty" yaml:",omitempty"`
+	Depth     int    `json:"depth"`
+}
+
+// GraphEdge a dependency in the graph.
+// The (from) application depends on the (to) application.
+type GraphEdge struct {
+	ID   uint `json:"id"`
+	From uint `json:"from"`
+	To   uint `json:"to"`
+}
+
 // Dependency REST resource.
 type Dependency struct {
 	Resource `yaml:",inline"`
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestAppGraph(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		g.Expect(db.Create(&model.Application{Name: name}).Error).To(gomega.BeNil())
+	}
+	// a->b->c, d->a
+	for _, dep := range [][2]uint{{1, 2}, {2, 3}, {4, 1}} {
+		m := &model.Dependency{FromID: dep[0], ToID: dep[1]}
+		g.Expect(m.Create(db)).To(gomega.BeNil())
+	}
+	h := DependencyHandler{}
+	e := newEngine(db)
+	e.GET(AppGraphRoot, h.Graph)
+	get := func(url string) (w *httptest.ResponseRecorder, r AppGraph) {
+		w = httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		_ = json.Unmarshal(w.Body.Bytes(), &r)
+		return
+	}
+
+	w, r := get("/applications/2/graph")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(r.Nodes).To(gomega.Equal(
+		[]GraphNode{
+			{ID: 2, Name: "b"},
+			{ID: 1, Name: "a", Direction: North, Depth: 1},
+			{ID: 4, Name: "d", Direction: North, Depth: 2},
+			{ID: 3, Name: "c", Direction: South, Depth: 1},
+		}))
+	g.Expect(r.Edges).To(gomega.Equal(
+		[]GraphEdge{
+			{ID: 1, From: 1, To: 2},
+			{ID: 3, From: 4, To: 1},
+			{ID: 2, From: 2, To: 3},
+		}))
+
+	w, r = get("/applications/2/graph?depth=1")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(r.Nodes).To(gomega.HaveLen(3))
+	g.Expect(r.Edges).To(gomega.HaveLen(2))
+
+	w, _ = get("/applications/2/graph?depth=x")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	w, _ = get("/applications/9/graph")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNotFound))
+}
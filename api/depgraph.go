@@ -0,0 +1,270 @@
+package api
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	qf "github.com/konveyor/tackle2-hub/api/filter"
+)
+
+// Routes
+const (
+	AnalysisReportDepsGraphRoot = AnalysisReportDepsRoot + "/graph"
+)
+
+// DepGraphReport godoc
+// @summary Get the dependency graph.
+// @description Get the (library->application) dependency graph merged from
+// @description the latest analysis of each (unarchived) application.
+// @description Libraries are de-duplicated by provider and name. Each library lists
+// @description the versions found and the applications using each version.
+// @description filters:
+// @description - provider
+// @description - name
+// @description - version
+// @description - indirect
+// @description - labels
+// @description - application.id
+// @description - application.name
+// @description - businessService.id
+// @description - businessService.name
+// @description - tag.id
+// @description Versions are compared by segment (numerically when possible) so
+// @description that: version<'2.17' matches 2.9 but not 2.17.1.
+// @tags dependencies
+// @produce json
+// @success 200 {object} api.DepGraph
+// @router /analyses/report/dependencies/graph [get]
+func (h AnalysisHandler) DepGraphReport(ctx *gin.Context) {
+	type M struct {
+		Provider string
+		Name     string
+		Version  string
+		Indirect bool
+		AppID    uint
+		AppName  string
+	}
+	// Filter
+	filter, err := qf.New(ctx,
+		[]qf.Assert{
+			{Field: "provider", Kind: qf.STRING},
+			{Field: "name", Kind: qf.STRING},
+			{Field: "version", Kind: qf.STRING},
+			{Field: "indirect", Kind: qf.STRING},
+			{Field: "labels", Kind: qf.STRING, And: true},
+			{Field: "application.id", Kind: qf.LITERAL},
+			{Field: "application.name", Kind: qf.STRING},
+			{Field: "businessService.id", Kind: qf.LITERAL},
+			{Field: "businessService.name", Kind: qf.STRING},
+			{Field: "tag.id", Kind: qf.LITERAL, And: true},
+		})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	version, hasVersion := filter.Field("version")
+	filter.Delete("version")
+	// Find
+	db := h.DB(ctx)
+	db = db.Select(
+		"d.Provider",
+		"d.Name",
+		"d.Version",
+		"d.Indirect",
+		"app.ID AppID",
+		"app.Name AppName")
+	db = db.Table("TechDependency d")
+	db = db.Joins("LEFT JOIN Analysis a ON a.ID = d.AnalysisID")
+	db = db.Joins("LEFT JOIN Application app ON app.ID = a.ApplicationID")
+	db = db.Where("d.AnalysisID IN (?)", h.analysisIDs(ctx, filter))
+	db = db.Where("d.ID IN (?)", h.depIDs(ctx, filter))
+	db = db.Order("d.Provider, d.Name, d.Version, app.ID")
+	var list []M
+	err = db.Scan(&list).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	// Render
+	r := DepGraph{
+		Libraries:    []DepGraphLibrary{},
+		Applications: []Ref{},
+	}
+	apps := map[uint]bool{}
+	for i := range list {
+		m := &list[i]
+		if hasVersion && !versionMatched(&version, m.Version) {
+			continue
+		}
+		n := len(r.Libraries)
+		if n == 0 || r.Libraries[n-1].Provider != m.Provider || r.Libraries[n-1].Name != m.Name {
+			r.Libraries = append(
+				r.Libraries,
+				DepGraphLibrary{
+					Provider: m.Provider,
+					Name:     m.Name,
+				})
+			n++
+		}
+		library := &r.Libraries[n-1]
+		library.add(m.Version, Ref{ID: m.AppID, Name: m.AppName}, m.Indirect)
+		if !apps[m.AppID] {
+			apps[m.AppID] = true
+			r.Applications = append(r.Applications, Ref{ID: m.AppID, Name: m.AppName})
+		}
+	}
+
+	h.Respond(ctx, http.StatusOK, r)
+}
+
+// DepGraph REST resource.
+type DepGraph struct {
+	Libraries    []DepGraphLibrary `json:"libraries"`
+	Applications []Ref             `json:"applications"`
+}
+
+// DepGraphLibrary dependency graph library (node).
+type DepGraphLibrary struct {
+	Provider     string            `json:"provider"`
+	Name         string            `json:"name"`
+	Applications int               `json:"applications"`
+	Versions     []DepGraphVersion `json:"versions"`
+	apps         map[uint]bool
+}
+
+// add an application (edge) using the version.
+func (r *DepGraphLibrary) add(version string, app Ref, indirect bool) {
+	n := len(r.Versions)
+	if n == 0 || r.Versions[n-1].Version != version {
+		r.Versions = append(r.Versions, DepGraphVersion{Version: version})
+		n++
+	}
+	v := &r.Versions[n-1]
+	for i := range v.Applications {
+		edge := &v.Applications[i]
+		if edge.Application.ID == app.ID {
+			edge.Indirect = edge.Indirect && indirect
+			return
+		}
+	}
+	v.Applications = append(
+		v.Applications,
+		DepGraphEdge{
+			Application: app,
+			Indirect:    indirect,
+		})
+	if r.apps == nil {
+		r.apps = map[uint]bool{}
+	}
+	if !r.apps[app.ID] {
+		r.apps[app.ID] = true
+		r.Applications++
+	}
+}
+
+// DepGraphVersion dependency graph library version.
+type DepGraphVersion struct {
+	Version      string         `json:"version"`
+	Applications []DepGraphEdge `json:"applications"`
+}
+
+// DepGraphEdge dependency graph (library->application) edge.
+type DepGraphEdge struct {
+	Application Ref  `json:"application"`
+	Indirect    bool `json:"indirect"`
+}
+
+// versionMatched returns true when the version matches the
+// filter field.
+func versionMatched(f *qf.Field, version string) (matched bool) {
+	values := f.Value.ByKind(qf.LITERAL, qf.STRING)
+	operator := f.Operator.Value
+	for _, v := range values {
+		switch operator {
+		case string(qf.LIKE):
+			pattern := strings.ReplaceAll(regexp.QuoteMeta(v.Value), `\*`, ".*")
+			matched, _ = regexp.MatchString("^"+pattern+"$", version)
+		default:
+			n := versionCompare(version, v.Value)
+			switch operator {
+			case string(qf.COLON), string(qf.EQ):
+				matched = n == 0
+			case string(qf.NOT) + string(qf.EQ):
+				matched = n != 0
+			case string(qf.LT):
+				matched = n < 0
+			case string(qf.GT):
+				matched = n > 0
+			case string(qf.LT) + string(qf.EQ):
+				matched = n <= 0
+			case string(qf.GT) + string(qf.EQ):
+				matched = n >= 0
+			}
+		}
+		if operator == string(qf.NOT)+string(qf.EQ) {
+			if !matched {
+				break
+			}
+		} else if matched {
+			break
+		}
+	}
+	return
+}
+
+// versionCompare compares versions by segment.
+// Segments are separated by (.-_+) and compared numerically when
+// both are numbers. A qualifier (non-numeric segment) is less than a
+// number or a missing segment. Example: 2.0-beta < 2.0 < 2.0.1.
+// Returns: -1 (a<b), 0 (a=b), 1 (a>b).
+func versionCompare(a, b string) (n int) {
+	split := func(s string) []string {
+		return strings.FieldsFunc(
+			strings.ToLower(s),
+			func(r rune) bool {
+				return strings.ContainsRune(".-_+", r)
+			})
+	}
+	pa, pb := split(a), split(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		sa, sb := "", ""
+		if i < len(pa) {
+			sa = pa[i]
+		}
+		if i < len(pb) {
+			sb = pb[i]
+		}
+		na, aErr := strconv.Atoi(sa)
+		nb, bErr := strconv.Atoi(sb)
+		aNumber := aErr == nil || sa == ""
+		bNumber := bErr == nil || sb == ""
+		switch {
+		case aNumber && bNumber:
+			n = compareInt(na, nb)
+		case aNumber:
+			n = 1
+		case bNumber:
+			n = -1
+		default:
+			n = strings.Compare(sa, sb)
+		}
+		if n != 0 {
+			return
+		}
+	}
+	return
+}
+
+// compareInt compares integers.
+func compareInt(a, b int) (n int) {
+	switch {
+	case a < b:
+		n = -1
+	case a > b:
+		n = 1
+	}
+	return
+}
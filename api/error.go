@@ -1,9 +1,11 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -63,6 +65,36 @@ func (r *TrackerError) Is(err error) (matched bool) {
 	return
 }
 
+// NotImplemented reports a feature not supported.
+type NotImplemented struct {
+	Reason string
+}
+
+func (r *NotImplemented) Error() string {
+	return r.Reason
+}
+
+func (r *NotImplemented) Is(err error) (matched bool) {
+	_, matched = err.(*NotImplemented)
+	return
+}
+
+// Conflict reports a resource conflicting with another.
+type Conflict struct {
+	Reason string
+	// ID of the conflicting resource (when known).
+	ID uint
+}
+
+func (r *Conflict) Error() string {
+	return r.Reason
+}
+
+func (r *Conflict) Is(err error) (matched bool) {
+	_, matched = err.(*Conflict)
+	return
+}
+
 // Forbidden reports auth errors.
 type Forbidden struct {
 	Reason string
@@ -77,6 +109,37 @@ func (r *Forbidden) Is(err error) (matched bool) {
 	return
 }
 
+// FieldError reports a field not valid.
+type FieldError struct {
+	Field string
+	Rule  string
+}
+
+func (r *FieldError) Error() string {
+	return r.Field + ": " + r.Rule
+}
+
+func (r *FieldError) Is(err error) (matched bool) {
+	_, matched = err.(*FieldError)
+	return
+}
+
+// FieldErrors reports fields not valid.
+type FieldErrors []FieldError
+
+func (r FieldErrors) Error() string {
+	var part []string
+	for i := range r {
+		part = append(part, r[i].Error())
+	}
+	return strings.Join(part, "; ")
+}
+
+func (r FieldErrors) Is(err error) (matched bool) {
+	_, matched = err.(FieldErrors)
+	return
+}
+
 // ErrorHandler handles error conditions from lower handlers.
 func ErrorHandler() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -89,103 +152,167 @@ func ErrorHandler() gin.HandlerFunc {
 		err := ctx.Errors[0]
 
 		rtx := WithContext(ctx)
-		if errors.Is(err, &BadRequestError{}) ||
-			errors.Is(err, &filter.Error{}) ||
-			errors.Is(err, &sort.SortError{}) ||
-			errors.Is(err, validator.ValidationErrors{}) {
-			rtx.Respond(
-				http.StatusBadRequest,
-				gin.H{
-					"error": err.Error(),
-				})
+		status, body := ErrorResponse(ctx, err)
+		if body != nil {
+			rtx.Respond(status, body)
+		} else {
+			rtx.Status(status)
+		}
+		if status != http.StatusInternalServerError {
 			return
 		}
 
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			if ctx.Request.Method == http.MethodDelete {
-				rtx.Status(http.StatusNoContent)
-				return
-			}
-			rtx.Respond(
-				http.StatusNotFound,
-				gin.H{
-					"error": err.Error(),
-				})
-			return
+		url := ctx.Request.URL.String()
+		log.Error(
+			err.Err,
+			"Request failed.",
+			"method",
+			ctx.Request.Method,
+			"url",
+			url)
+	}
+}
+
+// ErrorResponse returns the status code and body
+// that should be used to report the error.
+func ErrorResponse(ctx *gin.Context, err error) (status int, body interface{}) {
+	if errors.Is(err, &BadRequestError{}) ||
+		errors.Is(err, &filter.Error{}) ||
+		errors.Is(err, &sort.SortError{}) ||
+		errors.Is(err, validator.ValidationErrors{}) {
+		status = http.StatusBadRequest
+		body = gin.H{
+			"error": err.Error(),
 		}
+		return
+	}
 
-		if errors.Is(err, os.ErrNotExist) {
-			rtx.Respond(
-				http.StatusNotFound,
+	fErrs := FieldErrors{}
+	if errors.As(err, &fErrs) {
+		status = http.StatusUnprocessableEntity
+		fields := []gin.H{}
+		for _, fErr := range fErrs {
+			fields = append(
+				fields,
 				gin.H{
-					"error": err.Error(),
+					"field": fErr.Field,
+					"rule":  fErr.Rule,
 				})
-			return
 		}
+		body = gin.H{
+			"fields": fields,
+			"error":  err.Error(),
+		}
+		return
+	}
 
-		if errors.Is(err, model.DependencyCyclicError{}) {
-			rtx.Respond(
-				http.StatusConflict,
-				gin.H{
-					"error": err.Error(),
-				})
-			return
+	fErr := &FieldError{}
+	if errors.As(err, &fErr) {
+		status = http.StatusUnprocessableEntity
+		body = gin.H{
+			"field": fErr.Field,
+			"rule":  fErr.Rule,
+			"error": err.Error(),
 		}
+		return
+	}
 
-		if errors.Is(err, &TrackerError{}) {
-			rtx.Respond(
-				http.StatusServiceUnavailable,
-				gin.H{
-					"error": err.Error(),
-				})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if ctx.Request.Method == http.MethodDelete {
+			status = http.StatusNoContent
 			return
 		}
+		status = http.StatusNotFound
+		body = gin.H{
+			"error": err.Error(),
+		}
+		return
+	}
 
-		if errors.Is(err, &Forbidden{}) {
-			rtx.Respond(
-				http.StatusForbidden,
-				gin.H{
-					"error": err.Error(),
-				})
-			return
+	if errors.Is(err, os.ErrNotExist) {
+		status = http.StatusNotFound
+		body = gin.H{
+			"error": err.Error(),
 		}
+		return
+	}
 
-		sqliteErr := &sqlite3.Error{}
-		if errors.As(err, sqliteErr) {
-			switch sqliteErr.ExtendedCode {
-			case sqlite3.ErrConstraintUnique,
-				sqlite3.ErrConstraintPrimaryKey:
-				rtx.Respond(
-					http.StatusConflict,
-					gin.H{
-						"error": err.Error(),
-					})
-				return
-			}
+	if errors.Is(err, model.DependencyCyclicError{}) {
+		status = http.StatusConflict
+		body = gin.H{
+			"error": err.Error(),
+		}
+		return
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		status = http.StatusGatewayTimeout
+		body = gin.H{
+			"error": "Query timeout (" + QueryTimeout + ") exceeded.",
 		}
+		return
+	}
 
-		bErr := &BatchError{}
-		if errors.As(err, bErr) {
-			rtx.Respond(
-				http.StatusBadRequest,
-				bErr.Items,
-			)
-			return
+	if errors.Is(err, &TrackerError{}) {
+		status = http.StatusServiceUnavailable
+		body = gin.H{
+			"error": err.Error(),
 		}
+		return
+	}
 
-		rtx.Respond(
-			http.StatusInternalServerError,
-			gin.H{
+	if errors.Is(err, &NotImplemented{}) {
+		status = http.StatusNotImplemented
+		body = gin.H{
+			"error": err.Error(),
+		}
+		return
+	}
+
+	conflict := &Conflict{}
+	if errors.As(err, &conflict) {
+		status = http.StatusConflict
+		h := gin.H{
+			"error": err.Error(),
+		}
+		if conflict.ID > 0 {
+			h["id"] = conflict.ID
+		}
+		body = h
+		return
+	}
+
+	if errors.Is(err, &Forbidden{}) {
+		status = http.StatusForbidden
+		body = gin.H{
+			"error": err.Error(),
+		}
+		return
+	}
+
+	sqliteErr := &sqlite3.Error{}
+	if errors.As(err, sqliteErr) {
+		switch sqliteErr.ExtendedCode {
+		case sqlite3.ErrConstraintUnique,
+			sqlite3.ErrConstraintPrimaryKey:
+			status = http.StatusConflict
+			body = gin.H{
 				"error": err.Error(),
-			})
+			}
+			return
+		}
+	}
 
-		url := ctx.Request.URL.String()
-		log.Error(
-			err.Err,
-			"Request failed.",
-			"method",
-			ctx.Request.Method,
-			"url",
-			url)
+	bErr := &BatchError{}
+	if errors.As(err, bErr) {
+		status = http.StatusBadRequest
+		body = bErr.Items
+		return
 	}
+
+	status = http.StatusInternalServerError
+	body = gin.H{
+		"error": err.Error(),
+	}
+	return
 }
@@ -33,6 +33,7 @@ func (h FileHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.PUT(FileRoot, h.Create)
 	routeGroup.PATCH(FileRoot, h.Append)
 	routeGroup.GET(FileRoot, h.Get)
+	routeGroup.HEAD(FileRoot, h.Head)
 	routeGroup.DELETE(FileRoot, h.Delete)
 }
 
@@ -196,6 +197,7 @@ func (h FileHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 	if h.Accepted(ctx, BindMIMEs...) {
 		r := File{}
 		r.With(m)
@@ -209,6 +211,19 @@ func (h FileHandler) Get(ctx *gin.Context) {
 	}
 }
 
+// Head godoc
+// @summary Check a file exists.
+// @description Check a file exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags file
+// @success 200
+// @success 304
+// @router /files/{id} [head]
+// @param id path int true "File ID"
+func (h FileHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.File{})
+}
+
 // Delete godoc
 // @summary Delete a file.
 // @description Delete a file.
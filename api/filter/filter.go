@@ -15,8 +15,14 @@ const (
 // New filter.
 func New(ctx *gin.Context, assertions []Assert) (f Filter, err error) {
 	p := Parser{}
+	params := ctx.QueryArray(QueryParam)
+	if len(params) > 1 {
+		for i := range params {
+			params[i] = string(LPAREN) + params[i] + string(RPAREN)
+		}
+	}
 	q := strings.Join(
-		ctx.QueryArray(QueryParam),
+		params,
 		string(COMMA))
 	f, err = p.Filter(q)
 	if err != nil {
@@ -29,6 +35,7 @@ func New(ctx *gin.Context, assertions []Assert) (f Filter, err error) {
 // Filter is a collection of predicates.
 type Filter struct {
 	predicates []Predicate
+	root       Group
 }
 
 // Validate -
@@ -88,29 +95,27 @@ func (f *Filter) Fields(name string) (fields []Field) {
 // Resource returns a filter scoped to resource.
 func (f *Filter) Resource(r string) (filter Filter) {
 	r = strings.ToLower(r)
-	var predicates []Predicate
-	for _, p := range f.predicates {
+	filter = f.prune(func(p Predicate) (Predicate, bool) {
 		field := Field{p}
 		fr := field.Resource()
 		fr = strings.ToLower(fr)
 		if fr == r {
 			p.Field.Value = field.Name()
-			predicates = append(predicates, p)
+			return p, true
 		}
-	}
-	filter.predicates = predicates
+		return p, false
+	})
 	return
 }
 
 // Where applies (root) fields to the where clause.
+// Predicates not selected are omitted from their group.
 func (f *Filter) Where(in *gorm.DB, selector ...string) (out *gorm.DB) {
 	out = in
-	fs := FieldSelector(selector)
-	for _, p := range f.predicates {
-		field := Field{p}
-		if fs.Match(&field) {
-			out = field.Where(out)
-		}
+	selected := f.With(selector...)
+	sql, values := selected.root.SQL()
+	if sql != "" {
+		out = out.Where(sql, values...)
 	}
 	return
 }
@@ -118,26 +123,29 @@ func (f *Filter) Where(in *gorm.DB, selector ...string) (out *gorm.DB) {
 // With return filter with selected predicates.
 func (f *Filter) With(selector ...string) (out Filter) {
 	fs := FieldSelector(selector)
-	for _, p := range f.predicates {
+	out = f.prune(func(p Predicate) (Predicate, bool) {
 		field := Field{p}
-		if fs.Match(&field) {
-			out.predicates = append(out.predicates, p)
-		}
-	}
+		return p, fs.Match(&field)
+	})
 	return
 }
 
 // Delete specified fields.
 func (f *Filter) Delete(name string) (found bool) {
-	var wanted []Predicate
-	for _, p := range f.predicates {
+	*f = f.prune(func(p Predicate) (Predicate, bool) {
 		if strings.ToLower(p.Field.Value) != name {
-			wanted = append(wanted, p)
-		} else {
-			found = true
+			return p, true
 		}
-	}
-	f.predicates = wanted
+		found = true
+		return p, false
+	})
+	return
+}
+
+// prune returns a filter with the predicates kept by the function.
+func (f *Filter) prune(keep func(p Predicate) (Predicate, bool)) (out Filter) {
+	out.root = f.root.Prune(keep)
+	out.predicates = out.root.Predicates()
 	return
 }
 
@@ -327,6 +335,9 @@ func (f *Field) operator() (s string) {
 		}
 	default:
 		s = "IN"
+		if f.Operator.Value == string(NOT)+string(EQ) {
+			s = "NOT IN"
+		}
 	}
 
 	return
@@ -372,3 +372,75 @@ func TestFilterWith(t *testing.T) {
 	g.Expect(hasAge).To(gomega.BeTrue())
 	g.Expect(hasCat).To(gomega.BeFalse())
 }
+
+func TestFilterGroup(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	cases := []struct {
+		filter string
+		sql    string
+		values []interface{}
+	}{
+		{
+			filter: "kind=jira-cloud;connected=false",
+			sql:    "(kind = ? AND connected = ?)",
+			values: []interface{}{"jira-cloud", false},
+		},
+		{
+			filter: "name:elmer|age>20",
+			sql:    "(name = ? OR age > ?)",
+			values: []interface{}{"elmer", 20},
+		},
+		{
+			filter: "name:elmer,age>20|age<10",
+			sql:    "((name = ? AND age > ?) OR age < ?)",
+			values: []interface{}{"elmer", 20, 10},
+		},
+		{
+			filter: "name~el*,(age>=20|category!=(a|b))",
+			sql:    "(name LIKE ? AND (age >= ? OR category NOT IN ?))",
+			values: []interface{}{"el%", 20, []interface{}{"a", "b"}},
+		},
+		{
+			filter: "((name:elmer))",
+			sql:    "name = ?",
+			values: []interface{}{"elmer"},
+		},
+	}
+	for _, c := range cases {
+		p := Parser{}
+		filter, err := p.Filter(c.filter)
+		g.Expect(err).To(gomega.BeNil(), c.filter)
+		sql, values := filter.root.SQL()
+		g.Expect(sql).To(gomega.Equal(c.sql), c.filter)
+		g.Expect(values).To(gomega.Equal(c.values), c.filter)
+	}
+
+	// Pruned.
+	p := Parser{}
+	filter, err := p.Filter("name:elmer|(age>20,tag.id=1)")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(len(filter.predicates)).To(gomega.Equal(3))
+	f := filter.With("-age")
+	sql, _ := f.root.SQL()
+	g.Expect(sql).To(gomega.Equal("name = ?"))
+	f = filter.Resource("tag")
+	sql, _ = f.root.SQL()
+	g.Expect(sql).To(gomega.Equal("id = ?"))
+	g.Expect(filter.Delete("name")).To(gomega.BeTrue())
+	g.Expect(len(filter.predicates)).To(gomega.Equal(2))
+
+	// Rejected.
+	for _, s := range []string{
+		"name=~elmer",
+		"name!!elmer",
+		"age>(1|2)",
+		"(name:elmer",
+		"name:elmer)",
+		"name:elmer,|age:20",
+		"name:elmer,",
+	} {
+		p := Parser{}
+		_, err := p.Filter(s)
+		g.Expect(err).ToNot(gomega.BeNil(), s)
+	}
+}
@@ -5,6 +5,7 @@ const (
 	COLON  = ':'
 	COMMA  = ','
 	AND    = COMMA
+	SEMI   = ';'
 	OR     = '|'
 	EQ     = '='
 	LIKE   = '~'
@@ -75,6 +76,7 @@ func (r *Lexer) With(filter string) (err error) {
 			push(RPAREN)
 		case COLON,
 			COMMA,
+			SEMI,
 			OR,
 			EQ,
 			LIKE,
@@ -189,6 +191,8 @@ func (q *Operator) Read() (token Token, err error) {
 			break
 		}
 		switch ch {
+		case SEMI:
+			bfr = append(bfr, AND)
 		case COLON,
 			COMMA,
 			OR,
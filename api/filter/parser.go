@@ -1,67 +1,269 @@
 package filter
 
-import "math"
+import (
+	"math"
+	"strings"
+)
 
 // Parser used to parse the filter.
 type Parser struct {
+	lexer *Lexer
 }
 
 // Filter parses the filter and builds a Filter.
+// Predicates are joined by `,` (AND) and `|` (OR) and may be
+// grouped using (). AND takes precedence over OR.
 func (r *Parser) Filter(filter string) (f Filter, err error) {
 	if filter == "" {
 		return
 	}
-	lexer := Lexer{}
-	err = lexer.With(string(COMMA) + filter)
+	r.lexer = &Lexer{}
+	err = r.lexer.With(filter)
 	if err != nil {
 		return
 	}
-	var bfr []Token
+	root, err := r.or(Token{Kind: OPERATOR, Value: string(COMMA)})
+	if err != nil {
+		return
+	}
+	token, next := r.lexer.next()
+	if next {
+		err = Errorf("'%s' not expected.", token.Value)
+		return
+	}
+	f.root = root
+	f.predicates = root.Predicates()
+	return
+}
+
+// or parses terms joined by OR.
+func (r *Parser) or(sep Token) (g Group, err error) {
+	g.Operator = OR
 	for {
-		token, next := lexer.next()
+		and, nErr := r.and(sep)
+		if nErr != nil {
+			err = nErr
+			return
+		}
+		g.Terms = append(g.Terms, Term{Group: &and})
+		token, next := r.lexer.next()
 		if !next {
 			break
 		}
-		if len(bfr) > 2 {
-			if bfr[0].Kind != OPERATOR || bfr[2].Kind != OPERATOR {
-				err = Errorf("Syntax error.")
-				return
-			}
-			switch token.Kind {
-			case LITERAL, STRING:
-				p := Predicate{
-					Unused:   bfr[0],
-					Field:    bfr[1],
-					Operator: bfr[2],
-					Value:    Value{token},
-				}
-				f.predicates = append(f.predicates, p)
-				bfr = nil
-			case LPAREN:
-				lexer.put()
-				list := List{&lexer}
-				v, nErr := list.Build()
-				if nErr != nil {
-					err = nErr
-					return
-				}
-				p := Predicate{
-					Unused:   bfr[0],
-					Field:    bfr[1],
-					Operator: bfr[2],
-					Value:    v,
-				}
-				f.predicates = append(f.predicates, p)
-				bfr = nil
-			}
-		} else {
-			bfr = append(bfr, token)
+		if token.Kind == OPERATOR && token.Value == string(OR) {
+			sep = token
+			continue
 		}
+		r.lexer.put()
+		break
 	}
-	if len(bfr) != 0 {
+	return
+}
+
+// and parses terms joined by AND.
+func (r *Parser) and(sep Token) (g Group, err error) {
+	g.Operator = AND
+	for {
+		term, nErr := r.term(sep)
+		if nErr != nil {
+			err = nErr
+			return
+		}
+		g.Terms = append(g.Terms, term)
+		token, next := r.lexer.next()
+		if !next {
+			break
+		}
+		if token.Kind == OPERATOR && token.Value == string(AND) {
+			sep = token
+			continue
+		}
+		r.lexer.put()
+		break
+	}
+	return
+}
+
+// term parses a predicate or a (grouped) expression.
+func (r *Parser) term(sep Token) (term Term, err error) {
+	token, next := r.lexer.next()
+	if !next {
 		err = Errorf("Syntax error.")
 		return
 	}
+	switch token.Kind {
+	case LPAREN:
+		g, nErr := r.or(sep)
+		if nErr != nil {
+			err = nErr
+			return
+		}
+		token, next = r.lexer.next()
+		if !next || token.Kind != RPAREN {
+			err = Errorf("End ')' not found.")
+			return
+		}
+		term.Group = &g
+	case LITERAL:
+		p, nErr := r.predicate(sep, token)
+		if nErr != nil {
+			err = nErr
+			return
+		}
+		term.Predicate = &p
+	default:
+		err = Errorf("Syntax error.")
+	}
+	return
+}
+
+// predicate parses the operator and value of a predicate.
+func (r *Parser) predicate(sep Token, field Token) (p Predicate, err error) {
+	operator, next := r.lexer.next()
+	if !next || operator.Kind != OPERATOR {
+		err = Errorf("Syntax error.")
+		return
+	}
+	if !Operators.Match(operator.Value) {
+		err = Errorf("Operator '%s' not supported.", operator.Value)
+		return
+	}
+	p = Predicate{
+		Unused:   sep,
+		Field:    field,
+		Operator: operator,
+	}
+	token, next := r.lexer.next()
+	if !next {
+		err = Errorf("Syntax error.")
+		return
+	}
+	switch token.Kind {
+	case LITERAL, STRING:
+		p.Value = Value{token}
+	case LPAREN:
+		if !ListOperators.Match(operator.Value) {
+			err = Errorf("Operator '%s' cannot be used with ().", operator.Value)
+			return
+		}
+		r.lexer.put()
+		list := List{r.lexer}
+		p.Value, err = list.Build()
+	default:
+		err = Errorf("Syntax error.")
+	}
+	return
+}
+
+// OperatorSet a set of operators.
+type OperatorSet []string
+
+// Match returns true when the operator is in the set.
+func (r OperatorSet) Match(operator string) (matched bool) {
+	for _, s := range r {
+		if s == operator {
+			matched = true
+			break
+		}
+	}
+	return
+}
+
+// Operators supported predicate operators.
+var Operators = OperatorSet{
+	string(COLON),
+	string(EQ),
+	string(NOT) + string(EQ),
+	string(GT),
+	string(LT),
+	string(GT) + string(EQ),
+	string(LT) + string(EQ),
+	string(LIKE),
+}
+
+// ListOperators supported (list) predicate operators.
+var ListOperators = OperatorSet{
+	string(COLON),
+	string(EQ),
+	string(NOT) + string(EQ),
+	string(LIKE),
+}
+
+// Term is either a predicate or a (nested) group.
+type Term struct {
+	Predicate *Predicate
+	Group     *Group
+}
+
+// Group of terms joined by an operator (AND|OR).
+// Example: (name:elmer|age>20)
+type Group struct {
+	Operator byte
+	Terms    []Term
+}
+
+// Predicates returns the predicates in the order parsed.
+func (g *Group) Predicates() (predicates []Predicate) {
+	for _, t := range g.Terms {
+		if t.Group != nil {
+			predicates = append(predicates, t.Group.Predicates()...)
+		} else {
+			predicates = append(predicates, *t.Predicate)
+		}
+	}
+	return
+}
+
+// Prune returns the group with only the predicates kept
+// by the function. Empty groups are removed.
+func (g *Group) Prune(keep func(p Predicate) (Predicate, bool)) (pruned Group) {
+	pruned.Operator = g.Operator
+	for _, t := range g.Terms {
+		if t.Group != nil {
+			nested := t.Group.Prune(keep)
+			if len(nested.Terms) > 0 {
+				pruned.Terms = append(pruned.Terms, Term{Group: &nested})
+			}
+			continue
+		}
+		p, kept := keep(*t.Predicate)
+		if kept {
+			pruned.Terms = append(pruned.Terms, Term{Predicate: &p})
+		}
+	}
+	return
+}
+
+// SQL builds SQL.
+// Returns statement and values (for ?).
+func (g *Group) SQL() (s string, vList []interface{}) {
+	var clauses []string
+	for _, t := range g.Terms {
+		var sql string
+		var values []interface{}
+		if t.Group != nil {
+			sql, values = t.Group.SQL()
+		} else {
+			field := Field{*t.Predicate}
+			sql, values = field.SQL()
+		}
+		if sql == "" {
+			continue
+		}
+		clauses = append(clauses, sql)
+		vList = append(vList, values...)
+	}
+	switch len(clauses) {
+	case 0:
+	case 1:
+		s = clauses[0]
+	default:
+		operator := " AND "
+		if g.Operator == OR {
+			operator = " OR "
+		}
+		s = "(" + strings.Join(clauses, operator) + ")"
+	}
 	return
 }
 
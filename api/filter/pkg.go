@@ -5,7 +5,8 @@ package filter
 
 Predicates:
 
-filter (predicate (AND|OR)*)
+filter: term ((AND|OR) term)*
+term: predicate | `(` filter `)`
 predicate: field operator value
 field: LITERAL
 value: (LITERAL|STRING|list)
@@ -13,6 +14,7 @@ STRING: ('|")(.)*('|")
 list: `(` (LITERAL|STRING) OR* `)` `
 operator:
 - ,  COMMA, AND
+- ;  SEMI, AND
 - | OR
 - (:|,)= equal
 - != not equal
@@ -25,6 +27,7 @@ operator:
 - () one of
 
 \* is a wildcard for string matching.
+AND takes precedence over OR. Unknown operators are rejected.
 
 
 Examples:
@@ -39,5 +42,7 @@ Examples:
 ?filter=category!=(one|two)           // category NOT IN (one, two)
 ?filter=category:mandatory,effort:20  // category=mandatory AND effort=20
 ?filter=category:mandatory|effort:10  // category=mandatory OR effort=10
+?filter=kind=jira-cloud;connected=false        // kind=jira-cloud AND connected=false
+?filter=name~a*,(effort>5|category:mandatory)  // name LIKE a% AND (effort>5 OR category=mandatory)
 ?filter=tag.id:(1,2)                  // tag.id 1 AND 2.
 */
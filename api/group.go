@@ -27,6 +27,7 @@ func (h StakeholderGroupHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(StakeholderGroupsRoot+"/", h.List)
 	routeGroup.POST(StakeholderGroupsRoot, h.Create)
 	routeGroup.GET(StakeholderGroupRoot, h.Get)
+	routeGroup.HEAD(StakeholderGroupRoot, h.Head)
 	routeGroup.PUT(StakeholderGroupRoot, h.Update)
 	routeGroup.DELETE(StakeholderGroupRoot, h.Delete)
 }
@@ -48,12 +49,26 @@ func (h StakeholderGroupHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 	r := StakeholderGroup{}
 	r.With(m)
 
 	h.Respond(ctx, http.StatusOK, r)
 }
 
+// Head godoc
+// @summary Check a stakeholder group exists.
+// @description Check a stakeholder group exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags stakeholdergroups
+// @success 200
+// @success 304
+// @router /stakeholdergroups/{id} [head]
+// @param id path int true "Stakeholder Group ID"
+func (h StakeholderGroupHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.StakeholderGroup{})
+}
+
 // List godoc
 // @summary List all stakeholder groups.
 // @description List all stakeholder groups.
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/tracker"
+)
+
+// Routes
+const (
+	LivenessRoot  = "/healthz"
+	ReadinessRoot = "/readyz"
+)
+
+// HealthHandler handles health (probe) routes.
+type HealthHandler struct {
+	BaseHandler
+}
+
+// AddRoutes adds routes.
+// The probes are not authenticated.
+func (h HealthHandler) AddRoutes(e *gin.Engine) {
+	e.GET(LivenessRoot, h.Live)
+	e.GET(ReadinessRoot, h.Ready)
+}
+
+// Live godoc
+// @summary Liveness probe.
+// @description Liveness probe.
+// @description Returns 200 whenever the hub is able to serve requests.
+// @tags health
+// @success 200
+// @router /healthz [get]
+func (h HealthHandler) Live(ctx *gin.Context) {
+	h.Status(ctx, http.StatusOK)
+}
+
+// Ready godoc
+// @summary Readiness probe.
+// @description Readiness probe.
+// @description Returns 200 when the DB is reachable and the tracker manager
+// @description has completed at least one reconcile pass (or is paused).
+// @description Otherwise, returns 503.
+// @tags health
+// @produce json
+// @success 200 {object} api.Health
+// @failure 503 {object} api.Health
+// @router /readyz [get]
+func (h HealthHandler) Ready(ctx *gin.Context) {
+	r := Health{}
+	r.Database = h.dbReady(ctx)
+	r.Tracker = tracker.Ready()
+	if r.Database && r.Tracker {
+		h.Respond(ctx, http.StatusOK, r)
+	} else {
+		h.Respond(ctx, http.StatusServiceUnavailable, r)
+	}
+}
+
+// dbReady returns true when the DB can be pinged.
+func (h HealthHandler) dbReady(ctx *gin.Context) (ready bool) {
+	rtx := WithContext(ctx)
+	if rtx.DB == nil {
+		return
+	}
+	db, err := rtx.DB.DB()
+	if err != nil {
+		return
+	}
+	pctx, cancel := context.WithTimeout(ctx.Request.Context(), time.Second)
+	defer cancel()
+	err = db.PingContext(pctx)
+	ready = err == nil
+	return
+}
+
+// Health REST resource.
+type Health struct {
+	Database bool `json:"database"`
+	Tracker  bool `json:"tracker"`
+}
@@ -3,9 +3,11 @@ package api
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/konveyor/tackle2-hub/model"
+	"github.com/konveyor/tackle2-hub/tracker"
 )
 
 // Routes
@@ -32,6 +34,7 @@ func (h IdentityHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(IdentitiesRoot+"/", h.setDecrypted, h.List)
 	routeGroup.POST(IdentitiesRoot, h.Create)
 	routeGroup.GET(IdentityRoot, h.setDecrypted, h.Get)
+	routeGroup.HEAD(IdentityRoot, h.Head)
 	routeGroup.PUT(IdentityRoot, h.Update)
 	routeGroup.DELETE(IdentityRoot, h.Delete)
 }
@@ -52,6 +55,7 @@ func (h IdentityHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 	r := Identity{}
 	decrypted := ctx.GetBool(Decrypted)
 	if decrypted {
@@ -66,6 +70,19 @@ func (h IdentityHandler) Get(ctx *gin.Context) {
 	h.Respond(ctx, http.StatusOK, r)
 }
 
+// Head godoc
+// @summary Check an identity exists.
+// @description Check an identity exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags identities
+// @success 200
+// @success 304
+// @router /identities/{id} [head]
+// @param id path int true "Identity ID"
+func (h IdentityHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.Identity{})
+}
+
 // List godoc
 // @summary List all identities.
 // @description List all identities.
@@ -171,6 +188,8 @@ func (h IdentityHandler) Delete(ctx *gin.Context) {
 // Update godoc
 // @summary Update an identity.
 // @description Update an identity.
+// @description Trackers using the identity are re-tested. The IDs of the
+// @description trackers to be re-tested are reported in the X-Retested header.
 // @tags identities
 // @accept json
 // @success 204
@@ -205,6 +224,24 @@ func (h IdentityHandler) Update(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
+	tracker.Evict(id)
+	var trackers []model.Tracker
+	db = h.DB(ctx).Select("ID")
+	db = db.Where("IdentityID = ? OR TunnelIdentityID = ?", id, id)
+	err = db.Find(&trackers).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if !Settings.Hub.Tracker.Paused && len(trackers) > 0 {
+		var ids []string
+		for i := range trackers {
+			t := &trackers[i]
+			tracker.Retest(t.ID)
+			ids = append(ids, strconv.Itoa(int(t.ID)))
+		}
+		ctx.Header(Retested, strings.Join(ids, ","))
+	}
 
 	h.Status(ctx, http.StatusNoContent)
 }
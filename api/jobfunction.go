@@ -27,6 +27,7 @@ func (h JobFunctionHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(JobFunctionsRoot+"/", h.List)
 	routeGroup.POST(JobFunctionsRoot, h.Create)
 	routeGroup.GET(JobFunctionRoot, h.Get)
+	routeGroup.HEAD(JobFunctionRoot, h.Head)
 	routeGroup.PUT(JobFunctionRoot, h.Update)
 	routeGroup.DELETE(JobFunctionRoot, h.Delete)
 }
@@ -48,12 +49,26 @@ func (h JobFunctionHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 	r := JobFunction{}
 	r.With(m)
 
 	h.Respond(ctx, http.StatusOK, r)
 }
 
+// Head godoc
+// @summary Check a job function exists.
+// @description Check a job function exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags jobfunctions
+// @success 200
+// @success 304
+// @router /jobfunctions/{id} [head]
+// @param id path int true "Job Function ID"
+func (h JobFunctionHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.JobFunction{})
+}
+
 // List godoc
 // @summary List all job functions.
 // @description List all job functions.
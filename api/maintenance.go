@@ -0,0 +1,160 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/reaper"
+	"github.com/konveyor/tackle2-hub/tracker"
+)
+
+// Routes
+const (
+	MaintenanceRoot         = "/maintenance"
+	MaintenanceTrackersRoot = MaintenanceRoot + TrackersRoot
+	TrackerBackfillRoot     = MaintenanceTrackersRoot + "/backfill"
+	TrackerCanaryRoot       = MaintenanceTrackersRoot + "/canary"
+	ReaperDryRunRoot        = MaintenanceRoot + "/reaper/dryrun"
+)
+
+// MaintenanceHandler handles maintenance routes.
+type MaintenanceHandler struct {
+	BaseHandler
+}
+
+// AddRoutes adds routes.
+func (h MaintenanceHandler) AddRoutes(e *gin.Engine) {
+	routeGroup := e.Group("/")
+	routeGroup.Use(Required("trackers"))
+	routeGroup.GET(TrackerBackfillRoot, h.TrackerBackfill)
+	routeGroup.GET(TrackerCanaryRoot, h.TrackerCanary)
+	routeGroup = e.Group("/")
+	routeGroup.Use(Required("tasks"))
+	routeGroup.GET(ReaperDryRunRoot, h.ReaperDryRun)
+}
+
+// TrackerBackfill godoc
+// @summary Get the tracker status backfill progress.
+// @description Get the progress of the (one-time) backfill of the
+// @description tracker structured status fields from the legacy message.
+// @tags maintenance
+// @produce json
+// @success 200 {object} api.Backfill
+// @router /maintenance/trackers/backfill [get]
+func (h MaintenanceHandler) TrackerBackfill(ctx *gin.Context) {
+	r := Backfill{}
+	r.With(tracker.Backfilled())
+	h.Respond(ctx, http.StatusOK, r)
+}
+
+// TrackerCanary godoc
+// @summary List tracker canary divergences.
+// @description List the (recent) canary reconciles for which the result
+// @description of the canary connector did not match the stable connector.
+// @tags maintenance
+// @produce json
+// @success 200 {object} []api.Divergence
+// @router /maintenance/trackers/canary [get]
+func (h MaintenanceHandler) TrackerCanary(ctx *gin.Context) {
+	resources := []Divergence{}
+	for _, d := range tracker.Divergences() {
+		r := Divergence{}
+		r.With(&d)
+		resources = append(resources, r)
+	}
+	h.Respond(ctx, http.StatusOK, resources)
+}
+
+// ReaperDryRun godoc
+// @summary List what would be reaped.
+// @description List the tasks, groups, buckets and files that would be
+// @description deleted (or released) by the reaper based on the TTL and
+// @description retention settings. Nothing is reaped.
+// @tags maintenance
+// @produce json
+// @success 200 {object} []api.ReaperAction
+// @router /maintenance/reaper/dryrun [get]
+func (h MaintenanceHandler) ReaperDryRun(ctx *gin.Context) {
+	m := reaper.Manager{DB: h.DB(ctx)}
+	actions, err := m.DryRun()
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	resources := []ReaperAction{}
+	for i := range actions {
+		r := ReaperAction{}
+		r.With(&actions[i])
+		resources = append(resources, r)
+	}
+	h.Respond(ctx, http.StatusOK, resources)
+}
+
+// Backfill REST resource.
+type Backfill struct {
+	State     string     `json:"state"`
+	Total     int        `json:"total"`
+	Completed int        `json:"completed"`
+	Updated   int        `json:"updated"`
+	Error     string     `json:"error,omitempty" yaml:",omitempty"`
+	Started   *time.Time `json:"started,omitempty" yaml:",omitempty"`
+	Finished  *time.Time `json:"finished,omitempty" yaml:",omitempty"`
+}
+
+// With updates the resource with the status.
+func (r *Backfill) With(status tracker.BackfillStatus) {
+	r.State = status.State
+	r.Total = status.Total
+	r.Completed = status.Completed
+	r.Updated = status.Updated
+	r.Error = status.Error
+	r.Started = status.Started
+	r.Finished = status.Finished
+}
+
+// Divergence REST resource.
+type Divergence struct {
+	Tracker Ref           `json:"tracker"`
+	Time    time.Time     `json:"time"`
+	Stable  CanaryOutcome `json:"stable"`
+	Canary  CanaryOutcome `json:"canary"`
+}
+
+// With updates the resource with the divergence.
+func (r *Divergence) With(d *tracker.Divergence) {
+	r.Tracker = Ref{ID: d.Tracker}
+	r.Time = d.Time
+	r.Stable.With(&d.Stable)
+	r.Canary.With(&d.Canary)
+}
+
+// CanaryOutcome REST resource.
+type CanaryOutcome struct {
+	Connected bool   `json:"connected"`
+	Category  string `json:"category,omitempty" yaml:",omitempty"`
+	Reason    string `json:"reason,omitempty" yaml:",omitempty"`
+}
+
+// With updates the resource with the outcome.
+func (r *CanaryOutcome) With(o *tracker.Outcome) {
+	r.Connected = o.Connected
+	r.Category = o.Category
+	r.Reason = o.Reason
+}
+
+// ReaperAction REST resource.
+type ReaperAction struct {
+	Kind   string `json:"kind"`
+	ID     uint   `json:"id"`
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty" yaml:",omitempty"`
+}
+
+// With updates the resource with the action.
+func (r *ReaperAction) With(a *reaper.Action) {
+	r.Kind = a.Kind
+	r.ID = a.ID
+	r.Action = a.Action
+	r.Reason = a.Reason
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/konveyor/tackle2-hub/reaper"
+	tasking "github.com/konveyor/tackle2-hub/task"
+	"github.com/onsi/gomega"
+)
+
+func TestReaperDryRun(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	retention := Settings.Hub.Task.Retention
+	release := Settings.Hub.Task.Reaper
+	defer func() {
+		Settings.Hub.Task.Retention = retention
+		Settings.Hub.Task.Reaper = release
+	}()
+	Settings.Hub.Task.Retention.Succeeded = 7 * 24 * 60
+	Settings.Hub.Task.Retention.Failed = 30 * 24 * 60
+	Settings.Hub.Task.Reaper.Succeeded = 24 * 60
+	Settings.Hub.Task.Reaper.Failed = 3 * 24 * 60
+	days := func(n int) *time.Time {
+		mark := time.Now().Add(-time.Duration(n) * 24 * time.Hour)
+		return &mark
+	}
+	tasks := []model.Task{
+		{Name: "a", Addon: "analyzer", State: tasking.Succeeded, Terminated: days(10)},
+		{Name: "b", Addon: "analyzer", State: tasking.Failed, Terminated: days(10)},
+		{Name: "c", Addon: "analyzer", State: tasking.Succeeded, Terminated: days(0)},
+	}
+	for i := range tasks {
+		// Reset on create.
+		terminated := tasks[i].Terminated
+		g.Expect(db.Create(&tasks[i]).Error).To(gomega.BeNil())
+		err := db.Exec(
+			"UPDATE Task SET Terminated = ? WHERE ID = ?",
+			terminated,
+			tasks[i].ID).Error
+		g.Expect(err).To(gomega.BeNil())
+	}
+	e := newEngine(db)
+	h := MaintenanceHandler{}
+	e.GET(ReaperDryRunRoot, h.ReaperDryRun)
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ReaperDryRunRoot, nil))
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	actions := []ReaperAction{}
+	_ = json.Unmarshal(w.Body.Bytes(), &actions)
+	g.Expect(actions).To(gomega.Equal(
+		[]ReaperAction{
+			{
+				Kind:   "Task",
+				ID:     tasks[0].ID,
+				Action: reaper.Delete,
+				Reason: "Succeeded: retention (10080 minutes) expired.",
+			},
+			{
+				Kind:   "Task",
+				ID:     tasks[1].ID,
+				Action: reaper.Release,
+				Reason: "Bucket released.",
+			},
+		}))
+	// Nothing reaped.
+	var n int64
+	db.Model(&model.Task{}).Count(&n)
+	g.Expect(n).To(gomega.Equal(int64(3)))
+}
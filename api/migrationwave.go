@@ -13,6 +13,7 @@ import (
 const (
 	MigrationWavesRoot = "/migrationwaves"
 	MigrationWaveRoot  = MigrationWavesRoot + "/:" + ID
+	WaveTicketsRoot    = MigrationWaveRoot + "/tickets"
 )
 
 // MigrationWaveHandler handles Migration Wave resource routes.
@@ -27,9 +28,11 @@ func (h MigrationWaveHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(MigrationWavesRoot, h.List)
 	routeGroup.GET(MigrationWavesRoot+"/", h.List)
 	routeGroup.GET(MigrationWaveRoot, h.Get)
+	routeGroup.HEAD(MigrationWaveRoot, h.Head)
 	routeGroup.POST(MigrationWavesRoot, h.Create)
 	routeGroup.DELETE(MigrationWaveRoot, h.Delete)
 	routeGroup.PUT(MigrationWaveRoot, h.Update)
+	routeGroup.POST(WaveTicketsRoot, h.TicketCreate)
 }
 
 // Get godoc
@@ -49,12 +52,26 @@ func (h MigrationWaveHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 	r := MigrationWave{}
 	r.With(m)
 
 	h.Respond(ctx, http.StatusOK, r)
 }
 
+// Head godoc
+// @summary Check a migration wave exists.
+// @description Check a migration wave exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags migrationwaves
+// @success 200
+// @success 304
+// @router /migrationwaves/{id} [head]
+// @param id path int true "Migration Wave ID"
+func (h MigrationWaveHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.MigrationWave{})
+}
+
 // List godoc
 // @summary List all migration waves.
 // @description List all migration waves.
@@ -96,18 +113,104 @@ func (h MigrationWaveHandler) Create(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
+	err = r.Validate()
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	m := r.Model()
+	err = h.unarchived(ctx, m.Applications)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	m.CreateUser = h.CurrentUser(ctx)
+	members, err := h.members(ctx, 0, m.Applications)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	result := h.DB(ctx).Create(m)
 	if result.Error != nil {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	err = h.membersChanged(ctx, m, members)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	r.With(m)
 
 	h.Respond(ctx, http.StatusCreated, r)
 }
 
+// TicketCreate godoc
+// @summary Create tickets for the applications in a migration wave.
+// @description Create a ticket (in the tracker project) for each application in
+// @description the migration wave. The ticket defaults of each application are applied.
+// @description Returns the outcome for each application.
+// @tags migrationwaves
+// @accept json
+// @produce json
+// @success 200 {object} []api.WaveTicket
+// @router /migrationwaves/{id}/tickets [post]
+// @param id path int true "MigrationWave id"
+// @param tickets body api.WaveTickets true "Ticket data"
+func (h MigrationWaveHandler) TicketCreate(ctx *gin.Context) {
+	id := h.pk(ctx)
+	r := &WaveTickets{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = h.VerifyRefs(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m := &model.MigrationWave{}
+	db := h.preLoad(h.DB(ctx), "Applications")
+	result := db.First(m, id)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	tickets := TicketHandler{}
+	resources := []WaveTicket{}
+	for _, app := range m.Applications {
+		outcome := WaveTicket{}
+		outcome.Application.With(app.ID, app.Name)
+		ticket := &Ticket{
+			Kind:        r.Kind,
+			Parent:      r.Parent,
+			Fields:      r.Fields,
+			Application: outcome.Application,
+			Tracker:     r.Tracker,
+		}
+		err = tickets.withDefaults(ctx, ticket)
+		if err != nil {
+			outcome.Error = err.Error()
+			resources = append(resources, outcome)
+			continue
+		}
+		ticketModel := ticket.Model()
+		ticketModel.CreateUser = h.CurrentUser(ctx)
+		err = h.DB(ctx).Create(ticketModel).Error
+		if err != nil {
+			outcome.Error = err.Error()
+			resources = append(resources, outcome)
+			continue
+		}
+		ticket.With(ticketModel)
+		outcome.Ticket = ticket
+		resources = append(resources, outcome)
+	}
+
+	h.Respond(ctx, http.StatusOK, resources)
+}
+
 // Update godoc
 // @summary Update a migration wave.
 // @description Update a migration wave.
@@ -125,9 +228,24 @@ func (h MigrationWaveHandler) Update(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
+	err = r.Validate()
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	m := r.Model()
+	err = h.unarchived(ctx, m.Applications)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	m.ID = id
 	m.UpdateUser = h.CurrentUser(ctx)
+	members, err := h.members(ctx, id, m.Applications)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	db := h.DB(ctx).Model(m)
 	db = db.Omit(clause.Associations)
 	result := db.Updates(h.fields(m))
@@ -140,6 +258,11 @@ func (h MigrationWaveHandler) Update(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
+	err = h.membersChanged(ctx, m, members)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	err = h.DB(ctx).Model(m).Association("Stakeholders").Replace("Stakeholders", m.Stakeholders)
 	if err != nil {
 		_ = ctx.Error(err)
@@ -169,6 +292,17 @@ func (h MigrationWaveHandler) Delete(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	members, err := h.members(ctx, id, nil)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m.Applications = nil
+	err = h.membersChanged(ctx, m, members)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	result = h.DB(ctx).Delete(m)
 	if result.Error != nil {
 		_ = ctx.Error(result.Error)
@@ -178,6 +312,73 @@ func (h MigrationWaveHandler) Delete(ctx *gin.Context) {
 	h.Status(ctx, http.StatusNoContent)
 }
 
+// members returns the (current) members of the wave and the
+// applications (to be added) with their current wave.
+func (h MigrationWaveHandler) members(
+	ctx *gin.Context,
+	id uint,
+	applications []model.Application) (members []model.Application, err error) {
+	//
+	ids := []uint{}
+	for _, m := range applications {
+		ids = append(ids, m.ID)
+	}
+	db := h.DB(ctx).Select("ID", "MigrationWaveID")
+	db = db.Where("ID IN ?", ids)
+	if id > 0 {
+		db = db.Or("MigrationWaveID", id)
+	}
+	err = db.Order("ID").Find(&members).Error
+	return
+}
+
+// membersChanged records the application (wave) events
+// for the change of wave membership.
+func (h MigrationWaveHandler) membersChanged(
+	ctx *gin.Context,
+	m *model.MigrationWave,
+	members []model.Application) (err error) {
+	//
+	wanted := map[uint]bool{}
+	for _, app := range m.Applications {
+		wanted[app.ID] = true
+	}
+	for _, app := range members {
+		var after *uint
+		if wanted[app.ID] {
+			after = &m.ID
+		}
+		err = h.waveChanged(ctx, app.ID, app.MigrationWaveID, after)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// unarchived validates the (member) applications are not archived.
+func (h MigrationWaveHandler) unarchived(ctx *gin.Context, applications []model.Application) (err error) {
+	if len(applications) == 0 {
+		return
+	}
+	ids := []uint{}
+	for _, m := range applications {
+		ids = append(ids, m.ID)
+	}
+	var n int64
+	db := h.DB(ctx).Model(&model.Application{})
+	db = db.Where("ID IN ?", ids)
+	db = db.Where("Archived", true)
+	err = db.Count(&n).Error
+	if err != nil {
+		return
+	}
+	if n > 0 {
+		err = &BadRequestError{"archived applications cannot be wave members."}
+	}
+	return
+}
+
 // MigrationWave REST Resource
 type MigrationWave struct {
 	Resource          `yaml:",inline"`
@@ -187,6 +388,9 @@ type MigrationWave struct {
 	Applications      []Ref     `json:"applications"`
 	Stakeholders      []Ref     `json:"stakeholders"`
 	StakeholderGroups []Ref     `json:"stakeholderGroups" yaml:"stakeholderGroups"`
+	// TicketTemplate applied (over the tracker template) when
+	// creating the tickets for the applications in the wave.
+	TicketTemplate *TicketTemplate `json:"ticketTemplate,omitempty" yaml:"ticketTemplate,omitempty"`
 }
 
 // With updates the resource using the model.
@@ -213,6 +417,11 @@ func (r *MigrationWave) With(m *model.MigrationWave) {
 		ref.With(sg.ID, sg.Name)
 		r.StakeholderGroups = append(r.StakeholderGroups, ref)
 	}
+	r.TicketTemplate = nil
+	if len(m.TicketTemplate) > 0 {
+		r.TicketTemplate = &TicketTemplate{}
+		r.TicketTemplate.With(m.TicketTemplate)
+	}
 }
 
 // Model builds a model.
@@ -223,6 +432,9 @@ func (r *MigrationWave) Model() (m *model.MigrationWave) {
 		EndDate:   r.EndDate,
 	}
 	m.ID = r.ID
+	if r.TicketTemplate != nil {
+		m.TicketTemplate = r.TicketTemplate.Model()
+	}
 	for _, ref := range r.Applications {
 		m.Applications = append(
 			m.Applications,
@@ -246,3 +458,28 @@ func (r *MigrationWave) Model() (m *model.MigrationWave) {
 	}
 	return
 }
+
+// Validate the resource.
+func (r *MigrationWave) Validate() (err error) {
+	if r.TicketTemplate != nil {
+		err = r.TicketTemplate.Validate()
+	}
+	return
+}
+
+// WaveTickets REST resource.
+// The ticket created for each application in a migration wave.
+type WaveTickets struct {
+	Kind    string `json:"kind" binding:"required"`
+	Parent  string `json:"parent" binding:"required"`
+	Fields  Fields `json:"fields"`
+	Tracker Ref    `json:"tracker" binding:"required" ref:"tracker"`
+}
+
+// WaveTicket REST resource.
+// The outcome of creating the ticket for an application.
+type WaveTicket struct {
+	Application Ref     `json:"application"`
+	Ticket      *Ticket `json:"ticket,omitempty" yaml:",omitempty"`
+	Error       string  `json:"error,omitempty" yaml:",omitempty"`
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestWaveTicketCreate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	jira := newTracker(g, db, "jira")
+	wave := &model.MigrationWave{Name: "w1"}
+	g.Expect(db.Create(wave).Error).To(gomega.BeNil())
+	a := &model.Application{Name: "a", MigrationWaveID: &wave.ID}
+	g.Expect(db.Create(a).Error).To(gomega.BeNil())
+	b := &model.Application{Name: "b", MigrationWaveID: &wave.ID}
+	g.Expect(db.Create(b).Error).To(gomega.BeNil())
+	ticket := &model.Ticket{Kind: "10", Parent: "1", ApplicationID: b.ID, TrackerID: jira.ID}
+	g.Expect(db.Create(ticket).Error).To(gomega.BeNil())
+
+	h := MigrationWaveHandler{}
+	e := newEngine(db)
+	e.POST(WaveTicketsRoot, h.TicketCreate)
+	body := `{"kind":"10","parent":"2","tracker":{"id":` + strconv.Itoa(int(jira.ID)) + `}}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(
+		http.MethodPost,
+		"/migrationwaves/"+strconv.Itoa(int(wave.ID))+"/tickets",
+		bytes.NewBufferString(body))
+	e.ServeHTTP(w, req)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK), w.Body.String())
+	var outcomes []WaveTicket
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &outcomes)).To(gomega.BeNil())
+	g.Expect(len(outcomes)).To(gomega.Equal(2))
+	for _, outcome := range outcomes {
+		switch outcome.Application.ID {
+		case a.ID:
+			g.Expect(outcome.Error).To(gomega.BeEmpty())
+			g.Expect(outcome.Ticket.Parent).To(gomega.Equal("2"))
+			g.Expect(outcome.Ticket.Tracker.ID).To(gomega.Equal(jira.ID))
+		case b.ID:
+			// Already has a ticket in the tracker.
+			g.Expect(outcome.Error).ToNot(gomega.BeEmpty())
+			g.Expect(outcome.Ticket).To(gomega.BeNil())
+		}
+	}
+}
@@ -42,12 +42,21 @@ const (
 	ContentType   = "Content-Type"
 	Directory     = "X-Directory"
 	Total         = "X-Total"
+	TotalCount    = "X-Total-Count"
+	NextCursor    = "X-Cursor"
+	ETag          = "ETag"
+	IfNoneMatch   = "If-None-Match"
+	LastModified  = "Last-Modified"
+	Retested      = "X-Retested"
+	QueryTimeout  = "X-Query-Timeout"
 )
 
 // MIME Types.
 const (
 	MIMEOCTETSTREAM = "application/octet-stream"
 	TAR             = "application/x-tar"
+	CSV             = "text/csv"
+	OpenMetrics     = "application/openmetrics-text; version=1.0.0; charset=utf-8"
 )
 
 // BindMIMEs supported binding MIME types.
@@ -66,6 +75,7 @@ func All() []Handler {
 		&AdoptionPlanHandler{},
 		&AnalysisHandler{},
 		&ApplicationHandler{},
+		&AppSchemaHandler{},
 		&AuthHandler{},
 		&BusinessServiceHandler{},
 		&CacheHandler{},
@@ -80,10 +90,13 @@ func All() []Handler {
 		&SettingHandler{},
 		&StakeholderHandler{},
 		&StakeholderGroupHandler{},
+		&SuppressionHandler{},
 		&TagHandler{},
 		&TagCategoryHandler{},
 		&TaskHandler{},
 		&TaskGroupHandler{},
+		&TaskScheduleHandler{},
+		&TechnologyHandler{},
 		&TicketHandler{},
 		&TrackerHandler{},
 		&BucketHandler{},
@@ -94,6 +107,8 @@ func All() []Handler {
 		&QuestionnaireHandler{},
 		&AssessmentHandler{},
 		&ArchetypeHandler{},
+		&HealthHandler{},
+		&MaintenanceHandler{},
 	}
 }
 
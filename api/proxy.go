@@ -31,6 +31,7 @@ func (h ProxyHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(ProxiesRoot+"/", h.List)
 	routeGroup.POST(ProxiesRoot, h.Create)
 	routeGroup.GET(ProxyRoot, h.Get)
+	routeGroup.HEAD(ProxyRoot, h.Head)
 	routeGroup.PUT(ProxyRoot, h.Update)
 	routeGroup.DELETE(ProxyRoot, h.Delete)
 }
@@ -52,12 +53,26 @@ func (h ProxyHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &proxy.Model)
 	r := Proxy{}
 	r.With(proxy)
 
 	h.Respond(ctx, http.StatusOK, r)
 }
 
+// Head godoc
+// @summary Check a proxy exists.
+// @description Check a proxy exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags proxies
+// @success 200
+// @success 304
+// @router /proxies/{id} [head]
+// @param id path int true "Proxy ID"
+func (h ProxyHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.Proxy{})
+}
+
 // List godoc
 // @summary List all proxies.
 // @description List all proxies.
@@ -29,6 +29,7 @@ func (h QuestionnaireHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(QuestionnairesRoot+"/", h.List)
 	routeGroup.POST(QuestionnairesRoot, h.Create)
 	routeGroup.GET(QuestionnaireRoot, h.Get)
+	routeGroup.HEAD(QuestionnaireRoot, h.Head)
 	routeGroup.PUT(QuestionnaireRoot, h.Update)
 	routeGroup.DELETE(QuestionnaireRoot, h.Delete)
 }
@@ -50,12 +51,26 @@ func (h QuestionnaireHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 	r := Questionnaire{}
 	r.With(m)
 
 	h.Respond(ctx, http.StatusOK, r)
 }
 
+// Head godoc
+// @summary Check a questionnaire exists.
+// @description Check a questionnaire exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags questionnaires
+// @success 200
+// @success 304
+// @router /questionnaires/{id} [head]
+// @param id path int true "Questionnaire ID"
+func (h QuestionnaireHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.Questionnaire{})
+}
+
 // List godoc
 // @summary List all questionnaires.
 // @description List all questionnaires.
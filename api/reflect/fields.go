@@ -2,6 +2,7 @@ package reflect
 
 import (
 	"reflect"
+	"strings"
 	"time"
 )
 
@@ -21,6 +22,9 @@ func Fields(m interface{}) (mp map[string]interface{}) {
 			if !ft.IsExported() {
 				continue
 			}
+			if strings.Contains(ft.Tag.Get("gorm"), "autoUpdateTime") {
+				continue
+			}
 			switch fv.Kind() {
 			case reflect.Ptr:
 				pt := ft.Type.Elem()
@@ -60,6 +64,41 @@ func Fields(m interface{}) (mp map[string]interface{}) {
 	return
 }
 
+// TaggedField is a field with the tag.
+type TaggedField struct {
+	// Path struct (namespace) path. Example: Tracker.Identity.
+	Path string
+	// Tag value.
+	Tag string
+	// Value (pointer to) the field.
+	Value interface{}
+}
+
+// Tagged returns the (top-level) fields with the tag.
+func Tagged(r interface{}, tag string) (fields []TaggedField) {
+	mv := reflect.ValueOf(r)
+	if mv.Kind() != reflect.Ptr || mv.Elem().Kind() != reflect.Struct {
+		return
+	}
+	mv = mv.Elem()
+	mt := mv.Type()
+	for i := 0; i < mt.NumField(); i++ {
+		ft := mt.Field(i)
+		v, found := ft.Tag.Lookup(tag)
+		if !found || !ft.IsExported() {
+			continue
+		}
+		fields = append(
+			fields,
+			TaggedField{
+				Path:  mt.Name() + "." + ft.Name,
+				Tag:   v,
+				Value: mv.Field(i).Addr().Interface(),
+			})
+	}
+	return
+}
+
 // NameOf returns the name of a model.
 func NameOf(m interface{}) (name string) {
 	mt := reflect.TypeOf(m)
@@ -28,6 +28,7 @@ func (h ReviewHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(ReviewsRoot+"/", h.List)
 	routeGroup.POST(ReviewsRoot, h.Create)
 	routeGroup.GET(ReviewRoot, h.Get)
+	routeGroup.HEAD(ReviewRoot, h.Head)
 	routeGroup.PUT(ReviewRoot, h.Update)
 	routeGroup.DELETE(ReviewRoot, h.Delete)
 	routeGroup.POST(CopyRoot, h.CopyReview)
@@ -50,12 +51,26 @@ func (h ReviewHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 	r := Review{}
 	r.With(m)
 
 	h.Respond(ctx, http.StatusOK, r)
 }
 
+// Head godoc
+// @summary Check a review exists.
+// @description Check a review exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags reviews
+// @success 200
+// @success 304
+// @router /reviews/{id} [head]
+// @param id path int true "Review ID"
+func (h ReviewHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.Review{})
+}
+
 // List godoc
 // @summary List all reviews.
 // @description List all reviews.
@@ -29,6 +29,7 @@ func (h RuleSetHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(RuleSetsRoot+"/", h.List)
 	routeGroup.POST(RuleSetsRoot, h.Create)
 	routeGroup.GET(RuleSetRoot, h.Get)
+	routeGroup.HEAD(RuleSetRoot, h.Head)
 	routeGroup.PUT(RuleSetRoot, h.Update)
 	routeGroup.DELETE(RuleSetRoot, h.Delete)
 }
@@ -53,12 +54,26 @@ func (h RuleSetHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &ruleset.Model)
 	r := RuleSet{}
 	r.With(ruleset)
 
 	h.Respond(ctx, http.StatusOK, r)
 }
 
+// Head godoc
+// @summary Check a RuleSet exists.
+// @description Check a RuleSet exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags rulesets
+// @success 200
+// @success 304
+// @router /rulesets/{id} [head]
+// @param id path int true "RuleSet ID"
+func (h RuleSetHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.RuleSet{})
+}
+
 // List godoc
 // @summary List all bindings.
 // @description List all bindings.
@@ -0,0 +1,255 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+// Routes
+const (
+	AppAnalysisSarifRoot = AppAnalysisRoot + "/sarif"
+)
+
+// Params
+const (
+	RootParam = "root"
+)
+
+// SARIF.
+const (
+	MIMESARIF     = "application/sarif+json"
+	SarifVersion  = "2.1.0"
+	SarifSchema   = "https://json.schemastore.org/sarif-2.1.0.json"
+	SarifToolName = "konveyor"
+	SarifToolURI  = "https://konveyor.io"
+	SarifError    = "error"
+	SarifWarning  = "warning"
+	SarifNote     = "note"
+	SarifFileURI  = "file://"
+)
+
+// AppLatestSarif godoc
+// @summary Get the latest analysis as SARIF.
+// @description Get the latest analysis as a SARIF 2.1.0 log.
+// @description Rules are identified by: ruleset/rule.
+// @description The result level is mapped from the issue category:
+// @description - mandatory: error
+// @description - optional: warning
+// @description - (other): note
+// @description The (optional) root is removed from the incident file
+// @description paths to make them relative. Example: ?root=/opt/input/source.
+// @tags analyses
+// @produce application/sarif+json
+// @success 200 {object} api.SarifLog
+// @router /applications/{id}/analysis/sarif [get]
+// @param id path int true "Application ID"
+// @param root query string false "Source root"
+func (h AnalysisHandler) AppLatestSarif(ctx *gin.Context) {
+	id := h.pk(ctx)
+	app := &model.Application{}
+	err := h.DB(ctx).Select("ID", "Name").First(app, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m := &model.Analysis{}
+	db := h.DB(ctx).Select("ID")
+	db = db.Where("ApplicationID", id)
+	err = db.Last(m).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	run := SarifRun{}
+	run.With(app, m)
+	root := ctx.Query(RootParam)
+	batch := 10
+	for b := 0; ; b += batch {
+		db := h.DB(ctx)
+		db = db.Preload("Incidents")
+		db = db.Order("ID")
+		db = db.Limit(batch)
+		db = db.Offset(b)
+		var issues []model.Issue
+		err = db.Find(&issues, "AnalysisID", m.ID).Error
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		if len(issues) == 0 {
+			break
+		}
+		for i := range issues {
+			run.addIssue(&issues[i], root)
+		}
+	}
+	r := SarifLog{
+		Schema:  SarifSchema,
+		Version: SarifVersion,
+		Runs:    []SarifRun{run},
+	}
+	ctx.Header("Content-Type", MIMESARIF)
+	ctx.Status(http.StatusOK)
+	err = json.NewEncoder(ctx.Writer).Encode(r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+}
+
+// SarifLog SARIF (2.1.0) log.
+type SarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SarifRun `json:"runs"`
+}
+
+// SarifRun SARIF run.
+type SarifRun struct {
+	Tool       SarifTool         `json:"tool"`
+	Results    []SarifResult     `json:"results"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// With updates the run with the application and analysis.
+func (r *SarifRun) With(app *model.Application, m *model.Analysis) {
+	r.Tool.Driver = SarifDriver{
+		Name:           SarifToolName,
+		InformationURI: SarifToolURI,
+		Rules:          []SarifRule{},
+	}
+	r.Results = []SarifResult{}
+	r.Properties = map[string]string{
+		"application": app.Name,
+		"analysis":    strconv.Itoa(int(m.ID)),
+	}
+}
+
+// addIssue adds the issue (rule) and incidents (results).
+func (r *SarifRun) addIssue(m *model.Issue, root string) {
+	issue := Issue{}
+	issue.With(m)
+	rule := SarifRule{
+		ID:   m.RuleSet + "/" + m.Rule,
+		Name: m.Name,
+		Properties: SarifRuleProperties{
+			Category: m.Category,
+			Effort:   m.Effort,
+			Tags:     issue.Labels,
+		},
+	}
+	if m.Description != "" {
+		rule.ShortDescription = &SarifMessage{Text: m.Description}
+	}
+	if len(issue.Links) > 0 {
+		rule.HelpURI = issue.Links[0].URL
+	}
+	index := len(r.Tool.Driver.Rules)
+	r.Tool.Driver.Rules = append(r.Tool.Driver.Rules, rule)
+	level := SarifNote
+	switch m.Category {
+	case "mandatory":
+		level = SarifError
+	case "optional":
+		level = SarifWarning
+	}
+	for i := range m.Incidents {
+		incident := &m.Incidents[i]
+		result := SarifResult{
+			RuleID:    rule.ID,
+			RuleIndex: index,
+			Level:     level,
+			Message:   SarifMessage{Text: incident.Message},
+		}
+		if result.Message.Text == "" {
+			result.Message.Text = m.Description
+		}
+		if result.Message.Text == "" {
+			result.Message.Text = rule.ID
+		}
+		location := SarifLocation{}
+		location.Physical.Artifact.URI = r.uri(incident.File, root)
+		if incident.Line > 0 {
+			location.Physical.Region = &SarifRegion{StartLine: incident.Line}
+		}
+		result.Locations = []SarifLocation{location}
+		r.Results = append(r.Results, result)
+	}
+}
+
+// uri returns the artifact URI.
+// The root is removed to make the path relative.
+func (r *SarifRun) uri(path, root string) (uri string) {
+	uri = path
+	if root == "" {
+		return
+	}
+	p := strings.TrimPrefix(path, SarifFileURI)
+	root = strings.TrimSuffix(strings.TrimPrefix(root, SarifFileURI), "/")
+	if relative, found := strings.CutPrefix(p, root+"/"); found {
+		uri = relative
+	}
+	return
+}
+
+// SarifTool SARIF tool.
+type SarifTool struct {
+	Driver SarifDriver `json:"driver"`
+}
+
+// SarifDriver SARIF tool driver.
+type SarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SarifRule `json:"rules"`
+}
+
+// SarifRule SARIF reporting descriptor (rule).
+type SarifRule struct {
+	ID               string              `json:"id"`
+	Name             string              `json:"name,omitempty"`
+	ShortDescription *SarifMessage       `json:"shortDescription,omitempty"`
+	HelpURI          string              `json:"helpUri,omitempty"`
+	Properties       SarifRuleProperties `json:"properties"`
+}
+
+// SarifRuleProperties SARIF rule properties.
+type SarifRuleProperties struct {
+	Category string   `json:"category"`
+	Effort   int      `json:"effort"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// SarifResult SARIF result.
+type SarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   SarifMessage    `json:"message"`
+	Locations []SarifLocation `json:"locations"`
+}
+
+// SarifMessage SARIF message.
+type SarifMessage struct {
+	Text string `json:"text"`
+}
+
+// SarifLocation SARIF location.
+type SarifLocation struct {
+	Physical struct {
+		Artifact struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region *SarifRegion `json:"region,omitempty"`
+	} `json:"physicalLocation"`
+}
+
+// SarifRegion SARIF region.
+type SarifRegion struct {
+	StartLine int `json:"startLine"`
+}
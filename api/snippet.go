@@ -0,0 +1,210 @@
+package api
+
+import (
+	"bufio"
+	"net/http"
+	"os"
+	pathlib "path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+// Routes
+const (
+	AnalysesIncidentsRoot       = AnalysesRoot + "/incidents"
+	AnalysisIncidentRoot        = AnalysesIncidentsRoot + "/:" + ID
+	AnalysisIncidentSnippetRoot = AnalysisIncidentRoot + "/snippet"
+)
+
+// Params
+const (
+	ContextParam = "context"
+)
+
+// Snippet.
+const (
+	// SnippetContext the default number of context lines.
+	SnippetContext = 5
+	// SnippetContextMax the maximum number of context lines.
+	SnippetContextMax = 100
+	// SnippetBucket the lines read from the application bucket.
+	SnippetBucket = "bucket"
+	// SnippetCodeSnip the lines read from the incident code snip.
+	SnippetCodeSnip = "codeSnip"
+)
+
+// snipLine matches a (numbered) code snip line.
+var snipLine = regexp.MustCompile(`^\s*(\d+)  ?(.*)$`)
+
+// IncidentSnippet godoc
+// @summary Get the source snippet of an incident.
+// @description Get the source lines surrounding the incident line.
+// @description The lines are read from the file in the application bucket
+// @description when found. The bucket path is the incident file path (file:// removed)
+// @description with the (optional) root removed. Example: ?root=/opt/input/source.
+// @description Otherwise, the lines are read from the (stored) incident code snip.
+// @description The number of lines before and after the incident line is
+// @description specified by ?context= (default: 5, max: 100).
+// @tags incidents
+// @produce json
+// @success 200 {object} api.Snippet
+// @router /analyses/incidents/{id}/snippet [get]
+// @param id path int true "Incident ID"
+// @param context query int false "Context lines"
+// @param root query string false "Source root"
+func (h AnalysisHandler) IncidentSnippet(ctx *gin.Context) {
+	n := SnippetContext
+	s := ctx.Query(ContextParam)
+	if s != "" {
+		var err error
+		n, err = strconv.Atoi(s)
+		if err != nil || n < 0 || n > SnippetContextMax {
+			err = &BadRequestError{
+				ContextParam + ": '" + s + "' must be an integer (0-" + strconv.Itoa(SnippetContextMax) + ").",
+			}
+			_ = ctx.Error(err)
+			return
+		}
+	}
+	id := h.pk(ctx)
+	m := &model.Incident{}
+	err := h.DB(ctx).First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	r := Snippet{
+		Incident: m.ID,
+		File:     m.File,
+		Line:     m.Line,
+		Lines:    []SnippetLine{},
+	}
+	var bucketPath string
+	db := h.DB(ctx).Table("Incident n")
+	db = db.Select("b.Path")
+	db = db.Joins("JOIN Issue i ON i.ID = n.IssueID")
+	db = db.Joins("JOIN Analysis a ON a.ID = i.AnalysisID")
+	db = db.Joins("JOIN Application app ON app.ID = a.ApplicationID")
+	db = db.Joins("JOIN Bucket b ON b.ID = app.BucketID")
+	db = db.Where("n.ID", m.ID)
+	err = db.Scan(&bucketPath).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if bucketPath != "" {
+		path := r.path(bucketPath, ctx.Query(RootParam))
+		found, err := r.read(path, n)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		if found {
+			r.Source = SnippetBucket
+			h.Respond(ctx, http.StatusOK, r)
+			return
+		}
+	}
+	if m.CodeSnip == "" {
+		h.Status(ctx, http.StatusNotFound)
+		return
+	}
+	r.Source = SnippetCodeSnip
+	r.snip(m.CodeSnip, n)
+
+	h.Respond(ctx, http.StatusOK, r)
+}
+
+// Snippet REST resource.
+type Snippet struct {
+	Incident uint          `json:"incident"`
+	File     string        `json:"file"`
+	Line     int           `json:"line"`
+	Source   string        `json:"source"`
+	Lines    []SnippetLine `json:"lines"`
+}
+
+// path returns the bucket path of the incident file.
+// The path is constrained to the bucket.
+func (r *Snippet) path(bucket, root string) (path string) {
+	p := strings.TrimPrefix(r.File, SarifFileURI)
+	root = strings.TrimSuffix(strings.TrimPrefix(root, SarifFileURI), "/")
+	if root != "" {
+		if relative, found := strings.CutPrefix(p, root+"/"); found {
+			p = relative
+		}
+	}
+	path = pathlib.Join(bucket, pathlib.Clean("/"+p))
+	return
+}
+
+// read the lines surrounding the incident line from the file.
+// Returns false when the file is not found.
+func (r *Snippet) read(path string, n int) (found bool, err error) {
+	st, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+	if st.IsDir() {
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	found = true
+	begin, end := r.Line-n, r.Line+n
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 4096), 1<<20)
+	for number := 1; scanner.Scan(); number++ {
+		if number > end {
+			break
+		}
+		if number < begin {
+			continue
+		}
+		r.Lines = append(
+			r.Lines,
+			SnippetLine{
+				Number: number,
+				Text:   scanner.Text(),
+			})
+	}
+	err = scanner.Err()
+	return
+}
+
+// snip adds the lines surrounding the incident line using the
+// code snip. Numbered lines are selected by number. Otherwise, all
+// lines are included (unnumbered).
+func (r *Snippet) snip(snip string, n int) {
+	begin, end := r.Line-n, r.Line+n
+	for _, text := range strings.Split(strings.TrimRight(snip, "\n"), "\n") {
+		line := SnippetLine{Text: text}
+		match := snipLine.FindStringSubmatch(text)
+		if match != nil {
+			line.Number, _ = strconv.Atoi(match[1])
+			line.Text = match[2]
+			if line.Number < begin || line.Number > end {
+				continue
+			}
+		}
+		r.Lines = append(r.Lines, line)
+	}
+}
+
+// SnippetLine snippet (source) line.
+type SnippetLine struct {
+	Number int    `json:"number,omitempty" yaml:",omitempty"`
+	Text   string `json:"text"`
+}
@@ -27,6 +27,7 @@ func (h StakeholderHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(StakeholdersRoot+"/", h.List)
 	routeGroup.POST(StakeholdersRoot, h.Create)
 	routeGroup.GET(StakeholderRoot, h.Get)
+	routeGroup.HEAD(StakeholderRoot, h.Head)
 	routeGroup.PUT(StakeholderRoot, h.Update)
 	routeGroup.DELETE(StakeholderRoot, h.Delete)
 }
@@ -48,12 +49,26 @@ func (h StakeholderHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 
 	resource := Stakeholder{}
 	resource.With(m)
 	h.Respond(ctx, http.StatusOK, resource)
 }
 
+// Head godoc
+// @summary Check a stakeholder exists.
+// @description Check a stakeholder exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags stakeholders
+// @success 200
+// @success 304
+// @router /stakeholders/{id} [head]
+// @param id path int true "Stakeholder ID"
+func (h StakeholderHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.Stakeholder{})
+}
+
 // List godoc
 // @summary List all stakeholders.
 // @description List all stakeholders.
@@ -0,0 +1,382 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	pathlib "path"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	qf "github.com/konveyor/tackle2-hub/api/filter"
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Routes
+const (
+	SuppressionsRoot = "/suppressions"
+	SuppressionRoot  = SuppressionsRoot + "/:" + ID
+)
+
+// Params
+const (
+	SuppressedParam = "suppressed"
+)
+
+// Suppression scopes.
+const (
+	SuppressGlobal      = "global"
+	SuppressApplication = "application"
+	SuppressFile        = "file"
+)
+
+// SuppressionHandler handles issue suppression routes.
+type SuppressionHandler struct {
+	BaseHandler
+}
+
+// AddRoutes adds routes.
+func (h SuppressionHandler) AddRoutes(e *gin.Engine) {
+	routeGroup := e.Group("/")
+	routeGroup.Use(Required("suppressions"))
+	routeGroup.GET(SuppressionsRoot, h.List)
+	routeGroup.GET(SuppressionsRoot+"/", h.List)
+	routeGroup.POST(SuppressionsRoot, h.Create)
+	routeGroup.GET(SuppressionRoot, h.Get)
+	routeGroup.PUT(SuppressionRoot, h.Update)
+	routeGroup.DELETE(SuppressionRoot, h.Delete)
+}
+
+// Get godoc
+// @summary Get a suppression by ID.
+// @description Get an issue suppression by ID.
+// @tags suppressions
+// @produce json
+// @success 200 {object} api.Suppression
+// @router /suppressions/{id} [get]
+// @param id path int true "Suppression ID"
+func (h SuppressionHandler) Get(ctx *gin.Context) {
+	m := &model.IssueSuppression{}
+	id := h.pk(ctx)
+	db := h.preLoad(h.DB(ctx), clause.Associations)
+	err := db.First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	r := Suppression{}
+	r.With(m)
+
+	h.Respond(ctx, http.StatusOK, r)
+}
+
+// List godoc
+// @summary List all suppressions.
+// @description List all issue suppressions.
+// @description filters:
+// @description - ruleset
+// @description - rule
+// @description - file
+// @description - application.id
+// @tags suppressions
+// @produce json
+// @success 200 {object} []api.Suppression
+// @router /suppressions [get]
+func (h SuppressionHandler) List(ctx *gin.Context) {
+	filter, err := qf.New(ctx,
+		[]qf.Assert{
+			{Field: "ruleset", Kind: qf.STRING},
+			{Field: "rule", Kind: qf.STRING},
+			{Field: "file", Kind: qf.STRING},
+			{Field: "application.id", Kind: qf.LITERAL},
+		})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	db := h.preLoad(h.DB(ctx), clause.Associations)
+	db = filter.Where(db)
+	appFilter := filter.Resource("application")
+	if f, found := appFilter.Field("id"); found {
+		f = f.As("ApplicationID")
+		db = f.Where(db)
+	}
+	var list []model.IssueSuppression
+	err = db.Find(&list).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	resources := []Suppression{}
+	for i := range list {
+		r := Suppression{}
+		r.With(&list[i])
+		resources = append(resources, r)
+	}
+
+	h.Respond(ctx, http.StatusOK, resources)
+}
+
+// Create godoc
+// @summary Create a suppression.
+// @description Create an issue suppression.
+// @description Issues are matched by ruleset/rule. The scope is determined by:
+// @description - application: suppressed for the application.
+// @description - file: incidents suppressed when the file matches the (glob) pattern.
+// @description   An issue is suppressed when all of the incidents are suppressed.
+// @description - (neither): suppressed for all applications.
+// @description The reason is required.
+// @tags suppressions
+// @accept json
+// @produce json
+// @success 201 {object} api.Suppression
+// @router /suppressions [post]
+// @param suppression body api.Suppression true "Suppression data"
+func (h SuppressionHandler) Create(ctx *gin.Context) {
+	r := &Suppression{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = h.validate(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m := r.Model()
+	m.CreateUser = h.BaseHandler.CurrentUser(ctx)
+	err = h.DB(ctx).Create(m).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = h.preLoad(h.DB(ctx), clause.Associations).First(m).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	r.With(m)
+
+	h.Respond(ctx, http.StatusCreated, r)
+}
+
+// Delete godoc
+// @summary Delete a suppression.
+// @description Delete an issue suppression.
+// @tags suppressions
+// @success 204
+// @router /suppressions/{id} [delete]
+// @param id path int true "Suppression ID"
+func (h SuppressionHandler) Delete(ctx *gin.Context) {
+	id := h.pk(ctx)
+	m := &model.IssueSuppression{}
+	err := h.DB(ctx).First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = h.DB(ctx).Delete(m).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	h.Status(ctx, http.StatusNoContent)
+}
+
+// Update godoc
+// @summary Update a suppression.
+// @description Update an issue suppression.
+// @tags suppressions
+// @accept json
+// @success 204
+// @router /suppressions/{id} [put]
+// @param id path int true "Suppression ID"
+// @param suppression body api.Suppression true "Suppression data"
+func (h SuppressionHandler) Update(ctx *gin.Context) {
+	id := h.pk(ctx)
+	r := &Suppression{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = h.validate(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m := r.Model()
+	m.ID = id
+	m.UpdateUser = h.BaseHandler.CurrentUser(ctx)
+	db := h.DB(ctx).Model(m)
+	db = db.Omit(clause.Associations)
+	err = db.Updates(h.fields(m)).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	h.Status(ctx, http.StatusNoContent)
+}
+
+// validate the suppression.
+func (h *SuppressionHandler) validate(ctx *gin.Context, r *Suppression) (err error) {
+	if r.File != "" {
+		_, err = pathlib.Match(r.File, "")
+		if err != nil {
+			err = &BadRequestError{"file: '" + r.File + "' must be a (glob) pattern."}
+			return
+		}
+	}
+	if r.Application != nil {
+		m := &model.Application{}
+		err = h.DB(ctx).Select("ID").First(m, r.Application.ID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = &BadRequestError{
+					"application: (id=" + strconv.Itoa(int(r.Application.ID)) + ") not found.",
+				}
+			}
+			return
+		}
+	}
+	return
+}
+
+// Suppression REST resource.
+type Suppression struct {
+	Resource    `yaml:",inline"`
+	RuleSet     string `json:"ruleset" binding:"required"`
+	Rule        string `json:"rule" binding:"required"`
+	Application *Ref   `json:"application,omitempty" yaml:",omitempty"`
+	File        string `json:"file,omitempty" yaml:",omitempty"`
+	Reason      string `json:"reason" binding:"required"`
+	Scope       string `json:"scope,omitempty" yaml:",omitempty"`
+}
+
+// With updates the resource with the model.
+func (r *Suppression) With(m *model.IssueSuppression) {
+	r.Resource.With(&m.Model)
+	r.RuleSet = m.RuleSet
+	r.Rule = m.Rule
+	r.Application = r.refPtr(m.ApplicationID, m.Application)
+	r.File = m.File
+	r.Reason = m.Reason
+	switch {
+	case m.File != "":
+		r.Scope = SuppressFile
+	case m.ApplicationID != nil:
+		r.Scope = SuppressApplication
+	default:
+		r.Scope = SuppressGlobal
+	}
+}
+
+// Model builds a model.
+func (r *Suppression) Model() (m *model.IssueSuppression) {
+	m = &model.IssueSuppression{
+		RuleSet: r.RuleSet,
+		Rule:    r.Rule,
+		File:    r.File,
+		Reason:  r.Reason,
+	}
+	m.ID = r.ID
+	if r.Application != nil {
+		m.ApplicationID = &r.Application.ID
+	}
+	return
+}
+
+// suppressed applies the (optional) suppressed parameter.
+// true: only suppressed.
+// false: suppressed excluded.
+func (h *AnalysisHandler) suppressed(ctx *gin.Context, db *gorm.DB, column string, ids *gorm.DB) (out *gorm.DB, err error) {
+	out = db
+	s := ctx.Query(SuppressedParam)
+	if s == "" {
+		return
+	}
+	b, pErr := strconv.ParseBool(s)
+	if pErr != nil {
+		err = &BadRequestError{SuppressedParam + ": '" + s + "' must be a boolean."}
+		return
+	}
+	if b {
+		out = db.Where(column+" IN (?)", ids)
+	} else {
+		out = db.Where(column+" NOT IN (?)", ids)
+	}
+	return
+}
+
+// suppressedIn returns which of the IDs are suppressed.
+func (h *AnalysisHandler) suppressedIn(ctx *gin.Context, ids *gorm.DB, in []uint) (found map[uint]bool, err error) {
+	found = map[uint]bool{}
+	if len(in) == 0 {
+		return
+	}
+	var list []uint
+	db := h.DB(ctx).Table("(?) q", ids)
+	db = db.Where("q.ID IN ?", in)
+	err = db.Pluck("q.ID", &list).Error
+	if err != nil {
+		return
+	}
+	for _, id := range list {
+		found[id] = true
+	}
+	return
+}
+
+// suppressedIssueIDs returns the suppressed issue IDs.
+// An issue is suppressed when matched by an (application or
+// global) suppression or when all incidents are suppressed.
+func (h *AnalysisHandler) suppressedIssueIDs(ctx *gin.Context) (q *gorm.DB) {
+	issueScope := h.suppressionMatch(ctx)
+	issueScope = issueScope.Where("s.File = ''")
+	fileScope := h.suppressionMatch(ctx)
+	fileScope = fileScope.Where("s.File != ''")
+	fileScope = fileScope.Where("n.File GLOB s.File")
+	incidents := h.DB(ctx).Table("Incident n")
+	incidents = incidents.Select("1")
+	incidents = incidents.Where("n.IssueID = i.ID")
+	unsuppressed := h.DB(ctx).Table("Incident n")
+	unsuppressed = unsuppressed.Select("1")
+	unsuppressed = unsuppressed.Where("n.IssueID = i.ID")
+	unsuppressed = unsuppressed.Where("NOT EXISTS (?)", fileScope)
+	q = h.DB(ctx).Table("Issue i")
+	q = q.Select("i.ID")
+	q = q.Joins("JOIN Analysis a ON a.ID = i.AnalysisID")
+	q = q.Where(
+		"EXISTS (?) OR (EXISTS (?) AND NOT EXISTS (?))",
+		issueScope,
+		incidents,
+		unsuppressed)
+	return
+}
+
+// suppressedIncidentIDs returns the suppressed incident IDs.
+func (h *AnalysisHandler) suppressedIncidentIDs(ctx *gin.Context) (q *gorm.DB) {
+	match := h.suppressionMatch(ctx)
+	match = match.Where("(s.File = '' OR n.File GLOB s.File)")
+	q = h.DB(ctx).Table("Incident n")
+	q = q.Select("n.ID")
+	q = q.Joins("JOIN Issue i ON i.ID = n.IssueID")
+	q = q.Joins("JOIN Analysis a ON a.ID = i.AnalysisID")
+	q = q.Where("EXISTS (?)", match)
+	return
+}
+
+// suppressionMatch returns the suppressions matching
+// the issue (i) of the analysis (a).
+func (h *AnalysisHandler) suppressionMatch(ctx *gin.Context) (q *gorm.DB) {
+	q = h.DB(ctx).Table("IssueSuppression s")
+	q = q.Select("1")
+	q = q.Where("s.RuleSet = i.RuleSet")
+	q = q.Where("s.Rule = i.Rule")
+	q = q.Where("(s.ApplicationID IS NULL OR s.ApplicationID = a.ApplicationID)")
+	return
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestSuppression(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	apps := []model.Application{{Name: "a"}, {Name: "b"}}
+	g.Expect(db.Create(&apps).Error).To(gomega.BeNil())
+	analyses := []model.Analysis{
+		{
+			ApplicationID: apps[0].ID,
+			Issues: []model.Issue{
+				{
+					RuleSet:  "rs",
+					Rule:     "r1",
+					Category: "mandatory",
+					Incidents: []model.Incident{
+						{File: "/src/test/A.java"},
+						{File: "/src/main/B.java"},
+					},
+				},
+				{
+					RuleSet:  "rs",
+					Rule:     "r2",
+					Category: "optional",
+					Incidents: []model.Incident{
+						{File: "/src/test/C.java"},
+					},
+				},
+			},
+		},
+		{
+			ApplicationID: apps[1].ID,
+			Issues: []model.Issue{
+				{RuleSet: "rs", Rule: "r1", Category: "mandatory"},
+				{RuleSet: "rs", Rule: "r3", Category: "mandatory"},
+			},
+		},
+	}
+	g.Expect(db.Create(&analyses).Error).To(gomega.BeNil())
+
+	h := SuppressionHandler{}
+	ah := AnalysisHandler{}
+	e := newEngine(db)
+	e.POST(SuppressionsRoot, h.Create)
+	e.GET(SuppressionsRoot, h.List)
+	e.DELETE(SuppressionRoot, h.Delete)
+	e.GET(AnalysesIssuesRoot, ah.Issues)
+	e.GET(AnalysisIncidentsRoot, ah.Incidents)
+	post := func(r Suppression) (w *httptest.ResponseRecorder) {
+		b, _ := json.Marshal(r)
+		w = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, SuppressionsRoot, bytes.NewReader(b))
+		req.Header.Set(ContentType, "application/json")
+		e.ServeHTTP(w, req)
+		return
+	}
+	issues := func(query string) (flags map[uint]bool) {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, AnalysesIssuesRoot+query, nil))
+		g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+		list := []Issue{}
+		g.Expect(json.Unmarshal(w.Body.Bytes(), &list)).To(gomega.BeNil())
+		flags = map[uint]bool{}
+		for _, r := range list {
+			flags[r.ID] = r.Suppressed
+		}
+		return
+	}
+	// Validation.
+	w := post(Suppression{RuleSet: "rs", Rule: "r1"})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	w = post(Suppression{RuleSet: "rs", Rule: "r1", Reason: "x", Application: &Ref{ID: 99}})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	w = post(Suppression{RuleSet: "rs", Rule: "r1", Reason: "x", File: "["})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	// File scope: issue r2 (all incidents) suppressed,
+	// issue r1 (some incidents) not suppressed.
+	w = post(Suppression{RuleSet: "rs", Rule: "r1", Reason: "tests", File: "/src/test/*"})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusCreated))
+	r := Suppression{}
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+	g.Expect(r.Scope).To(gomega.Equal(SuppressFile))
+	w = post(Suppression{RuleSet: "rs", Rule: "r2", Reason: "tests", File: "/src/test/*"})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusCreated))
+	g.Expect(issues("")).To(gomega.Equal(map[uint]bool{1: false, 2: true, 3: false, 4: false}))
+	// Incidents flagged.
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/analyses/issues/1/incidents", nil))
+	incidents := []Incident{}
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &incidents)).To(gomega.BeNil())
+	g.Expect(len(incidents)).To(gomega.Equal(2))
+	g.Expect(incidents[0].Suppressed).To(gomega.BeTrue())
+	g.Expect(incidents[1].Suppressed).To(gomega.BeFalse())
+	// Application scope.
+	w = post(Suppression{RuleSet: "rs", Rule: "r1", Reason: "triaged", Application: &Ref{ID: apps[1].ID}})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusCreated))
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+	g.Expect(r.Scope).To(gomega.Equal(SuppressApplication))
+	g.Expect(r.Application.Name).To(gomega.Equal("b"))
+	g.Expect(issues("")).To(gomega.Equal(map[uint]bool{1: false, 2: true, 3: true, 4: false}))
+	// Global scope.
+	w = post(Suppression{RuleSet: "rs", Rule: "r3", Reason: "noise"})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusCreated))
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+	g.Expect(r.Scope).To(gomega.Equal(SuppressGlobal))
+	g.Expect(issues("?suppressed=false")).To(gomega.Equal(map[uint]bool{1: false}))
+	g.Expect(issues("?suppressed=true")).To(gomega.Equal(map[uint]bool{2: true, 3: true, 4: true}))
+	// List and delete.
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, SuppressionsRoot+"?filter=application.id="+"2", nil))
+	list := []Suppression{}
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &list)).To(gomega.BeNil())
+	g.Expect(len(list)).To(gomega.Equal(1))
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/suppressions/4", nil))
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	g.Expect(issues("?suppressed=true")).To(gomega.Equal(map[uint]bool{2: true, 3: true}))
+}
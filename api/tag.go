@@ -27,6 +27,7 @@ func (h TagHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(TagsRoot+"/", h.List)
 	routeGroup.POST(TagsRoot, h.Create)
 	routeGroup.GET(TagRoot, h.Get)
+	routeGroup.HEAD(TagRoot, h.Head)
 	routeGroup.PUT(TagRoot, h.Update)
 	routeGroup.DELETE(TagRoot, h.Delete)
 }
@@ -48,12 +49,26 @@ func (h TagHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 
 	resource := Tag{}
 	resource.With(m)
 	h.Respond(ctx, http.StatusOK, resource)
 }
 
+// Head godoc
+// @summary Check a tag exists.
+// @description Check a tag exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags tags
+// @success 200
+// @success 304
+// @router /tags/{id} [head]
+// @param id path int true "Tag ID"
+func (h TagHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.Tag{})
+}
+
 // List godoc
 // @summary List all tags.
 // @description List all tags.
@@ -166,7 +181,7 @@ func (h TagHandler) Update(ctx *gin.Context) {
 type Tag struct {
 	Resource `yaml:",inline"`
 	Name     string `json:"name" binding:"required"`
-	Category Ref    `json:"category" binding:"required"`
+	Category Ref    `json:"category" binding:"required" ref:"tagCategory"`
 }
 
 // With updates the resource with the model.
@@ -28,6 +28,7 @@ func (h TagCategoryHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(TagCategoriesRoot+"/", h.List)
 	routeGroup.POST(TagCategoriesRoot, h.Create)
 	routeGroup.GET(TagCategoryRoot, h.Get)
+	routeGroup.HEAD(TagCategoryRoot, h.Head)
 	routeGroup.PUT(TagCategoryRoot, h.Update)
 	routeGroup.DELETE(TagCategoryRoot, h.Delete)
 	routeGroup.GET(TagCategoryTagsRoot, h.TagList)
@@ -51,12 +52,26 @@ func (h TagCategoryHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 
 	resource := TagCategory{}
 	resource.With(m)
 	h.Respond(ctx, http.StatusOK, resource)
 }
 
+// Head godoc
+// @summary Check a tag category exists.
+// @description Check a tag category exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags tagcategories
+// @success 200
+// @success 304
+// @router /tagcategories/{id} [head]
+// @param id path int true "Tag Category ID"
+func (h TagCategoryHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.TagCategory{})
+}
+
 // List godoc
 // @summary List all tag categories.
 // @description List all tag categories.
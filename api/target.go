@@ -31,6 +31,7 @@ func (h TargetHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(TargetsRoot+"/", h.List)
 	routeGroup.POST(TargetsRoot, h.Create)
 	routeGroup.GET(TargetRoot, h.Get)
+	routeGroup.HEAD(TargetRoot, h.Head)
 	routeGroup.PUT(TargetRoot, h.Update)
 	routeGroup.DELETE(TargetRoot, h.Delete)
 }
@@ -56,12 +57,26 @@ func (h TargetHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &target.Model)
 	r := Target{}
 	r.With(target)
 
 	h.Respond(ctx, http.StatusOK, r)
 }
 
+// Head godoc
+// @summary Check a Target exists.
+// @description Check a Target exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags targets
+// @success 200
+// @success 304
+// @router /targets/{id} [head]
+// @param id path int true "Target ID"
+func (h TargetHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.Target{})
+}
+
 // List godoc
 // @summary List all targets.
 // @description List all targets.
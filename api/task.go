@@ -2,35 +2,45 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"path"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/k8s"
 	"github.com/konveyor/tackle2-hub/model"
 	tasking "github.com/konveyor/tackle2-hub/task"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/utils/strings/slices"
 )
 
 // Routes
 const (
 	TasksRoot             = "/tasks"
+	TasksReportRoot       = TasksRoot + "/report"
+	TasksBulkRoot         = TasksRoot + "/bulk"
 	TaskRoot              = TasksRoot + "/:" + ID
 	TaskReportRoot        = TaskRoot + "/report"
 	TaskBucketRoot        = TaskRoot + "/bucket"
 	TaskBucketContentRoot = TaskBucketRoot + "/*" + Wildcard
 	TaskSubmitRoot        = TaskRoot + "/submit"
 	TaskCancelRoot        = TaskRoot + "/cancel"
+	TaskLogRoot           = TaskRoot + "/log"
 )
 
 const (
 	LocatorParam = "locator"
+	PodParam     = "pod"
+	Follow       = "follow"
 )
 
 // TaskHandler handles task routes.
@@ -44,13 +54,17 @@ func (h TaskHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.Use(Required("tasks"))
 	routeGroup.GET(TasksRoot, h.List)
 	routeGroup.GET(TasksRoot+"/", h.List)
+	routeGroup.GET(TasksReportRoot, h.QueueReport)
 	routeGroup.POST(TasksRoot, h.Create)
+	routeGroup.POST(TasksBulkRoot, h.BulkCreate)
 	routeGroup.GET(TaskRoot, h.Get)
+	routeGroup.HEAD(TaskRoot, h.Head)
 	routeGroup.PUT(TaskRoot, h.Update)
 	routeGroup.DELETE(TaskRoot, h.Delete)
 	// Actions
 	routeGroup.PUT(TaskSubmitRoot, h.Submit, h.Update)
 	routeGroup.PUT(TaskCancelRoot, h.Cancel)
+	routeGroup.GET(TaskLogRoot, h.Log)
 	// Bucket
 	routeGroup = e.Group("/")
 	routeGroup.Use(Required("tasks.bucket"))
@@ -84,6 +98,7 @@ func (h TaskHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &task.Model)
 	r := Task{}
 	r.With(task)
 	q := ctx.Query("merged")
@@ -98,9 +113,25 @@ func (h TaskHandler) Get(ctx *gin.Context) {
 	h.Respond(ctx, http.StatusOK, r)
 }
 
+// Head godoc
+// @summary Check a task exists.
+// @description Check a task exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags tasks
+// @success 200
+// @success 304
+// @router /tasks/{id} [head]
+// @param id path int true "Task ID"
+func (h TaskHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.Task{})
+}
+
 // List godoc
 // @summary List all tasks.
 // @description List all tasks.
+// @description Filters:
+// @description - locator
+// @description - pod: (namespace/name) used by (pool) addons.
 // @tags tasks
 // @produce json
 // @success 200 {object} []api.Task
@@ -112,6 +143,10 @@ func (h TaskHandler) List(ctx *gin.Context) {
 	if locator != "" {
 		db = db.Where("locator", locator)
 	}
+	pod := ctx.Query(PodParam)
+	if pod != "" {
+		db = db.Where("pod", pod)
+	}
 	db = db.Preload(clause.Associations)
 	result := db.Find(&list)
 	if result.Error != nil {
@@ -128,6 +163,86 @@ func (h TaskHandler) List(ctx *gin.Context) {
 	h.Respond(ctx, http.StatusOK, resources)
 }
 
+// QueueReport godoc
+// @summary Get the task queue report.
+// @description Get task counts by state and addon with the average queue
+// @description wait and runtime (seconds) of tasks created within the (optional)
+// @description time window: ?from=&to= (RFC3339).
+// @tags tasks
+// @produce json
+// @success 200 {object} api.TaskQueueReport
+// @router /tasks/report [get]
+// @param from query string false "Created at or after (RFC3339)"
+// @param to query string false "Created before (RFC3339)"
+func (h TaskHandler) QueueReport(ctx *gin.Context) {
+	r := TaskQueueReport{
+		States: map[string]int{},
+		Addons: []TaskAddonReport{},
+	}
+	db := h.DB(ctx).Model(&model.Task{})
+	for _, p := range []struct {
+		param string
+		where string
+		time  **time.Time
+	}{
+		{param: From, where: "CreateTime >= ?", time: &r.From},
+		{param: To, where: "CreateTime < ?", time: &r.To},
+	} {
+		s := ctx.Query(p.param)
+		if s == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			err = &BadRequestError{p.param + ": " + err.Error()}
+			_ = ctx.Error(err)
+			return
+		}
+		db = db.Where(p.where, t)
+		*p.time = &t
+	}
+	var counts []struct {
+		Addon string
+		State string
+		Count int
+	}
+	q := db.Session(&gorm.Session{})
+	q = q.Select("Addon, State, COUNT(*) Count")
+	q = q.Group("Addon, State")
+	q = q.Order("Addon, State")
+	err := q.Scan(&counts).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	for _, c := range counts {
+		r.States[c.State] += c.Count
+		n := len(r.Addons)
+		if n == 0 || r.Addons[n-1].Addon != c.Addon {
+			r.Addons = append(
+				r.Addons,
+				TaskAddonReport{
+					Addon:  c.Addon,
+					States: map[string]int{},
+				})
+			n++
+		}
+		r.Addons[n-1].States[c.State] = c.Count
+	}
+	var list []model.Task
+	q = db.Session(&gorm.Session{})
+	q = q.Select("CreateTime", "Started", "Terminated")
+	q = q.Where("Started IS NOT NULL")
+	err = q.Find(&list).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	r.With(list)
+
+	h.Respond(ctx, http.StatusOK, r)
+}
+
 // Create godoc
 // @summary Create a task.
 // @description Create a task.
@@ -144,6 +259,11 @@ func (h TaskHandler) Create(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
+	err = r.Validate()
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	switch r.State {
 	case "":
 		r.State = tasking.Created
@@ -169,6 +289,93 @@ func (h TaskHandler) Create(ctx *gin.Context) {
 	h.Respond(ctx, http.StatusCreated, r)
 }
 
+// BulkCreate godoc
+// @summary Create a task for each application.
+// @description Create a task for each application using the task template.
+// @description The tasks are created in a single transaction.
+// @description Returns the created tasks (refs).
+// @tags tasks
+// @accept json
+// @produce json
+// @success 201 {object} []api.Ref
+// @router /tasks/bulk [post]
+// @param tasks body api.TaskBulk true "Task template and applications"
+func (h TaskHandler) BulkCreate(ctx *gin.Context) {
+	r := &TaskBulk{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	template := &r.Template
+	err = template.Validate()
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	switch template.State {
+	case "":
+		template.State = tasking.Created
+	case tasking.Created,
+		tasking.Ready:
+	default:
+		h.Respond(ctx,
+			http.StatusBadRequest,
+			gin.H{
+				"error": "state must be (''|Created|Ready)",
+			})
+		return
+	}
+	var apps []model.Application
+	ids := []uint{}
+	seen := map[uint]bool{}
+	for _, ref := range r.Applications {
+		if !seen[ref.ID] {
+			ids = append(ids, ref.ID)
+			seen[ref.ID] = true
+		}
+	}
+	err = h.DB(ctx).Find(&apps, ids).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if len(apps) != len(ids) {
+		err = &BadRequestError{"application(s) not found."}
+		_ = ctx.Error(err)
+		return
+	}
+	resources := []Ref{}
+	user := h.BaseHandler.CurrentUser(ctx)
+	err = h.DB(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		for i := range apps {
+			app := &apps[i]
+			m := template.Model()
+			m.ID = 0
+			m.Name = app.Name
+			if template.Name != "" {
+				m.Name = template.Name + "." + app.Name
+			}
+			m.ApplicationID = &app.ID
+			m.CreateUser = user
+			err = tx.Create(m).Error
+			if err != nil {
+				return
+			}
+			ref := Ref{}
+			ref.With(m.ID, m.Name)
+			resources = append(resources, ref)
+		}
+		return
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	h.Respond(ctx, http.StatusCreated, resources)
+}
+
 // Delete godoc
 // @summary Delete a task.
 // @description Delete a task.
@@ -217,6 +424,11 @@ func (h TaskHandler) Update(ctx *gin.Context) {
 	if err != nil {
 		return
 	}
+	err = r.Validate()
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	switch r.State {
 	case tasking.Created,
 		tasking.Ready:
@@ -278,6 +490,7 @@ func (h TaskHandler) Submit(ctx *gin.Context) {
 // Cancel godoc
 // @summary Cancel a task.
 // @description Cancel a task.
+// @description The (pending|running) task pod is deleted and the bucket is released.
 // @tags tasks
 // @success 204
 // @router /tasks/{id}/cancel [put]
@@ -301,6 +514,13 @@ func (h TaskHandler) Cancel(ctx *gin.Context) {
 			})
 		return
 	}
+	m.Canceled = true
+	rt := tasking.Task{Task: m}
+	err := rt.Cancel(h.Client(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	db := h.DB(ctx).Model(m)
 	db = db.Where("id", id)
 	db = db.Where(
@@ -310,7 +530,20 @@ func (h TaskHandler) Cancel(ctx *gin.Context) {
 			tasking.Failed,
 			tasking.Canceled,
 		})
-	err := db.Update("Canceled", true).Error
+	err = db.Updates(
+		map[string]interface{}{
+			"Canceled":   m.Canceled,
+			"State":      m.State,
+			"Pod":        m.Pod,
+			"Terminated": m.Terminated,
+			"BucketID":   m.BucketID,
+		}).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	db = h.DB(ctx).Where("TaskID", id)
+	err = db.Delete(&model.TaskReport{}).Error
 	if err != nil {
 		_ = ctx.Error(err)
 		return
@@ -319,6 +552,66 @@ func (h TaskHandler) Cancel(ctx *gin.Context) {
 	h.Status(ctx, http.StatusNoContent)
 }
 
+// Log godoc
+// @summary Get the task (pod) log.
+// @description Get the log of the task pod (main) container.
+// @description When ?follow=true, the log is streamed (chunked) while the task runs.
+// @tags tasks
+// @produce plain
+// @success 200
+// @router /tasks/{id}/log [get]
+// @param id path int true "Task ID"
+// @param follow query bool false "Follow (stream) the log"
+func (h TaskHandler) Log(ctx *gin.Context) {
+	id := h.pk(ctx)
+	m := &model.Task{}
+	result := h.DB(ctx).First(m, id)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	if m.Pod == "" {
+		h.Respond(ctx,
+			http.StatusNotFound,
+			gin.H{
+				"error": "task pod not found.",
+			})
+		return
+	}
+	follow, _ := strconv.ParseBool(ctx.Query(Follow))
+	reader, err := k8s.PodLog(
+		ctx.Request.Context(),
+		path.Dir(m.Pod),
+		path.Base(m.Pod),
+		tasking.MainContainer,
+		follow)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			h.Respond(ctx,
+				http.StatusNotFound,
+				gin.H{
+					"error": "task pod not found.",
+				})
+			return
+		}
+		_ = ctx.Error(err)
+		return
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	ctx.Header(ContentType, "text/plain; charset=utf-8")
+	ctx.Status(http.StatusOK)
+	buf := make([]byte, 4096)
+	ctx.Stream(func(w io.Writer) bool {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			_, _ = w.Write(buf[:n])
+		}
+		return err == nil
+	})
+}
+
 // BucketGet godoc
 // @summary Get bucket content by ID and path.
 // @description Get bucket content by ID and path.
@@ -489,11 +782,13 @@ func (h *TaskHandler) omitted(db *gorm.DB) (out *gorm.DB) {
 		"Bucket",
 		"Image",
 		"Pod",
+		"PodStatus",
 		"Started",
 		"Terminated",
 		"Canceled",
 		"Error",
 		"Retries",
+		"Events",
 	}...)
 	return
 }
@@ -508,6 +803,106 @@ type TTL struct {
 	Failed    int `json:"failed,omitempty"`
 }
 
+// TaskBulk REST resource.
+// A task (template) created for each application.
+type TaskBulk struct {
+	Template     Task  `json:"template"`
+	Applications []Ref `json:"applications" binding:"required,min=1"`
+}
+
+// TaskQueueReport REST resource.
+type TaskQueueReport struct {
+	From    *time.Time        `json:"from,omitempty" yaml:",omitempty"`
+	To      *time.Time        `json:"to,omitempty" yaml:",omitempty"`
+	States  map[string]int    `json:"states"`
+	Addons  []TaskAddonReport `json:"addons"`
+	Wait    float64           `json:"wait"`
+	Runtime float64           `json:"runtime"`
+}
+
+// With updates the average wait (created to started) and
+// runtime (started to terminated) using the started tasks.
+func (r *TaskQueueReport) With(list []model.Task) {
+	var wait, runtime time.Duration
+	terminated := 0
+	for i := range list {
+		m := &list[i]
+		wait += m.Started.Sub(m.CreateTime)
+		if m.Terminated != nil {
+			runtime += m.Terminated.Sub(*m.Started)
+			terminated++
+		}
+	}
+	if len(list) > 0 {
+		r.Wait = wait.Seconds() / float64(len(list))
+	}
+	if terminated > 0 {
+		r.Runtime = runtime.Seconds() / float64(terminated)
+	}
+}
+
+// TaskAddonReport task counts (by state) for an addon.
+type TaskAddonReport struct {
+	Addon  string         `json:"addon"`
+	States map[string]int `json:"states"`
+}
+
+// TaskEvent used in Task.Events.
+type TaskEvent struct {
+	Kind   string    `json:"kind"`
+	Reason string    `json:"reason,omitempty" yaml:",omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// TaskPodStatus the (last) observed task pod status.
+type TaskPodStatus struct {
+	Phase      string                `json:"phase,omitempty" yaml:",omitempty"`
+	Message    string                `json:"message,omitempty" yaml:",omitempty"`
+	Containers []TaskContainerStatus `json:"containers,omitempty" yaml:",omitempty"`
+}
+
+// TaskContainerStatus task pod container status.
+type TaskContainerStatus struct {
+	Name     string `json:"name"`
+	Ready    bool   `json:"ready"`
+	State    string `json:"state"`
+	Reason   string `json:"reason,omitempty" yaml:",omitempty"`
+	Message  string `json:"message,omitempty" yaml:",omitempty"`
+	ExitCode int32  `json:"exitCode,omitempty" yaml:"exitCode,omitempty"`
+	Restarts int32  `json:"restarts,omitempty" yaml:",omitempty"`
+}
+
+// TaskResources task pod (container) resource requirements.
+type TaskResources struct {
+	Limits   map[string]string `json:"limits,omitempty" yaml:",omitempty"`
+	Requests map[string]string `json:"requests,omitempty" yaml:",omitempty"`
+}
+
+// Validate the resource quantities.
+func (r *TaskResources) Validate() (err error) {
+	for _, m := range []map[string]string{r.Limits, r.Requests} {
+		for name, q := range m {
+			_, pErr := resource.ParseQuantity(q)
+			if pErr != nil {
+				err = &BadRequestError{
+					Reason: fmt.Sprintf("resource (%s) quantity: '%s' not valid.", name, q),
+				}
+				return
+			}
+		}
+	}
+	return
+}
+
+// TaskToleration task pod toleration.
+type TaskToleration struct {
+	Key      string `json:"key,omitempty" yaml:",omitempty"`
+	Operator string `json:"operator,omitempty" yaml:",omitempty" binding:"omitempty,oneof=Exists Equal"`
+	Value    string `json:"value,omitempty" yaml:",omitempty"`
+	Effect   string `json:"effect,omitempty" yaml:",omitempty" binding:"omitempty,oneof=NoSchedule PreferNoSchedule NoExecute"`
+	Seconds  *int64 `json:"tolerationSeconds,omitempty" yaml:"tolerationSeconds,omitempty"`
+}
+
 // TaskError used in Task.Errors.
 type TaskError struct {
 	Severity    string `json:"severity"`
@@ -516,28 +911,36 @@ type TaskError struct {
 
 // Task REST resource.
 type Task struct {
-	Resource    `yaml:",inline"`
-	Name        string       `json:"name"`
-	Locator     string       `json:"locator,omitempty" yaml:",omitempty"`
-	Priority    int          `json:"priority,omitempty" yaml:",omitempty"`
-	Variant     string       `json:"variant,omitempty" yaml:",omitempty"`
-	Policy      string       `json:"policy,omitempty" yaml:",omitempty"`
-	TTL         *TTL         `json:"ttl,omitempty" yaml:",omitempty"`
-	Addon       string       `json:"addon,omitempty" binding:"required" yaml:",omitempty"`
-	Data        interface{}  `json:"data" swaggertype:"object" binding:"required"`
-	Application *Ref         `json:"application,omitempty" yaml:",omitempty"`
-	State       string       `json:"state"`
-	Image       string       `json:"image,omitempty" yaml:",omitempty"`
-	Pod         string       `json:"pod,omitempty" yaml:",omitempty"`
-	Retries     int          `json:"retries,omitempty" yaml:",omitempty"`
-	Started     *time.Time   `json:"started,omitempty" yaml:",omitempty"`
-	Terminated  *time.Time   `json:"terminated,omitempty" yaml:",omitempty"`
-	Canceled    bool         `json:"canceled,omitempty" yaml:",omitempty"`
-	Bucket      *Ref         `json:"bucket,omitempty" yaml:",omitempty"`
-	Purged      bool         `json:"purged,omitempty" yaml:",omitempty"`
-	Errors      []TaskError  `json:"errors,omitempty" yaml:",omitempty"`
-	Activity    []string     `json:"activity,omitempty" yaml:",omitempty"`
-	Attached    []Attachment `json:"attached" yaml:",omitempty"`
+	Resource     `yaml:",inline"`
+	Name         string            `json:"name"`
+	Locator      string            `json:"locator,omitempty" yaml:",omitempty"`
+	Priority     int               `json:"priority,omitempty" yaml:",omitempty"`
+	Variant      string            `json:"variant,omitempty" yaml:",omitempty"`
+	Policy       string            `json:"policy,omitempty" yaml:",omitempty"`
+	TTL          *TTL              `json:"ttl,omitempty" yaml:",omitempty"`
+	Addon        string            `json:"addon,omitempty" binding:"required" yaml:",omitempty"`
+	Data         interface{}       `json:"data" swaggertype:"object" binding:"required"`
+	Application  *Ref              `json:"application,omitempty" yaml:",omitempty"`
+	DependsOn    []string          `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
+	Resources    *TaskResources    `json:"resources,omitempty" yaml:",omitempty"`
+	NodeSelector map[string]string `json:"nodeSelector,omitempty" yaml:"nodeSelector,omitempty"`
+	Tolerations  []TaskToleration  `json:"tolerations,omitempty" yaml:",omitempty" binding:"omitempty,dive"`
+	State        string            `json:"state"`
+	Image        string            `json:"image,omitempty" yaml:",omitempty"`
+	Pod          string            `json:"pod,omitempty" yaml:",omitempty"`
+	PodStatus    *TaskPodStatus    `json:"podStatus,omitempty" yaml:"podStatus,omitempty"`
+	Retries      int               `json:"retries,omitempty" yaml:",omitempty"`
+	BackoffLimit *int              `json:"backoffLimit,omitempty" yaml:"backoffLimit,omitempty" binding:"omitempty,min=0"`
+	Timeout      int               `json:"timeout,omitempty" yaml:",omitempty" binding:"omitempty,min=0"`
+	Events       []TaskEvent       `json:"events,omitempty" yaml:",omitempty"`
+	Started      *time.Time        `json:"started,omitempty" yaml:",omitempty"`
+	Terminated   *time.Time        `json:"terminated,omitempty" yaml:",omitempty"`
+	Canceled     bool              `json:"canceled,omitempty" yaml:",omitempty"`
+	Bucket       *Ref              `json:"bucket,omitempty" yaml:",omitempty"`
+	Purged       bool              `json:"purged,omitempty" yaml:",omitempty"`
+	Errors       []TaskError       `json:"errors,omitempty" yaml:",omitempty"`
+	Activity     []string          `json:"activity,omitempty" yaml:",omitempty"`
+	Attached     []Attachment      `json:"attached" yaml:",omitempty"`
 }
 
 // With updates the resource with the model.
@@ -557,14 +960,34 @@ func (r *Task) With(m *model.Task) {
 	r.Terminated = m.Terminated
 	r.Pod = m.Pod
 	r.Retries = m.Retries
+	r.BackoffLimit = m.BackoffLimit
+	r.Timeout = m.Timeout
 	r.Canceled = m.Canceled
 	_ = json.Unmarshal(m.Data, &r.Data)
+	if m.DependsOn != nil {
+		_ = json.Unmarshal(m.DependsOn, &r.DependsOn)
+	}
+	if m.Resources != nil {
+		_ = json.Unmarshal(m.Resources, &r.Resources)
+	}
+	if m.NodeSelector != nil {
+		_ = json.Unmarshal(m.NodeSelector, &r.NodeSelector)
+	}
+	if m.Tolerations != nil {
+		_ = json.Unmarshal(m.Tolerations, &r.Tolerations)
+	}
 	if m.TTL != nil {
 		_ = json.Unmarshal(m.TTL, &r.TTL)
 	}
 	if m.Errors != nil {
 		_ = json.Unmarshal(m.Errors, &r.Errors)
 	}
+	if m.Events != nil {
+		_ = json.Unmarshal(m.Events, &r.Events)
+	}
+	if m.PodStatus != nil {
+		_ = json.Unmarshal(m.PodStatus, &r.PodStatus)
+	}
 	if m.Report != nil {
 		report := &TaskReport{}
 		report.With(m.Report)
@@ -591,6 +1014,8 @@ func (r *Task) Model() (m *model.Task) {
 		Priority:      r.Priority,
 		Policy:        r.Policy,
 		State:         r.State,
+		BackoffLimit:  r.BackoffLimit,
+		Timeout:       r.Timeout,
 		ApplicationID: r.idPtr(r.Application),
 	}
 	m.Data, _ = json.Marshal(StrMap(r.Data))
@@ -598,6 +1023,26 @@ func (r *Task) Model() (m *model.Task) {
 	if r.TTL != nil {
 		m.TTL, _ = json.Marshal(r.TTL)
 	}
+	if len(r.DependsOn) > 0 {
+		m.DependsOn, _ = json.Marshal(r.DependsOn)
+	}
+	if r.Resources != nil {
+		m.Resources, _ = json.Marshal(r.Resources)
+	}
+	if len(r.NodeSelector) > 0 {
+		m.NodeSelector, _ = json.Marshal(r.NodeSelector)
+	}
+	if len(r.Tolerations) > 0 {
+		m.Tolerations, _ = json.Marshal(r.Tolerations)
+	}
+	return
+}
+
+// Validate the resource.
+func (r *Task) Validate() (err error) {
+	if r.Resources != nil {
+		err = r.Resources.Validate()
+	}
 	return
 }
 
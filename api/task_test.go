@@ -0,0 +1,175 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/model"
+	tasking "github.com/konveyor/tackle2-hub/task"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestTaskCancel(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	pod := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "konveyor",
+			Name:      "task-1-abc",
+		},
+	}
+	client := fake.NewClientBuilder().WithObjects(pod).Build()
+	running := &model.Task{
+		Name:  "running",
+		State: tasking.Running,
+		Pod:   "konveyor/task-1-abc",
+	}
+	g.Expect(db.Create(running).Error).To(gomega.BeNil())
+	g.Expect(running.BucketID).ToNot(gomega.BeNil())
+	report := &model.TaskReport{TaskID: running.ID}
+	g.Expect(db.Create(report).Error).To(gomega.BeNil())
+	succeeded := &model.Task{Name: "succeeded", State: tasking.Succeeded}
+	g.Expect(db.Create(succeeded).Error).To(gomega.BeNil())
+
+	e := newEngine(db)
+	e.Use(func(ctx *gin.Context) {
+		rtx := WithContext(ctx)
+		rtx.Client = client
+		ctx.Next()
+	})
+	h := TaskHandler{}
+	e.PUT(TaskCancelRoot, h.Cancel)
+	cancel := func(id uint) int {
+		r := httptest.NewRequest(http.MethodPut, "/tasks/"+strconv.Itoa(int(id))+"/cancel", nil)
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	g.Expect(cancel(running.ID)).To(gomega.Equal(http.StatusNoContent))
+	m := &model.Task{}
+	g.Expect(db.First(m, running.ID).Error).To(gomega.BeNil())
+	g.Expect(m.State).To(gomega.Equal(tasking.Canceled))
+	g.Expect(m.Canceled).To(gomega.BeTrue())
+	g.Expect(m.Pod).To(gomega.BeEmpty())
+	g.Expect(m.Terminated).ToNot(gomega.BeNil())
+	g.Expect(m.BucketID).To(gomega.BeNil())
+	var count int64
+	db.Model(&model.TaskReport{}).Where("TaskID", running.ID).Count(&count)
+	g.Expect(count).To(gomega.BeZero())
+	err := client.Get(context.TODO(), k8s.ObjectKeyFromObject(pod), &core.Pod{})
+	g.Expect(k8serr.IsNotFound(err)).To(gomega.BeTrue())
+
+	// Terminated.
+	g.Expect(cancel(succeeded.ID)).To(gomega.Equal(http.StatusBadRequest))
+	g.Expect(cancel(running.ID)).To(gomega.Equal(http.StatusBadRequest))
+}
+
+func TestTaskQueueReport(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	created := time.Date(2023, time.May, 1, 10, 0, 0, 0, time.UTC)
+	started := created.Add(time.Minute)
+	terminated := started.Add(4 * time.Minute)
+	tasks := []model.Task{
+		{Name: "a", Addon: "analyzer", State: tasking.Succeeded, Started: &started, Terminated: &terminated},
+		{Name: "b", Addon: "analyzer", State: tasking.Running, Started: &started},
+		{Name: "c", Addon: "analyzer", State: tasking.Ready},
+		{Name: "d", Addon: "language", State: tasking.Ready},
+	}
+	for i := range tasks {
+		// Reset on create.
+		started, terminated := tasks[i].Started, tasks[i].Terminated
+		g.Expect(db.Create(&tasks[i]).Error).To(gomega.BeNil())
+		err := db.Exec(
+			"UPDATE Task SET CreateTime = ?, Started = ?, Terminated = ? WHERE ID = ?",
+			created,
+			started,
+			terminated,
+			tasks[i].ID).Error
+		g.Expect(err).To(gomega.BeNil())
+	}
+	e := newEngine(db)
+	h := TaskHandler{}
+	e.GET(TasksReportRoot, h.QueueReport)
+	get := func(url string) (w *httptest.ResponseRecorder, r TaskQueueReport) {
+		w = httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		_ = json.Unmarshal(w.Body.Bytes(), &r)
+		return
+	}
+	w, r := get(TasksReportRoot)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(r.States).To(gomega.Equal(map[string]int{tasking.Succeeded: 1, tasking.Running: 1, tasking.Ready: 2}))
+	g.Expect(r.Addons).To(gomega.HaveLen(2))
+	g.Expect(r.Addons[0].Addon).To(gomega.Equal("analyzer"))
+	g.Expect(r.Addons[0].States[tasking.Ready]).To(gomega.Equal(1))
+	g.Expect(r.Addons[1].States).To(gomega.Equal(map[string]int{tasking.Ready: 1}))
+	g.Expect(r.Wait).To(gomega.Equal(float64(60)))
+	g.Expect(r.Runtime).To(gomega.Equal(float64(240)))
+
+	// Window.
+	_, r = get(TasksReportRoot + "?from=2023-05-02T00:00:00Z")
+	g.Expect(r.States).To(gomega.BeEmpty())
+	g.Expect(r.Wait).To(gomega.BeZero())
+	w, _ = get(TasksReportRoot + "?to=yesterday")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+}
+
+func TestTaskBulkCreate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	apps := []model.Application{{Name: "a"}, {Name: "b"}}
+	for i := range apps {
+		g.Expect(db.Create(&apps[i]).Error).To(gomega.BeNil())
+	}
+	e := newEngine(db)
+	h := TaskHandler{}
+	e.POST(TasksBulkRoot, h.BulkCreate)
+	post := func(r *TaskBulk) (w *httptest.ResponseRecorder) {
+		b, _ := json.Marshal(r)
+		w = httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodPost, TasksBulkRoot, bytes.NewReader(b)))
+		return
+	}
+	bulk := &TaskBulk{
+		Template: Task{
+			Name:  "analysis",
+			Addon: "analyzer",
+			Data:  map[string]interface{}{"mode": "binary"},
+			State: tasking.Ready,
+		},
+		Applications: []Ref{{ID: apps[0].ID}, {ID: apps[1].ID}},
+	}
+	w := post(bulk)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusCreated))
+	refs := []Ref{}
+	_ = json.Unmarshal(w.Body.Bytes(), &refs)
+	g.Expect(refs).To(gomega.HaveLen(2))
+	g.Expect(refs[1].Name).To(gomega.Equal("analysis.b"))
+	m := &model.Task{}
+	g.Expect(db.First(m, refs[1].ID).Error).To(gomega.BeNil())
+	g.Expect(*m.ApplicationID).To(gomega.Equal(apps[1].ID))
+	g.Expect(m.State).To(gomega.Equal(tasking.Ready))
+	g.Expect(m.Addon).To(gomega.Equal("analyzer"))
+
+	// Not found.
+	bulk.Applications = append(bulk.Applications, Ref{ID: 99})
+	w = post(bulk)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	var count int64
+	db.Model(&model.Task{}).Count(&count)
+	g.Expect(count).To(gomega.Equal(int64(2)))
+}
@@ -34,6 +34,7 @@ func (h TaskGroupHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.POST(TaskGroupsRoot, h.Create)
 	routeGroup.PUT(TaskGroupRoot, h.Update)
 	routeGroup.GET(TaskGroupRoot, h.Get)
+	routeGroup.HEAD(TaskGroupRoot, h.Head)
 	routeGroup.PUT(TaskGroupSubmitRoot, h.Submit, h.Update)
 	routeGroup.DELETE(TaskGroupRoot, h.Delete)
 	// Bucket
@@ -63,12 +64,26 @@ func (h TaskGroupHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 	r := TaskGroup{}
 	r.With(m)
 
 	h.Respond(ctx, http.StatusOK, r)
 }
 
+// Head godoc
+// @summary Check a task group exists.
+// @description Check a task group exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags taskgroups
+// @success 200
+// @success 304
+// @router /taskgroups/{id} [head]
+// @param id path int true "TaskGroup ID"
+func (h TaskGroupHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.TaskGroup{})
+}
+
 // List godoc
 // @summary List all task groups.
 // @description List all task groups.
@@ -97,6 +112,8 @@ func (h TaskGroupHandler) List(ctx *gin.Context) {
 // Create godoc
 // @summary Create a task group.
 // @description Create a task group.
+// @description Member tasks may depend on (the names of) other members and
+// @description are started only when the dependencies have succeeded.
 // @tags taskgroups
 // @accept json
 // @produce json
@@ -110,8 +127,18 @@ func (h TaskGroupHandler) Create(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
+	err = r.Validate()
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	db := h.DB(ctx)
 	m := r.Model()
+	m.Tasks, err = tasking.Ordered(m.Tasks)
+	if err != nil {
+		_ = ctx.Error(&BadRequestError{err.Error()})
+		return
+	}
 	switch r.State {
 	case "":
 		m.State = tasking.Created
@@ -159,6 +186,11 @@ func (h TaskGroupHandler) Update(ctx *gin.Context) {
 	if err != nil {
 		return
 	}
+	err = updated.Validate()
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	current := &model.TaskGroup{}
 	err = h.DB(ctx).First(current, id).Error
 	if err != nil {
@@ -166,6 +198,11 @@ func (h TaskGroupHandler) Update(ctx *gin.Context) {
 		return
 	}
 	m := updated.Model()
+	m.Tasks, err = tasking.Ordered(m.Tasks)
+	if err != nil {
+		_ = ctx.Error(&BadRequestError{err.Error()})
+		return
+	}
 	m.ID = current.ID
 	m.UpdateUser = h.BaseHandler.CurrentUser(ctx)
 	db := h.DB(ctx).Model(m)
@@ -407,3 +444,14 @@ func (r *TaskGroup) Model() (m *model.TaskGroup) {
 	}
 	return
 }
+
+// Validate the resource.
+func (r *TaskGroup) Validate() (err error) {
+	for i := range r.Tasks {
+		err = r.Tasks[i].Validate()
+		if err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/model"
+	tasking "github.com/konveyor/tackle2-hub/task"
+	"gorm.io/gorm/clause"
+)
+
+// Routes
+const (
+	TaskSchedulesRoot = "/taskschedules"
+	TaskScheduleRoot  = TaskSchedulesRoot + "/:" + ID
+)
+
+// TaskScheduleHandler handles task schedule routes.
+type TaskScheduleHandler struct {
+	BaseHandler
+}
+
+// AddRoutes adds routes.
+func (h TaskScheduleHandler) AddRoutes(e *gin.Engine) {
+	routeGroup := e.Group("/")
+	routeGroup.Use(Required("tasks"), Transaction)
+	routeGroup.GET(TaskSchedulesRoot, h.List)
+	routeGroup.GET(TaskSchedulesRoot+"/", h.List)
+	routeGroup.POST(TaskSchedulesRoot, h.Create)
+	routeGroup.GET(TaskScheduleRoot, h.Get)
+	routeGroup.HEAD(TaskScheduleRoot, h.Head)
+	routeGroup.PUT(TaskScheduleRoot, h.Update)
+	routeGroup.DELETE(TaskScheduleRoot, h.Delete)
+}
+
+// Get godoc
+// @summary Get a task schedule by ID.
+// @description Get a task schedule by ID.
+// @tags taskschedules
+// @produce json
+// @success 200 {object} api.TaskSchedule
+// @router /taskschedules/{id} [get]
+// @param id path int true "TaskSchedule ID"
+func (h TaskScheduleHandler) Get(ctx *gin.Context) {
+	m := &model.TaskSchedule{}
+	id := h.pk(ctx)
+	db := h.preLoad(h.DB(ctx), "Applications")
+	result := db.First(m, id)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	r := TaskSchedule{}
+	r.With(m)
+
+	h.Respond(ctx, http.StatusOK, r)
+}
+
+// Head godoc
+// @summary Check a task schedule exists.
+// @description Check a task schedule exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags taskschedules
+// @success 200
+// @success 304
+// @router /taskschedules/{id} [head]
+// @param id path int true "TaskSchedule ID"
+func (h TaskScheduleHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.TaskSchedule{})
+}
+
+// List godoc
+// @summary List all task schedules.
+// @description List all task schedules.
+// @tags taskschedules
+// @produce json
+// @success 200 {object} []api.TaskSchedule
+// @router /taskschedules [get]
+func (h TaskScheduleHandler) List(ctx *gin.Context) {
+	var list []model.TaskSchedule
+	db := h.preLoad(h.DB(ctx), "Applications")
+	result := db.Find(&list)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	resources := []TaskSchedule{}
+	for i := range list {
+		r := TaskSchedule{}
+		r.With(&list[i])
+		resources = append(resources, r)
+	}
+
+	h.Respond(ctx, http.StatusOK, resources)
+}
+
+// Create godoc
+// @summary Create a task schedule.
+// @description Create a task schedule.
+// @description The task (data) is expanded into a task for each application
+// @description when the (cron) schedule is due.
+// @tags taskschedules
+// @accept json
+// @produce json
+// @success 201 {object} api.TaskSchedule
+// @router /taskschedules [post]
+// @param taskschedule body api.TaskSchedule true "TaskSchedule data"
+func (h TaskScheduleHandler) Create(ctx *gin.Context) {
+	r := &TaskSchedule{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m := r.Model()
+	err = h.schedule(m)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m.CreateUser = h.CurrentUser(ctx)
+	result := h.DB(ctx).Create(m)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	r.With(m)
+
+	h.Respond(ctx, http.StatusCreated, r)
+}
+
+// Update godoc
+// @summary Update a task schedule.
+// @description Update a task schedule.
+// @tags taskschedules
+// @accept json
+// @success 204
+// @router /taskschedules/{id} [put]
+// @param id path int true "TaskSchedule ID"
+// @param taskschedule body api.TaskSchedule true "TaskSchedule data"
+func (h TaskScheduleHandler) Update(ctx *gin.Context) {
+	id := h.pk(ctx)
+	r := &TaskSchedule{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m := r.Model()
+	err = h.schedule(m)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m.ID = id
+	m.UpdateUser = h.CurrentUser(ctx)
+	db := h.DB(ctx).Model(m)
+	db = db.Omit(clause.Associations, "LastRun")
+	result := db.Updates(h.fields(m))
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	err = h.DB(ctx).Model(m).Association("Applications").Replace("Applications", m.Applications)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	h.Status(ctx, http.StatusNoContent)
+}
+
+// Delete godoc
+// @summary Delete a task schedule.
+// @description Delete a task schedule.
+// @description Tasks created by the schedule are not deleted.
+// @tags taskschedules
+// @success 204
+// @router /taskschedules/{id} [delete]
+// @param id path int true "TaskSchedule ID"
+func (h TaskScheduleHandler) Delete(ctx *gin.Context) {
+	id := h.pk(ctx)
+	m := &model.TaskSchedule{}
+	result := h.DB(ctx).First(m, id)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	result = h.DB(ctx).Delete(m)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+
+	h.Status(ctx, http.StatusNoContent)
+}
+
+// schedule validates the cron expression and sets the next run.
+func (h TaskScheduleHandler) schedule(m *model.TaskSchedule) (err error) {
+	cron, err := tasking.ParseCron(m.Cron)
+	if err != nil {
+		err = &BadRequestError{err.Error()}
+		return
+	}
+	next := cron.Next(time.Now())
+	if next.IsZero() {
+		err = &BadRequestError{"cron: '" + m.Cron + "' never matched."}
+		return
+	}
+	m.NextRun = &next
+	return
+}
+
+// TaskSchedule REST resource.
+type TaskSchedule struct {
+	Resource     `yaml:",inline"`
+	Name         string      `json:"name" binding:"required"`
+	Cron         string      `json:"cron" binding:"required"`
+	Addon        string      `json:"addon" binding:"required"`
+	Variant      string      `json:"variant,omitempty" yaml:",omitempty"`
+	Priority     int         `json:"priority,omitempty" yaml:",omitempty"`
+	Policy       string      `json:"policy,omitempty" yaml:",omitempty"`
+	Data         interface{} `json:"data" swaggertype:"object" binding:"required"`
+	Paused       bool        `json:"paused,omitempty" yaml:",omitempty"`
+	Applications []Ref       `json:"applications"`
+	LastRun      *time.Time  `json:"lastRun,omitempty" yaml:"lastRun,omitempty"`
+	NextRun      *time.Time  `json:"nextRun,omitempty" yaml:"nextRun,omitempty"`
+}
+
+// With updates the resource with the model.
+func (r *TaskSchedule) With(m *model.TaskSchedule) {
+	r.Resource.With(&m.Model)
+	r.Name = m.Name
+	r.Cron = m.Cron
+	r.Addon = m.Addon
+	r.Variant = m.Variant
+	r.Priority = m.Priority
+	r.Policy = m.Policy
+	r.Paused = m.Paused
+	r.LastRun = m.LastRun
+	r.NextRun = m.NextRun
+	_ = json.Unmarshal(m.Data, &r.Data)
+	r.Applications = []Ref{}
+	for _, app := range m.Applications {
+		ref := Ref{}
+		ref.With(app.ID, app.Name)
+		r.Applications = append(r.Applications, ref)
+	}
+}
+
+// Model builds a model.
+func (r *TaskSchedule) Model() (m *model.TaskSchedule) {
+	m = &model.TaskSchedule{
+		Name:     r.Name,
+		Cron:     r.Cron,
+		Addon:    r.Addon,
+		Variant:  r.Variant,
+		Priority: r.Priority,
+		Policy:   r.Policy,
+		Paused:   r.Paused,
+	}
+	m.ID = r.ID
+	m.Data, _ = json.Marshal(StrMap(r.Data))
+	for _, ref := range r.Applications {
+		m.Applications = append(
+			m.Applications,
+			model.Application{
+				Model: model.Model{ID: ref.ID},
+			})
+	}
+	return
+}
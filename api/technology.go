@@ -0,0 +1,369 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	qf "github.com/konveyor/tackle2-hub/api/filter"
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/gorm"
+)
+
+// Routes
+const (
+	TechnologiesRoot    = "/technologies"
+	AppTechnologiesRoot = ApplicationRoot + "/technologies"
+)
+
+// Technology facts.
+const (
+	// TechFactLanguages the discovered languages fact.
+	TechFactLanguages = "languages"
+	// TechFactTechnologies the discovered technologies fact.
+	TechFactTechnologies = "technologies"
+	// TechLanguage the category of (fact) languages.
+	TechLanguage = "Language"
+	// TechConfidence the confidence when not reported.
+	TechConfidence = 100
+)
+
+// TechnologyHandler handles technology routes.
+type TechnologyHandler struct {
+	BaseHandler
+}
+
+// AddRoutes adds routes.
+func (h TechnologyHandler) AddRoutes(e *gin.Engine) {
+	routeGroup := e.Group("/")
+	routeGroup.Use(Required("technologies"))
+	routeGroup.GET(TechnologiesRoot, h.List)
+	routeGroup.GET(TechnologiesRoot+"/", h.List)
+	routeGroup = e.Group("/")
+	routeGroup.Use(Required("applications"))
+	routeGroup.GET(AppTechnologiesRoot, h.AppList)
+}
+
+// AppList godoc
+// @summary List application technologies.
+// @description List the languages and technologies discovered for the application.
+// @description Technologies are collected from:
+// @description - tags applied by addons (source not empty). The category is the tag category.
+// @description - the `languages` and `technologies` facts. The fact value is a list of
+// @description   names or objects: {name: Java, category: Language, confidence: 90}.
+// @description The confidence (0-100) defaults to 100. The source is the tag or fact source.
+// @tags technologies
+// @produce json
+// @success 200 {object} []api.Technology
+// @router /applications/{id}/technologies [get]
+// @param id path int true "Application ID"
+func (h TechnologyHandler) AppList(ctx *gin.Context) {
+	id := h.pk(ctx)
+	app := &model.Application{}
+	err := h.DB(ctx).Select("ID").First(app, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	db := h.DB(ctx)
+	db = db.Model(&model.Application{})
+	db = db.Select("ID")
+	db = db.Where("ID", id)
+	found, err := h.technologies(ctx, db)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	resources := found[id]
+	if resources == nil {
+		resources = []Technology{}
+	}
+
+	h.Respond(ctx, http.StatusOK, resources)
+}
+
+// List godoc
+// @summary List (portfolio) technologies.
+// @description List the languages and technologies discovered across the portfolio.
+// @description Technologies are aggregated by name and category. See: /applications/{id}/technologies.
+// @description filters:
+// @description - name
+// @description - category
+// @description - source
+// @description - application.id
+// @description - application.name
+// @tags technologies
+// @produce json
+// @success 200 {object} []api.TechnologyReport
+// @router /technologies [get]
+func (h TechnologyHandler) List(ctx *gin.Context) {
+	filter, err := qf.New(ctx,
+		[]qf.Assert{
+			{Field: "name", Kind: qf.STRING},
+			{Field: "category", Kind: qf.STRING},
+			{Field: "source", Kind: qf.STRING},
+			{Field: "application.id", Kind: qf.LITERAL},
+			{Field: "application.name", Kind: qf.STRING},
+		})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	db := h.DB(ctx)
+	db = db.Model(&model.Application{})
+	db = db.Select("ID")
+	db = db.Where("Archived", false)
+	appFilter := filter.Resource("application")
+	db = appFilter.Where(db)
+	found, err := h.technologies(ctx, db)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	matched := func(name, value string) (m bool) {
+		f, found := filter.Field(name)
+		if !found {
+			m = true
+			return
+		}
+		for _, v := range f.Value.ByKind(qf.LITERAL, qf.STRING) {
+			if f.Operator.Value == string(qf.LIKE) {
+				pattern := strings.ReplaceAll(regexp.QuoteMeta(v.Value), `\*`, ".*")
+				m, _ = regexp.MatchString("(?i)^"+pattern+"$", value)
+			} else {
+				m = strings.EqualFold(value, v.Value)
+			}
+			if m {
+				break
+			}
+		}
+		if f.Operator.Value == string(qf.NOT)+string(qf.EQ) {
+			m = !m
+		}
+		return
+	}
+	appIds := []uint{}
+	for appId := range found {
+		appIds = append(appIds, appId)
+	}
+	sort.Slice(
+		appIds,
+		func(i, j int) bool {
+			return appIds[i] < appIds[j]
+		})
+	index := map[string]*TechnologyReport{}
+	for _, appId := range appIds {
+		for _, t := range found[appId] {
+			if !matched("name", t.Name) ||
+				!matched("category", t.Category) ||
+				!matched("source", t.Source) {
+				continue
+			}
+			key := strings.ToLower(t.Name + "|" + t.Category)
+			r, found := index[key]
+			if !found {
+				r = &TechnologyReport{
+					Name:     t.Name,
+					Category: t.Category,
+					apps:     map[uint]int{},
+				}
+				index[key] = r
+			}
+			r.add(appId, &t)
+		}
+	}
+	resources := []TechnologyReport{}
+	for _, r := range index {
+		sort.Strings(r.Sources)
+		resources = append(resources, *r)
+	}
+	sort.Slice(
+		resources,
+		func(i, j int) bool {
+			ri, rj := resources[i], resources[j]
+			if ri.Applications != rj.Applications {
+				return ri.Applications > rj.Applications
+			}
+			return strings.ToLower(ri.Name) < strings.ToLower(rj.Name)
+		})
+
+	h.Respond(ctx, http.StatusOK, resources)
+}
+
+// technologies returns the technologies of the (selected)
+// applications keyed by application ID.
+func (h *TechnologyHandler) technologies(ctx *gin.Context, appIDs *gorm.DB) (found map[uint][]Technology, err error) {
+	found = map[uint][]Technology{}
+	index := map[uint]map[string]int{}
+	add := func(appId uint, t Technology) {
+		key := strings.ToLower(t.Name + "|" + t.Source)
+		keys := index[appId]
+		if keys == nil {
+			keys = map[string]int{}
+			index[appId] = keys
+		}
+		if i, matched := keys[key]; matched {
+			p := &found[appId][i]
+			if p.Tag == nil {
+				p.Tag = t.Tag
+			}
+			if p.Category == "" {
+				p.Category = t.Category
+			}
+			if t.Confidence > p.Confidence {
+				p.Confidence = t.Confidence
+			}
+			return
+		}
+		keys[key] = len(found[appId])
+		found[appId] = append(found[appId], t)
+	}
+	//
+	// Tags
+	var tags []struct {
+		ApplicationID uint
+		TagID         uint
+		TagName       string
+		Category      string
+		Source        string
+	}
+	db := h.DB(ctx)
+	db = db.Table("ApplicationTags at")
+	db = db.Select(
+		"at.ApplicationID",
+		"at.TagID",
+		"t.Name TagName",
+		"c.Name Category",
+		"at.Source")
+	db = db.Joins("JOIN Tag t ON t.ID = at.TagID")
+	db = db.Joins("JOIN TagCategory c ON c.ID = t.CategoryID")
+	db = db.Where("at.Source != ''")
+	db = db.Where("at.ApplicationID IN (?)", appIDs)
+	db = db.Order("at.ApplicationID, t.Name")
+	err = db.Scan(&tags).Error
+	if err != nil {
+		return
+	}
+	for _, m := range tags {
+		add(
+			m.ApplicationID,
+			Technology{
+				Name:       m.TagName,
+				Category:   m.Category,
+				Confidence: TechConfidence,
+				Source:     m.Source,
+				Tag:        &Ref{ID: m.TagID, Name: m.TagName},
+			})
+	}
+	//
+	// Facts
+	var facts []model.Fact
+	db = h.DB(ctx)
+	db = db.Where("Key IN ?", []string{TechFactLanguages, TechFactTechnologies})
+	db = db.Where("ApplicationID IN (?)", appIDs)
+	db = db.Order("ApplicationID, Key, Source")
+	err = db.Find(&facts).Error
+	if err != nil {
+		return
+	}
+	for i := range facts {
+		m := &facts[i]
+		for _, t := range h.techFact(m) {
+			add(m.ApplicationID, t)
+		}
+	}
+	return
+}
+
+// techFact returns the technologies reported by the fact.
+// The value is a list of names or objects. Unknown
+// formats are ignored.
+func (h *TechnologyHandler) techFact(m *model.Fact) (list []Technology) {
+	var entries []interface{}
+	err := json.Unmarshal(m.Value, &entries)
+	if err != nil {
+		return
+	}
+	category := ""
+	if m.Key == TechFactLanguages {
+		category = TechLanguage
+	}
+	for _, entry := range entries {
+		t := Technology{
+			Category:   category,
+			Confidence: TechConfidence,
+			Source:     m.Source,
+		}
+		switch v := entry.(type) {
+		case string:
+			t.Name = v
+		case map[string]interface{}:
+			t.Name, _ = v["name"].(string)
+			if s, cast := v["category"].(string); cast && s != "" {
+				t.Category = s
+			}
+			if n, cast := v["confidence"].(float64); cast {
+				t.Confidence = int(n)
+			}
+		}
+		t.Name = strings.TrimSpace(t.Name)
+		if t.Name == "" {
+			continue
+		}
+		if t.Confidence < 0 {
+			t.Confidence = 0
+		}
+		if t.Confidence > 100 {
+			t.Confidence = 100
+		}
+		list = append(list, t)
+	}
+	return
+}
+
+// Technology REST resource.
+type Technology struct {
+	Name       string `json:"name"`
+	Category   string `json:"category,omitempty" yaml:",omitempty"`
+	Confidence int    `json:"confidence"`
+	Source     string `json:"source"`
+	Tag        *Ref   `json:"tag,omitempty" yaml:",omitempty"`
+}
+
+// TechnologyReport (portfolio) technology report.
+// The confidence is the average of the (highest) confidence
+// reported for each application.
+type TechnologyReport struct {
+	Name         string   `json:"name"`
+	Category     string   `json:"category,omitempty" yaml:",omitempty"`
+	Applications int      `json:"applications"`
+	Confidence   int      `json:"confidence"`
+	Sources      []string `json:"sources"`
+	apps         map[uint]int
+}
+
+// add a technology discovered for an application.
+func (r *TechnologyReport) add(appId uint, t *Technology) {
+	found := false
+	for _, s := range r.Sources {
+		if s == t.Source {
+			found = true
+			break
+		}
+	}
+	if !found {
+		r.Sources = append(r.Sources, t.Source)
+	}
+	if n, found := r.apps[appId]; !found || t.Confidence > n {
+		r.apps[appId] = t.Confidence
+	}
+	total := 0
+	for _, n := range r.apps {
+		total += n
+	}
+	r.Applications = len(r.apps)
+	r.Confidence = total / r.Applications
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestTechnologies(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	category := &model.TagCategory{Name: "Language"}
+	g.Expect(db.Create(category).Error).To(gomega.BeNil())
+	tags := []model.Tag{
+		{Name: "Java", CategoryID: category.ID},
+		{Name: "Critical", CategoryID: category.ID},
+	}
+	g.Expect(db.Create(&tags).Error).To(gomega.BeNil())
+	for _, name := range []string{"a", "b"} {
+		g.Expect(db.Create(&model.Application{Name: name}).Error).To(gomega.BeNil())
+	}
+	for _, m := range []model.ApplicationTag{
+		{ApplicationID: 1, TagID: 1, Source: "language-discovery"},
+		{ApplicationID: 2, TagID: 2},
+	} {
+		g.Expect(db.Create(&m).Error).To(gomega.BeNil())
+	}
+	for _, m := range []model.Fact{
+		{ApplicationID: 1, Key: TechFactLanguages, Source: "language-discovery", Value: []byte(`["Java"]`)},
+		{ApplicationID: 2, Key: TechFactLanguages, Source: "language-discovery", Value: []byte(`["java",{"name":"Python","confidence":40}]`)},
+		{ApplicationID: 2, Key: TechFactTechnologies, Source: "tech-discovery", Value: []byte(`[{"name":"Spring Boot","category":"Framework","confidence":80}]`)},
+		{ApplicationID: 2, Key: "other", Source: "tech-discovery", Value: []byte(`["Ignored"]`)},
+	} {
+		g.Expect(db.Create(&m).Error).To(gomega.BeNil())
+	}
+
+	h := TechnologyHandler{}
+	e := newEngine(db)
+	e.GET(AppTechnologiesRoot, h.AppList)
+	e.GET(TechnologiesRoot, h.List)
+	get := func(path string, r interface{}) {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+		g.Expect(json.Unmarshal(w.Body.Bytes(), r)).To(gomega.BeNil())
+	}
+	// application.
+	var list []Technology
+	get("/applications/1/technologies", &list)
+	g.Expect(list).To(gomega.Equal([]Technology{
+		{
+			Name:       "Java",
+			Category:   "Language",
+			Confidence: 100,
+			Source:     "language-discovery",
+			Tag:        &Ref{ID: 1, Name: "Java"},
+		},
+	}))
+	get("/applications/2/technologies", &list)
+	g.Expect(list).To(gomega.HaveLen(3))
+	g.Expect(list[1]).To(gomega.Equal(Technology{
+		Name:       "Python",
+		Category:   "Language",
+		Confidence: 40,
+		Source:     "language-discovery",
+	}))
+	// portfolio.
+	var report []TechnologyReport
+	get("/technologies", &report)
+	g.Expect(report).To(gomega.HaveLen(3))
+	g.Expect(report[0].Name).To(gomega.Equal("Java"))
+	g.Expect(report[0].Applications).To(gomega.Equal(2))
+	g.Expect(report[0].Sources).To(gomega.Equal([]string{"language-discovery"}))
+	get("/technologies?filter="+url.QueryEscape("category=Framework"), &report)
+	g.Expect(report).To(gomega.HaveLen(1))
+	g.Expect(report[0].Name).To(gomega.Equal("Spring Boot"))
+	g.Expect(report[0].Confidence).To(gomega.Equal(80))
+}
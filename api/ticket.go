@@ -3,17 +3,23 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/konveyor/tackle2-hub/model"
+	"github.com/konveyor/tackle2-hub/tracker"
 	"gorm.io/gorm/clause"
 )
 
 // Routes
 const (
-	TicketsRoot = "/tickets"
-	TicketRoot  = "/tickets" + "/:" + ID
+	TicketsRoot   = "/tickets"
+	TicketRoot    = "/tickets" + "/:" + ID
+	WorklogRoot   = TicketRoot + "/worklogs"
+	CommentsRoot  = TicketRoot + "/comments"
+	RetryRoot     = TicketRoot + "/retry"
+	TicketAppRoot = TicketRoot + "/application"
 )
 
 // Params.
@@ -34,7 +40,13 @@ func (h TicketHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(TicketsRoot+"/", h.List)
 	routeGroup.POST(TicketsRoot, h.Create)
 	routeGroup.GET(TicketRoot, h.Get)
+	routeGroup.HEAD(TicketRoot, h.Head)
 	routeGroup.DELETE(TicketRoot, h.Delete)
+	routeGroup.POST(WorklogRoot, h.WorklogCreate)
+	routeGroup.GET(CommentsRoot, h.CommentList)
+	routeGroup.POST(CommentsRoot, h.CommentCreate)
+	routeGroup.POST(RetryRoot, h.Retry)
+	routeGroup.PUT(TicketAppRoot, h.ApplicationPut)
 }
 
 // Get godoc
@@ -54,12 +66,26 @@ func (h TicketHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 
 	resource := Ticket{}
 	resource.With(m)
 	h.Respond(ctx, http.StatusOK, resource)
 }
 
+// Head godoc
+// @summary Check a ticket exists.
+// @description Check a ticket exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags tickets
+// @success 200
+// @success 304
+// @router /tickets/{id} [head]
+// @param id path int true "Ticket ID"
+func (h TicketHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.Ticket{})
+}
+
 // List godoc
 // @summary List all tickets.
 // @description List all tickets.
@@ -96,6 +122,9 @@ func (h TicketHandler) List(ctx *gin.Context) {
 // Create godoc
 // @summary Create a ticket.
 // @description Create a ticket.
+// @description The application ticket defaults are merged into
+// @description the fields. The fields in the request take precedence.
+// @description The application default tracker is used when the tracker is not specified.
 // @tags tickets
 // @accept json
 // @produce json
@@ -109,6 +138,16 @@ func (h TicketHandler) Create(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
+	err = h.withDefaults(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = h.validateFields(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	m := r.Model()
 	m.CreateUser = h.BaseHandler.CurrentUser(ctx)
 	result := h.DB(ctx).Create(m)
@@ -145,20 +184,357 @@ func (h TicketHandler) Delete(ctx *gin.Context) {
 	h.Status(ctx, http.StatusNoContent)
 }
 
+// WorklogCreate godoc
+// @summary Log work on a ticket.
+// @description Log work on a ticket in the external tracker.
+// @description Returns 501 when not supported by the tracker.
+// @tags tickets
+// @accept json
+// @produce json
+// @success 201 {object} api.Worklog
+// @router /tickets/{id}/worklogs [post]
+// @param id path int true "Ticket ID"
+// @param worklog body api.Worklog true "Worklog data"
+func (h TicketHandler) WorklogCreate(ctx *gin.Context) {
+	id := h.pk(ctx)
+	r := &Worklog{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m := &model.Ticket{}
+	db := h.preLoad(h.DB(ctx), "Tracker", "Tracker.Identity", "Tracker.TunnelIdentity")
+	err = db.First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if !m.Created {
+		err = &BadRequestError{"ticket: not created."}
+		_ = ctx.Error(err)
+		return
+	}
+	conn, err := tracker.NewConnector(m.Tracker)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	wConn, supported := conn.(tracker.WorklogConnector)
+	if !supported {
+		err = &NotImplemented{"worklogs not supported by the tracker."}
+		_ = ctx.Error(err)
+		return
+	}
+	worklog := r.Model()
+	err = wConn.AddWorklog(m, worklog)
+	if err != nil {
+		_ = ctx.Error(&TrackerError{err.Error()})
+		return
+	}
+	r.With(worklog)
+
+	h.Respond(ctx, http.StatusCreated, r)
+}
+
+// CommentList godoc
+// @summary List the comments on a ticket.
+// @description List the comments on a ticket. The comments are
+// @description synchronized with the (remote) issue.
+// @description Returns 501 when not supported by the tracker.
+// @tags tickets
+// @produce json
+// @success 200 {object} []api.TicketComment
+// @router /tickets/{id}/comments [get]
+// @param id path int true "Ticket ID"
+func (h TicketHandler) CommentList(ctx *gin.Context) {
+	m, conn, err := h.commentConnector(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	comments, err := tracker.SyncComments(h.DB(ctx), conn, m)
+	if err != nil {
+		_ = ctx.Error(&TrackerError{err.Error()})
+		return
+	}
+	resources := []TicketComment{}
+	for i := range comments {
+		r := TicketComment{}
+		r.With(&comments[i])
+		resources = append(resources, r)
+	}
+
+	h.Respond(ctx, http.StatusOK, resources)
+}
+
+// CommentCreate godoc
+// @summary Comment on a ticket.
+// @description Add a comment to a ticket in the external tracker.
+// @description Returns 501 when not supported by the tracker.
+// @tags tickets
+// @accept json
+// @produce json
+// @success 201 {object} api.TicketComment
+// @router /tickets/{id}/comments [post]
+// @param id path int true "Ticket ID"
+// @param comment body api.TicketComment true "Comment data"
+func (h TicketHandler) CommentCreate(ctx *gin.Context) {
+	r := &TicketComment{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m, conn, err := h.commentConnector(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	comment := &tracker.Comment{Body: r.Body}
+	added, err := tracker.AddComment(h.DB(ctx), conn, m, comment)
+	if err != nil {
+		_ = ctx.Error(&TrackerError{err.Error()})
+		return
+	}
+	r.With(added)
+
+	h.Respond(ctx, http.StatusCreated, r)
+}
+
+// Retry godoc
+// @summary Retry creating a ticket.
+// @description Retry creating the ticket in the external tracker immediately
+// @description rather than when the next attempt (backoff) is scheduled.
+// @description The outcome of the attempt is reported by the ticket.
+// @description Returns 409 when the ticket has been created.
+// @tags tickets
+// @produce json
+// @success 200 {object} api.Ticket
+// @router /tickets/{id}/retry [post]
+// @param id path int true "Ticket ID"
+func (h TicketHandler) Retry(ctx *gin.Context) {
+	id := h.pk(ctx)
+	m := &model.Ticket{}
+	db := h.preLoad(
+		h.DB(ctx),
+		clause.Associations,
+		"Application.Tags",
+		"Application.BusinessService",
+		"Application.MigrationWave")
+	err := db.First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if m.Created {
+		err = &Conflict{Reason: "ticket: already created."}
+		_ = ctx.Error(err)
+		return
+	}
+	err = tracker.CreateNow(h.DB(ctx), m)
+	if err != nil {
+		_ = ctx.Error(&TrackerError{err.Error()})
+		return
+	}
+	r := Ticket{}
+	r.With(m)
+
+	h.Respond(ctx, http.StatusOK, r)
+}
+
+// commentConnector returns the (created) ticket and
+// the comment connector for its tracker.
+func (h TicketHandler) commentConnector(ctx *gin.Context) (m *model.Ticket, cConn tracker.CommentConnector, err error) {
+	id := h.pk(ctx)
+	m = &model.Ticket{}
+	db := h.preLoad(h.DB(ctx), "Tracker", "Tracker.Identity", "Tracker.TunnelIdentity")
+	err = db.First(m, id).Error
+	if err != nil {
+		return
+	}
+	if !m.Created {
+		err = &BadRequestError{"ticket: not created."}
+		return
+	}
+	conn, err := tracker.NewConnector(m.Tracker)
+	if err != nil {
+		return
+	}
+	cConn, supported := conn.(tracker.CommentConnector)
+	if !supported {
+		err = &NotImplemented{"comments not supported by the tracker."}
+		return
+	}
+	return
+}
+
+// ApplicationPut godoc
+// @summary Link a ticket to an application.
+// @description Link (ensure) a ticket to an application.
+// @description A no-op when already linked to the application.
+// @description Changing the application is recorded.
+// @tags tickets
+// @accept json
+// @produce json
+// @success 200 {object} api.Ticket
+// @router /tickets/{id}/application [put]
+// @param id path int true "Ticket ID"
+// @param application body api.TicketApplication true "Application reference"
+func (h TicketHandler) ApplicationPut(ctx *gin.Context) {
+	id := h.pk(ctx)
+	r := &TicketApplication{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = h.VerifyRefs(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m := &model.Ticket{}
+	err = h.DB(ctx).First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if m.ApplicationID != r.Application.ID {
+		previous := m.ApplicationID
+		m.ApplicationID = r.Application.ID
+		m.UpdateUser = h.CurrentUser(ctx)
+		db := h.DB(ctx).Model(m)
+		db = db.Select("ApplicationID", "UpdateUser")
+		err = db.Updates(m).Error
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		log.Info(
+			"Ticket application changed.",
+			"ticket",
+			m.ID,
+			"from",
+			previous,
+			"to",
+			m.ApplicationID,
+			"user",
+			m.UpdateUser)
+	}
+	db := h.preLoad(h.DB(ctx), clause.Associations)
+	err = db.First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	resource := Ticket{}
+	resource.With(m)
+	h.Respond(ctx, http.StatusOK, resource)
+}
+
+// withDefaults merges the application ticket defaults
+// into the ticket fields and applies the default tracker.
+func (h TicketHandler) withDefaults(ctx *gin.Context, r *Ticket) (err error) {
+	app := &model.Application{}
+	err = h.DB(ctx).First(app, r.Application.ID).Error
+	if err != nil {
+		return
+	}
+	if r.Tracker.ID == 0 {
+		if app.DefaultTrackerID == nil {
+			err = &FieldError{
+				Field: "tracker.id",
+				Rule:  "required",
+			}
+			return
+		}
+		r.Tracker.ID = *app.DefaultTrackerID
+	}
+	defaults := Fields{}
+	_ = json.Unmarshal(app.TicketDefaults, &defaults)
+	r.Fields = defaults.Merge(r.Fields)
+	return
+}
+
+// validateFields validates the (merged) ticket fields include
+// the fields required by the issue type.
+// Skipped when the tracker is not connected, within a transaction
+// so that the remote is not called while holding the DB lock, or when
+// the required fields cannot be fetched. Tickets are created (in the
+// tracker) asynchronously and failures are reported on the ticket.
+func (h TicketHandler) validateFields(ctx *gin.Context, r *Ticket) (err error) {
+	rtx := WithContext(ctx)
+	if rtx.Transaction {
+		return
+	}
+	m := &model.Tracker{}
+	db := h.preLoad(h.DB(ctx), "Identity", "TunnelIdentity")
+	err = db.First(m, r.Tracker.ID).Error
+	if err != nil {
+		return
+	}
+	if !m.Connected {
+		return
+	}
+	conn, err := tracker.NewConnector(m)
+	if err != nil {
+		return
+	}
+	required, rErr := conn.RequiredFields(r.Parent, r.Kind)
+	if rErr != nil {
+		Log.Info(
+			"Required fields not validated.",
+			"tracker",
+			m.ID,
+			"reason",
+			rErr.Error())
+		return
+	}
+	supplied := Fields{}
+	for _, key := range tracker.SuppliedFields {
+		supplied[key] = true
+	}
+	supplied = supplied.Merge(r.Fields)
+	var missing []string
+	for _, key := range required {
+		if _, found := supplied[key]; !found {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		err = &BadRequestError{
+			"fields: required: " + strings.Join(missing, ", "),
+		}
+	}
+	return
+}
+
 // Ticket API Resource
 type Ticket struct {
-	Resource    `yaml:",inline"`
-	Kind        string    `json:"kind" binding:"required"`
-	Reference   string    `json:"reference"`
-	Link        string    `json:"link"`
-	Parent      string    `json:"parent" binding:"required"`
-	Error       bool      `json:"error"`
-	Message     string    `json:"message"`
-	Status      string    `json:"status"`
-	LastUpdated time.Time `json:"lastUpdated" yaml:"lastUpdated"`
-	Fields      Fields    `json:"fields"`
-	Application Ref       `json:"application" binding:"required"`
-	Tracker     Ref       `json:"tracker" binding:"required"`
+	Resource  `yaml:",inline"`
+	Kind      string `json:"kind" binding:"required"`
+	Reference string `json:"reference"`
+	Link      string `json:"link"`
+	Parent    string `json:"parent" binding:"required"`
+	Error     bool   `json:"error"`
+	Message   string `json:"message"`
+	// Status (normalized): New, In Progress, Done, Unknown.
+	Status       string    `json:"status"`
+	RemoteStatus string    `json:"remoteStatus,omitempty" yaml:"remoteStatus,omitempty"`
+	LastUpdated  time.Time `json:"lastUpdated" yaml:"lastUpdated"`
+	Fields       Fields    `json:"fields"`
+	// Summary (read-only) rendered when the ticket is created.
+	Summary string `json:"summary,omitempty" yaml:"summary,omitempty"`
+	// Description (read-only) rendered when the ticket is created.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Attempts (read-only) to create the ticket.
+	Attempts    int        `json:"attempts,omitempty" yaml:"attempts,omitempty"`
+	LastAttempt *time.Time `json:"lastAttempt,omitempty" yaml:"lastAttempt,omitempty"`
+	// NextAttempt (read-only) scheduled after a failed attempt.
+	NextAttempt *time.Time `json:"nextAttempt,omitempty" yaml:"nextAttempt,omitempty"`
+	Application Ref        `json:"application" binding:"required" ref:"application"`
+	Tracker     Ref        `json:"tracker" binding:"-" ref:"tracker"`
 }
 
 // With updates the resource with the model.
@@ -171,7 +547,19 @@ func (r *Ticket) With(m *model.Ticket) {
 	r.Error = m.Error
 	r.Message = m.Message
 	r.Status = m.Status
+	r.RemoteStatus = m.RemoteStatus
 	r.LastUpdated = m.LastUpdated
+	r.Summary = m.Summary
+	r.Description = m.Description
+	r.Attempts = m.Attempts
+	r.LastAttempt = nil
+	if !m.LastAttempt.IsZero() {
+		r.LastAttempt = &m.LastAttempt
+	}
+	r.NextAttempt = nil
+	if !m.NextAttempt.IsZero() {
+		r.NextAttempt = &m.NextAttempt
+	}
 	r.Application = r.ref(m.ApplicationID, m.Application)
 	r.Tracker = r.ref(m.TrackerID, m.Tracker)
 	_ = json.Unmarshal(m.Fields, &r.Fields)
@@ -194,4 +582,98 @@ func (r *Ticket) Model() (m *model.Ticket) {
 	return
 }
 
+// TicketApplication REST resource.
+// The application linked to a ticket.
+type TicketApplication struct {
+	Application Ref `json:"application" binding:"required" ref:"application"`
+}
+
+// TicketComment REST resource.
+type TicketComment struct {
+	ID        uint      `json:"id"`
+	Reference string    `json:"reference"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body" binding:"required"`
+	Created   time.Time `json:"created"`
+}
+
+// With updates the resource with the model.
+func (r *TicketComment) With(m *model.TicketComment) {
+	r.ID = m.ID
+	r.Reference = m.Reference
+	r.Author = m.Author
+	r.Body = m.Body
+	r.Created = m.Created
+}
+
+// Worklog REST resource.
+type Worklog struct {
+	ID string `json:"id"`
+	// TimeSpent (seconds).
+	TimeSpent int    `json:"timeSpent" yaml:"timeSpent" binding:"required,gt=0"`
+	Note      string `json:"note"`
+}
+
+// With updates the resource with the worklog.
+func (r *Worklog) With(m *tracker.Worklog) {
+	r.ID = m.ID
+	r.TimeSpent = m.TimeSpent
+	r.Note = m.Note
+}
+
+// Model builds a worklog.
+func (r *Worklog) Model() (m *tracker.Worklog) {
+	m = &tracker.Worklog{
+		TimeSpent: r.TimeSpent,
+		Note:      r.Note,
+	}
+	return
+}
+
 type Fields map[string]interface{}
+
+// TicketTemplate REST resource.
+// The summary, description and (string) field values are
+// text templates. See: tracker.TemplateData.
+type TicketTemplate struct {
+	Summary     string `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Fields      Fields `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// With updates the resource with the model.
+func (r *TicketTemplate) With(m model.JSON) {
+	_ = json.Unmarshal(m, r)
+}
+
+// Model builds the model.
+func (r *TicketTemplate) Model() (m model.JSON) {
+	m, _ = json.Marshal(r)
+	return
+}
+
+// Validate the templates.
+func (r *TicketTemplate) Validate() (err error) {
+	t := tracker.Template{
+		Summary:     r.Summary,
+		Description: r.Description,
+		Fields:      r.Fields,
+	}
+	err = t.Validate()
+	if err != nil {
+		err = &BadRequestError{err.Error()}
+	}
+	return
+}
+
+// Merge returns the fields merged with the overrides.
+func (f Fields) Merge(overrides Fields) (merged Fields) {
+	merged = Fields{}
+	for k, v := range f {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return
+}
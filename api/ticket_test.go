@@ -0,0 +1,166 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/konveyor/tackle2-hub/tracker"
+	"github.com/onsi/gomega"
+)
+
+func TestFieldsMerge(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	cases := []struct {
+		defaults  Fields
+		overrides Fields
+		expected  Fields
+	}{
+		{
+			expected: Fields{},
+		},
+		{
+			defaults: Fields{"a": 1},
+			expected: Fields{"a": 1},
+		},
+		{
+			overrides: Fields{"b": 2},
+			expected:  Fields{"b": 2},
+		},
+		{
+			defaults:  Fields{"a": 1, "b": 1},
+			overrides: Fields{"b": 2, "c": 3},
+			expected:  Fields{"a": 1, "b": 2, "c": 3},
+		},
+	}
+	for _, c := range cases {
+		merged := c.defaults.Merge(c.overrides)
+		g.Expect(merged).To(gomega.Equal(c.expected))
+	}
+	// Not modified.
+	defaults := Fields{"a": 1}
+	_ = defaults.Merge(Fields{"a": 2})
+	g.Expect(defaults).To(gomega.Equal(Fields{"a": 1}))
+}
+
+func TestValidateFields(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	jira := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+  "projects": [
+    {
+      "issuetypes": [
+        {
+          "fields": {
+            "summary": {"required": true, "name": "Summary"},
+            "priority": {"required": true, "name": "Priority"},
+            "labels": {"required": false, "name": "Labels"}
+          }
+        }
+      ]
+    }
+  ]
+}`))
+	}))
+	defer jira.Close()
+	db := newDB(t)
+	identity := &model.Identity{Name: "jira", Kind: tracker.BasicAuth, User: "elmer"}
+	g.Expect(db.Create(identity).Error).To(gomega.BeNil())
+	connected := &model.Tracker{
+		Name:       "connected",
+		URL:        jira.URL,
+		Kind:       tracker.JiraCloud,
+		Connected:  true,
+		IdentityID: identity.ID,
+	}
+	g.Expect(db.Create(connected).Error).To(gomega.BeNil())
+	unreachable := &model.Tracker{
+		Name:       "unreachable",
+		URL:        "http://127.0.0.1:1",
+		Kind:       tracker.JiraCloud,
+		Connected:  true,
+		IdentityID: identity.ID,
+	}
+	g.Expect(db.Create(unreachable).Error).To(gomega.BeNil())
+
+	h := TicketHandler{}
+	ctx := &gin.Context{}
+	rtx := WithContext(ctx)
+	rtx.DB = db
+	r := &Ticket{Parent: "1", Kind: "2"}
+
+	// Missing.
+	r.Tracker.ID = connected.ID
+	err := h.validateFields(ctx, r)
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(err.Error()).To(gomega.Equal("fields: required: priority"))
+
+	// Supplied.
+	r.Fields = Fields{"priority": "high"}
+	err = h.validateFields(ctx, r)
+	g.Expect(err).To(gomega.BeNil())
+
+	// Remote failure (skipped).
+	r.Fields = nil
+	r.Tracker.ID = unreachable.ID
+	err = h.validateFields(ctx, r)
+	g.Expect(err).To(gomega.BeNil())
+
+	// Transaction (skipped).
+	r.Tracker.ID = connected.ID
+	rtx.Transaction = true
+	err = h.validateFields(ctx, r)
+	g.Expect(err).To(gomega.BeNil())
+}
+
+func TestTicketApplicationPut(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	m := newTracker(g, db, "jira")
+	a := &model.Application{Name: "a"}
+	g.Expect(db.Create(a).Error).To(gomega.BeNil())
+	b := &model.Application{Name: "b"}
+	g.Expect(db.Create(b).Error).To(gomega.BeNil())
+	ticket := &model.Ticket{Kind: "1", Parent: "1", ApplicationID: a.ID, TrackerID: m.ID}
+	g.Expect(db.Create(ticket).Error).To(gomega.BeNil())
+
+	h := TicketHandler{}
+	e := newEngine(db)
+	e.PUT(TicketAppRoot, h.ApplicationPut)
+	put := func(body string) (w *httptest.ResponseRecorder) {
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, "/tickets/1/application", bytes.NewBufferString(body))
+		e.ServeHTTP(w, req)
+		return
+	}
+	get := func() (m *model.Ticket) {
+		m = &model.Ticket{}
+		g.Expect(db.First(m, ticket.ID).Error).To(gomega.BeNil())
+		return
+	}
+
+	// Already linked.
+	for i := 0; i < 2; i++ {
+		w := put(`{"application":{"id":1}}`)
+		g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+		g.Expect(get().UpdateTime).To(gomega.BeTemporally("==", ticket.UpdateTime))
+	}
+
+	// Changed.
+	w := put(`{"application":{"id":2}}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	r := Ticket{}
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+	g.Expect(r.Application.ID).To(gomega.Equal(b.ID))
+	g.Expect(r.Application.Name).To(gomega.Equal("b"))
+	g.Expect(get().ApplicationID).To(gomega.Equal(b.ID))
+
+	// Not found.
+	w = put(`{"application":{"id":9}}`)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusUnprocessableEntity))
+	g.Expect(get().ApplicationID).To(gomega.Equal(b.ID))
+}
@@ -1,13 +1,21 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	qf "github.com/konveyor/tackle2-hub/api/filter"
 	"github.com/konveyor/tackle2-hub/model"
 	"github.com/konveyor/tackle2-hub/tracker"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -15,14 +23,39 @@ import (
 const (
 	TrackersRoot             = "/trackers"
 	TrackerRoot              = "/trackers" + "/:" + ID
+	TrackerValidateRoot      = TrackersRoot + "/validate"
+	TrackerEventsRoot        = TrackersRoot + "/events"
+	TrackerTagsRoot          = TrackersRoot + "/tags"
+	TrackerMetricsRoot       = TrackersRoot + "/metrics"
 	TrackerProjects          = TrackerRoot + "/projects"
+	TrackerRateLimitRoot     = TrackerRoot + "/ratelimit"
+	TrackerHistoryRoot       = TrackerRoot + "/history"
+	TrackerTestRoot          = TrackerRoot + "/test"
+	TrackerRefreshRoot       = TrackerRoot + "/refresh"
+	TrackerWebhookTestRoot   = TrackerRoot + "/test-webhook"
+	TrackerWebhookRoot       = tracker.WebhookPath + "/:" + Key
+	TrackerEventRoot         = TrackerRoot + "/webhook"
+	TrackerChangeURLRoot     = TrackerRoot + "/change-url"
 	TrackerProject           = TrackerRoot + "/projects" + "/:" + ID2
 	TrackerProjectIssueTypes = TrackerProject + "/issuetypes"
 )
 
 // Params
 const (
-	Connected = "connected"
+	Connected     = "connected"
+	Strict        = "strict"
+	PageCursor    = "cursor"
+	Q             = "q"
+	SchemaValid   = "schemaValid"
+	ErrorCategory = "errorCategory"
+	From          = "from"
+	To            = "to"
+	Outcome       = "outcome"
+	Format        = "format"
+	Tenant        = "tenant"
+	Stream        = "stream"
+	Force         = "force"
+	Secret        = "secret"
 )
 
 // TrackerHandler handles ticket tracker routes.
@@ -37,12 +70,29 @@ func (h TrackerHandler) AddRoutes(e *gin.Engine) {
 	routeGroup.GET(TrackersRoot, h.List)
 	routeGroup.GET(TrackersRoot+"/", h.List)
 	routeGroup.POST(TrackersRoot, h.Create)
+	routeGroup.POST(TrackerValidateRoot, h.Validate)
+	routeGroup.GET(TrackerEventsRoot, h.EventList)
+	routeGroup.POST(TrackerTagsRoot, Transaction, h.TagUpdate)
+	routeGroup.GET(TrackerMetricsRoot, h.Metrics)
 	routeGroup.GET(TrackerRoot, h.Get)
+	routeGroup.HEAD(TrackerRoot, h.Head)
 	routeGroup.PUT(TrackerRoot, h.Update)
-	routeGroup.DELETE(TrackerRoot, h.Delete)
+	routeGroup.DELETE(TrackerRoot, Transaction, h.Delete)
+	routeGroup.GET(TrackerRateLimitRoot, h.RateLimit)
+	routeGroup.GET(TrackerHistoryRoot, h.History)
+	routeGroup.POST(TrackerChangeURLRoot, h.ChangeURL)
+	routeGroup.GET(TrackerTestRoot, h.Test)
+	routeGroup.POST(TrackerTestRoot, h.TestNow)
+	routeGroup.POST(TrackerRefreshRoot, h.Refresh)
+	routeGroup.POST(TrackerWebhookTestRoot, h.WebhookTest)
 	routeGroup.GET(TrackerProjects, h.ProjectList)
 	routeGroup.GET(TrackerProject, h.ProjectGet)
 	routeGroup.GET(TrackerProjectIssueTypes, h.ProjectIssueTypeList)
+	//
+	// Probes are delivered by the remote (unauthenticated).
+	e.POST(TrackerWebhookRoot, h.WebhookReceived)
+	// Events are delivered by the remote (shared secret).
+	e.POST(TrackerEventRoot, h.EventReceived)
 }
 
 // Get godoc
@@ -62,22 +112,72 @@ func (h TrackerHandler) Get(ctx *gin.Context) {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	h.Validators(ctx, &m.Model)
 
 	resource := Tracker{}
 	resource.With(m)
 	h.Respond(ctx, http.StatusOK, resource)
 }
 
+// Head godoc
+// @summary Check a tracker exists.
+// @description Check a tracker exists.
+// @description The ETag and Last-Modified headers reflect the update time.
+// @tags trackers
+// @success 200
+// @success 304
+// @router /trackers/{id} [head]
+// @param id path int true "Tracker ID"
+func (h TrackerHandler) Head(ctx *gin.Context) {
+	h.BaseHandler.Head(ctx, &model.Tracker{})
+}
+
 // List godoc
 // @summary List all trackers.
 // @description List all trackers.
+// @description When ?id= is specified (repeatable), only the trackers
+// @description with the specified IDs are listed in the order requested.
+// @description Missing trackers are omitted unless ?strict=true.
+// @description CSV is returned when Accept: text/csv or ?format=csv. Columns are selected using: ?columns=name,url.
+// @description filters:
+// @description - id
+// @description - name
+// @description - kind
+// @description - url
+// @description - connected
+// @description - errorCategory
+// @description - statusReason
+// @description - identity.id
+// @description - identity.name
+// @description Name substring matched using: name~*text*.
+// @description Sorted using ?sort= (eg: name, D:lastUpdated).
 // @tags trackers
-// @produce json
+// @produce json,text/csv
 // @success 200 {object} []api.Tracker
 // @router /trackers [get]
+// @param filter query string false "Filter"
+// @param id query []int false "Tracker ID"
+// @param strict query bool false "404 when any ID not found"
+// @param schemaValid query bool false "Metadata valid for the kind schema"
+// @param errorCategory query string false "Connection error category"
+// @param sort query string false "Sort"
+// @param format query string false "csv"
 func (h TrackerHandler) List(ctx *gin.Context) {
 	var list []model.Tracker
+	filter, err := h.filter(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	sort := Sort{}
+	err = sort.With(ctx, &model.Tracker{})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 	db := h.preLoad(h.DB(ctx), clause.Associations)
+	db = h.where(ctx, db, filter)
+	db = sort.Sorted(db)
 	kind := ctx.Query(Kind)
 	if kind != "" {
 		db = db.Where(Kind, kind)
@@ -91,24 +191,170 @@ func (h TrackerHandler) List(ctx *gin.Context) {
 		}
 		db = db.Where(Connected, connected)
 	}
+	category := ctx.Query(ErrorCategory)
+	if category != "" {
+		db = db.Where("ErrorCategory", category)
+	}
+	ids, err := h.ids(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if len(ids) > 0 {
+		db = db.Where("id IN ?", ids)
+	}
 	result := db.Find(&list)
 	if result.Error != nil {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	if len(ids) > 0 {
+		list, err = h.ordered(ctx, ids, list)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+	schemaValid := ctx.Query(SchemaValid)
 	resources := []Tracker{}
 	for i := range list {
 		r := Tracker{}
 		r.With(&list[i])
+		if schemaValid != "" {
+			b, err := strconv.ParseBool(schemaValid)
+			if err != nil {
+				h.Status(ctx, http.StatusBadRequest)
+				return
+			}
+			if r.SchemaValid != b {
+				continue
+			}
+		}
 		resources = append(resources, r)
 	}
+	if h.Accepted(ctx, CSV) || ctx.Query(Format) == "csv" {
+		h.writeCSV(ctx, h.trackerCSV(resources))
+		return
+	}
 
 	h.Respond(ctx, http.StatusOK, resources)
 }
 
+// Metrics godoc
+// @summary Tracker health metrics.
+// @description Tracker health in the OpenMetrics text format.
+// @description Only the trackers matched by the filter are reported.
+// @description filters: (see: List)
+// @tags trackers
+// @produce plain
+// @success 200 {string} string
+// @router /trackers/metrics [get]
+// @param filter query string false "Filter"
+// @param tenant query string false "Tenant (not supported)"
+func (h TrackerHandler) Metrics(ctx *gin.Context) {
+	if ctx.Query(Tenant) != "" {
+		err := &NotImplemented{"tenant scoping not supported."}
+		_ = ctx.Error(err)
+		return
+	}
+	filter, err := h.filter(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var list []model.Tracker
+	db := h.DB(ctx)
+	db = h.where(ctx, db, filter)
+	err = db.Order("ID").Find(&list).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.Writer.Header().Set(ContentType, OpenMetrics)
+	ctx.Writer.WriteHeader(http.StatusOK)
+	h.Status(ctx, http.StatusOK)
+	writer := TrackerMetrics{Writer: ctx.Writer}
+	writer.Write(list)
+}
+
+// unique enforces the (configured) uniqueness policy.
+func (h TrackerHandler) unique(ctx *gin.Context, m *model.Tracker) (err error) {
+	policy := Settings.Hub.Tracker.Unique
+	conflict, err := tracker.Conflict(h.DB(ctx), policy, m)
+	if err != nil || conflict == nil {
+		return
+	}
+	err = &Conflict{
+		Reason: "tracker: conflicts with '" + conflict.Name + "' (unique: " + policy + ").",
+	}
+	return
+}
+
+// filter returns the (validated) tracker filter.
+func (h TrackerHandler) filter(ctx *gin.Context) (filter qf.Filter, err error) {
+	filter, err = qf.New(ctx,
+		[]qf.Assert{
+			{Field: "id", Kind: qf.LITERAL},
+			{Field: "name", Kind: qf.STRING},
+			{Field: "kind", Kind: qf.STRING},
+			{Field: "url", Kind: qf.STRING},
+			{Field: "connected", Kind: qf.LITERAL},
+			{Field: "errorCategory", Kind: qf.STRING},
+			{Field: "statusReason", Kind: qf.STRING},
+			{Field: "identity.id", Kind: qf.LITERAL},
+			{Field: "identity.name", Kind: qf.STRING},
+		})
+	return
+}
+
+// where applies the filter.
+// The identity fields are matched using a subquery.
+func (h TrackerHandler) where(ctx *gin.Context, in *gorm.DB, filter qf.Filter) (db *gorm.DB) {
+	db = filter.Where(in)
+	idFilter := filter.Resource("identity")
+	if !idFilter.Empty() {
+		iq := h.DB(ctx)
+		iq = iq.Model(&model.Identity{})
+		iq = iq.Select("ID")
+		iq = idFilter.Where(iq)
+		db = db.Where("IdentityID IN (?)", iq)
+	}
+	return
+}
+
+// trackerCSV returns the trackers CSV table.
+// Metadata and secrets are excluded.
+func (h TrackerHandler) trackerCSV(resources []Tracker) (table *CsvTable) {
+	table = &CsvTable{
+		Columns: []string{
+			"name",
+			"url",
+			"kind",
+			"connected",
+			"lastUpdated",
+			"identity",
+		},
+	}
+	for i := range resources {
+		r := &resources[i]
+		table.Add(
+			map[string]string{
+				"name":        r.Name,
+				"url":         r.URL,
+				"kind":        r.Kind,
+				"connected":   strconv.FormatBool(r.Connected),
+				"lastUpdated": r.LastUpdated.Format(time.RFC3339),
+				"identity":    r.Identity.Name,
+			})
+	}
+	return
+}
+
 // Create godoc
 // @summary Create a tracker.
 // @description Create a tracker.
+// @description The configured default kind is used when the kind is not specified.
+// @description The configured default metadata is applied (when not specified) for the default kind.
 // @tags trackers
 // @accept json
 // @produce json
@@ -117,86 +363,760 @@ func (h TrackerHandler) List(ctx *gin.Context) {
 // @param tracker body api.Tracker true "Tracker data"
 func (h TrackerHandler) Create(ctx *gin.Context) {
 	r := &Tracker{}
+	r.Kind = Settings.Hub.Tracker.Default.Kind
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	r.withDefaults()
+	err = r.Validate()
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = h.VerifyRefs(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m := r.Model()
+	err = h.unique(ctx, m)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m.CreateUser = h.BaseHandler.CurrentUser(ctx)
+	result := h.DB(ctx).Create(m)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	r.With(m)
+
+	h.Respond(ctx, http.StatusCreated, r)
+}
+
+// Delete godoc
+// @summary Delete a tracker.
+// @description Delete a tracker.
+// @description A tracker with tickets is not deleted (409) unless forced
+// @description and the (IDs of) the tickets are reported.
+// @description When forced, the tickets are deleted.
+// @tags trackers
+// @success 204
+// @router /trackers/{id} [delete]
+// @param id path int true "Tracker id"
+// @param force query bool false "Delete the tickets"
+func (h TrackerHandler) Delete(ctx *gin.Context) {
+	id := h.pk(ctx)
+	m := &model.Tracker{}
+	result := h.DB(ctx).First(m, id)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	var tickets []uint
+	result = h.DB(ctx).Model(&model.Ticket{}).Where("TrackerID", id).Pluck("ID", &tickets)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	if len(tickets) > 0 {
+		if ctx.Query(Force) != "true" {
+			var ids []string
+			for _, ticket := range tickets {
+				ids = append(ids, strconv.Itoa(int(ticket)))
+			}
+			err := &Conflict{
+				Reason: "tracker: has tickets: " + strings.Join(ids, ",") + " (force=true to delete).",
+			}
+			_ = ctx.Error(err)
+			return
+		}
+		result = h.DB(ctx).Delete(&model.Ticket{}, tickets)
+		if result.Error != nil {
+			_ = ctx.Error(result.Error)
+			return
+		}
+	}
+	result = h.DB(ctx).Delete(m)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+
+	h.Status(ctx, http.StatusNoContent)
+}
+
+// Update godoc
+// @summary Update a tracker.
+// @description Update a tracker.
+// @tags trackers
+// @accept json
+// @success 204
+// @router /trackers/{id} [put]
+// @param id path int true "Tracker id"
+// @param application body api.Tracker true "Tracker data"
+func (h TrackerHandler) Update(ctx *gin.Context) {
+	id := h.pk(ctx)
+	r := &Tracker{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = r.Validate()
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	err = h.VerifyRefs(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m := r.Model()
+	m.ID = id
+	err = h.unique(ctx, m)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m.UpdateUser = h.BaseHandler.CurrentUser(ctx)
+	db := h.DB(ctx).Model(m)
+	db = db.Omit(clause.Associations)
+	fields := h.fields(m)
+	delete(fields, "RateLimit")
+	delete(fields, "ConnectorVersion")
+	result := db.Updates(fields)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	err = h.DB(ctx).Model(m).Association("Tags").Replace("Tags", m.Tags)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	h.Status(ctx, http.StatusNoContent)
+}
+
+// ChangeURL godoc
+// @summary Change a tracker's URL.
+// @description Change the URL of a tracker. The connection is tested
+// @description using the new URL and the change is only committed on success;
+// @description otherwise, the tracker is not changed and the failure is reported.
+// @tags trackers
+// @accept json
+// @produce json
+// @success 200 {object} api.Tracker
+// @router /trackers/{id}/change-url [post]
+// @param id path int true "Tracker id"
+// @param url body api.TrackerURL true "URL data"
+func (h TrackerHandler) ChangeURL(ctx *gin.Context) {
+	id := h.pk(ctx)
+	r := &TrackerURL{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m := &model.Tracker{}
+	db := h.preLoad(h.DB(ctx), clause.Associations)
+	err = db.First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	resource := Tracker{}
+	resource.With(m)
+	resource.URL = r.URL
+	err = resource.Validate()
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m.URL = r.URL
+	conn, err := tracker.NewConnector(m)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	connected, err := conn.TestConnection()
+	if err == nil && !connected {
+		err = errors.New("not connected.")
+	}
+	if err != nil {
+		_ = ctx.Error(&TrackerError{err.Error()})
+		return
+	}
+	m.Connected = true
+	m.Message = ""
+	m.StatusReason = ""
+	m.ErrorCategory = ""
+	m.LastUpdated = time.Now()
+	m.UpdateUser = h.BaseHandler.CurrentUser(ctx)
+	db = h.DB(ctx).Model(m)
+	db = db.Select(
+		"URL",
+		"Connected",
+		"Message",
+		"StatusReason",
+		"ErrorCategory",
+		"LastUpdated",
+		"UpdateUser")
+	err = db.Updates(m).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	resource.With(m)
+
+	h.Respond(ctx, http.StatusOK, resource)
+}
+
+// TagUpdate godoc
+// @summary Add and remove tags on trackers.
+// @description Add and remove tags on the trackers matched by
+// @description the ID list or the filter. Reports the number of trackers affected.
+// @tags trackers
+// @accept json
+// @produce json
+// @success 200 {object} api.TrackerTagged
+// @router /trackers/tags [post]
+// @param tagging body api.TrackerTagging true "Tagging data"
+func (h TrackerHandler) TagUpdate(ctx *gin.Context) {
+	r := &TrackerTagging{}
+	err := h.Bind(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if len(r.IDs) == 0 && r.Filter == nil {
+		err = &BadRequestError{"ids or filter required."}
+		_ = ctx.Error(err)
+		return
+	}
+	err = h.VerifyRefs(ctx, r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	db := h.DB(ctx)
+	if len(r.IDs) > 0 {
+		db = db.Where("id IN ?", r.IDs)
+	}
+	if r.Filter != nil {
+		if r.Filter.Kind != "" {
+			db = db.Where("Kind", r.Filter.Kind)
+		}
+		if r.Filter.Connected != nil {
+			db = db.Where("Connected", *r.Filter.Connected)
+		}
+		if r.Filter.ErrorCategory != "" {
+			db = db.Where("ErrorCategory", r.Filter.ErrorCategory)
+		}
+	}
+	var list []model.Tracker
+	err = db.Find(&list).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	add := r.tags(r.Add)
+	remove := r.tags(r.Remove)
+	for i := range list {
+		m := &list[i]
+		if len(add) > 0 {
+			err = h.DB(ctx).Model(m).Association("Tags").Append(add)
+			if err != nil {
+				_ = ctx.Error(err)
+				return
+			}
+		}
+		if len(remove) > 0 {
+			err = h.DB(ctx).Model(m).Association("Tags").Delete(remove)
+			if err != nil {
+				_ = ctx.Error(err)
+				return
+			}
+		}
+	}
+
+	h.Respond(ctx, http.StatusOK, TrackerTagged{Affected: len(list)})
+}
+
+// Validate godoc
+// @summary Validate a tracker.
+// @description Validate a tracker without creating it.
+// @description Reports whether the payload is well-formed, the identity exists
+// @description and is compatible, the metadata is valid and the remote connects.
+// @description URL (TLS) failures are reported as binding failures.
+// @tags trackers
+// @accept json
+// @produce json
+// @success 200 {object} api.TrackerValidation
+// @router /trackers/validate [post]
+// @param tracker body api.Tracker true "Tracker data"
+func (h TrackerHandler) Validate(ctx *gin.Context) {
+	report := TrackerValidation{}
+	r := &Tracker{}
+	r.Kind = Settings.Hub.Tracker.Default.Kind
 	err := h.Bind(ctx, r)
+	if err == nil {
+		r.withDefaults()
+		err = r.validURL()
+	}
+	report.Binding.With(err)
+	if err != nil {
+		h.Respond(ctx, http.StatusOK, report)
+		return
+	}
+	err = r.Validate()
+	report.Metadata.With(err)
+	identity := &model.Identity{}
+	err = h.DB(ctx).First(identity, r.Identity.ID).Error
+	if err == nil {
+		switch identity.Kind {
+		case tracker.BasicAuth, tracker.BearerAuth:
+		default:
+			err = &BadRequestError{"identity kind: " + identity.Kind + " not supported."}
+		}
+	}
+	var tunnelIdentity *model.Identity
+	if err == nil && r.TunnelIdentity != nil {
+		tunnelIdentity = &model.Identity{}
+		err = h.DB(ctx).First(tunnelIdentity, r.TunnelIdentity.ID).Error
+	}
+	report.Identity.With(err)
+	if report.Identity.Valid && report.Metadata.Valid {
+		m := r.Model()
+		m.Identity = identity
+		m.TunnelIdentity = tunnelIdentity
+		conn, err := tracker.NewConnector(m)
+		if err == nil {
+			_, err = conn.TestConnection()
+		}
+		report.Connectivity.With(err)
+	} else {
+		report.Connectivity.Message = "skipped."
+	}
+	report.Valid = report.Binding.Valid &&
+		report.Identity.Valid &&
+		report.Metadata.Valid &&
+		report.Connectivity.Valid
+
+	h.Respond(ctx, http.StatusOK, report)
+}
+
+// EventList godoc
+// @summary List tracker reconcile events.
+// @description List reconcile events across all trackers.
+// @description Filtered by ?from= and ?to= (RFC3339) and ?outcome=.
+// @description Paginated using ?limit= and ?offset=.
+// @tags trackers
+// @produce json
+// @success 200 {object} []api.TrackerEvent
+// @router /trackers/events [get]
+// @param from query string false "Events at or after (RFC3339)"
+// @param to query string false "Events before (RFC3339)"
+// @param outcome query string false "Outcome (Connected|Failed)"
+func (h TrackerHandler) EventList(ctx *gin.Context) {
+	db := h.DB(ctx)
+	outcome := ctx.Query(Outcome)
+	if outcome != "" {
+		db = db.Where("Outcome", outcome)
+	}
+	db = db.Order("Time, ID")
+	h.events(ctx, db)
+}
+
+// History godoc
+// @summary List a tracker's connection history.
+// @description List the reconcile events (connection transitions) for a tracker, newest first.
+// @description Filtered by ?from= and ?to= (RFC3339) and ?connected=.
+// @description Paginated using ?limit= and ?offset=.
+// @tags trackers
+// @produce json
+// @success 200 {object} []api.TrackerEvent
+// @router /trackers/{id}/history [get]
+// @param id path int true "Tracker ID"
+// @param from query string false "Events at or after (RFC3339)"
+// @param to query string false "Events before (RFC3339)"
+// @param connected query bool false "Connected"
+func (h TrackerHandler) History(ctx *gin.Context) {
+	id := h.pk(ctx)
+	m := &model.Tracker{}
+	err := h.DB(ctx).Select("ID").First(m, id).Error
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	db := h.DB(ctx)
+	db = db.Where("TrackerID", id)
+	q := ctx.Query(Connected)
+	if q != "" {
+		connected, err := strconv.ParseBool(q)
+		if err != nil {
+			err = &BadRequestError{Connected + ": " + err.Error()}
+			_ = ctx.Error(err)
+			return
+		}
+		if connected {
+			db = db.Where("Outcome", tracker.EventConnected)
+		} else {
+			db = db.Where("Outcome != ?", tracker.EventConnected)
+		}
+	}
+	db = db.Order("Time DESC, ID DESC")
+	h.events(ctx, db)
+}
+
+// events responds with the (paginated) events
+// filtered by the time range.
+func (h TrackerHandler) events(ctx *gin.Context, db *gorm.DB) {
+	db = db.Model(&model.TrackerEvent{})
+	for _, p := range []struct {
+		param string
+		where string
+	}{
+		{param: From, where: "Time >= ?"},
+		{param: To, where: "Time < ?"},
+	} {
+		s := ctx.Query(p.param)
+		if s == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			err = &BadRequestError{p.param + ": " + err.Error()}
+			_ = ctx.Error(err)
+			return
+		}
+		db = db.Where(p.where, t)
+	}
+	var list []model.TrackerEvent
+	var m model.TrackerEvent
+	page := Page{}
+	page.With(ctx)
+	cursor := Cursor{}
+	cursor.With(db, page)
+	defer func() {
+		cursor.Close()
+	}()
+	for cursor.Next(&m) {
+		if cursor.Error != nil {
+			_ = ctx.Error(cursor.Error)
+			return
+		}
+		list = append(list, m)
+	}
+	err := h.WithCount(ctx, cursor.Count())
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	resources := []TrackerEvent{}
+	for i := range list {
+		r := TrackerEvent{}
+		r.With(&list[i])
+		resources = append(resources, r)
+	}
+
+	h.Respond(ctx, http.StatusOK, resources)
+}
+
+// Test godoc
+// @summary Test the connection to a tracker step by step.
+// @description Test the connection to a tracker step by step: dns, tcp, tls, auth
+// @description and permission. Steps following a failed step are not run.
+// @description When stream=true, each step is sent (server-sent event `step`) as it
+// @description completes followed by the verdict (event `verdict`).
+// @description The tracker status is not updated.
+// @tags trackers
+// @produce json
+// @produce text/event-stream
+// @success 200 {object} api.TrackerTest
+// @router /trackers/{id}/test [get]
+// @param id path int true "Tracker ID"
+// @param stream query bool false "Stream the steps (SSE)"
+func (h TrackerHandler) Test(ctx *gin.Context) {
+	id := h.pk(ctx)
+	m := &model.Tracker{}
+	db := h.preLoad(h.DB(ctx), clause.Associations)
+	result := db.First(m, id)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	_, err := tracker.NewConnector(m)
+	if err != nil {
+		_ = ctx.Error(&TrackerError{err.Error()})
+		return
+	}
+	stream := ctx.Query(Stream) == "true"
+	r := TrackerTest{}
+	report := func(step tracker.ProbeStep) {
+		s := TrackerTestStep{}
+		s.With(&step)
+		r.Steps = append(r.Steps, s)
+		if stream {
+			ctx.SSEvent("step", s)
+			ctx.Writer.Flush()
+		}
+	}
+	r.Connected, err = tracker.Probe(m, report)
+	if err != nil && len(r.Steps) == 0 {
+		_ = ctx.Error(&TrackerError{err.Error()})
+		return
+	}
+	r.verdict()
+	if stream {
+		ctx.SSEvent("verdict", r)
+		ctx.Writer.Flush()
+		return
+	}
+	h.Respond(ctx, http.StatusOK, r)
+}
+
+// TestNow godoc
+// @summary Test the connection to a tracker.
+// @description Test the connection to a tracker immediately (rather than
+// @description on the next reconcile) and update the tracker status.
+// @description Returns the updated tracker.
+// @tags trackers
+// @produce json
+// @success 200 {object} api.Tracker
+// @router /trackers/{id}/test [post]
+// @param id path int true "Tracker ID"
+func (h TrackerHandler) TestNow(ctx *gin.Context) {
+	id := h.pk(ctx)
+	m := &model.Tracker{}
+	db := h.preLoad(h.DB(ctx), clause.Associations)
+	result := db.First(m, id)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	err := tracker.TestNow(h.DB(ctx), m)
 	if err != nil {
-		_ = ctx.Error(err)
+		_ = ctx.Error(&TrackerError{err.Error()})
 		return
 	}
-	m := r.Model()
-	m.CreateUser = h.BaseHandler.CurrentUser(ctx)
-	result := h.DB(ctx).Create(m)
+	m = &model.Tracker{}
+	result = db.First(m, id)
 	if result.Error != nil {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	r := Tracker{}
 	r.With(m)
-
-	h.Respond(ctx, http.StatusCreated, r)
+	h.Respond(ctx, http.StatusOK, r)
 }
 
-// Delete godoc
-// @summary Delete a tracker.
-// @description Delete a tracker.
+// WebhookTest godoc
+// @summary Test webhook delivery from a tracker.
+// @description Registers a temporary webhook on the remote tracker, asks the remote
+// @description to deliver a test event and waits for the hub to receive it.
+// @description Detects when the hub can reach the remote but the remote cannot
+// @description reach the hub. The webhook is deleted afterward.
 // @tags trackers
-// @success 204
-// @router /trackers/{id} [delete]
-// @param id path int true "Tracker id"
-func (h TrackerHandler) Delete(ctx *gin.Context) {
+// @produce json
+// @success 200 {object} api.WebhookTest
+// @router /trackers/{id}/test-webhook [post]
+// @param id path int true "Tracker ID"
+func (h TrackerHandler) WebhookTest(ctx *gin.Context) {
 	id := h.pk(ctx)
 	m := &model.Tracker{}
-	result := h.DB(ctx).First(m, id)
+	db := h.preLoad(h.DB(ctx), clause.Associations)
+	result := db.First(m, id)
 	if result.Error != nil {
 		_ = ctx.Error(result.Error)
 		return
 	}
-	result = h.DB(ctx).Delete(m)
-	if result.Error != nil {
-		_ = ctx.Error(result.Error)
+	hubURL := Settings.Hub.Tracker.Webhook.URL
+	if hubURL == "" {
+		err := &BadRequestError{"webhook: hub URL not configured."}
+		_ = ctx.Error(err)
+		return
+	}
+	conn, err := tracker.NewConnector(m)
+	if err != nil {
+		_ = ctx.Error(&TrackerError{err.Error()})
+		return
+	}
+	wConn, supported := conn.(tracker.WebhookConnector)
+	if !supported {
+		err = &NotImplemented{"webhooks not supported by the tracker."}
+		_ = ctx.Error(err)
+		return
+	}
+	timeout := time.Duration(Settings.Hub.Tracker.Webhook.Timeout) * time.Second
+	test, err := tracker.ProbeWebhook(wConn, hubURL, timeout)
+	if err != nil {
+		_ = ctx.Error(&TrackerError{err.Error()})
 		return
 	}
+	r := WebhookTest{}
+	r.With(&test)
+	h.Respond(ctx, http.StatusOK, r)
+}
 
+// WebhookReceived godoc
+// @summary Receive a webhook (test) probe.
+// @description Receive a webhook (test) probe delivered by the remote tracker.
+// @tags trackers
+// @success 204
+// @router /trackers/webhooks/{key} [post]
+// @param key path string true "Probe token"
+func (h TrackerHandler) WebhookReceived(ctx *gin.Context) {
+	if !tracker.Probes.Receive(ctx.Param(Key)) {
+		h.Status(ctx, http.StatusNotFound)
+		return
+	}
 	h.Status(ctx, http.StatusNoContent)
 }
 
-// Update godoc
-// @summary Update a tracker.
-// @description Update a tracker.
+// EventReceived godoc
+// @summary Receive an (issue) event delivered by a tracker.
+// @description Receive an (issue) event delivered to the webhook by the remote
+// @description tracker and update the referenced ticket immediately.
+// @description Authenticated using the secret (key or password) of the tracker
+// @description webhook identity: either the (sha256) HMAC signature of the body
+// @description reported in the X-Hub-Signature header or the ?secret= param.
+// @description Events not relevant or not referencing a ticket are ignored.
 // @tags trackers
 // @accept json
 // @success 204
-// @router /trackers/{id} [put]
-// @param id path int true "Tracker id"
-// @param application body api.Tracker true "Tracker data"
-func (h TrackerHandler) Update(ctx *gin.Context) {
+// @router /trackers/{id}/webhook [post]
+// @param id path int true "Tracker ID"
+// @param secret query string false "Shared secret"
+func (h TrackerHandler) EventReceived(ctx *gin.Context) {
 	id := h.pk(ctx)
-	r := &Tracker{}
-	err := h.Bind(ctx, r)
+	m := &model.Tracker{}
+	db := h.preLoad(h.DB(ctx), "Identity", "TunnelIdentity", "WebhookIdentity")
+	err := db.First(m, id).Error
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	m := r.Model()
-	m.ID = id
-	m.UpdateUser = h.BaseHandler.CurrentUser(ctx)
-	db := h.DB(ctx).Model(m)
-	db = db.Omit(clause.Associations)
-	result := db.Updates(h.fields(m))
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	verified := tracker.Verify(
+		tracker.WebhookSecret(m),
+		body,
+		ctx.GetHeader(tracker.SignatureHeader),
+		ctx.Query(Secret))
+	if !verified {
+		err = &Forbidden{"webhook: secret not matched."}
+		_ = ctx.Error(err)
+		return
+	}
+	conn, err := tracker.NewConnector(m)
+	if err != nil {
+		_ = ctx.Error(&TrackerError{err.Error()})
+		return
+	}
+	eConn, supported := conn.(tracker.EventConnector)
+	if !supported {
+		err = &NotImplemented{"events not supported by the tracker."}
+		_ = ctx.Error(err)
+		return
+	}
+	event, err := eConn.Event(body)
+	if err != nil {
+		_ = ctx.Error(&BadRequestError{err.Error()})
+		return
+	}
+	if event != nil {
+		_, err = tracker.ApplyEvent(h.DB(ctx), m, event)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+	h.Status(ctx, http.StatusNoContent)
+}
+
+// RateLimit godoc
+// @summary Get the rate-limit reported by a tracker.
+// @description Get the (latest) rate-limit reported by the remote tracker.
+// @tags trackers
+// @produce json
+// @success 200 {object} api.RateLimit
+// @router /trackers/{id}/ratelimit [get]
+// @param id path int true "Tracker ID"
+func (h TrackerHandler) RateLimit(ctx *gin.Context) {
+	id := h.pk(ctx)
+	m := &model.Tracker{}
+	result := h.DB(ctx).First(m, id)
 	if result.Error != nil {
 		_ = ctx.Error(result.Error)
 		return
 	}
+	rateLimit := tracker.RateLimit{}
+	rateLimit.Load(m)
+	r := RateLimit{}
+	r.With(&rateLimit)
+	h.Respond(ctx, http.StatusOK, r)
+}
 
-	h.Status(ctx, http.StatusNoContent)
+// Refresh godoc
+// @summary Refresh a tracker's projects and issue types.
+// @description Fetch the tracker's projects and their issue types from the remote
+// @description (rather than the cache). Used after projects are added to the remote.
+// @tags trackers
+// @produce json
+// @success 200 {object} []api.ProjectMetadata
+// @router /trackers/{id}/refresh [post]
+// @param id path int true "Tracker ID"
+func (h TrackerHandler) Refresh(ctx *gin.Context) {
+	id := h.pk(ctx)
+	m := &model.Tracker{}
+	db := h.preLoad(h.DB(ctx), clause.Associations)
+	result := db.First(m, id)
+	if result.Error != nil {
+		_ = ctx.Error(result.Error)
+		return
+	}
+	if !m.Connected {
+		_ = ctx.Error(&TrackerError{m.Message})
+		return
+	}
+	projects, err := tracker.RefreshProjects(m)
+	if err != nil {
+		_ = ctx.Error(&TrackerError{err.Error()})
+		return
+	}
+	resources := []ProjectMetadata{}
+	for i := range projects {
+		r := ProjectMetadata{}
+		r.With(&projects[i])
+		resources = append(resources, r)
+	}
+	h.Respond(ctx, http.StatusOK, resources)
 }
 
 // ProjectList godoc
 // @summary List a tracker's projects.
 // @description List a tracker's projects.
+// @description Paginated using ?limit= and ?offset= or ?cursor=.
+// @description The cursor for the next page is returned in the X-Cursor header.
+// @description The ?q= (name) search is pushed down to the remote when supported.
 // @tags trackers
 // @produce json
 // @success 200 {object} []api.Project
 // @router /trackers/{id}/projects [get]
 // @param id path int true "Tracker ID"
+// @param q query string false "Search"
+// @param limit query int false "Page limit"
+// @param offset query int false "Page offset"
+// @param cursor query string false "Page cursor"
 func (h TrackerHandler) ProjectList(ctx *gin.Context) {
 	id := h.pk(ctx)
 	m := &model.Tracker{}
@@ -210,23 +1130,39 @@ func (h TrackerHandler) ProjectList(ctx *gin.Context) {
 		_ = ctx.Error(&TrackerError{m.Message})
 		return
 	}
-	conn, err := tracker.NewConnector(m)
-	if err != nil {
-		_ = ctx.Error(&TrackerError{err.Error()})
-		return
+	page := Page{}
+	page.With(ctx)
+	cursor := ctx.Query(PageCursor)
+	if cursor != "" {
+		offset, err := strconv.Atoi(cursor)
+		if err != nil || offset < 0 {
+			_ = ctx.Error(&BadRequestError{"cursor: not valid."})
+			return
+		}
+		page.Offset = offset
 	}
-	projects, err := conn.Projects()
+	filter := tracker.ProjectFilter{
+		Query:  ctx.Query(Q),
+		Offset: page.Offset,
+		Limit:  page.Limit,
+	}
+	projects, err := tracker.SearchProjects(m, filter)
 	if err != nil {
 		_ = ctx.Error(&TrackerError{err.Error()})
 		return
 	}
 
 	resources := []Project{}
-	for i := range projects {
+	for i := range projects.Projects {
 		r := Project{}
-		r.With(&projects[i])
+		r.With(&projects.Projects[i])
 		resources = append(resources, r)
 	}
+	header := ctx.Writer.Header()
+	header.Set(Total, strconv.Itoa(projects.Total))
+	if projects.Next > 0 {
+		header.Set(NextCursor, strconv.Itoa(projects.Next))
+	}
 	h.Respond(ctx, http.StatusOK, resources)
 }
 
@@ -310,17 +1246,71 @@ func (h TrackerHandler) ProjectIssueTypeList(ctx *gin.Context) {
 	h.Respond(ctx, http.StatusOK, resources)
 }
 
+// ids returns the (repeatable) ?id= query parameter.
+func (h TrackerHandler) ids(ctx *gin.Context) (ids []uint, err error) {
+	for _, s := range ctx.QueryArray(ID) {
+		n, pErr := strconv.ParseUint(s, 10, 0)
+		if pErr != nil {
+			err = &BadRequestError{"id: must be an integer."}
+			return
+		}
+		ids = append(ids, uint(n))
+	}
+	return
+}
+
+// ordered returns the list in the order of the (requested) ids.
+// When ?strict=true, not-found is reported when any are missing.
+func (h TrackerHandler) ordered(ctx *gin.Context, ids []uint, in []model.Tracker) (out []model.Tracker, err error) {
+	strict, _ := strconv.ParseBool(ctx.Query(Strict))
+	found := make(map[uint]model.Tracker)
+	for _, m := range in {
+		found[m.ID] = m
+	}
+	for _, id := range ids {
+		m, matched := found[id]
+		if !matched {
+			if strict {
+				err = gorm.ErrRecordNotFound
+				return
+			}
+			continue
+		}
+		out = append(out, m)
+	}
+	return
+}
+
 // Tracker API Resource
 type Tracker struct {
-	Resource    `yaml:",inline"`
-	Name        string    `json:"name" binding:"required"`
-	URL         string    `json:"url" binding:"required"`
-	Kind        string    `json:"kind" binding:"required,oneof=jira-cloud jira-onprem"`
-	Message     string    `json:"message"`
-	Connected   bool      `json:"connected"`
-	LastUpdated time.Time `json:"lastUpdated" yaml:"lastUpdated"`
-	Identity    Ref       `json:"identity" binding:"required"`
-	Insecure    bool      `json:"insecure"`
+	Resource      `yaml:",inline"`
+	Name          string    `json:"name" binding:"required"`
+	URL           string    `json:"url" binding:"required"`
+	Kind          string    `json:"kind" binding:"required,oneof=jira-cloud jira-onprem github gitlab azure-devops servicenow"`
+	ExternalID    string    `json:"externalId,omitempty" yaml:"externalId,omitempty"`
+	Message       string    `json:"message"`
+	StatusReason  string    `json:"statusReason,omitempty" yaml:"statusReason,omitempty"`
+	ErrorCategory string    `json:"errorCategory,omitempty" yaml:"errorCategory,omitempty"`
+	Connected     bool      `json:"connected"`
+	LastUpdated   time.Time `json:"lastUpdated" yaml:"lastUpdated"`
+	// ConnectorVersion (read-only) of the connector that last tested the connection.
+	ConnectorVersion string `json:"connectorVersion,omitempty" yaml:"connectorVersion,omitempty"`
+	// Interval (seconds) between polls (min: 10). Zero(0) for the default.
+	Interval int `json:"interval,omitempty" yaml:"interval,omitempty" binding:"omitempty,min=10"`
+	// StatusMapping of remote ticket status to (hub) status.
+	StatusMapping  map[string]string `json:"statusMapping,omitempty" yaml:"statusMapping,omitempty" binding:"omitempty,dive,oneof=New 'In Progress' Done Unknown"`
+	Identity       Ref               `json:"identity" binding:"required" ref:"identity"`
+	TunnelIdentity *Ref              `json:"tunnelIdentity,omitempty" yaml:"tunnelIdentity,omitempty" ref:"identity"`
+	// WebhookIdentity (shared secret) used to authenticate delivered events.
+	WebhookIdentity *Ref     `json:"webhookIdentity,omitempty" yaml:"webhookIdentity,omitempty" ref:"identity"`
+	Insecure        bool     `json:"insecure"`
+	InsecureURL     bool     `json:"insecureURL,omitempty" yaml:"insecureURL,omitempty"`
+	Metadata        Metadata `json:"metadata"`
+	SchemaValid     bool     `json:"schemaValid"`
+	Schema          []string `json:"schemaErrors,omitempty" yaml:"schemaErrors,omitempty"`
+	Tags            []Ref    `json:"tags" ref:"tag"`
+	// Template (ticket) applied when creating issues.
+	Template *TicketTemplate `json:"template,omitempty" yaml:"template,omitempty"`
 }
 
 // With updates the resource with the model.
@@ -329,11 +1319,46 @@ func (r *Tracker) With(m *model.Tracker) {
 	r.Name = m.Name
 	r.URL = m.URL
 	r.Kind = m.Kind
+	r.ExternalID = m.ExternalID
 	r.Message = m.Message
+	r.StatusReason = m.StatusReason
+	r.ErrorCategory = m.ErrorCategory
 	r.Connected = m.Connected
 	r.LastUpdated = m.LastUpdated
+	r.ConnectorVersion = m.ConnectorVersion
+	r.Interval = m.Interval
+	_ = json.Unmarshal(m.StatusMapping, &r.StatusMapping)
 	r.Insecure = m.Insecure
+	r.InsecureURL = r.plainHTTP()
 	r.Identity = r.ref(m.IdentityID, m.Identity)
+	r.TunnelIdentity = r.refPtr(m.TunnelIdentityID, m.TunnelIdentity)
+	r.WebhookIdentity = r.refPtr(m.WebhookIdentityID, m.WebhookIdentity)
+	r.Tags = []Ref{}
+	for _, t := range m.Tags {
+		ref := Ref{}
+		ref.With(t.ID, t.Name)
+		r.Tags = append(r.Tags, ref)
+	}
+	r.Metadata = Metadata{}
+	_ = json.Unmarshal(m.Metadata, &r.Metadata)
+	r.Template = nil
+	if len(m.Template) > 0 {
+		r.Template = &TicketTemplate{}
+		r.Template.With(m.Template)
+	}
+	r.SchemaValid = true
+	r.Schema = nil
+	md := tracker.Metadata(r.Metadata)
+	err := md.Validate(m.Kind)
+	if err != nil {
+		r.SchemaValid = false
+		sErr := &tracker.SchemaError{}
+		if errors.As(err, &sErr) {
+			r.Schema = sErr.Reasons
+		} else {
+			r.Schema = []string{err.Error()}
+		}
+	}
 }
 
 // Model builds a model.
@@ -342,15 +1367,338 @@ func (r *Tracker) Model() (m *model.Tracker) {
 		Name:       r.Name,
 		URL:        r.URL,
 		Kind:       r.Kind,
+		ExternalID: r.ExternalID,
+		Interval:   r.Interval,
 		Insecure:   r.Insecure,
 		IdentityID: r.Identity.ID,
 	}
-
+	m.TunnelIdentityID = r.idPtr(r.TunnelIdentity)
+	m.WebhookIdentityID = r.idPtr(r.WebhookIdentity)
+	if r.Metadata == nil {
+		r.Metadata = Metadata{}
+	}
+	m.Metadata, _ = json.Marshal(r.Metadata)
+	if len(r.StatusMapping) > 0 {
+		m.StatusMapping, _ = json.Marshal(r.StatusMapping)
+	}
+	if r.Template != nil {
+		m.Template = r.Template.Model()
+	}
+	for _, ref := range r.Tags {
+		m.Tags = append(
+			m.Tags,
+			model.Tag{
+				Model: model.Model{
+					ID: ref.ID,
+				},
+			})
+	}
 	m.ID = r.ID
 
 	return
 }
 
+// Validate the resource.
+func (r *Tracker) Validate() (err error) {
+	err = r.validURL()
+	if err != nil {
+		return
+	}
+	md := tracker.Metadata(r.Metadata)
+	err = md.Validate(r.Kind)
+	if err != nil {
+		err = &BadRequestError{err.Error()}
+		return
+	}
+	tunnel := tracker.TunnelConfig{}
+	if tunnel.With(md) && r.TunnelIdentity == nil {
+		err = &BadRequestError{"tunnelIdentity: required when tunnel configured."}
+		return
+	}
+	if r.Template != nil {
+		err = r.Template.Validate()
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// withDefaults applies the configured default metadata
+// not specified when the kind is the default kind.
+func (r *Tracker) withDefaults() {
+	defaults := Settings.Hub.Tracker.Default
+	if r.Kind != defaults.Kind {
+		return
+	}
+	for k, v := range defaults.Metadata {
+		if _, found := r.Metadata[k]; found {
+			continue
+		}
+		if r.Metadata == nil {
+			r.Metadata = Metadata{}
+		}
+		r.Metadata[k] = v
+	}
+}
+
+// validURL validates the URL scheme.
+// Plain http is forbidden when TLS is required.
+func (r *Tracker) validURL() (err error) {
+	if r.plainHTTP() {
+		err = &Forbidden{"url: https required."}
+	}
+	return
+}
+
+// plainHTTP returns true when TLS is required for trackers
+// and the URL is not https.
+func (r *Tracker) plainHTTP() (b bool) {
+	if !Settings.Hub.Tracker.RequireTLS {
+		return
+	}
+	u, err := url.Parse(r.URL)
+	b = err != nil || u.Scheme != "https"
+	return
+}
+
+// Metadata tracker metadata.
+type Metadata map[string]interface{}
+
+// TrackerURL tracker URL change.
+type TrackerURL struct {
+	URL string `json:"url" binding:"required,url"`
+}
+
+// TrackerValidation REST resource.
+type TrackerValidation struct {
+	Valid        bool             `json:"valid"`
+	Binding      ValidationResult `json:"binding"`
+	Identity     ValidationResult `json:"identity"`
+	Metadata     ValidationResult `json:"metadata-schema" yaml:"metadata-schema"`
+	Connectivity ValidationResult `json:"connectivity"`
+}
+
+// ValidationResult reports the result of an individual validation.
+type ValidationResult struct {
+	Valid   bool   `json:"valid"`
+	Message string `json:"message,omitempty" yaml:",omitempty"`
+}
+
+// With updates the result with the error.
+func (r *ValidationResult) With(err error) {
+	r.Valid = err == nil
+	if err != nil {
+		r.Message = err.Error()
+	}
+}
+
+// TrackerTagging REST resource.
+// Tags to be added to and removed from the trackers
+// matched by the ID list or the filter.
+type TrackerTagging struct {
+	IDs    []uint         `json:"ids"`
+	Filter *TrackerFilter `json:"filter"`
+	Add    []Ref          `json:"add" ref:"tag"`
+	Remove []Ref          `json:"remove" ref:"tag"`
+}
+
+// tags builds tag models.
+func (r *TrackerTagging) tags(refs []Ref) (tags []model.Tag) {
+	for _, ref := range refs {
+		tags = append(
+			tags,
+			model.Tag{
+				Model: model.Model{
+					ID: ref.ID,
+				},
+			})
+	}
+	return
+}
+
+// TrackerFilter matches trackers.
+type TrackerFilter struct {
+	Kind          string `json:"kind"`
+	Connected     *bool  `json:"connected"`
+	ErrorCategory string `json:"errorCategory" yaml:"errorCategory"`
+}
+
+// TrackerMetrics writes tracker health in the OpenMetrics text format.
+type TrackerMetrics struct {
+	Writer io.Writer
+}
+
+// Write the metrics for the trackers.
+func (r *TrackerMetrics) Write(list []model.Tracker) {
+	r.family(
+		"konveyor_tracker_connected",
+		"Tracker connected (1) or not (0).")
+	for i := range list {
+		m := &list[i]
+		v := 0
+		if m.Connected {
+			v = 1
+		}
+		r.sample("konveyor_tracker_connected", r.labels(m), strconv.Itoa(v))
+	}
+	r.family(
+		"konveyor_tracker_last_updated_seconds",
+		"Time the tracker connection was last tested.")
+	for i := range list {
+		m := &list[i]
+		if m.LastUpdated.IsZero() {
+			continue
+		}
+		r.sample(
+			"konveyor_tracker_last_updated_seconds",
+			r.labels(m),
+			strconv.FormatInt(m.LastUpdated.Unix(), 10))
+	}
+	r.family(
+		"konveyor_tracker_error",
+		"Tracker connection error by category.")
+	for i := range list {
+		m := &list[i]
+		if m.ErrorCategory == "" {
+			continue
+		}
+		labels := r.labels(m) + ",category=\"" + r.escape(m.ErrorCategory) + "\""
+		r.sample("konveyor_tracker_error", labels, "1")
+	}
+	_, _ = io.WriteString(r.Writer, "# EOF\n")
+}
+
+// family writes the metric family header.
+func (r *TrackerMetrics) family(name, help string) {
+	_, _ = fmt.Fprintf(r.Writer, "# TYPE %s gauge\n# HELP %s %s\n", name, name, help)
+}
+
+// sample writes a sample.
+func (r *TrackerMetrics) sample(name, labels, value string) {
+	_, _ = fmt.Fprintf(r.Writer, "%s{%s} %s\n", name, labels, value)
+}
+
+// labels returns the tracker labels.
+func (r *TrackerMetrics) labels(m *model.Tracker) (s string) {
+	s = fmt.Sprintf(
+		"id=\"%d\",name=\"%s\",kind=\"%s\"",
+		m.ID,
+		r.escape(m.Name),
+		r.escape(m.Kind))
+	return
+}
+
+// escape a label value.
+func (r *TrackerMetrics) escape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "\"", "\\\"")
+	s = strings.ReplaceAll(s, "\n", "\\n")
+	return s
+}
+
+// TrackerTagged REST resource.
+type TrackerTagged struct {
+	Affected int `json:"affected"`
+}
+
+// TrackerEvent REST resource.
+type TrackerEvent struct {
+	ID       uint      `json:"id"`
+	Tracker  Ref       `json:"tracker"`
+	Time     time.Time `json:"time"`
+	Outcome  string    `json:"outcome"`
+	Category string    `json:"category,omitempty" yaml:",omitempty"`
+	Reason   string    `json:"reason,omitempty" yaml:",omitempty"`
+	Duration int64     `json:"duration"`
+}
+
+// With updates the resource with the model.
+func (r *TrackerEvent) With(m *model.TrackerEvent) {
+	r.ID = m.ID
+	r.Tracker = Ref{ID: m.TrackerID}
+	r.Time = m.Time
+	r.Outcome = m.Outcome
+	r.Category = m.Category
+	r.Reason = m.Reason
+	r.Duration = m.Duration
+}
+
+// RateLimit REST resource.
+type RateLimit struct {
+	Limit      int        `json:"limit"`
+	Remaining  *int       `json:"remaining,omitempty" yaml:",omitempty"`
+	Reset      *time.Time `json:"reset,omitempty" yaml:",omitempty"`
+	RetryAfter int        `json:"retryAfter" yaml:"retryAfter"`
+	Updated    *time.Time `json:"updated,omitempty" yaml:",omitempty"`
+	Throttled  bool       `json:"throttled"`
+}
+
+// With updates the resource with the rate-limit.
+func (r *RateLimit) With(m *tracker.RateLimit) {
+	r.Limit = m.Limit
+	r.Remaining = m.Remaining
+	r.Reset = m.Reset
+	r.RetryAfter = m.RetryAfter
+	if !m.Updated.IsZero() {
+		r.Updated = &m.Updated
+	}
+	r.Throttled = m.Throttled()
+}
+
+// TrackerTest REST resource.
+type TrackerTest struct {
+	Connected bool              `json:"connected"`
+	Category  string            `json:"category,omitempty" yaml:",omitempty"`
+	Reason    string            `json:"reason,omitempty" yaml:",omitempty"`
+	Steps     []TrackerTestStep `json:"steps"`
+}
+
+// verdict sets the category and reason of the failed step.
+func (r *TrackerTest) verdict() {
+	for _, step := range r.Steps {
+		if !step.Passed && !step.Skipped {
+			r.Category = step.Category
+			r.Reason = step.Step + ": " + step.Reason
+			break
+		}
+	}
+}
+
+// TrackerTestStep REST resource.
+type TrackerTestStep struct {
+	Step     string `json:"step"`
+	Passed   bool   `json:"passed"`
+	Skipped  bool   `json:"skipped,omitempty" yaml:",omitempty"`
+	Category string `json:"category,omitempty" yaml:",omitempty"`
+	Reason   string `json:"reason,omitempty" yaml:",omitempty"`
+	Duration int64  `json:"duration"` // milliseconds.
+}
+
+// With updates the resource with the step.
+func (r *TrackerTestStep) With(m *tracker.ProbeStep) {
+	r.Step = m.Step
+	r.Passed = m.Passed
+	r.Skipped = m.Skipped
+	r.Category = m.Category
+	r.Reason = m.Reason
+	r.Duration = m.Duration.Milliseconds()
+}
+
+// WebhookTest REST resource.
+type WebhookTest struct {
+	Delivered bool   `json:"delivered"`
+	Duration  int64  `json:"duration"` // milliseconds.
+	Reason    string `json:"reason,omitempty" yaml:",omitempty"`
+}
+
+// With updates the resource with the outcome.
+func (r *WebhookTest) With(m *tracker.WebhookTest) {
+	r.Delivered = m.Delivered
+	r.Duration = m.Duration.Milliseconds()
+	r.Reason = m.Reason
+}
+
 // Project API Resource
 type Project struct {
 	ID   string `json:"id"`
@@ -363,6 +1711,23 @@ func (r *Project) With(i *tracker.Project) {
 	r.Name = i.Name
 }
 
+// ProjectMetadata API Resource
+type ProjectMetadata struct {
+	Project    `yaml:",inline"`
+	IssueTypes []IssueType `json:"issueTypes"`
+}
+
+// With updates the resource with the model.
+func (r *ProjectMetadata) With(m *tracker.ProjectMetadata) {
+	r.Project.With(&m.Project)
+	r.IssueTypes = []IssueType{}
+	for i := range m.IssueTypes {
+		t := IssueType{}
+		t.With(&m.IssueTypes[i])
+		r.IssueTypes = append(r.IssueTypes, t)
+	}
+}
+
 // IssueType API Resource
 type IssueType struct {
 	ID   string `json:"id"`
@@ -0,0 +1,526 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/konveyor/tackle2-hub/tracker"
+	"github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+// newTracker creates a tracker (and identity).
+func newTracker(g *gomega.WithT, db *gorm.DB, name string) (m *model.Tracker) {
+	identity := &model.Identity{Name: name, Kind: tracker.BasicAuth}
+	g.Expect(db.Create(identity).Error).To(gomega.BeNil())
+	m = &model.Tracker{
+		Name:       name,
+		URL:        "https://" + name,
+		Kind:       tracker.JiraCloud,
+		IdentityID: identity.ID,
+	}
+	g.Expect(db.Create(m).Error).To(gomega.BeNil())
+	return
+}
+
+func TestTrackerTagUpdate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	m := newTracker(g, db, "jira")
+	category := &model.TagCategory{Name: "c"}
+	g.Expect(db.Create(category).Error).To(gomega.BeNil())
+	tag := &model.Tag{Name: "t", CategoryID: category.ID}
+	g.Expect(db.Create(tag).Error).To(gomega.BeNil())
+
+	h := TrackerHandler{}
+	e := newEngine(db)
+	e.POST(TrackerTagsRoot, Transaction, h.TagUpdate)
+	post := func(r TrackerTagging) (w *httptest.ResponseRecorder) {
+		b, _ := json.Marshal(r)
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, TrackerTagsRoot, bytes.NewBuffer(b))
+		e.ServeHTTP(w, req)
+		return
+	}
+
+	// Tag not found.
+	w := post(TrackerTagging{
+		IDs: []uint{m.ID},
+		Add: []Ref{{ID: tag.ID}, {ID: 99}},
+	})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusUnprocessableEntity))
+	body := struct {
+		Fields []struct {
+			Field string
+			Rule  string
+		}
+	}{}
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(gomega.BeNil())
+	g.Expect(len(body.Fields)).To(gomega.Equal(1))
+	g.Expect(body.Fields[0].Field).To(gomega.Equal("add.1.id"))
+	g.Expect(body.Fields[0].Rule).To(gomega.Equal("exists"))
+
+	// Added.
+	w = post(TrackerTagging{
+		IDs: []uint{m.ID},
+		Add: []Ref{{ID: tag.ID}},
+	})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	found := &model.Tracker{}
+	g.Expect(db.Preload("Tags").First(found, m.ID).Error).To(gomega.BeNil())
+	g.Expect(len(found.Tags)).To(gomega.Equal(1))
+
+	// Removed.
+	w = post(TrackerTagging{
+		IDs:    []uint{m.ID},
+		Remove: []Ref{{ID: tag.ID}},
+	})
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	found = &model.Tracker{}
+	g.Expect(db.Preload("Tags").First(found, m.ID).Error).To(gomega.BeNil())
+	g.Expect(len(found.Tags)).To(gomega.Equal(0))
+}
+
+func TestTrackerInsecureURL(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	required := Settings.Hub.Tracker.RequireTLS
+	defer func() {
+		Settings.Hub.Tracker.RequireTLS = required
+	}()
+	m := &model.Tracker{
+		URL:  "http://jira.example.com",
+		Kind: tracker.JiraCloud,
+	}
+
+	// Not required.
+	Settings.Hub.Tracker.RequireTLS = false
+	r := Tracker{}
+	r.With(m)
+	g.Expect(r.InsecureURL).To(gomega.BeFalse())
+	g.Expect(r.Validate()).To(gomega.BeNil())
+
+	// Required.
+	Settings.Hub.Tracker.RequireTLS = true
+	r = Tracker{}
+	r.With(m)
+	g.Expect(r.InsecureURL).To(gomega.BeTrue())
+	g.Expect(r.Insecure).To(gomega.BeFalse())
+	g.Expect(r.Model().Insecure).To(gomega.BeFalse())
+	err := r.Validate()
+	g.Expect(errors.Is(err, &Forbidden{})).To(gomega.BeTrue())
+
+	// Https.
+	m.URL = "https://jira.example.com"
+	r = Tracker{}
+	r.With(m)
+	g.Expect(r.InsecureURL).To(gomega.BeFalse())
+	g.Expect(r.Validate()).To(gomega.BeNil())
+}
+
+func TestTrackerUpdateRateLimit(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	m := newTracker(g, db, "jira")
+	remaining := 0
+	rateLimit := tracker.RateLimit{Limit: 10, Remaining: &remaining}
+	rateLimit.Store(m)
+	g.Expect(db.Save(m).Error).To(gomega.BeNil())
+
+	h := TrackerHandler{}
+	e := newEngine(db)
+	e.PUT(TrackerRoot, h.Update)
+
+	// Round-trip (GET => PUT).
+	found := &model.Tracker{}
+	g.Expect(db.Preload("Identity").First(found, m.ID).Error).To(gomega.BeNil())
+	r := Tracker{}
+	r.With(found)
+	b, _ := json.Marshal(r)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/trackers/1", bytes.NewBuffer(b))
+	e.ServeHTTP(w, req)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	updated := &model.Tracker{}
+	g.Expect(db.First(updated, m.ID).Error).To(gomega.BeNil())
+	loaded := tracker.RateLimit{}
+	g.Expect(loaded.Load(updated)).To(gomega.BeTrue())
+	g.Expect(loaded.Limit).To(gomega.Equal(10))
+}
+
+func TestTrackerValidate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	required := Settings.Hub.Tracker.RequireTLS
+	Settings.Hub.Tracker.RequireTLS = true
+	defer func() {
+		Settings.Hub.Tracker.RequireTLS = required
+	}()
+	db := newDB(t)
+	h := TrackerHandler{}
+	e := newEngine(db)
+	e.POST(TrackerValidateRoot, h.Validate)
+	post := func(r Tracker) (report TrackerValidation) {
+		b, _ := json.Marshal(r)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, TrackerValidateRoot, bytes.NewBuffer(b))
+		e.ServeHTTP(w, req)
+		g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+		g.Expect(json.Unmarshal(w.Body.Bytes(), &report)).To(gomega.BeNil())
+		return
+	}
+
+	// Plain http.
+	report := post(Tracker{
+		Name:     "jira",
+		URL:      "http://jira.example.com",
+		Kind:     tracker.JiraCloud,
+		Identity: Ref{ID: 1},
+	})
+	g.Expect(report.Valid).To(gomega.BeFalse())
+	g.Expect(report.Binding.Valid).To(gomega.BeFalse())
+	g.Expect(report.Binding.Message).To(gomega.ContainSubstring("https required"))
+	g.Expect(report.Metadata.Message).To(gomega.BeEmpty())
+
+	// Metadata not valid.
+	report = post(Tracker{
+		Name:     "jira",
+		URL:      "https://jira.example.com",
+		Kind:     tracker.JiraCloud,
+		Identity: Ref{ID: 1},
+		Metadata: Metadata{tracker.Tunnel: "bastion"},
+	})
+	g.Expect(report.Binding.Valid).To(gomega.BeTrue())
+	g.Expect(report.Metadata.Valid).To(gomega.BeFalse())
+	g.Expect(report.Connectivity.Message).To(gomega.Equal("skipped."))
+
+	// Interval below the minimum.
+	report = post(Tracker{
+		Name:     "jira",
+		URL:      "https://jira.example.com",
+		Kind:     tracker.JiraCloud,
+		Identity: Ref{ID: 1},
+		Interval: 5,
+	})
+	g.Expect(report.Binding.Valid).To(gomega.BeFalse())
+	g.Expect(report.Binding.Message).To(gomega.ContainSubstring("Interval"))
+
+	// Status mapped to an unknown status.
+	report = post(Tracker{
+		Name:          "jira",
+		URL:           "https://jira.example.com",
+		Kind:          tracker.JiraCloud,
+		Identity:      Ref{ID: 1},
+		StatusMapping: map[string]string{"Closed": tracker.InProgress, "Done": "Resolved"},
+	})
+	g.Expect(report.Binding.Valid).To(gomega.BeFalse())
+	g.Expect(report.Binding.Message).To(gomega.ContainSubstring("StatusMapping[Done]"))
+}
+
+func TestTrackerListFilter(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	newTracker(g, db, "a")
+	b := newTracker(g, db, "b")
+	g.Expect(db.Model(b).Update("Connected", true).Error).To(gomega.BeNil())
+	c := newTracker(g, db, "c")
+	g.Expect(db.Model(c).Update("Kind", tracker.JiraOnPrem).Error).To(gomega.BeNil())
+
+	h := TrackerHandler{}
+	e := newEngine(db)
+	e.GET(TrackersRoot, h.List)
+	cases := []struct {
+		filter string
+		sort   string
+		status int
+		names  []string
+	}{
+		{filter: "kind=jira-cloud;connected=false", status: http.StatusOK, names: []string{"a"}},
+		{filter: "connected=true|kind!=jira-cloud", status: http.StatusOK, names: []string{"b", "c"}},
+		{filter: "name~*,(name:a|name:c)", status: http.StatusOK, names: []string{"a", "c"}},
+		{filter: "name~*b*", status: http.StatusOK, names: []string{"b"}},
+		{filter: "identity.id=" + strconv.Itoa(int(b.IdentityID)), status: http.StatusOK, names: []string{"b"}},
+		{filter: "identity.name:(a|c)", sort: "D:name", status: http.StatusOK, names: []string{"c", "a"}},
+		{sort: "D:kind,name", status: http.StatusOK, names: []string{"c", "a", "b"}},
+		{filter: "password=x", status: http.StatusBadRequest},
+		{filter: "name=~a", status: http.StatusBadRequest},
+		{sort: "password", status: http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, TrackersRoot, nil)
+		q := req.URL.Query()
+		q.Set(Filter, c.filter)
+		if c.sort != "" {
+			q.Set("sort", c.sort)
+		}
+		req.URL.RawQuery = q.Encode()
+		e.ServeHTTP(w, req)
+		g.Expect(w.Code).To(gomega.Equal(c.status), c.filter)
+		if c.status != http.StatusOK {
+			continue
+		}
+		var list []Tracker
+		g.Expect(json.Unmarshal(w.Body.Bytes(), &list)).To(gomega.BeNil())
+		var names []string
+		for _, r := range list {
+			names = append(names, r.Name)
+		}
+		g.Expect(names).To(gomega.Equal(c.names), c.filter)
+	}
+	// CSV.
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, TrackersRoot+"?format=csv&columns=name,lastupdated", nil)
+	e.ServeHTTP(w, req)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(w.Header().Get(ContentType)).To(gomega.Equal(CSV))
+	g.Expect(strings.Split(w.Body.String(), "\n")[0]).To(gomega.Equal("name,lastUpdated"))
+}
+
+func TestTrackerMetrics(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	a := newTracker(g, db, "a")
+	g.Expect(db.Model(a).Updates(map[string]interface{}{
+		"Connected":     true,
+		"LastUpdated":   time.Unix(100, 0),
+		"ErrorCategory": "",
+	}).Error).To(gomega.BeNil())
+	b := newTracker(g, db, "b\"")
+	g.Expect(db.Model(b).Update("ErrorCategory", tracker.ErrorAuth).Error).To(gomega.BeNil())
+
+	h := TrackerHandler{}
+	e := newEngine(db)
+	e.GET(TrackerMetricsRoot, h.Metrics)
+	get := func(query string) (w *httptest.ResponseRecorder) {
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, TrackerMetricsRoot+query, nil)
+		e.ServeHTTP(w, req)
+		return
+	}
+
+	w := get("")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(w.Header().Get(ContentType)).To(gomega.Equal(OpenMetrics))
+	body := w.Body.String()
+	g.Expect(body).To(gomega.ContainSubstring(`konveyor_tracker_connected{id="1",name="a",kind="jira-cloud"} 1`))
+	g.Expect(body).To(gomega.ContainSubstring(`konveyor_tracker_connected{id="2",name="b\"",kind="jira-cloud"} 0`))
+	g.Expect(body).To(gomega.ContainSubstring(`konveyor_tracker_last_updated_seconds{id="1",name="a",kind="jira-cloud"} 100`))
+	g.Expect(body).To(gomega.ContainSubstring(`konveyor_tracker_error{id="2",name="b\"",kind="jira-cloud",category="AUTH"} 1`))
+	g.Expect(body).To(gomega.HaveSuffix("# EOF\n"))
+
+	// Filtered.
+	w = get("?filter=connected=true")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(w.Body.String()).ToNot(gomega.ContainSubstring(`id="2"`))
+
+	// Tenant.
+	w = get("?tenant=a")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNotImplemented))
+}
+
+func TestTrackerCreateDefaults(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	identity := &model.Identity{Name: "jira", Kind: tracker.BasicAuth}
+	g.Expect(db.Create(identity).Error).To(gomega.BeNil())
+	defaults := Settings.Hub.Tracker.Default
+	defer func() {
+		Settings.Hub.Tracker.Default = defaults
+	}()
+	Settings.Hub.Tracker.Default.Kind = tracker.JiraCloud
+	Settings.Hub.Tracker.Default.Metadata = map[string]interface{}{
+		tracker.HealthPath: "/health",
+	}
+
+	h := TrackerHandler{}
+	e := newEngine(db)
+	e.POST(TrackersRoot, h.Create)
+	post := func(body string) (r Tracker) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, TrackersRoot, bytes.NewBufferString(body))
+		e.ServeHTTP(w, req)
+		g.Expect(w.Code).To(gomega.Equal(http.StatusCreated), w.Body.String())
+		g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+		return
+	}
+
+	// Minimal.
+	r := post(`{"name":"a","url":"https://a","identity":{"id":1}}`)
+	g.Expect(r.Kind).To(gomega.Equal(tracker.JiraCloud))
+	g.Expect(r.Metadata[tracker.HealthPath]).To(gomega.Equal("/health"))
+
+	// Explicit metadata.
+	r = post(`{"name":"b","url":"https://b","identity":{"id":1},"metadata":{"healthPath":"/b"}}`)
+	g.Expect(r.Metadata[tracker.HealthPath]).To(gomega.Equal("/b"))
+
+	// Explicit kind.
+	r = post(`{"name":"c","url":"https://c","identity":{"id":1},"kind":"jira-onprem"}`)
+	g.Expect(r.Kind).To(gomega.Equal(tracker.JiraOnPrem))
+	g.Expect(r.Metadata).ToNot(gomega.HaveKey(tracker.HealthPath))
+}
+
+func TestTrackerCreateUnique(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	newTracker(g, db, "a")
+	unique := Settings.Hub.Tracker.Unique
+	defer func() {
+		Settings.Hub.Tracker.Unique = unique
+	}()
+
+	h := TrackerHandler{}
+	e := newEngine(db)
+	e.POST(TrackersRoot, h.Create)
+	post := func(body string) (code int) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, TrackersRoot, bytes.NewBufferString(body))
+		e.ServeHTTP(w, req)
+		code = w.Code
+		return
+	}
+	Settings.Hub.Tracker.Unique = tracker.UniqueName
+	body := `{"name":"a","url":"https://b","kind":"jira-cloud","identity":{"id":1}}`
+	g.Expect(post(body)).To(gomega.Equal(http.StatusConflict))
+	Settings.Hub.Tracker.Unique = tracker.UniqueURLKind
+	g.Expect(post(body)).To(gomega.Equal(http.StatusCreated))
+	body = `{"name":"c","url":"https://b","kind":"jira-cloud","identity":{"id":1}}`
+	g.Expect(post(body)).To(gomega.Equal(http.StatusConflict))
+}
+
+func TestTrackerTestStream(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	m := newTracker(g, db, "invalid.")
+
+	h := TrackerHandler{}
+	e := newEngine(db)
+	e.GET(TrackerTestRoot, h.Test)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/trackers/"+strconv.Itoa(int(m.ID))+"/test?stream=true", nil)
+	e.ServeHTTP(w, req)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(w.Header().Get("Content-Type")).To(gomega.Equal("text/event-stream"))
+	body := w.Body.String()
+	g.Expect(body).To(gomega.ContainSubstring("event:step\ndata:{\"step\":\"dns\",\"passed\":false"))
+	g.Expect(body).To(gomega.ContainSubstring("event:verdict\n"))
+	g.Expect(body).To(gomega.ContainSubstring("\"category\":\"NETWORK\""))
+}
+
+func TestTrackerTestNow(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	m := newTracker(g, db, "invalid.")
+	g.Expect(db.Model(m).Update("Connected", true).Error).To(gomega.BeNil())
+
+	h := TrackerHandler{}
+	e := newEngine(db)
+	e.POST(TrackerTestRoot, h.TestNow)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/trackers/"+strconv.Itoa(int(m.ID))+"/test", nil)
+	e.ServeHTTP(w, req)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK), w.Body.String())
+	r := Tracker{}
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &r)).To(gomega.BeNil())
+	g.Expect(r.Connected).To(gomega.BeFalse())
+	g.Expect(r.Message).ToNot(gomega.BeEmpty())
+	g.Expect(r.ErrorCategory).To(gomega.Equal(tracker.ErrorNetwork))
+}
+
+func TestTrackerDelete(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	m := newTracker(g, db, "jira")
+	app := &model.Application{Name: "a"}
+	g.Expect(db.Create(app).Error).To(gomega.BeNil())
+	ticket := &model.Ticket{Kind: "10", Parent: "1", ApplicationID: app.ID, TrackerID: m.ID}
+	g.Expect(db.Create(ticket).Error).To(gomega.BeNil())
+
+	h := TrackerHandler{}
+	e := newEngine(db)
+	e.DELETE(TrackerRoot, Transaction, h.Delete)
+	del := func(query string) (w *httptest.ResponseRecorder) {
+		w = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, "/trackers/"+strconv.Itoa(int(m.ID))+query, nil)
+		e.ServeHTTP(w, req)
+		return
+	}
+
+	// Blocked.
+	w := del("")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusConflict))
+	g.Expect(w.Body.String()).To(gomega.ContainSubstring("has tickets: " + strconv.Itoa(int(ticket.ID))))
+
+	// Forced.
+	w = del("?force=true")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	err := db.First(&model.Ticket{}, ticket.ID).Error
+	g.Expect(errors.Is(err, gorm.ErrRecordNotFound)).To(gomega.BeTrue())
+}
+
+func TestTrackerEventReceived(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	m := newTracker(g, db, "jira")
+	identity := &model.Identity{Name: "webhook", Kind: "source", Key: "secret"}
+	g.Expect(identity.Encrypt(&model.Identity{})).To(gomega.BeNil())
+	g.Expect(db.Create(identity).Error).To(gomega.BeNil())
+	g.Expect(db.Model(m).Update("WebhookIdentityID", identity.ID).Error).To(gomega.BeNil())
+	app := &model.Application{Name: "a"}
+	g.Expect(db.Create(app).Error).To(gomega.BeNil())
+	ticket := &model.Ticket{Kind: "10", Parent: "1", Reference: "MIG-1", ApplicationID: app.ID, TrackerID: m.ID}
+	g.Expect(db.Create(ticket).Error).To(gomega.BeNil())
+
+	h := TrackerHandler{}
+	e := newEngine(db)
+	e.POST(TrackerEventRoot, h.EventReceived)
+	post := func(body, signature, query string) (w *httptest.ResponseRecorder) {
+		w = httptest.NewRecorder()
+		path := "/trackers/" + strconv.Itoa(int(m.ID)) + "/webhook" + query
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		if signature != "" {
+			req.Header.Set(tracker.SignatureHeader, signature)
+		}
+		e.ServeHTTP(w, req)
+		return
+	}
+	sign := func(body, secret string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	updated := `{"webhookEvent":"jira:issue_updated","issue":{"key":"MIG-1","fields":{"status":{"name":"Review","statusCategory":{"key":"indeterminate"}}}}}`
+
+	// Not authenticated.
+	w := post(updated, sign(updated, "invalid"), "")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusForbidden))
+	w = post(updated, "", "?secret=invalid")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusForbidden))
+
+	// Signed.
+	w = post(updated, sign(updated, "secret"), "")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent), w.Body.String())
+	saved := &model.Ticket{}
+	g.Expect(db.First(saved, ticket.ID).Error).To(gomega.BeNil())
+	g.Expect(saved.Status).To(gomega.Equal(tracker.InProgress))
+	g.Expect(saved.RemoteStatus).To(gomega.Equal("Review"))
+
+	// Not relevant.
+	w = post(`{"webhookEvent":"comment_created"}`, "", "?secret=secret")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+
+	// Deleted.
+	w = post(`{"webhookEvent":"jira:issue_deleted","issue":{"key":"MIG-1"}}`, "", "?secret=secret")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusNoContent))
+	err := db.First(&model.Ticket{}, ticket.ID).Error
+	g.Expect(errors.Is(err, gorm.ErrRecordNotFound)).To(gomega.BeTrue())
+}
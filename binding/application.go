@@ -52,6 +52,43 @@ func (h *Application) Delete(id uint) (err error) {
 	return
 }
 
+// Archive an Application.
+func (h *Application) Archive(id uint) (err error) {
+	path := Path(api.AppArchiveRoot).Inject(Params{api.ID: id})
+	err = h.client.Put(path, nil)
+	return
+}
+
+// Restore an (archived) Application.
+func (h *Application) Restore(id uint) (err error) {
+	path := Path(api.AppRestoreRoot).Inject(Params{api.ID: id})
+	err = h.client.Put(path, nil)
+	return
+}
+
+// Copy the assessments, review and tags to other Applications.
+func (h *Application) Copy(id uint, r *api.AppCopy) (err error) {
+	path := Path(api.AppCopyRoot).Inject(Params{api.ID: id})
+	err = h.client.Post(path, r)
+	return
+}
+
+// Events returns the Application events (timeline).
+func (h *Application) Events(id uint) (list []api.AppEvent, err error) {
+	list = []api.AppEvent{}
+	path := Path(api.AppEventsRoot).Inject(Params{api.ID: id})
+	err = h.client.Get(path, &list)
+	return
+}
+
+// BinaryUpload uploads the Application binary (WAR|EAR|JAR).
+func (h *Application) BinaryUpload(id uint, source string) (r *api.AppBinary, err error) {
+	r = &api.AppBinary{}
+	path := Path(api.AppBinaryRoot).Inject(Params{api.ID: id})
+	err = h.client.FilePost(path, source, r)
+	return
+}
+
 // Bucket returns the bucket API.
 func (h *Application) Bucket(id uint) (b *BucketContent) {
 	params := Params{
@@ -66,6 +103,14 @@ func (h *Application) Bucket(id uint) (b *BucketContent) {
 	return
 }
 
+// Identities returns the Application identities (resolution).
+func (h *Application) Identities(id uint) (list []api.AppIdentity, err error) {
+	list = []api.AppIdentity{}
+	path := Path(api.AppIdentitiesRoot).Inject(Params{api.ID: id})
+	err = h.client.Get(path, &list)
+	return
+}
+
 // FindIdentity by kind.
 func (h *Application) FindIdentity(id uint, kind string) (r *api.Identity, found bool, err error) {
 	list := []api.Identity{}
@@ -228,16 +273,25 @@ func (h *AppFacts) Source(source string) {
 	h.source = source
 }
 
-// List facts.
+// List facts (in the source).
 func (h *AppFacts) List() (facts api.FactMap, err error) {
 	facts = api.FactMap{}
 	key := api.FactKey("")
 	key.Qualify(h.source)
-	path := Path(api.ApplicationFactsRoot).Inject(Params{api.ID: h.appId, api.Key: key})
+	path := Path(api.ApplicationFactRoot).Inject(Params{api.ID: h.appId, api.Key: key})
 	err = h.client.Get(path, &facts)
 	return
 }
 
+// All lists facts (all sources).
+// The keys are qualified by source.
+func (h *AppFacts) All() (facts api.FactMap, err error) {
+	facts = api.FactMap{}
+	path := Path(api.ApplicationFactsRoot).Inject(Params{api.ID: h.appId})
+	err = h.client.Get(path, &facts, Param{Key: api.QualifiedParam, Value: "true"})
+	return
+}
+
 // Get a fact.
 func (h *AppFacts) Get(name string, value interface{}) (err error) {
 	key := api.FactKey(name)
@@ -281,11 +335,19 @@ func (h *AppFacts) Delete(name string) (err error) {
 func (h *AppFacts) Replace(facts api.FactMap) (err error) {
 	key := api.FactKey("")
 	key.Qualify(h.source)
-	path := Path(api.ApplicationFactsRoot).Inject(Params{api.ID: h.appId, api.Key: key})
+	path := Path(api.ApplicationFactRoot).Inject(Params{api.ID: h.appId, api.Key: key})
 	err = h.client.Put(path, facts)
 	return
 }
 
+// Technologies returns the discovered technologies.
+func (h *Application) Technologies(id uint) (list []api.Technology, err error) {
+	list = []api.Technology{}
+	path := Path(api.AppTechnologiesRoot).Inject(Params{api.ID: id})
+	err = h.client.Get(path, &list)
+	return
+}
+
 // Analysis returns the analysis API.
 func (h *Application) Analysis(id uint) (a Analysis) {
 	a = Analysis{
@@ -343,3 +405,40 @@ func (h *Analysis) Create(r *api.Analysis, encoding string, issues, deps io.Read
 		r)
 	return
 }
+
+// Diff compares the (base and target) analyses.
+// Zero IDs select the defaults (latest and preceding).
+func (h *Analysis) Diff(base, target uint) (r *api.AnalysisDiff, err error) {
+	r = &api.AnalysisDiff{}
+	path := Path(api.AppAnalysesDiffRoot).Inject(Params{api.ID: h.appId})
+	params := []Param{}
+	if base > 0 {
+		params = append(params, Param{Key: api.BaseParam, Value: strconv.Itoa(int(base))})
+	}
+	if target > 0 {
+		params = append(params, Param{Key: api.TargetParam, Value: strconv.Itoa(int(target))})
+	}
+	err = h.client.Get(path, r, params...)
+	return
+}
+
+// Sarif returns the latest analysis as a SARIF log.
+// The (optional) root is removed from the file paths.
+func (h *Analysis) Sarif(root string) (r *api.SarifLog, err error) {
+	r = &api.SarifLog{}
+	path := Path(api.AppAnalysisSarifRoot).Inject(Params{api.ID: h.appId})
+	params := []Param{}
+	if root != "" {
+		params = append(params, Param{Key: api.RootParam, Value: root})
+	}
+	err = h.client.Get(path, r, params...)
+	return
+}
+
+// Trend returns the analysis trend.
+func (h *Analysis) Trend() (list []api.AnalysisTrend, err error) {
+	list = []api.AnalysisTrend{}
+	path := Path(api.AppAnalysesTrendRoot).Inject(Params{api.ID: h.appId})
+	err = h.client.Get(path, &list)
+	return
+}
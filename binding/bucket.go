@@ -2,6 +2,7 @@ package binding
 
 import (
 	pathlib "path"
+	"strconv"
 
 	"github.com/konveyor/tackle2-hub/api"
 )
@@ -52,6 +53,20 @@ func (h *Bucket) Content(id uint) (b *BucketContent) {
 	return
 }
 
+// Archive returns the archive API.
+func (h *Bucket) Archive(id uint) (b *BucketArchive) {
+	params := Params{
+		api.Wildcard: "",
+		api.ID:       id,
+	}
+	path := Path(api.BucketRoot).Inject(params)
+	b = &BucketArchive{
+		root:   path,
+		client: h.client,
+	}
+	return
+}
+
 // BucketContent API.
 type BucketContent struct {
 	client *Client
@@ -65,6 +80,20 @@ func (h *BucketContent) Get(source, destination string) (err error) {
 	return
 }
 
+// List the directory entries.
+// The path is relative to the bucket root.
+func (h *BucketContent) List(path string, recursive bool) (list []api.BucketEntry, err error) {
+	list = []api.BucketEntry{}
+	err = h.client.Get(
+		pathlib.Join(h.root, path)+"/",
+		&list,
+		Param{
+			Key:   api.RecursiveParam,
+			Value: strconv.FormatBool(recursive),
+		})
+	return
+}
+
 // Put writes to the bucket.
 // The destination (root) is relative to the bucket root.
 func (h *BucketContent) Put(source, destination string) (err error) {
@@ -78,3 +107,35 @@ func (h *BucketContent) Delete(path string) (err error) {
 	err = h.client.Delete(pathlib.Join(h.root, path))
 	return
 }
+
+// BucketArchive API.
+type BucketArchive struct {
+	client *Client
+	root   string
+}
+
+// Get downloads the directory (subtree) and expands it into the destination.
+// The path is relative to the bucket root.
+func (h *BucketArchive) Get(path, destination string) (err error) {
+	err = h.client.BucketGet(
+		pathlib.Join(h.root, path),
+		destination,
+		Param{
+			Key:   api.ArchiveParam,
+			Value: "true",
+		})
+	return
+}
+
+// Put uploads the source directory which replaces the directory (subtree).
+// The path is relative to the bucket root.
+func (h *BucketArchive) Put(source, path string) (err error) {
+	err = h.client.BucketPut(
+		source,
+		pathlib.Join(h.root, path),
+		Param{
+			Key:   api.ArchiveParam,
+			Value: "true",
+		})
+	return
+}
@@ -282,7 +282,7 @@ func (r *Client) Delete(path string, params ...Param) (err error) {
 
 // BucketGet downloads a file/directory.
 // The source (path) is relative to the bucket root.
-func (r *Client) BucketGet(source, destination string) (err error) {
+func (r *Client) BucketGet(source, destination string, params ...Param) (err error) {
 	request := func() (request *http.Request, err error) {
 		request = &http.Request{
 			Header: http.Header{},
@@ -290,6 +290,13 @@ func (r *Client) BucketGet(source, destination string) (err error) {
 			URL:    r.join(source),
 		}
 		request.Header.Set(api.Accept, api.MIMEOCTETSTREAM)
+		if len(params) > 0 {
+			q := request.URL.Query()
+			for _, p := range params {
+				q.Add(p.Key, p.Value)
+			}
+			request.URL.RawQuery = q.Encode()
+		}
 		return
 	}
 	response, err := r.send(request)
@@ -317,7 +324,7 @@ func (r *Client) BucketGet(source, destination string) (err error) {
 
 // BucketPut uploads a file/directory.
 // The destination (path) is relative to the bucket root.
-func (r *Client) BucketPut(source, destination string) (err error) {
+func (r *Client) BucketPut(source, destination string, params ...Param) (err error) {
 	isDir, err := r.IsDir(source, true)
 	if err != nil {
 		return
@@ -336,6 +343,13 @@ func (r *Client) BucketPut(source, destination string) (err error) {
 		if isDir {
 			request.Header.Set(api.Directory, api.DirectoryExpand)
 		}
+		if len(params) > 0 {
+			q := request.URL.Query()
+			for _, p := range params {
+				q.Add(p.Key, p.Value)
+			}
+			request.URL.RawQuery = q.Encode()
+		}
 		go func() {
 			var err error
 			defer func() {
@@ -702,6 +716,9 @@ func (r *Client) buildTransport() (err error) {
 func (r *Client) join(path string) (parsedURL *url.URL) {
 	parsedURL, _ = url.Parse(r.baseURL)
 	parsedURL.Path = pathlib.Join(parsedURL.Path, path)
+	if strings.HasSuffix(path, "/") {
+		parsedURL.Path += "/"
+	}
 	return
 }
 
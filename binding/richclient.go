@@ -38,10 +38,12 @@ type RichClient struct {
 	Setting          Setting
 	Stakeholder      Stakeholder
 	StakeholderGroup StakeholderGroup
+	Suppression      Suppression
 	Tag              Tag
 	TagCategory      TagCategory
 	Target           Target
 	Task             Task
+	Technology       Technology
 	Ticket           Ticket
 	Tracker          Tracker
 
@@ -109,6 +111,9 @@ func New(baseUrl string) (r *RichClient) {
 		StakeholderGroup: StakeholderGroup{
 			client: client,
 		},
+		Suppression: Suppression{
+			client: client,
+		},
 		Tag: Tag{
 			client: client,
 		},
@@ -121,6 +126,9 @@ func New(baseUrl string) (r *RichClient) {
 		Task: Task{
 			client: client,
 		},
+		Technology: Technology{
+			client: client,
+		},
 		Ticket: Ticket{
 			client: client,
 		},
@@ -0,0 +1,44 @@
+package binding
+
+import (
+	"github.com/konveyor/tackle2-hub/api"
+)
+
+// Suppression (issue) API.
+type Suppression struct {
+	client *Client
+}
+
+// Create a Suppression.
+func (h *Suppression) Create(r *api.Suppression) (err error) {
+	err = h.client.Post(api.SuppressionsRoot, &r)
+	return
+}
+
+// Get a Suppression by ID.
+func (h *Suppression) Get(id uint) (r *api.Suppression, err error) {
+	r = &api.Suppression{}
+	path := Path(api.SuppressionRoot).Inject(Params{api.ID: id})
+	err = h.client.Get(path, r)
+	return
+}
+
+// List Suppressions.
+func (h *Suppression) List() (list []api.Suppression, err error) {
+	list = []api.Suppression{}
+	err = h.client.Get(api.SuppressionsRoot, &list)
+	return
+}
+
+// Update a Suppression.
+func (h *Suppression) Update(r *api.Suppression) (err error) {
+	path := Path(api.SuppressionRoot).Inject(Params{api.ID: r.ID})
+	err = h.client.Put(path, r)
+	return
+}
+
+// Delete a Suppression.
+func (h *Suppression) Delete(id uint) (err error) {
+	err = h.client.Delete(Path(api.SuppressionRoot).Inject(Params{api.ID: id}))
+	return
+}
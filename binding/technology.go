@@ -0,0 +1,17 @@
+package binding
+
+import (
+	"github.com/konveyor/tackle2-hub/api"
+)
+
+// Technology API.
+type Technology struct {
+	client *Client
+}
+
+// List (portfolio) technologies.
+func (h *Technology) List() (list []api.TechnologyReport, err error) {
+	list = []api.TechnologyReport{}
+	err = h.client.Get(api.TechnologiesRoot, &list)
+	return
+}
@@ -1,6 +1,9 @@
 package binding
 
 import (
+	"encoding/json"
+
+	liberr "github.com/jortel/go-utils/error"
 	"github.com/konveyor/tackle2-hub/api"
 )
 
@@ -63,3 +66,40 @@ func (h *Tracker) ListProjectIssueTypes(id1 uint, id2 uint) (issueType []api.Iss
 	err = h.client.Get(Path(api.TrackerProjectIssueTypes).Inject(Params{api.ID: id1, api.ID2: id2}), &issueType)
 	return
 }
+
+// Validate a Tracker without creating it.
+func (h *Tracker) Validate(r *api.Tracker) (report api.TrackerValidation, err error) {
+	report = api.TrackerValidation{}
+	b, err := json.Marshal(r)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	raw := json.RawMessage(b)
+	err = h.client.Post(api.TrackerValidateRoot, &raw)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(raw, &report)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	return
+}
+
+// RateLimit returns the rate-limit reported by the remote.
+func (h *Tracker) RateLimit(id uint) (r api.RateLimit, err error) {
+	r = api.RateLimit{}
+	path := Path(api.TrackerRateLimitRoot).Inject(Params{api.ID: id})
+	err = h.client.Get(path, &r)
+	return
+}
+
+// History returns the connection history.
+func (h *Tracker) History(id uint) (list []api.TrackerEvent, err error) {
+	list = []api.TrackerEvent{}
+	path := Path(api.TrackerHistoryRoot).Inject(Params{api.ID: id})
+	err = h.client.Get(path, &list)
+	return
+}
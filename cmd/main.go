@@ -50,6 +50,10 @@ func Setup() (db *gorm.DB, err error) {
 	if err != nil {
 		return
 	}
+	err = tracker.ApplyUnique(db, Settings.Hub.Tracker.Unique)
+	if err != nil {
+		return
+	}
 	return
 }
 
@@ -200,6 +204,7 @@ func main() {
 			rtx.DB = db
 			rtx.Client = client
 		})
+	router.Use(api.Timeout)
 	for _, h := range api.All() {
 		h.AddRoutes(router)
 	}
@@ -157,6 +157,22 @@ func (m *Manager) createApplication(imp *model.Import) (ok bool) {
 		}
 	}
 
+	// Enforce the uniqueness policy.
+	policy := api.Settings.Hub.Application.Unique
+	conflict, err := api.AppConflict(m.DB, policy, app)
+	if err != nil {
+		imp.ErrorMessage = err.Error()
+		return
+	}
+	if conflict != nil {
+		imp.ErrorMessage = fmt.Sprintf(
+			"Application conflicts with '%s' (id: %d, unique: %s).",
+			conflict.Name,
+			conflict.ID,
+			policy)
+		return
+	}
+
 	// Assign Business Service
 	businessService := &model.BusinessService{}
 	businessServices := []model.BusinessService{}
@@ -31,6 +31,10 @@ type AddonSpec struct {
 	ImagePullPolicy core.PullPolicy `json:"imagePullPolicy,omitempty"`
 	// Resource requirements.
 	Resources core.ResourceRequirements `json:"resources,omitempty"`
+	// NodeSelector an optional task pod node selector.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations optional task pod tolerations.
+	Tolerations []core.Toleration `json:"tolerations,omitempty"`
 }
 
 // AddonStatus defines the observed state of Addon
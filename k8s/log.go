@@ -0,0 +1,42 @@
+package k8s
+
+import (
+	"context"
+	"io"
+
+	liberr "github.com/jortel/go-utils/error"
+	core "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// PodLog returns a reader for the log of a pod container.
+// The log is streamed (followed) until the container terminates
+// or the context is canceled.
+func PodLog(ctx context.Context, namespace, name, container string, follow bool) (reader io.ReadCloser, err error) {
+	if Settings.Disconnected {
+		err = liberr.New("Hub is disconnected.")
+		return
+	}
+	cfg, err := config.GetConfig()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	request := clientset.CoreV1().Pods(namespace).GetLogs(
+		name,
+		&core.PodLogOptions{
+			Container: container,
+			Follow:    follow,
+		})
+	reader, err = request.Stream(ctx)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
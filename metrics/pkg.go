@@ -22,4 +22,16 @@ var (
 		Name: "konveyor_issues_exported_total",
 		Help: "The total number of issues exported to external trackers",
 	})
+	TrackerQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "konveyor_tracker_reconcile_queue_depth",
+		Help: "The current number of tracker reconcile items due and not yet processed",
+	})
+	TrackerWorkersActive = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "konveyor_tracker_reconcile_workers_active",
+		Help: "The current number of tracker reconcile workers processing an item",
+	})
+	TrackerQueueWait = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "konveyor_tracker_reconcile_wait_seconds",
+		Help: "The average time tracker reconcile items waited after becoming due (last pass)",
+	})
 )
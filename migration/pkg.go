@@ -5,6 +5,7 @@ import (
 	v10 "github.com/konveyor/tackle2-hub/migration/v10"
 	v11 "github.com/konveyor/tackle2-hub/migration/v11"
 	v12 "github.com/konveyor/tackle2-hub/migration/v12"
+	v13 "github.com/konveyor/tackle2-hub/migration/v13"
 	v2 "github.com/konveyor/tackle2-hub/migration/v2"
 	v3 "github.com/konveyor/tackle2-hub/migration/v3"
 	v4 "github.com/konveyor/tackle2-hub/migration/v4"
@@ -52,5 +53,6 @@ func All() []Migration {
 		v10.Migration{},
 		v11.Migration{},
 		v12.Migration{},
+		v13.Migration{},
 	}
 }
@@ -0,0 +1,93 @@
+package v13
+
+import (
+	"strings"
+
+	liberr "github.com/jortel/go-utils/error"
+	"github.com/jortel/go-utils/logr"
+	"github.com/konveyor/tackle2-hub/migration/v13/model"
+	"gorm.io/gorm"
+)
+
+var log = logr.WithName("migration|v13")
+
+type Migration struct{}
+
+func (r Migration) Apply(db *gorm.DB) (err error) {
+	err = db.AutoMigrate(r.Models()...)
+	if err != nil {
+		return
+	}
+	err = r.trackerName(db)
+	if err != nil {
+		return
+	}
+	err = r.updateTime(db)
+	return
+}
+
+// trackerName drops the unique constraint on the tracker name.
+// Uniqueness is applied by (deployment) policy.
+// The table is copied and rebuilt (not renamed) so the references
+// by other tables are preserved.
+func (r Migration) trackerName(db *gorm.DB) (err error) {
+	m := &model.Tracker{}
+	stmt := &gorm.Statement{DB: db}
+	err = stmt.Parse(m)
+	if err != nil {
+		return
+	}
+	columns := strings.Join(stmt.Schema.DBNames, ",")
+	err = db.Exec("CREATE TABLE Tracker__old AS SELECT * FROM Tracker").Error
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = db.Migrator().DropTable(m)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = db.Migrator().CreateTable(m)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = db.Exec(
+		"INSERT INTO Tracker (" + columns + ") SELECT " + columns + " FROM Tracker__old").Error
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = db.Migrator().DropTable("Tracker__old")
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	return
+}
+
+// updateTime initializes the (new) UpdateTime using the CreateTime.
+func (r Migration) updateTime(db *gorm.DB) (err error) {
+	for _, m := range r.Models() {
+		if !db.Migrator().HasColumn(m, "UpdateTime") {
+			continue
+		}
+		stmt := &gorm.Statement{DB: db}
+		err = stmt.Parse(m)
+		if err != nil {
+			return
+		}
+		err = db.Table(stmt.Schema.Table).
+			Where("UpdateTime IS NULL").
+			UpdateColumn("UpdateTime", gorm.Expr("CreateTime")).Error
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (r Migration) Models() []interface{} {
+	return model.All()
+}
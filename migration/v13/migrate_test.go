@@ -0,0 +1,70 @@
+package v13
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/database"
+	v12 "github.com/konveyor/tackle2-hub/migration/v12/model"
+	"github.com/konveyor/tackle2-hub/migration/v13/model"
+	"github.com/onsi/gomega"
+)
+
+func TestUpdateTime(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	database.Settings.DB.Path = path.Join(t.TempDir(), "hub.db")
+	db, err := database.Open(false)
+	g.Expect(err).To(gomega.BeNil())
+	err = db.AutoMigrate(v12.All()...)
+	g.Expect(err).To(gomega.BeNil())
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	category := &v12.TagCategory{Name: "c"}
+	category.CreateTime = created
+	g.Expect(db.Create(category).Error).To(gomega.BeNil())
+
+	err = Migration{}.Apply(db)
+	g.Expect(err).To(gomega.BeNil())
+	found := &model.TagCategory{}
+	g.Expect(db.First(found, category.ID).Error).To(gomega.BeNil())
+	g.Expect(found.UpdateTime.Equal(created)).To(gomega.BeTrue())
+
+	// Updated rows are not re-initialized.
+	g.Expect(db.Model(found).Update("Name", "u").Error).To(gomega.BeNil())
+	g.Expect(db.First(found, category.ID).Error).To(gomega.BeNil())
+	updated := found.UpdateTime
+	g.Expect(updated.After(created)).To(gomega.BeTrue())
+	// Reopened as by the migrator (prepared statements).
+	g.Expect(database.Close(db)).To(gomega.BeNil())
+	db, err = database.Open(false)
+	g.Expect(err).To(gomega.BeNil())
+	defer func() {
+		_ = database.Close(db)
+	}()
+	err = Migration{}.Apply(db)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(db.First(found, category.ID).Error).To(gomega.BeNil())
+	g.Expect(found.UpdateTime.Equal(updated)).To(gomega.BeTrue())
+}
+
+func TestTrackerName(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	database.Settings.DB.Path = path.Join(t.TempDir(), "hub.db")
+	db, err := database.Open(false)
+	g.Expect(err).To(gomega.BeNil())
+	defer func() {
+		_ = database.Close(db)
+	}()
+	err = db.AutoMigrate(v12.All()...)
+	g.Expect(err).To(gomega.BeNil())
+	tracker := &v12.Tracker{Name: "jira", URL: "https://jira"}
+	g.Expect(db.Create(tracker).Error).To(gomega.BeNil())
+
+	err = Migration{}.Apply(db)
+	g.Expect(err).To(gomega.BeNil())
+	found := &model.Tracker{}
+	g.Expect(db.First(found, tracker.ID).Error).To(gomega.BeNil())
+	g.Expect(found.URL).To(gomega.Equal(tracker.URL))
+	// Duplicate name permitted (by the schema).
+	g.Expect(db.Create(&model.Tracker{Name: "jira"}).Error).To(gomega.BeNil())
+}
@@ -0,0 +1,443 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	liberr "github.com/jortel/go-utils/error"
+	"github.com/konveyor/tackle2-hub/encryption"
+	"gorm.io/gorm"
+)
+
+// Model Base model.
+type Model struct {
+	ID         uint      `gorm:"<-:create;primaryKey"`
+	CreateTime time.Time `gorm:"<-:create;autoCreateTime"`
+	CreateUser string    `gorm:"<-:create"`
+	UpdateUser string
+	UpdateTime time.Time `gorm:"autoUpdateTime"`
+}
+
+type Setting struct {
+	Model
+	Key   string `gorm:"<-:create;uniqueIndex"`
+	Value JSON   `gorm:"type:json"`
+}
+
+// With updates the value of the Setting with the json representation
+// of the `value` parameter.
+func (r *Setting) With(value interface{}) (err error) {
+	r.Value, err = json.Marshal(value)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// As unmarshalls the value of the Setting into the `ptr` parameter.
+func (r *Setting) As(ptr interface{}) (err error) {
+	err = json.Unmarshal(r.Value, ptr)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+type Bucket struct {
+	Model
+	Path       string `gorm:"<-:create;uniqueIndex"`
+	Expiration *time.Time
+}
+
+func (m *Bucket) BeforeCreate(db *gorm.DB) (err error) {
+	if m.Path == "" {
+		uid := uuid.New()
+		m.Path = path.Join(
+			Settings.Hub.Bucket.Path,
+			uid.String())
+		err = os.MkdirAll(m.Path, 0777)
+		if err != nil {
+			err = liberr.Wrap(
+				err,
+				"path",
+				m.Path)
+		}
+	}
+	return
+}
+
+type BucketOwner struct {
+	BucketID *uint `gorm:"index" ref:"bucket"`
+	Bucket   *Bucket
+}
+
+func (m *BucketOwner) BeforeCreate(db *gorm.DB) (err error) {
+	if !m.HasBucket() {
+		b := &Bucket{}
+		err = db.Create(b).Error
+		m.SetBucket(&b.ID)
+	}
+	return
+}
+
+func (m *BucketOwner) SetBucket(id *uint) {
+	m.BucketID = id
+	m.Bucket = nil
+}
+
+func (m *BucketOwner) HasBucket() (b bool) {
+	return m.BucketID != nil
+}
+
+type File struct {
+	Model
+	Name       string
+	Path       string `gorm:"<-:create;uniqueIndex"`
+	Expiration *time.Time
+}
+
+func (m *File) BeforeCreate(db *gorm.DB) (err error) {
+	uid := uuid.New()
+	m.Path = path.Join(
+		Settings.Hub.Bucket.Path,
+		".file",
+		uid.String())
+	err = os.MkdirAll(path.Dir(m.Path), 0777)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"path",
+			m.Path)
+	}
+	return
+}
+
+type Task struct {
+	Model
+	BucketOwner
+	Name          string `gorm:"index"`
+	Addon         string `gorm:"index"`
+	Locator       string `gorm:"index"`
+	Priority      int
+	Image         string
+	Variant       string
+	Policy        string
+	TTL           JSON
+	Data          JSON
+	Started       *time.Time
+	Terminated    *time.Time
+	State         string `gorm:"index"`
+	Errors        JSON
+	Pod           string `gorm:"index"`
+	PodStatus     JSON
+	Retries       int
+	BackoffLimit  *int
+	Timeout       int
+	Events        JSON
+	Canceled      bool
+	DependsOn     JSON
+	Resources     JSON
+	NodeSelector  JSON
+	Tolerations   JSON
+	Report        *TaskReport `gorm:"constraint:OnDelete:CASCADE"`
+	ApplicationID *uint
+	Application   *Application
+	TaskGroupID   *uint `gorm:"<-:create"`
+	TaskGroup     *TaskGroup
+	ScheduleID    *uint         `gorm:"<-:create;index"`
+	Schedule      *TaskSchedule `gorm:"constraint:OnDelete:SET NULL"`
+}
+
+func (m *Task) Reset() {
+	m.Started = nil
+	m.Terminated = nil
+	m.Report = nil
+	m.Errors = nil
+	m.PodStatus = nil
+}
+
+func (m *Task) BeforeCreate(db *gorm.DB) (err error) {
+	err = m.BucketOwner.BeforeCreate(db)
+	m.Reset()
+	m.Events = nil
+	m.Event("Created", "")
+	return
+}
+
+// Error appends an error.
+func (m *Task) Error(severity, description string, x ...interface{}) {
+	var list []TaskError
+	description = fmt.Sprintf(description, x...)
+	te := TaskError{Severity: severity, Description: description}
+	_ = json.Unmarshal(m.Errors, &list)
+	list = append(list, te)
+	m.Errors, _ = json.Marshal(list)
+}
+
+// Event appends an event.
+// An event repeating the last event is ignored.
+func (m *Task) Event(kind, reason string, x ...interface{}) {
+	var list []TaskEvent
+	reason = fmt.Sprintf(reason, x...)
+	event := TaskEvent{Kind: kind, Reason: reason, Time: time.Now()}
+	_ = json.Unmarshal(m.Events, &list)
+	if n := len(list); n > 0 {
+		last := list[n-1]
+		if last.Kind == kind && last.Reason == reason {
+			return
+		}
+	}
+	list = append(list, event)
+	m.Events, _ = json.Marshal(list)
+}
+
+// Map alias.
+type Map = map[string]interface{}
+
+// TTL time-to-live.
+type TTL struct {
+	Created   int `json:"created,omitempty"`
+	Pending   int `json:"pending,omitempty"`
+	Postponed int `json:"postponed,omitempty"`
+	Running   int `json:"running,omitempty"`
+	Succeeded int `json:"succeeded,omitempty"`
+	Failed    int `json:"failed,omitempty"`
+}
+
+// TaskError used in Task.Errors.
+type TaskError struct {
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+}
+
+// TaskEvent used in Task.Events.
+type TaskEvent struct {
+	Kind   string    `json:"kind"`
+	Reason string    `json:"reason,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// TaskPodStatus used in Task.PodStatus.
+type TaskPodStatus struct {
+	Phase      string                `json:"phase,omitempty"`
+	Message    string                `json:"message,omitempty"`
+	Containers []TaskContainerStatus `json:"containers,omitempty"`
+}
+
+// TaskContainerStatus used in TaskPodStatus.
+type TaskContainerStatus struct {
+	Name     string `json:"name"`
+	Ready    bool   `json:"ready"`
+	State    string `json:"state"`
+	Reason   string `json:"reason,omitempty"`
+	Message  string `json:"message,omitempty"`
+	ExitCode int32  `json:"exitCode,omitempty"`
+	Restarts int32  `json:"restarts,omitempty"`
+}
+
+type TaskReport struct {
+	Model
+	Status    string
+	Errors    JSON
+	Total     int
+	Completed int
+	Activity  JSON `gorm:"type:json"`
+	Attached  JSON `gorm:"type:json" ref:"[]file"`
+	Result    JSON `gorm:"type:json"`
+	TaskID    uint `gorm:"<-:create;uniqueIndex"`
+	Task      *Task
+}
+
+type TaskGroup struct {
+	Model
+	BucketOwner
+	Name  string
+	Addon string
+	Data  JSON
+	Tasks []Task `gorm:"constraint:OnDelete:CASCADE"`
+	List  JSON
+	State string
+}
+
+// Propagate group data into the task.
+func (m *TaskGroup) Propagate() (err error) {
+	for i := range m.Tasks {
+		task := &m.Tasks[i]
+		task.State = m.State
+		task.SetBucket(m.BucketID)
+		if task.Addon == "" {
+			task.Addon = m.Addon
+		}
+		if m.Data == nil {
+			continue
+		}
+		a := Map{}
+		err = json.Unmarshal(m.Data, &a)
+		if err != nil {
+			err = liberr.Wrap(
+				err,
+				"id",
+				m.ID)
+			return
+		}
+		b := Map{}
+		err = json.Unmarshal(task.Data, &b)
+		if err != nil {
+			err = liberr.Wrap(
+				err,
+				"id",
+				m.ID)
+			return
+		}
+		task.Data, _ = json.Marshal(m.merge(a, b))
+	}
+
+	return
+}
+
+// merge maps B into A.
+// The B map is the authority.
+func (m *TaskGroup) merge(a, b Map) (out Map) {
+	if a == nil {
+		a = Map{}
+	}
+	if b == nil {
+		b = Map{}
+	}
+	out = Map{}
+	//
+	// Merge-in elements found in B and in A.
+	for k, v := range a {
+		out[k] = v
+		if bv, found := b[k]; found {
+			out[k] = bv
+			if av, cast := v.(Map); cast {
+				if bv, cast := bv.(Map); cast {
+					out[k] = m.merge(av, bv)
+				} else {
+					out[k] = bv
+				}
+			}
+		}
+	}
+	//
+	// Add elements found only in B.
+	for k, v := range b {
+		if _, found := a[k]; !found {
+			out[k] = v
+		}
+	}
+
+	return
+}
+
+// TaskSchedule expanded into tasks on (cron) schedule.
+type TaskSchedule struct {
+	Model
+	Name         string `gorm:"uniqueIndex;not null"`
+	Cron         string `gorm:"not null"`
+	Addon        string
+	Variant      string
+	Priority     int
+	Policy       string
+	Data         JSON
+	Paused       bool
+	LastRun      *time.Time
+	NextRun      *time.Time
+	Applications []Application `gorm:"many2many:TaskScheduleApplication;constraint:OnDelete:CASCADE"`
+	Tasks        []Task        `gorm:"foreignKey:ScheduleID"`
+}
+
+// Proxy configuration.
+// kind = (http|https)
+type Proxy struct {
+	Model
+	Enabled    bool
+	Kind       string `gorm:"uniqueIndex"`
+	Host       string `gorm:"not null"`
+	Port       int
+	Excluded   JSON  `gorm:"type:json"`
+	IdentityID *uint `gorm:"index"`
+	Identity   *Identity
+}
+
+// Identity represents and identity with a set of credentials.
+type Identity struct {
+	Model
+	Kind        string `gorm:"not null"`
+	Name        string `gorm:"index;unique;not null"`
+	Description string
+	User        string
+	Password    string
+	Key         string
+	Settings    string
+	Proxies     []Proxy `gorm:"constraint:OnDelete:SET NULL"`
+}
+
+// Encrypt sensitive fields.
+// The ref identity is used to determine when sensitive fields
+// have changed and need to be (re)encrypted.
+func (r *Identity) Encrypt(ref *Identity) (err error) {
+	passphrase := Settings.Encryption.Passphrase
+	aes := encryption.New(passphrase)
+	if r.Password != ref.Password {
+		if r.Password != "" {
+			r.Password, err = aes.Encrypt(r.Password)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+		}
+	}
+	if r.Key != ref.Key {
+		if r.Key != "" {
+			r.Key, err = aes.Encrypt(r.Key)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+		}
+	}
+	if r.Settings != ref.Settings {
+		if r.Settings != "" {
+			r.Settings, err = aes.Encrypt(r.Settings)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// Decrypt sensitive fields.
+func (r *Identity) Decrypt() (err error) {
+	passphrase := Settings.Encryption.Passphrase
+	aes := encryption.New(passphrase)
+	if r.Password != "" {
+		r.Password, err = aes.Decrypt(r.Password)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	if r.Key != "" {
+		r.Key, err = aes.Decrypt(r.Key)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	if r.Settings != "" {
+		r.Settings, err = aes.Decrypt(r.Settings)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	return
+}
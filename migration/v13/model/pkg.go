@@ -0,0 +1,59 @@
+package model
+
+import "github.com/konveyor/tackle2-hub/settings"
+
+var (
+	Settings = &settings.Settings
+)
+
+// JSON field (data) type.
+type JSON = []byte
+
+// All builds all models.
+// Models are enumerated such that each are listed after
+// all the other models on which they may depend.
+func All() []interface{} {
+	return []interface{}{
+		Application{},
+		TechDependency{},
+		Incident{},
+		Analysis{},
+		Issue{},
+		IssueSuppression{},
+		Bucket{},
+		BusinessService{},
+		Dependency{},
+		File{},
+		Fact{},
+		Identity{},
+		Import{},
+		ImportSummary{},
+		ImportTag{},
+		JobFunction{},
+		MigrationWave{},
+		Proxy{},
+		Review{},
+		Setting{},
+		RuleSet{},
+		Rule{},
+		Stakeholder{},
+		StakeholderGroup{},
+		Tag{},
+		TagCategory{},
+		Target{},
+		Task{},
+		TaskGroup{},
+		TaskSchedule{},
+		TaskReport{},
+		Ticket{},
+		Tracker{},
+		TrackerEvent{},
+		ApplicationEvent{},
+		ApplicationField{},
+		TicketComment{},
+		ApplicationTag{},
+		Questionnaire{},
+		Assessment{},
+		Archetype{},
+	}
+}
@@ -1,7 +1,7 @@
 package model
 
 import (
-	"github.com/konveyor/tackle2-hub/migration/v12/model"
+	"github.com/konveyor/tackle2-hub/migration/v13/model"
 )
 
 // Field (data) types.
@@ -17,6 +17,7 @@ type Incident = model.Incident
 type Analysis = model.Analysis
 type ArchivedIssue = model.ArchivedIssue
 type Issue = model.Issue
+type IssueSuppression = model.IssueSuppression
 type Bucket = model.Bucket
 type BucketOwner = model.BucketOwner
 type BusinessService = model.BusinessService
@@ -42,11 +43,19 @@ type TagCategory = model.TagCategory
 type Target = model.Target
 type Task = model.Task
 type TaskGroup = model.TaskGroup
+type TaskSchedule = model.TaskSchedule
 type TaskReport = model.TaskReport
 type Ticket = model.Ticket
+type TicketComment = model.TicketComment
 type Tracker = model.Tracker
+type TrackerEvent = model.TrackerEvent
+type ApplicationEvent = model.ApplicationEvent
+type ApplicationField = model.ApplicationField
 
 type TTL = model.TTL
+type TaskEvent = model.TaskEvent
+type TaskPodStatus = model.TaskPodStatus
+type TaskContainerStatus = model.TaskContainerStatus
 
 // Join tables
 type ApplicationTag = model.ApplicationTag
@@ -0,0 +1,83 @@
+package reaper
+
+import (
+	"time"
+
+	liberr "github.com/jortel/go-utils/error"
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/gorm"
+)
+
+// AnalysisReaper analysis reaper.
+type AnalysisReaper struct {
+	// DB
+	DB *gorm.DB
+}
+
+// Run Executes the reaper.
+// Analyses are deleted based on the (per application) retention
+// settings. The latest analysis is always kept.
+func (r *AnalysisReaper) Run() {
+	Log.V(1).Info("Reaping analyses.")
+	actions, err := r.Plan()
+	if err != nil {
+		Log.Error(err, "")
+		return
+	}
+	for _, action := range actions {
+		err = r.delete(action.ID)
+		if err != nil {
+			Log.Error(err, "")
+			continue
+		}
+		Log.Info("Analysis deleted.", "id", action.ID, "reason", action.Reason)
+	}
+}
+
+// Plan returns the actions to be taken (dry-run).
+func (r *AnalysisReaper) Plan() (actions []Action, err error) {
+	retention := Settings.Analysis.Retention
+	if retention.Count < 1 && retention.Age < 1 {
+		return
+	}
+	list := []model.Analysis{}
+	db := r.DB.Select("ID", "ApplicationID", "CreateTime")
+	db = db.Order("ApplicationID, ID DESC")
+	err = db.Find(&list).Error
+	if err != nil {
+		return
+	}
+	age := time.Duration(retention.Age) * 24 * time.Hour
+	kept := 0
+	for i := range list {
+		m := &list[i]
+		if i == 0 || list[i-1].ApplicationID != m.ApplicationID {
+			kept = 1 // latest.
+			continue
+		}
+		action := Action{Kind: "Analysis", ID: m.ID}
+		switch {
+		case retention.Count > 0 && kept >= retention.Count:
+			action.Action = Delete
+			action.Reason = "Retention: count exceeded."
+		case retention.Age > 0 && time.Since(m.CreateTime) > age:
+			action.Action = Delete
+			action.Reason = "Retention: age exceeded."
+		default:
+			kept++
+			continue
+		}
+		actions = append(actions, action)
+	}
+	return
+}
+
+// delete the analysis.
+func (r *AnalysisReaper) delete(id uint) (err error) {
+	err = r.DB.Delete(&model.Analysis{}, id).Error
+	if err != nil {
+		err = liberr.Wrap(err, "id", id)
+		return
+	}
+	return
+}
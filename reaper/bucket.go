@@ -59,6 +59,39 @@ func (r *BucketReaper) Run() {
 	}
 }
 
+// Plan returns the actions to be taken (dry-run).
+// Orphaned buckets are listed after the TTL has expired.
+func (r *BucketReaper) Plan() (actions []Action, err error) {
+	list := []model.Bucket{}
+	err = r.DB.Order("id").Find(&list).Error
+	if err != nil {
+		return
+	}
+	for i := range list {
+		bucket := &list[i]
+		if bucket.Expiration == nil || time.Now().Before(*bucket.Expiration) {
+			continue
+		}
+		var busy bool
+		busy, err = r.busy(bucket)
+		if err != nil {
+			return
+		}
+		if busy {
+			continue
+		}
+		actions = append(
+			actions,
+			Action{
+				Kind:   "Bucket",
+				ID:     bucket.ID,
+				Action: Delete,
+				Reason: "Orphaned: TTL expired.",
+			})
+	}
+	return
+}
+
 // busy determines if anything references the bucket.
 func (r *BucketReaper) busy(bucket *model.Bucket) (busy bool, err error) {
 	nRef := int64(0)
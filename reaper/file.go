@@ -58,6 +58,39 @@ func (r *FileReaper) Run() {
 	}
 }
 
+// Plan returns the actions to be taken (dry-run).
+// Orphaned files are listed after the TTL has expired.
+func (r *FileReaper) Plan() (actions []Action, err error) {
+	list := []model.File{}
+	err = r.DB.Order("id").Find(&list).Error
+	if err != nil {
+		return
+	}
+	for i := range list {
+		file := &list[i]
+		if file.Expiration == nil || time.Now().Before(*file.Expiration) {
+			continue
+		}
+		var busy bool
+		busy, err = r.busy(file)
+		if err != nil {
+			return
+		}
+		if busy {
+			continue
+		}
+		actions = append(
+			actions,
+			Action{
+				Kind:   "File",
+				ID:     file.ID,
+				Action: Delete,
+				Reason: "Orphaned: TTL expired.",
+			})
+	}
+	return
+}
+
 // busy determines if anything references the file.
 func (r *FileReaper) busy(file *model.File) (busy bool, err error) {
 	nRef := int64(0)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	liberr "github.com/jortel/go-utils/error"
 	"github.com/jortel/go-utils/logr"
 	"github.com/konveyor/tackle2-hub/settings"
 	"github.com/konveyor/tackle2-hub/task"
@@ -46,6 +47,9 @@ func (m *Manager) Run(ctx context.Context) {
 		&FileReaper{
 			DB: m.DB,
 		},
+		&AnalysisReaper{
+			DB: m.DB,
+		},
 	}
 	go func() {
 		Log.Info("Started.")
@@ -70,7 +74,66 @@ func (m *Manager) pause() {
 	time.Sleep(d)
 }
 
+// DryRun returns the actions the reapers would take.
+func (m *Manager) DryRun() (actions []Action, err error) {
+	planners := []Planner{
+		&TaskReaper{
+			DB: m.DB,
+		},
+		&GroupReaper{
+			DB: m.DB,
+		},
+		&BucketReaper{
+			DB: m.DB,
+		},
+		&FileReaper{
+			DB: m.DB,
+		},
+		&AnalysisReaper{
+			DB: m.DB,
+		},
+	}
+	actions = []Action{}
+	for _, r := range planners {
+		var planned []Action
+		planned, err = r.Plan()
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		actions = append(actions, planned...)
+	}
+	return
+}
+
 // Reaper interface.
 type Reaper interface {
 	Run()
 }
+
+// Planner reports the actions the reaper would take.
+type Planner interface {
+	Plan() (actions []Action, err error)
+}
+
+// Reaper actions.
+const (
+	Delete  = "Delete"
+	Release = "Release"
+)
+
+// Action a (planned) reaper action.
+type Action struct {
+	// Kind of resource.
+	Kind string
+	// ID of the resource.
+	ID uint
+	// Action to be taken.
+	Action string
+	// Reason the action is taken.
+	Reason string
+	// pod to be released.
+	pod bool
+	// bucket to be released.
+	bucket bool
+}
@@ -2,9 +2,9 @@ package reaper
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
-	"github.com/konveyor/tackle2-hub/api"
 	"github.com/konveyor/tackle2-hub/model"
 	"github.com/konveyor/tackle2-hub/task"
 	"gorm.io/gorm"
@@ -25,115 +25,171 @@ type TaskReaper struct {
 //
 //	Created
 //	- Deleted after TTL.Created > created timestamp or
-//	  settings.Task.Reaper.Created.
+//	  settings.Task.Retention.Created.
+//	- Bucket is released after settings.Task.Reaper.Created.
 //	Pending
-//	- Deleted after TTL.Pending > created timestamp or
-//	  settings.Task.Reaper.Created.
+//	- Deleted after TTL.Pending > created timestamp.
 //	Postponed
-//	- Deleted after TTL.Postponed > created timestamp or
-//	  settings.Task.Reaper.Created.
+//	- Deleted after TTL.Postponed > created timestamp.
 //	Running
 //	- Deleted after TTL.Running > started timestamp.
 //	Succeeded
 //	- Deleted after TTL > terminated timestamp or
-//	  settings.Task.Reaper.Succeeded.
+//	  settings.Task.Retention.Succeeded.
 //	- Bucket is released after the defined period.
 //	- Pod is deleted after the defined period.
 //	Failed
 //	- Deleted after TTL > terminated timestamp or
-//	  settings.Task.Reaper.Failed.
+//	  settings.Task.Retention.Failed.
 //	- Bucket is released after the defined period.
 //	- Pod is deleted after the defined period.
+//	Canceled
+//	- Deleted after settings.Task.Retention.Canceled.
+//
+// The (release) period of the bucket is overridden
+// by settings.Bucket.Retention.Task.
 func (r *TaskReaper) Run() {
 	Log.V(1).Info("Reaping tasks.")
-	list := []model.Task{}
-	result := r.DB.Find(
+	list, err := r.find()
+	Log.Error(err, "")
+	if err != nil {
+		return
+	}
+	for i := range list {
+		m := &list[i]
+		action := r.action(m)
+		switch action.Action {
+		case Delete:
+			r.delete(m)
+		case Release:
+			r.release(m, action)
+		}
+	}
+}
+
+// Plan returns the actions to be taken (dry-run).
+func (r *TaskReaper) Plan() (actions []Action, err error) {
+	list, err := r.find()
+	if err != nil {
+		return
+	}
+	for i := range list {
+		action := r.action(&list[i])
+		if action.Action != "" {
+			actions = append(actions, action)
+		}
+	}
+	return
+}
+
+// find returns the tasks to be considered.
+func (r *TaskReaper) find() (list []model.Task, err error) {
+	db := r.DB.Order("id")
+	err = db.Find(
 		&list,
 		"state IN ?",
 		[]string{
 			task.Created,
 			task.Succeeded,
 			task.Failed,
-		})
-	Log.Error(result.Error, "")
-	if result.Error != nil {
+			task.Canceled,
+		}).Error
+	return
+}
+
+// action returns the action to be taken for the task.
+func (r *TaskReaper) action(m *model.Task) (action Action) {
+	action = Action{Kind: "Task", ID: m.ID}
+	ttl := r.TTL(m)
+	var mark time.Time
+	var expiration, retention, release int
+	switch m.State {
+	case task.Created:
+		mark = m.CreateTime
+		expiration = ttl.Created
+		retention = Settings.Hub.Task.Retention.Created
+		release = Settings.Hub.Task.Reaper.Created
+	case task.Pending:
+		mark = m.CreateTime
+		expiration = ttl.Pending
+	case task.Postponed:
+		mark = m.CreateTime
+		expiration = ttl.Postponed
+	case task.Running:
+		mark = *m.Started
+		expiration = ttl.Running
+	case task.Succeeded:
+		mark = *m.Terminated
+		expiration = ttl.Succeeded
+		retention = Settings.Hub.Task.Retention.Succeeded
+		release = Settings.Hub.Task.Reaper.Succeeded
+	case task.Failed:
+		mark = *m.Terminated
+		expiration = ttl.Failed
+		retention = Settings.Hub.Task.Retention.Failed
+		release = Settings.Hub.Task.Reaper.Failed
+	case task.Canceled:
+		mark = m.UpdateTime
+		if m.Terminated != nil {
+			mark = *m.Terminated
+		}
+		retention = Settings.Hub.Task.Retention.Canceled
+	default:
 		return
 	}
-	for i := range list {
-		m := &list[i]
-		ttl := r.TTL(m)
-		switch m.State {
-		case task.Created:
-			mark := m.CreateTime
-			if ttl.Created > 0 {
-				d := time.Duration(ttl.Created) * Unit
-				if time.Since(mark) > d {
-					r.delete(m)
-				}
-			} else {
-				d := time.Duration(Settings.Hub.Task.Reaper.Created) * Unit
-				if time.Since(mark) > d {
-					r.release(m)
-				}
-			}
-		case task.Pending:
-			mark := m.CreateTime
-			if ttl.Pending > 0 {
-				d := time.Duration(ttl.Pending) * Unit
-				if time.Since(mark) > d {
-					r.delete(m)
-				}
-			}
-		case task.Postponed:
-			mark := m.CreateTime
-			if ttl.Postponed > 0 {
-				d := time.Duration(ttl.Postponed) * Unit
-				if time.Since(mark) > d {
-					r.delete(m)
-				}
-			}
-		case task.Running:
-			mark := *m.Started
-			if ttl.Running > 0 {
-				d := time.Duration(ttl.Running) * Unit
-				if time.Since(mark) > d {
-					r.delete(m)
-				}
-			}
-		case task.Succeeded:
-			mark := *m.Terminated
-			if ttl.Succeeded > 0 {
-				d := time.Duration(ttl.Succeeded) * Unit
-				if time.Since(mark) > d {
-					r.delete(m)
-				}
-			} else {
-				d := time.Duration(Settings.Hub.Task.Reaper.Succeeded) * Unit
-				if time.Since(mark) > d {
-					r.release(m)
-				}
-			}
-		case task.Failed:
-			mark := *m.Terminated
-			if ttl.Succeeded > 0 {
-				d := time.Duration(ttl.Failed) * Unit
-				if time.Since(mark) > d {
-					r.delete(m)
-				}
-			} else {
-				d := time.Duration(Settings.Hub.Task.Reaper.Failed) * Unit
-				if time.Since(mark) > d {
-					r.release(m)
-				}
-			}
+	switch {
+	case expiration > 0:
+		if r.elapsed(mark, expiration) {
+			action.Action = Delete
+			action.Reason = fmt.Sprintf("%s: TTL (%d minutes) expired.", m.State, expiration)
 		}
+		return
+	case retention > 0:
+		if r.elapsed(mark, retention) {
+			action.Action = Delete
+			action.Reason = fmt.Sprintf("%s: retention (%d minutes) expired.", m.State, retention)
+			return
+		}
+	}
+	switch m.State {
+	case task.Created,
+		task.Succeeded,
+		task.Failed:
+	default:
+		return
 	}
+	bucket := release
+	if Settings.Bucket.Retention.Task > 0 {
+		bucket = Settings.Bucket.Retention.Task
+	}
+	action.pod = m.Pod != "" && r.elapsed(mark, release)
+	action.bucket = m.HasBucket() && r.elapsed(mark, bucket)
+	switch {
+	case action.pod && action.bucket:
+		action.Action = Release
+		action.Reason = "Pod and bucket released."
+	case action.pod:
+		action.Action = Release
+		action.Reason = "Pod released."
+	case action.bucket:
+		action.Action = Release
+		action.Reason = "Bucket released."
+	}
+	return
+}
+
+// elapsed returns true when the period (minutes) has
+// elapsed since the mark.
+func (r *TaskReaper) elapsed(mark time.Time, period int) (b bool) {
+	d := time.Duration(period) * Unit
+	b = time.Since(mark) > d
+	return
 }
 
 // release resources.
-func (r *TaskReaper) release(m *model.Task) {
+func (r *TaskReaper) release(m *model.Task, action Action) {
 	nChanged := 0
-	if m.Pod != "" {
+	if action.pod {
 		rt := Task{Task: m}
 		err := rt.Delete(r.Client)
 		if err == nil {
@@ -143,7 +199,7 @@ func (r *TaskReaper) release(m *model.Task) {
 			Log.Error(err, "")
 		}
 	}
-	if m.HasBucket() {
+	if action.bucket {
 		Log.Info("Task bucket released.", "id", m.ID)
 		m.SetBucket(nil)
 		nChanged++
@@ -173,7 +229,7 @@ func (r *TaskReaper) delete(m *model.Task) {
 }
 
 // TTL returns the task TTL.
-func (r *TaskReaper) TTL(m *model.Task) (ttl api.TTL) {
+func (r *TaskReaper) TTL(m *model.Task) (ttl model.TTL) {
 	if m.TTL != nil {
 		_ = json.Unmarshal(m.TTL, &ttl)
 	}
@@ -197,37 +253,81 @@ type GroupReaper struct {
 //	- Deleted after the defined period.
 //	Ready (submitted)
 //	- Deleted when all of its task have been deleted.
-//	- Bucket is released immediately.
+//	- Bucket is released immediately or after
+//	  settings.Bucket.Retention.TaskGroup.
 func (r *GroupReaper) Run() {
 	Log.V(1).Info("Reaping groups.")
-	list := []model.TaskGroup{}
-	db := r.DB.Preload(clause.Associations)
-	result := db.Find(&list)
-	if result.Error != nil {
+	list, err := r.find()
+	if err != nil {
 		return
 	}
 	for i := range list {
 		m := &list[i]
-		switch m.State {
-		case task.Created:
-			mark := m.CreateTime
-			d := time.Duration(
-				Settings.Hub.Task.Reaper.Created) * Unit
-			if time.Since(mark) > d {
-				r.delete(m)
-			}
-		case task.Ready:
-			if len(m.Tasks) == 0 {
-				r.delete(m)
-				continue
-			}
-			if m.HasBucket() {
-				r.release(m)
-			}
+		action := r.action(m)
+		switch action.Action {
+		case Delete:
+			r.delete(m)
+		case Release:
+			r.release(m)
 		}
 	}
 }
 
+// Plan returns the actions to be taken (dry-run).
+func (r *GroupReaper) Plan() (actions []Action, err error) {
+	list, err := r.find()
+	if err != nil {
+		return
+	}
+	for i := range list {
+		action := r.action(&list[i])
+		if action.Action != "" {
+			actions = append(actions, action)
+		}
+	}
+	return
+}
+
+// find returns the groups to be considered.
+func (r *GroupReaper) find() (list []model.TaskGroup, err error) {
+	db := r.DB.Preload(clause.Associations)
+	db = db.Order("id")
+	err = db.Find(&list).Error
+	return
+}
+
+// action returns the action to be taken for the group.
+func (r *GroupReaper) action(m *model.TaskGroup) (action Action) {
+	action = Action{Kind: "TaskGroup", ID: m.ID}
+	switch m.State {
+	case task.Created:
+		mark := m.CreateTime
+		d := time.Duration(
+			Settings.Hub.Task.Reaper.Created) * Unit
+		if time.Since(mark) > d {
+			action.Action = Delete
+			action.Reason = "Created: not submitted."
+		}
+	case task.Ready:
+		if len(m.Tasks) == 0 {
+			action.Action = Delete
+			action.Reason = "Tasks deleted."
+			return
+		}
+		if !m.HasBucket() {
+			return
+		}
+		mark := m.UpdateTime
+		d := time.Duration(
+			Settings.Bucket.Retention.TaskGroup) * Unit
+		if time.Since(mark) > d {
+			action.Action = Release
+			action.Reason = "Bucket released."
+		}
+	}
+	return
+}
+
 // release resources.
 func (r *GroupReaper) release(m *model.TaskGroup) {
 	m.SetBucket(nil)
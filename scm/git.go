@@ -0,0 +1,201 @@
+package scm
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	liberr "github.com/jortel/go-utils/error"
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+// Commit SHA (full or abbreviated).
+var commitSHA = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// askPass git (GIT_ASKPASS) credentials script.
+const askPass = `#!/bin/sh
+case "$1" in
+Username*) echo "$SCM_USER" ;;
+*) echo "$SCM_PASSWORD" ;;
+esac
+`
+
+// Schemes the (git) URL schemes accepted. Local (file) URLs
+// and paths are not accepted so the hub filesystem cannot be probed.
+// The scp-like (user@host:path) syntax is accepted as ssh.
+var Schemes = []string{"http", "https", "ssh", "git"}
+
+// scpLike matches the scp-like (ssh) syntax: user@host:path.
+var scpLike = regexp.MustCompile(`^[A-Za-z0-9._~-]+@[A-Za-z0-9.-]+:[^/]`)
+
+// GitValidator validates git repositories using: git ls-remote.
+// The path is validated using a shallow (blobless) fetch.
+// When an (ssh) identity is used, host keys are verified using
+// the known_hosts file defined by settings (SCM_KNOWN_HOSTS).
+// When not defined, host keys are NOT verified (StrictHostKeyChecking=no).
+type GitValidator struct {
+	Identity *model.Identity
+}
+
+// Validate the remote.
+func (v *GitValidator) Validate(r *Remote) (errors []Error, err error) {
+	if r.URL == "" {
+		errors = append(errors, Error{Field: FieldURL, Reason: "required."})
+		return
+	}
+	if !v.supported(r.URL) {
+		errors = append(
+			errors,
+			Error{
+				Field:  FieldURL,
+				Reason: "must be a (" + strings.Join(Schemes, "|") + ") URL.",
+			})
+		return
+	}
+	tmpDir, err := os.MkdirTemp("", "scm-")
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+	cmd, err := v.command(tmpDir)
+	if err != nil {
+		return
+	}
+	output, reason, cErr := cmd.Run("ls-remote", "--heads", "--tags", "--", r.URL)
+	if cErr != nil {
+		errors = append(errors, Error{Field: FieldURL, Reason: reason})
+		return
+	}
+	heads, tags := v.refs(output)
+	ref := "HEAD"
+	if r.Branch != "" {
+		if heads[r.Branch] || tags[r.Branch] || commitSHA.MatchString(r.Branch) {
+			ref = r.Branch
+		} else {
+			errors = append(errors, Error{Field: FieldBranch, Reason: "not found."})
+		}
+	}
+	if r.Tag != "" {
+		if tags[r.Tag] {
+			ref = r.Tag
+		} else {
+			errors = append(errors, Error{Field: FieldTag, Reason: "not found."})
+		}
+	}
+	p := strings.Trim(r.Path, "/")
+	if p == "" || len(errors) > 0 {
+		return
+	}
+	repoDir := path.Join(tmpDir, "repository")
+	_, _, err = cmd.Run("init", "--quiet", repoDir)
+	if err != nil {
+		return
+	}
+	cmd.Dir = repoDir
+	_, reason, cErr = cmd.Run("fetch", "--quiet", "--depth=1", "--filter=blob:none", "--", r.URL, ref)
+	if cErr != nil {
+		errors = append(errors, Error{Field: FieldPath, Reason: reason})
+		return
+	}
+	_, _, cErr = cmd.Run("cat-file", "-e", "FETCH_HEAD:"+p)
+	if cErr != nil {
+		errors = append(errors, Error{Field: FieldPath, Reason: "not found."})
+	}
+	return
+}
+
+// supported returns true when the URL scheme is supported.
+func (v *GitValidator) supported(url string) (b bool) {
+	if strings.HasPrefix(url, "-") {
+		return
+	}
+	scheme, _, found := strings.Cut(url, "://")
+	if !found {
+		b = scpLike.MatchString(url)
+		return
+	}
+	for _, s := range Schemes {
+		if strings.EqualFold(scheme, s) {
+			b = true
+			break
+		}
+	}
+	return
+}
+
+// refs parses the ls-remote output.
+// Returns the branch and tag names.
+func (v *GitValidator) refs(output string) (heads, tags map[string]bool) {
+	heads = map[string]bool{}
+	tags = map[string]bool{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		ref := strings.TrimSuffix(fields[1], "^{}")
+		if name, found := strings.CutPrefix(ref, "refs/heads/"); found {
+			heads[name] = true
+			continue
+		}
+		if name, found := strings.CutPrefix(ref, "refs/tags/"); found {
+			tags[name] = true
+		}
+	}
+	return
+}
+
+// command returns the git command with the credentials
+// (identity) written to the (temporary) directory.
+func (v *GitValidator) command(tmpDir string) (cmd *Command, err error) {
+	cmd = &Command{
+		Path: "git",
+		Dir:  tmpDir,
+		Env: []string{
+			"GIT_TERMINAL_PROMPT=0",
+			"GIT_CONFIG_NOSYSTEM=1",
+			"HOME=" + tmpDir,
+		},
+	}
+	id := v.Identity
+	if id == nil {
+		return
+	}
+	if id.Key != "" {
+		key := path.Join(tmpDir, "id")
+		err = os.WriteFile(key, []byte(id.Key+"\n"), 0600)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		hostKey := " -o StrictHostKeyChecking=no" +
+			" -o UserKnownHostsFile=/dev/null"
+		if Settings.Scm.KnownHosts != "" {
+			hostKey = " -o StrictHostKeyChecking=yes" +
+				" -o UserKnownHostsFile=" + Settings.Scm.KnownHosts
+		}
+		cmd.Env = append(
+			cmd.Env,
+			"GIT_SSH_COMMAND=ssh -i "+key+
+				" -o IdentitiesOnly=yes"+
+				" -o BatchMode=yes"+
+				hostKey)
+		return
+	}
+	script := path.Join(tmpDir, "askpass")
+	err = os.WriteFile(script, []byte(askPass), 0700)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	cmd.Env = append(
+		cmd.Env,
+		"GIT_ASKPASS="+script,
+		"SCM_USER="+id.User,
+		"SCM_PASSWORD="+id.Password)
+	return
+}
@@ -0,0 +1,69 @@
+package scm
+
+import (
+	"os"
+	"os/exec"
+	"path"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestGitValidate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	url := t.TempDir()
+	g.Expect(os.MkdirAll(path.Join(url, "service", "src"), 0755)).To(gomega.Succeed())
+	g.Expect(os.WriteFile(path.Join(url, "service", "src", "a.java"), []byte("a"), 0644)).To(gomega.Succeed())
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch=main"},
+		{"add", "."},
+		{"-c", "user.name=t", "-c", "user.email=t@x", "commit", "--quiet", "-m", "init"},
+		{"tag", "v1.0"},
+		{"branch", "dev"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = url
+		out, err := cmd.CombinedOutput()
+		g.Expect(err).To(gomega.BeNil(), string(out))
+	}
+	v, err := New(Git, nil)
+	g.Expect(err).To(gomega.BeNil())
+	// Local not supported.
+	for _, u := range []string{url, "file://" + url, "-uhalt", "--upload-pack=touch /tmp/x"} {
+		errors, err := v.Validate(&Remote{URL: u})
+		g.Expect(err).To(gomega.BeNil())
+		g.Expect(errors).To(gomega.Equal([]Error{{Field: FieldURL, Reason: "must be a (http|https|ssh|git) URL."}}), u)
+	}
+	git := &GitValidator{}
+	g.Expect(git.supported("git@github.com:konveyor/tackle2-hub.git")).To(gomega.BeTrue())
+	g.Expect(git.supported("https://github.com/konveyor/tackle2-hub.git")).To(gomega.BeTrue())
+	g.Expect(git.supported("ssh://git@github.com/konveyor/tackle2-hub.git")).To(gomega.BeTrue())
+	g.Expect(git.supported("-oProxyCommand=x@host:path")).To(gomega.BeFalse())
+	schemes := Schemes
+	Schemes = append(Schemes, "file")
+	t.Cleanup(func() {
+		Schemes = schemes
+	})
+	url = "file://" + url
+
+	errors, err := v.Validate(&Remote{URL: url, Branch: "dev", Path: "/service/src"})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(errors).To(gomega.BeEmpty())
+	errors, _ = v.Validate(&Remote{URL: url, Tag: "v1.0", Path: "service"})
+	g.Expect(errors).To(gomega.BeEmpty())
+
+	errors, _ = v.Validate(&Remote{URL: url, Branch: "other", Tag: "v2"})
+	g.Expect(errors).To(gomega.Equal([]Error{
+		{Field: FieldBranch, Reason: "not found."},
+		{Field: FieldTag, Reason: "not found."},
+	}))
+	errors, _ = v.Validate(&Remote{URL: url, Branch: "main", Path: "missing"})
+	g.Expect(errors).To(gomega.Equal([]Error{{Field: FieldPath, Reason: "not found."}}))
+	errors, _ = v.Validate(&Remote{URL: url + "/missing"})
+	g.Expect(errors).To(gomega.HaveLen(1))
+	g.Expect(errors[0].Field).To(gomega.Equal(FieldURL))
+	g.Expect(errors[0].Reason).ToNot(gomega.BeEmpty())
+
+	_, err = New("cvs", nil)
+	g.Expect(err).To(gomega.MatchError(&KindError{Kind: "cvs"}))
+}
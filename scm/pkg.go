@@ -0,0 +1,126 @@
+package scm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	liberr "github.com/jortel/go-utils/error"
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/konveyor/tackle2-hub/settings"
+)
+
+var Settings = &settings.Settings
+
+// Repository kinds.
+const (
+	Git        = "git"
+	Subversion = "subversion"
+)
+
+// Validated fields.
+const (
+	FieldKind     = "kind"
+	FieldURL      = "url"
+	FieldBranch   = "branch"
+	FieldTag      = "tag"
+	FieldPath     = "path"
+	FieldIdentity = "identity"
+)
+
+// Timeout (command) timeout.
+var Timeout = time.Minute
+
+// Remote repository coordinates.
+type Remote struct {
+	Kind   string
+	URL    string
+	Branch string
+	Tag    string
+	Path   string
+}
+
+// Error a validation error.
+type Error struct {
+	Field  string
+	Reason string
+}
+
+// Validator validates the remote repository coordinates.
+type Validator interface {
+	// Validate returns the (structured) validation errors.
+	// The err is returned when the validation could not be performed.
+	Validate(r *Remote) (errors []Error, err error)
+}
+
+// New returns a validator for the repository kind.
+// The identity (optional) is decrypted.
+func New(kind string, identity *model.Identity) (v Validator, err error) {
+	switch kind {
+	case "", Git:
+		v = &GitValidator{Identity: identity}
+	case Subversion:
+		v = &SvnValidator{Identity: identity}
+	default:
+		err = &KindError{Kind: kind}
+	}
+	return
+}
+
+// KindError reports the repository kind not supported.
+type KindError struct {
+	Kind string
+}
+
+func (e *KindError) Error() (s string) {
+	return "kind: '" + e.Kind + "' must be (git|subversion)."
+}
+
+func (e *KindError) Is(err error) (matched bool) {
+	_, matched = err.(*KindError)
+	return
+}
+
+// Command runs a (git|svn) command.
+type Command struct {
+	Path string
+	Dir  string
+	Env  []string
+}
+
+// Run the command.
+// The err is a liberr wrapped exec.ExitError when the command fails
+// and the reason is the (last) line reported on stderr.
+func (r *Command) Run(args ...string) (output string, reason string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, r.Path, args...)
+	cmd.Dir = r.Dir
+	cmd.Env = append(os.Environ(), r.Env...)
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	output = stdout.String()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			reason = "timed out."
+		} else {
+			reason = r.lastLine(stderr.String())
+		}
+		err = liberr.Wrap(err, "reason", reason)
+	}
+	return
+}
+
+// lastLine returns the last non-empty line.
+func (r *Command) lastLine(s string) (line string) {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	line = strings.TrimSpace(lines[len(lines)-1])
+	return
+}
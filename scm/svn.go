@@ -0,0 +1,79 @@
+package scm
+
+import (
+	"os"
+	"strings"
+
+	liberr "github.com/jortel/go-utils/error"
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+// SvnValidator validates subversion repositories using: svn info.
+// The branch (when specified) is appended to the URL.
+type SvnValidator struct {
+	Identity *model.Identity
+}
+
+// Validate the remote.
+func (v *SvnValidator) Validate(r *Remote) (errors []Error, err error) {
+	if r.URL == "" {
+		errors = append(errors, Error{Field: FieldURL, Reason: "required."})
+		return
+	}
+	tmpDir, err := os.MkdirTemp("", "scm-")
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+	cmd := &Command{
+		Path: "svn",
+		Dir:  tmpDir,
+	}
+	url := strings.TrimSuffix(r.URL, "/")
+	_, reason, cErr := cmd.Run(v.args(tmpDir, url)...)
+	if cErr != nil {
+		errors = append(errors, Error{Field: FieldURL, Reason: reason})
+		return
+	}
+	if r.Branch != "" {
+		url += "/" + strings.Trim(r.Branch, "/")
+		_, reason, cErr = cmd.Run(v.args(tmpDir, url)...)
+		if cErr != nil {
+			errors = append(errors, Error{Field: FieldBranch, Reason: reason})
+			return
+		}
+	}
+	p := strings.Trim(r.Path, "/")
+	if p != "" {
+		_, reason, cErr = cmd.Run(v.args(tmpDir, url+"/"+p)...)
+		if cErr != nil {
+			errors = append(errors, Error{Field: FieldPath, Reason: reason})
+		}
+	}
+	return
+}
+
+// args returns the svn info arguments.
+func (v *SvnValidator) args(tmpDir, url string) (args []string) {
+	args = []string{
+		"info",
+		"--non-interactive",
+		"--no-auth-cache",
+		"--config-dir",
+		tmpDir,
+	}
+	id := v.Identity
+	if id != nil && id.User != "" {
+		args = append(
+			args,
+			"--username",
+			id.User,
+			"--password",
+			id.Password)
+	}
+	args = append(args, url)
+	return
+}
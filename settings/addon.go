@@ -10,6 +10,7 @@ const (
 	EnvHubBaseURL = "HUB_BASE_URL"
 	EnvHubToken   = "TOKEN"
 	EnvTask       = "TASK"
+	EnvTaskPool   = "TASK_POOL"
 )
 
 // Addon settings.
@@ -23,6 +24,8 @@ type Addon struct {
 	}
 	//
 	Task int
+	// Pool (warm) pod waiting to be dispatched a task.
+	Pool bool
 }
 
 func (r *Addon) Load() (err error) {
@@ -39,6 +42,9 @@ func (r *Addon) Load() (err error) {
 	if s, found := os.LookupEnv(EnvTask); found {
 		r.Task, _ = strconv.Atoi(s)
 	}
+	if s, found := os.LookupEnv(EnvTaskPool); found {
+		r.Pool, _ = strconv.ParseBool(s)
+	}
 
 	return
 }
@@ -1,6 +1,7 @@
 package settings
 
 import (
+	"encoding/json"
 	"os"
 	"strconv"
 )
@@ -9,6 +10,7 @@ const (
 	EnvNamespace          = "NAMESPACE"
 	EnvDbPath             = "DB_PATH"
 	EnvDbSeedPath         = "DB_SEED_PATH"
+	EnvDbMaxTimeout       = "DB_MAX_QUERY_TIMEOUT"
 	EnvBucketPath         = "BUCKET_PATH"
 	EnvRwxSupported       = "RWX_SUPPORTED"
 	EnvCachePath          = "CACHE_PATH"
@@ -17,16 +19,44 @@ const (
 	EnvTaskReapCreated    = "TASK_REAP_CREATED"
 	EnvTaskReapSucceeded  = "TASK_REAP_SUCCEEDED"
 	EnvTaskReapFailed     = "TASK_REAP_FAILED"
+	EnvTaskRetainCreated  = "TASK_RETAIN_CREATED"
+	EnvTaskRetainSucceed  = "TASK_RETAIN_SUCCEEDED"
+	EnvTaskRetainFailed   = "TASK_RETAIN_FAILED"
+	EnvTaskRetainCanceled = "TASK_RETAIN_CANCELED"
 	EnvTaskSA             = "TASK_SA"
 	EnvTaskRetries        = "TASK_RETRIES"
+	EnvTaskPreemption     = "TASK_PREEMPTION"
+	EnvTaskTimeout        = "TASK_TIMEOUT"
+	EnvTaskQuotaPods      = "TASK_QUOTA_PODS"
+	EnvTaskQuotaApp       = "TASK_QUOTA_APPLICATION"
+	EnvTaskPoolSize       = "TASK_POOL_SIZE"
 	EnvFrequencyTask      = "FREQUENCY_TASK"
 	EnvFrequencyReaper    = "FREQUENCY_REAPER"
 	EnvDevelopment        = "DEVELOPMENT"
 	EnvBucketTTL          = "BUCKET_TTL"
+	EnvBucketRetainTask   = "BUCKET_RETAIN_TASK"
+	EnvBucketRetainGroup  = "BUCKET_RETAIN_TASKGROUP"
 	EnvFileTTL            = "FILE_TTL"
 	EnvAppName            = "APP_NAME"
 	EnvDisconnected       = "DISCONNECTED"
 	EnvAnalysisReportPath = "ANALYSIS_REPORT_PATH"
+	EnvAnalysisRetainNum  = "ANALYSIS_RETAIN_COUNT"
+	EnvAnalysisRetainAge  = "ANALYSIS_RETAIN_AGE"
+	EnvTrackerPaused      = "TRACKER_PAUSED"
+	EnvTrackerRetention   = "TRACKER_EVENT_RETENTION"
+	EnvTrackerRequireTLS  = "TRACKER_REQUIRE_TLS"
+	EnvTrackerCanary      = "TRACKER_CANARY"
+	EnvTrackerMaxIdle     = "TRACKER_MAX_IDLE_CONNS"
+	EnvTrackerMaxIdleHost = "TRACKER_MAX_IDLE_CONNS_PER_HOST"
+	EnvTrackerIdleTimeout = "TRACKER_IDLE_CONN_TIMEOUT"
+	EnvTrackerWebhookURL  = "TRACKER_WEBHOOK_URL"
+	EnvTrackerWebhookWait = "TRACKER_WEBHOOK_TIMEOUT"
+	EnvTrackerKind        = "TRACKER_DEFAULT_KIND"
+	EnvTrackerMetadata    = "TRACKER_DEFAULT_METADATA"
+	EnvTrackerUnique      = "TRACKER_UNIQUE"
+	EnvApplicationUnique  = "APPLICATION_UNIQUE"
+	EnvApplicationBinary  = "APPLICATION_BINARY_LIMIT"
+	EnvScmKnownHosts      = "SCM_KNOWN_HOSTS"
 )
 
 type Hub struct {
@@ -34,13 +64,18 @@ type Hub struct {
 	Namespace string
 	// DB settings.
 	DB struct {
-		Path     string
-		SeedPath string
+		Path       string
+		SeedPath   string
+		MaxTimeout int // seconds.
 	}
 	// Bucket settings.
 	Bucket struct {
-		Path string
-		TTL  int
+		Path      string
+		TTL       int
+		Retention struct { // minutes (0=default) held by owner.
+			Task      int
+			TaskGroup int
+		}
 	}
 	// File settings.
 	File struct {
@@ -58,12 +93,25 @@ type Hub struct {
 	}
 	// Task
 	Task struct {
-		SA      string
-		Retries int
-		Reaper  struct { // minutes.
+		SA         string
+		Retries    int
+		Preemption bool
+		Timeout    int      // seconds.
+		Pool       int      // idle pods per addon (0=disabled).
+		Quota      struct { // concurrent (0=unlimited).
+			Pods        int
+			Application int
+		}
+		Reaper struct { // minutes.
+			Created   int
+			Succeeded int
+			Failed    int
+		}
+		Retention struct { // minutes (0=forever).
 			Created   int
 			Succeeded int
 			Failed    int
+			Canceled  int
 		}
 	}
 	// Frequency
@@ -81,6 +129,41 @@ type Hub struct {
 	// Analysis settings.
 	Analysis struct {
 		ReportPath string
+		Retention  struct { // per application (0=forever).
+			Count int // analyses.
+			Age   int // days.
+		}
+	}
+	// Application settings.
+	Application struct {
+		Unique      string // uniqueness policy.
+		BinaryLimit int    // uploaded binary size limit (MB).
+	}
+	// Scm (repository validation) settings.
+	Scm struct {
+		KnownHosts string // ssh known_hosts path (empty=not verified).
+	}
+	// Tracker settings.
+	Tracker struct {
+		Paused     bool
+		Retention  int // minutes.
+		RequireTLS bool
+		Canary     bool
+		Unique     string // uniqueness policy.
+		Transport  struct {
+			MaxIdle        int
+			MaxIdlePerHost int
+			IdleTimeout    int // seconds.
+		}
+		Webhook struct {
+			URL     string // hub URL reachable by the remote.
+			Timeout int    // seconds.
+		}
+		// Defaults applied when created.
+		Default struct {
+			Kind     string
+			Metadata map[string]interface{} // (json) applied to Kind.
+		}
 	}
 }
 
@@ -98,11 +181,18 @@ func (r *Hub) Load() (err error) {
 	if !found {
 		r.DB.SeedPath = "/tmp/seed"
 	}
+	s, found := os.LookupEnv(EnvDbMaxTimeout)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.DB.MaxTimeout = n
+	} else {
+		r.DB.MaxTimeout = 300 // seconds.
+	}
 	r.Bucket.Path, found = os.LookupEnv(EnvBucketPath)
 	if !found {
 		r.Bucket.Path = "/tmp/bucket"
 	}
-	s, found := os.LookupEnv(EnvRwxSupported)
+	s, found = os.LookupEnv(EnvRwxSupported)
 	if found {
 		b, _ := strconv.ParseBool(s)
 		r.Cache.RWX = b
@@ -140,6 +230,26 @@ func (r *Hub) Load() (err error) {
 	} else {
 		r.Task.Reaper.Failed = 4320 // 72 hours.
 	}
+	s, found = os.LookupEnv(EnvTaskRetainCreated)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Task.Retention.Created = n
+	}
+	s, found = os.LookupEnv(EnvTaskRetainSucceed)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Task.Retention.Succeeded = n
+	}
+	s, found = os.LookupEnv(EnvTaskRetainFailed)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Task.Retention.Failed = n
+	}
+	s, found = os.LookupEnv(EnvTaskRetainCanceled)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Task.Retention.Canceled = n
+	}
 	r.Task.SA, found = os.LookupEnv(EnvTaskSA)
 	if !found {
 		r.Task.SA = "tackle-hub"
@@ -151,6 +261,31 @@ func (r *Hub) Load() (err error) {
 	} else {
 		r.Task.Retries = 1
 	}
+	s, found = os.LookupEnv(EnvTaskPreemption)
+	if found {
+		b, _ := strconv.ParseBool(s)
+		r.Task.Preemption = b
+	}
+	s, found = os.LookupEnv(EnvTaskTimeout)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Task.Timeout = n
+	}
+	s, found = os.LookupEnv(EnvTaskQuotaPods)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Task.Quota.Pods = n
+	}
+	s, found = os.LookupEnv(EnvTaskQuotaApp)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Task.Quota.Application = n
+	}
+	s, found = os.LookupEnv(EnvTaskPoolSize)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Task.Pool = n
+	}
 	s, found = os.LookupEnv(EnvFrequencyTask)
 	if found {
 		n, _ := strconv.Atoi(s)
@@ -177,6 +312,16 @@ func (r *Hub) Load() (err error) {
 	} else {
 		r.Bucket.TTL = 1 // minutes.
 	}
+	s, found = os.LookupEnv(EnvBucketRetainTask)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Bucket.Retention.Task = n
+	}
+	s, found = os.LookupEnv(EnvBucketRetainGroup)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Bucket.Retention.TaskGroup = n
+	}
 	s, found = os.LookupEnv(EnvFileTTL)
 	if found {
 		n, _ := strconv.Atoi(s)
@@ -197,6 +342,91 @@ func (r *Hub) Load() (err error) {
 	if !found {
 		r.Analysis.ReportPath = "/tmp/analysis/report"
 	}
+	s, found = os.LookupEnv(EnvAnalysisRetainNum)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Analysis.Retention.Count = n
+	}
+	s, found = os.LookupEnv(EnvAnalysisRetainAge)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Analysis.Retention.Age = n
+	}
+	s, found = os.LookupEnv(EnvTrackerPaused)
+	if found {
+		b, _ := strconv.ParseBool(s)
+		r.Tracker.Paused = b
+	}
+	s, found = os.LookupEnv(EnvTrackerRetention)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Tracker.Retention = n
+	} else {
+		r.Tracker.Retention = 10080 // minutes: 7 days.
+	}
+	s, found = os.LookupEnv(EnvTrackerRequireTLS)
+	if found {
+		b, _ := strconv.ParseBool(s)
+		r.Tracker.RequireTLS = b
+	}
+	s, found = os.LookupEnv(EnvTrackerCanary)
+	if found {
+		b, _ := strconv.ParseBool(s)
+		r.Tracker.Canary = b
+	}
+	s, found = os.LookupEnv(EnvTrackerMaxIdle)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Tracker.Transport.MaxIdle = n
+	} else {
+		r.Tracker.Transport.MaxIdle = 100
+	}
+	s, found = os.LookupEnv(EnvTrackerMaxIdleHost)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Tracker.Transport.MaxIdlePerHost = n
+	} else {
+		r.Tracker.Transport.MaxIdlePerHost = 10
+	}
+	s, found = os.LookupEnv(EnvTrackerIdleTimeout)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Tracker.Transport.IdleTimeout = n
+	} else {
+		r.Tracker.Transport.IdleTimeout = 90 // seconds.
+	}
+	r.Tracker.Webhook.URL = os.Getenv(EnvTrackerWebhookURL)
+	s, found = os.LookupEnv(EnvTrackerWebhookWait)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Tracker.Webhook.Timeout = n
+	} else {
+		r.Tracker.Webhook.Timeout = 30 // seconds.
+	}
+	r.Application.Unique, found = os.LookupEnv(EnvApplicationUnique)
+	if !found {
+		r.Application.Unique = "name"
+	}
+	s, found = os.LookupEnv(EnvApplicationBinary)
+	if found {
+		n, _ := strconv.Atoi(s)
+		r.Application.BinaryLimit = n
+	} else {
+		r.Application.BinaryLimit = 500 // MB.
+	}
+	r.Scm.KnownHosts = os.Getenv(EnvScmKnownHosts)
+	r.Tracker.Unique, found = os.LookupEnv(EnvTrackerUnique)
+	if !found {
+		r.Tracker.Unique = "name"
+	}
+	r.Tracker.Default.Kind = os.Getenv(EnvTrackerKind)
+	s, found = os.LookupEnv(EnvTrackerMetadata)
+	if found {
+		err = json.Unmarshal([]byte(s), &r.Tracker.Default.Metadata)
+		if err != nil {
+			return
+		}
+	}
 
 	return
 }
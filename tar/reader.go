@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	pathlib "path"
+	"strings"
 
 	liberr "github.com/jortel/go-utils/error"
 	"github.com/konveyor/tackle2-hub/nas"
@@ -49,6 +50,11 @@ func (r *Reader) Extract(outDir string, reader io.Reader) (err error) {
 			}
 		}
 		path := pathlib.Join(outDir, header.Name)
+		if path != pathlib.Clean(outDir) &&
+			!strings.HasPrefix(path, pathlib.Clean(outDir)+"/") {
+			err = liberr.New("Path: '" + header.Name + "' not within the destination.")
+			return
+		}
 		if !r.Filter.Match(path) {
 			return
 		}
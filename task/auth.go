@@ -23,11 +23,15 @@ type Validator struct {
 //   - The token references a task.
 //   - The task is valid and running.
 //   - The task pod valid and pending|running.
+//
+// Or, when the token references a (pool) pod:
+//   - The pod valid and pending|running.
 func (r *Validator) Valid(token *jwt.Token, db *gorm.DB) (err error) {
 	claims := token.Claims.(jwt.MapClaims)
 	v, found := claims["task"]
 	id, cast := v.(float64)
 	if !found || !cast {
+		err = r.poolValid(token)
 		return
 	}
 	task := &model.Task{}
@@ -85,3 +89,44 @@ func (r *Validator) Valid(token *jwt.Token, db *gorm.DB) (err error) {
 	}
 	return
 }
+
+// poolValid validates tokens referencing a (pool) pod.
+func (r *Validator) poolValid(token *jwt.Token) (err error) {
+	claims := token.Claims.(jwt.MapClaims)
+	v, found := claims["pod"]
+	ref, cast := v.(string)
+	if !found || !cast {
+		return
+	}
+	pod := &core.Pod{}
+	err = r.Client.Get(
+		context.TODO(),
+		k8s.ObjectKey{
+			Namespace: path.Dir(ref),
+			Name:      path.Base(ref),
+		},
+		pod)
+	if err != nil {
+		err = &auth.NotValid{
+			Token: token.Raw,
+			Reason: fmt.Sprintf(
+				"Pod (%s) referenced by token: not found.",
+				ref),
+		}
+		return
+	}
+	switch pod.Status.Phase {
+	case core.PodPending,
+		core.PodRunning:
+	default:
+		err = &auth.NotValid{
+			Token: token.Raw,
+			Reason: fmt.Sprintf(
+				"Pod (%s) referenced by token: not pending|running. Phase detected: %s",
+				ref,
+				pod.Status.Phase),
+		}
+		return
+	}
+	return
+}
@@ -0,0 +1,193 @@
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron macros.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// CronError reports an invalid cron expression.
+type CronError struct {
+	Expression string
+	Reason     string
+}
+
+func (e *CronError) Error() (s string) {
+	return fmt.Sprintf("Cron: '%s' not valid: %s", e.Expression, e.Reason)
+}
+
+func (e *CronError) Is(err error) (matched bool) {
+	_, matched = err.(*CronError)
+	return
+}
+
+// Cron schedule.
+// Parsed from the standard (5 field) expression:
+// minute hour day-of-month month day-of-week.
+// Each field supports: *, lists, ranges and steps.
+type Cron struct {
+	minute map[int]bool
+	hour   map[int]bool
+	dom    map[int]bool
+	month  map[int]bool
+	dow    map[int]bool
+	// day-of-month restricted.
+	domSet bool
+	// day-of-week restricted.
+	dowSet bool
+}
+
+// ParseCron parses the cron expression.
+func ParseCron(expression string) (cron *Cron, err error) {
+	spec := strings.TrimSpace(expression)
+	if macro, found := cronMacros[spec]; found {
+		spec = macro
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		err = &CronError{
+			Expression: expression,
+			Reason:     "expected 5 fields.",
+		}
+		return
+	}
+	cron = &Cron{
+		domSet: fields[2] != "*",
+		dowSet: fields[4] != "*",
+	}
+	parsed := []struct {
+		set      *map[int]bool
+		min, max int
+	}{
+		{set: &cron.minute, min: 0, max: 59},
+		{set: &cron.hour, min: 0, max: 23},
+		{set: &cron.dom, min: 1, max: 31},
+		{set: &cron.month, min: 1, max: 12},
+		{set: &cron.dow, min: 0, max: 7},
+	}
+	for i, p := range parsed {
+		*p.set, err = cron.field(fields[i], p.min, p.max)
+		if err != nil {
+			err = &CronError{
+				Expression: expression,
+				Reason:     err.Error(),
+			}
+			cron = nil
+			return
+		}
+	}
+	if cron.dow[7] {
+		cron.dow[0] = true
+	}
+	return
+}
+
+// Next returns the next (minute) time after the specified time
+// matched by the schedule.
+func (r *Cron) Next(after time.Time) (next time.Time) {
+	next = after.Truncate(time.Minute).Add(time.Minute)
+	limit := next.AddDate(5, 0, 0)
+	for next.Before(limit) {
+		if !r.month[int(next.Month())] {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if !r.day(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if !r.hour[next.Hour()] {
+			next = next.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if !r.minute[next.Minute()] {
+			next = next.Add(time.Minute)
+			continue
+		}
+		return
+	}
+	next = time.Time{}
+	return
+}
+
+// day returns true when the day is matched.
+// When both the day-of-month and day-of-week are
+// restricted, either may match.
+func (r *Cron) day(t time.Time) (matched bool) {
+	dom := r.dom[t.Day()]
+	dow := r.dow[int(t.Weekday())]
+	if r.domSet && r.dowSet {
+		matched = dom || dow
+	} else {
+		matched = dom && dow
+	}
+	return
+}
+
+// field parses a field.
+func (r *Cron) field(s string, min, max int) (set map[int]bool, err error) {
+	set = map[int]bool{}
+	for _, part := range strings.Split(s, ",") {
+		step := 1
+		part, stepStr, stepped := strings.Cut(part, "/")
+		if stepped {
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step < 1 {
+				err = fmt.Errorf("step '%s' not valid.", stepStr)
+				return
+			}
+		}
+		begin, end := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			lower, upper, _ := strings.Cut(part, "-")
+			begin, err = r.number(lower, min, max)
+			if err != nil {
+				return
+			}
+			end, err = r.number(upper, min, max)
+			if err != nil {
+				return
+			}
+			if begin > end {
+				err = fmt.Errorf("range '%s' not valid.", part)
+				return
+			}
+		default:
+			begin, err = r.number(part, min, max)
+			if err != nil {
+				return
+			}
+			if !stepped {
+				end = begin
+			}
+		}
+		for n := begin; n <= end; n += step {
+			set[n] = true
+		}
+	}
+	return
+}
+
+// number parses a number within the range.
+func (r *Cron) number(s string, min, max int) (n int, err error) {
+	n, err = strconv.Atoi(s)
+	if err != nil || n < min || n > max {
+		err = fmt.Errorf("'%s' must be (%d-%d).", s, min, max)
+		return
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+func TestCron(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	after := time.Date(2023, time.March, 15, 10, 30, 20, 0, time.UTC)
+	cases := []struct {
+		expression string
+		next       time.Time
+	}{
+		{
+			expression: "* * * * *",
+			next:       time.Date(2023, time.March, 15, 10, 31, 0, 0, time.UTC),
+		},
+		{
+			expression: "@daily",
+			next:       time.Date(2023, time.March, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			expression: "*/15 9-17 * * *",
+			next:       time.Date(2023, time.March, 15, 10, 45, 0, 0, time.UTC),
+		},
+		{
+			expression: "0 2 * * 6,7",
+			next:       time.Date(2023, time.March, 18, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			expression: "0 0 1 */6 *",
+			next:       time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			// Either day-of-month or day-of-week.
+			expression: "0 0 20 * 5",
+			next:       time.Date(2023, time.March, 17, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, c := range cases {
+		cron, err := ParseCron(c.expression)
+		g.Expect(err).To(gomega.BeNil(), c.expression)
+		g.Expect(cron.Next(after)).To(gomega.Equal(c.next), c.expression)
+	}
+	// Never matched.
+	cron, err := ParseCron("0 0 30 2 *")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(cron.Next(after).IsZero()).To(gomega.BeTrue())
+	// Not valid.
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		_, err := ParseCron(expression)
+		g.Expect(errors.Is(err, &CronError{})).To(gomega.BeTrue(), expression)
+	}
+}
@@ -0,0 +1,105 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+// DependencyError reports (group) task dependencies not valid.
+type DependencyError struct {
+	Task   string
+	Reason string
+}
+
+func (e *DependencyError) Error() (s string) {
+	return fmt.Sprintf("Task: '%s' dependencies not valid: %s", e.Task, e.Reason)
+}
+
+func (e *DependencyError) Is(err error) (matched bool) {
+	_, matched = err.(*DependencyError)
+	return
+}
+
+// Ordered returns the (group) tasks topologically ordered
+// by dependencies. Dependencies are the names of other tasks in
+// the group. Otherwise, the declared order is preserved.
+func Ordered(tasks []model.Task) (ordered []model.Task, err error) {
+	index := map[string]int{}
+	depends := make([][]string, len(tasks))
+	declared := false
+	for i := range tasks {
+		task := &tasks[i]
+		if task.DependsOn != nil {
+			err = json.Unmarshal(task.DependsOn, &depends[i])
+			if err != nil {
+				err = &DependencyError{Task: task.Name, Reason: err.Error()}
+				return
+			}
+		}
+		if len(depends[i]) > 0 {
+			declared = true
+		}
+		if _, found := index[task.Name]; found {
+			index[task.Name] = -1
+			continue
+		}
+		index[task.Name] = i
+	}
+	if !declared {
+		ordered = tasks
+		return
+	}
+	for i := range tasks {
+		task := &tasks[i]
+		if index[task.Name] < 0 {
+			err = &DependencyError{Task: task.Name, Reason: "name must be unique."}
+			return
+		}
+		for _, name := range depends[i] {
+			if _, found := index[name]; !found {
+				err = &DependencyError{
+					Task:   task.Name,
+					Reason: fmt.Sprintf("'%s' not found.", name),
+				}
+				return
+			}
+			if name == task.Name {
+				err = &DependencyError{Task: task.Name, Reason: "depends on itself."}
+				return
+			}
+		}
+	}
+	added := make([]bool, len(tasks))
+	for len(ordered) < len(tasks) {
+		progress := false
+		for i := range tasks {
+			if added[i] {
+				continue
+			}
+			satisfied := true
+			for _, name := range depends[i] {
+				if !added[index[name]] {
+					satisfied = false
+					break
+				}
+			}
+			if satisfied {
+				ordered = append(ordered, tasks[i])
+				added[i] = true
+				progress = true
+			}
+		}
+		if !progress {
+			for i := range tasks {
+				if !added[i] {
+					err = &DependencyError{Task: tasks[i].Name, Reason: "cycle detected."}
+					ordered = nil
+					return
+				}
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,46 @@
+package task
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestOrdered(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	names := func(tasks []model.Task) (list []string) {
+		for _, task := range tasks {
+			list = append(list, task.Name)
+		}
+		return
+	}
+	tasks := []model.Task{
+		{Name: "analysis", DependsOn: []byte(`["discovery","language"]`)},
+		{Name: "discovery"},
+		{Name: "language", DependsOn: []byte(`["discovery"]`)},
+		{Name: "other"},
+	}
+	ordered, err := Ordered(tasks)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(names(ordered)).To(gomega.Equal([]string{"discovery", "language", "other", "analysis"}))
+
+	// No dependencies.
+	tasks = []model.Task{{Name: "b"}, {Name: "a"}, {Name: "a"}}
+	ordered, err = Ordered(tasks)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(names(ordered)).To(gomega.Equal([]string{"b", "a", "a"}))
+
+	// Not valid.
+	invalid := [][]model.Task{
+		{{Name: "a", DependsOn: []byte(`["b"]`)}, {Name: "b", DependsOn: []byte(`["a"]`)}},
+		{{Name: "a", DependsOn: []byte(`["c"]`)}, {Name: "b"}},
+		{{Name: "a", DependsOn: []byte(`["a"]`)}},
+		{{Name: "a", DependsOn: []byte(`["b"]`)}, {Name: "b"}, {Name: "b"}},
+	}
+	for _, tasks := range invalid {
+		_, err = Ordered(tasks)
+		g.Expect(errors.Is(err, &DependencyError{})).To(gomega.BeTrue())
+	}
+}
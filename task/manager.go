@@ -2,10 +2,12 @@ package task
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"path"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -17,6 +19,7 @@ import (
 	"github.com/konveyor/tackle2-hub/model"
 	"github.com/konveyor/tackle2-hub/settings"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	core "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,15 +38,32 @@ const (
 	Canceled  = "Canceled"
 )
 
+// Events (in addition to states).
+const (
+	EventPodCreated   = "PodCreated"
+	EventPodScheduled = "PodScheduled"
+	EventError        = "Error"
+	EventRetry        = "Retry"
+	EventTimeout      = "Timeout"
+	EventPreempted    = "Preempted"
+)
+
 // Policies
 const (
 	Isolated = "isolated"
 )
 
+// HubAddon the (empty) addon of tasks run (in-process) by the hub.
+// Hub tasks have no pod and are not scheduled by the manager.
+const HubAddon = ""
+
 const (
 	Unit = time.Second
 )
 
+// MainContainer the (addon) task pod container name.
+const MainContainer = "main"
+
 var (
 	Settings = &settings.Settings
 	Log      = logr.WithName("task-scheduler")
@@ -64,6 +84,21 @@ func (e *AddonNotFound) Is(err error) (matched bool) {
 	return
 }
 
+// QuotaExceeded used to report the task pod cannot
+// be created because the (namespace) quota is exhausted.
+type QuotaExceeded struct {
+	Reason string
+}
+
+func (e *QuotaExceeded) Error() (s string) {
+	return fmt.Sprintf("Quota exceeded: %s", e.Reason)
+}
+
+func (e *QuotaExceeded) Is(err error) (matched bool) {
+	_, matched = err.(*QuotaExceeded)
+	return
+}
+
 // Manager provides task management.
 type Manager struct {
 	// DB
@@ -72,6 +107,12 @@ type Manager struct {
 	Client k8s.Client
 	// Addon token scopes.
 	Scopes []string
+	// Pool of warm addon pods.
+	pool *Pool
+	// Pods of preempted tasks (being terminated).
+	preempted map[string]bool
+	// Ready tasks blocked by the (namespace) quota.
+	blocked bool
 }
 
 // Run the manager.
@@ -81,6 +122,8 @@ func (m *Manager) Run(ctx context.Context) {
 		&Validator{
 			Client: m.Client,
 		})
+	m.pool = &Pool{Client: m.Client}
+	m.orphaned()
 	go func() {
 		Log.Info("Started.")
 		defer Log.Info("Done.")
@@ -90,13 +133,42 @@ func (m *Manager) Run(ctx context.Context) {
 				return
 			default:
 				m.updateRunning()
+				m.startScheduled()
 				m.startReady()
+				m.fillPool()
 				m.pause()
 			}
 		}
 	}()
 }
 
+// orphaned fails the (running) hub tasks orphaned by a restart.
+// Hub tasks run in-process and cannot be resumed.
+func (m *Manager) orphaned() {
+	list := []model.Task{}
+	db := m.DB.Where("Addon", HubAddon)
+	db = db.Where("State", Running)
+	err := db.Find(&list).Error
+	if err != nil {
+		Log.Error(err, "")
+		return
+	}
+	for i := range list {
+		task := &list[i]
+		mark := time.Now()
+		task.State = Failed
+		task.Terminated = &mark
+		task.Error("Error", "Orphaned: hub restarted.")
+		task.Event(Failed, "Orphaned: hub restarted.")
+		err = m.DB.Save(task).Error
+		if err != nil {
+			Log.Error(err, "")
+			continue
+		}
+		Log.Info("Orphaned (hub) task failed.", "id", task.ID)
+	}
+}
+
 // Pause.
 func (m *Manager) pause() {
 	d := Unit * time.Duration(Settings.Frequency.Task)
@@ -107,6 +179,7 @@ func (m *Manager) pause() {
 func (m *Manager) startReady() {
 	list := []model.Task{}
 	db := m.DB.Order("priority DESC, id")
+	db = db.Where("Addon != ?", HubAddon)
 	result := db.Find(
 		&list,
 		"state IN ?",
@@ -120,6 +193,7 @@ func (m *Manager) startReady() {
 	if result.Error != nil {
 		return
 	}
+	m.blocked = false
 	for i := range list {
 		task := &list[i]
 		if Settings.Disconnected {
@@ -139,8 +213,24 @@ func (m *Manager) startReady() {
 		case Ready,
 			Postponed:
 			ready := task
-			if m.postpone(ready, list) {
+			satisfied, err := m.dependencies(ready)
+			if err != nil {
+				Log.Error(err, "")
+				continue
+			}
+			if ready.State == Failed {
+				Log.Info("Task dependency failed.", "id", ready.ID)
+				sErr := m.DB.Save(ready).Error
+				Log.Error(sErr, "")
+				continue
+			}
+			reason := "Dependencies not satisfied."
+			if satisfied {
+				reason = m.postpone(ready, list)
+			}
+			if reason != "" {
 				ready.State = Postponed
+				ready.Event(Postponed, reason)
 				Log.Info("Task postponed.", "id", ready.ID)
 				sErr := m.DB.Save(ready).Error
 				Log.Error(sErr, "")
@@ -149,15 +239,40 @@ func (m *Manager) startReady() {
 			if ready.Retries == 0 {
 				metrics.TasksInitiated.Inc()
 			}
+			if m.dispatched(ready) {
+				Log.Info("Task dispatched to pool.", "id", ready.ID)
+				sErr := m.DB.Save(ready).Error
+				Log.Error(sErr, "")
+				continue
+			}
 			rt := Task{ready}
-			err := rt.Run(m.Client)
+			err = rt.Run(m.Client)
 			if err != nil {
 				if errors.Is(err, &AddonNotFound{}) {
 					ready.Error("Error", err.Error())
+					ready.Event(EventError, err.Error())
 					ready.State = Failed
 					sErr := m.DB.Save(ready).Error
 					Log.Error(sErr, "")
 				}
+				if errors.Is(err, &QuotaExceeded{}) {
+					Log.Info("Task quota exceeded.", "id", ready.ID)
+					status, _ := json.Marshal(
+						model.TaskPodStatus{
+							Message: err.Error(),
+						})
+					db := m.DB.Model(ready)
+					sErr := db.Update("PodStatus", status).Error
+					Log.Error(sErr, "")
+					m.blocked = true
+					if m.released() {
+						continue
+					}
+					if Settings.Hub.Task.Preemption {
+						m.preempt(ready, list)
+					}
+					continue
+				}
 				Log.Error(err, "")
 				continue
 			}
@@ -172,10 +287,145 @@ func (m *Manager) startReady() {
 	}
 }
 
+// dispatched returns true when the ready task has been
+// dispatched to a (warm) pool pod.
+func (m *Manager) dispatched(ready *model.Task) (claimed bool) {
+	if m.pool == nil || Settings.Hub.Task.Pool < 1 {
+		return
+	}
+	claimed, err := m.pool.Claim(ready)
+	Log.Error(err, "")
+	return
+}
+
+// released returns true when an idle pool pod has been
+// released (deleted) to free the quota.
+func (m *Manager) released() (released bool) {
+	if m.pool == nil || Settings.Hub.Task.Pool < 1 {
+		return
+	}
+	released, err := m.pool.Release()
+	Log.Error(err, "")
+	return
+}
+
+// fillPool creates pool pods as needed.
+// The pool is not filled while ready tasks are blocked by the quota.
+func (m *Manager) fillPool() {
+	if Settings.Disconnected || m.blocked {
+		return
+	}
+	var active int64
+	db := m.DB.Model(&model.Task{})
+	db = db.Where("State IN ?", []string{Pending, Running})
+	err := db.Count(&active).Error
+	if err != nil {
+		Log.Error(err, "")
+		return
+	}
+	err = m.pool.Fill(int(active))
+	Log.Error(err, "")
+}
+
+// startScheduled expands (due) task schedules into ready tasks.
+// A task is created for each application (when specified).
+func (m *Manager) startScheduled() {
+	list := []model.TaskSchedule{}
+	db := m.DB.Preload("Applications")
+	result := db.Find(&list, "Paused", false)
+	Log.Error(result.Error, "")
+	if result.Error != nil {
+		return
+	}
+	now := time.Now()
+	for i := range list {
+		schedule := &list[i]
+		cron, err := ParseCron(schedule.Cron)
+		if err != nil {
+			Log.Error(err, "", "schedule", schedule.ID)
+			continue
+		}
+		if schedule.NextRun != nil && schedule.NextRun.After(now) {
+			continue
+		}
+		due := schedule.NextRun != nil
+		next := cron.Next(now)
+		if next.IsZero() {
+			Log.Info("Schedule never matched (paused).", "schedule", schedule.ID)
+			schedule.Paused = true
+			schedule.NextRun = nil
+			db := m.DB.Omit(clause.Associations)
+			err = db.Save(schedule).Error
+			Log.Error(err, "")
+			continue
+		}
+		schedule.NextRun = &next
+		if due {
+			err = m.expand(schedule)
+			if err != nil {
+				Log.Error(err, "", "schedule", schedule.ID)
+				continue
+			}
+			schedule.LastRun = &now
+		}
+		db := m.DB.Omit(clause.Associations)
+		err = db.Save(schedule).Error
+		Log.Error(err, "")
+	}
+}
+
+// expand the schedule into ready tasks.
+func (m *Manager) expand(schedule *model.TaskSchedule) (err error) {
+	var tasks []model.Task
+	for i := range schedule.Applications {
+		app := &schedule.Applications[i]
+		task := Scheduled(schedule)
+		task.Name = fmt.Sprintf("%s.%s", schedule.Name, app.Name)
+		task.ApplicationID = &app.ID
+		tasks = append(tasks, task)
+	}
+	if len(tasks) == 0 {
+		tasks = append(tasks, Scheduled(schedule))
+	}
+	err = m.DB.Transaction(func(tx *gorm.DB) (err error) {
+		for i := range tasks {
+			task := &tasks[i]
+			err = tx.Create(task).Error
+			if err != nil {
+				return
+			}
+			Log.Info(
+				"Scheduled task created.",
+				"id",
+				task.ID,
+				"schedule",
+				schedule.ID)
+		}
+		return
+	})
+	return
+}
+
+// Scheduled returns a (ready) task built from the schedule.
+func Scheduled(schedule *model.TaskSchedule) (task model.Task) {
+	task = model.Task{
+		Name:       schedule.Name,
+		Addon:      schedule.Addon,
+		Variant:    schedule.Variant,
+		Priority:   schedule.Priority,
+		Policy:     schedule.Policy,
+		Data:       schedule.Data,
+		State:      Ready,
+		ScheduleID: &schedule.ID,
+	}
+	return
+}
+
 // updateRunning tasks to reflect pod state.
 func (m *Manager) updateRunning() {
 	list := []model.Task{}
 	db := m.DB.Order("priority DESC, id")
+	db = db.Where("Addon != ?", HubAddon)
 	result := db.Find(
 		&list,
 		"state IN ?",
@@ -198,6 +448,13 @@ func (m *Manager) updateRunning() {
 			Log.Error(err, "")
 			continue
 		}
+		if rt.timedOut() {
+			err = rt.Kill(m.Client)
+			if err != nil {
+				Log.Error(err, "")
+				continue
+			}
+		}
 		err = m.DB.Save(&running).Error
 		if err != nil {
 			Log.Error(result.Error, "")
@@ -207,8 +464,53 @@ func (m *Manager) updateRunning() {
 	}
 }
 
+// dependencies determines whether the dependencies of a (group)
+// task have succeeded. The task is failed when a dependency has
+// failed or been canceled.
+func (m *Manager) dependencies(ready *model.Task) (satisfied bool, err error) {
+	var names []string
+	if ready.DependsOn != nil {
+		_ = json.Unmarshal(ready.DependsOn, &names)
+	}
+	if len(names) == 0 || ready.TaskGroupID == nil {
+		satisfied = true
+		return
+	}
+	list := []model.Task{}
+	db := m.DB.Where("TaskGroupID", *ready.TaskGroupID)
+	db = db.Where("Name IN ?", names)
+	err = db.Find(&list).Error
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	succeeded := 0
+	for i := range list {
+		dep := &list[i]
+		switch dep.State {
+		case Succeeded:
+			succeeded++
+		case Failed,
+			Canceled:
+			mark := time.Now()
+			ready.State = Failed
+			ready.Terminated = &mark
+			ready.Error(
+				"Error",
+				"Dependency: '%s' %s.",
+				dep.Name,
+				strings.ToLower(dep.State))
+			ready.Event(Failed, "Dependency: '%s' %s.", dep.Name, strings.ToLower(dep.State))
+			return
+		}
+	}
+	satisfied = succeeded == len(names)
+	return
+}
+
 // postpone Postpones a task as needed based on rules.
-func (m *Manager) postpone(ready *model.Task, list []model.Task) (postponed bool) {
+// Returns the reason the task is postponed or "".
+func (m *Manager) postpone(ready *model.Task, list []model.Task) (reason string) {
 	ruleSet := []Rule{
 		&RuleIsolated{},
 		&RuleUnique{},
@@ -223,13 +525,101 @@ func (m *Manager) postpone(ready *model.Task, list []model.Task) (postponed bool
 			Pending:
 			for _, rule := range ruleSet {
 				if rule.Match(ready, other) {
-					postponed = true
+					reason = rule.Reason(other)
 					return
 				}
 			}
 		}
 	}
+	quota := Quota{
+		Pods:        Settings.Hub.Task.Quota.Pods,
+		Application: Settings.Hub.Task.Quota.Application,
+	}
+	_, reason = quota.Exceeded(ready, list)
+	return
+}
 
+// preempt a (pending or running) task with a lower priority
+// than the ready task. The lowest priority (most recently created) task
+// is preempted and rescheduled so its pod quota may be released.
+func (m *Manager) preempt(ready *model.Task, list []model.Task) {
+	if m.terminating() {
+		Log.Info("Task preemption postponed (pod terminating).", "id", ready.ID)
+		return
+	}
+	victim := Preemptable(ready, list)
+	if victim == nil {
+		return
+	}
+	pod := victim.Pod
+	rt := Task{victim}
+	err := rt.Preempt(m.Client)
+	Log.Error(err, "")
+	if err != nil {
+		return
+	}
+	if pod != "" {
+		if m.preempted == nil {
+			m.preempted = map[string]bool{}
+		}
+		m.preempted[pod] = true
+	}
+	err = m.DB.Save(victim).Error
+	Log.Error(err, "")
+	Log.Info(
+		"Task preempted.",
+		"id",
+		victim.ID,
+		"by",
+		ready.ID)
+}
+
+// terminating returns true when the pod of a preempted task
+// has not been deleted. The terminating pod is counted against
+// the quota so another task is not preempted until deleted.
+func (m *Manager) terminating() (found bool) {
+	for name := range m.preempted {
+		pod := &core.Pod{}
+		err := m.Client.Get(
+			context.TODO(),
+			k8s.ObjectKey{
+				Namespace: path.Dir(name),
+				Name:      path.Base(name),
+			},
+			pod)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				delete(m.preempted, name)
+				continue
+			}
+			Log.Error(err, "")
+		}
+		found = true
+	}
+	return
+}
+
+// Preemptable returns the (pending or running) task that may
+// be preempted by the ready task. Returns nil when none has a lower
+// priority. The lowest priority (most recently created) task is selected.
+func Preemptable(ready *model.Task, list []model.Task) (victim *model.Task) {
+	for i := range list {
+		other := &list[i]
+		switch other.State {
+		case Running,
+			Pending:
+		default:
+			continue
+		}
+		if other.Priority >= ready.Priority {
+			continue
+		}
+		if victim == nil ||
+			other.Priority < victim.Priority ||
+			(other.Priority == victim.Priority && other.ID > victim.ID) {
+			victim = other
+		}
+	}
 	return
 }
 
@@ -288,7 +678,11 @@ func (r *Task) Run(client k8s.Client) (err error) {
 	pod := r.pod(addon, owner, &secret)
 	err = client.Create(context.TODO(), &pod)
 	if err != nil {
-		err = liberr.Wrap(err)
+		if k8serr.IsForbidden(err) && strings.Contains(err.Error(), "exceeded quota") {
+			err = &QuotaExceeded{Reason: err.Error()}
+		} else {
+			err = liberr.Wrap(err)
+		}
 		return
 	}
 	defer func() {
@@ -314,6 +708,7 @@ func (r *Task) Run(client k8s.Client) (err error) {
 	r.Pod = path.Join(
 		pod.Namespace,
 		pod.Name)
+	r.Event(EventPodCreated, "Pod: %s", r.Pod)
 	return
 }
 
@@ -335,37 +730,172 @@ func (r *Task) Reflect(client k8s.Client) (err error) {
 		}
 		return
 	}
+	r.podStatus(pod)
 	mark := time.Now()
 	status := pod.Status
 	switch status.Phase {
+	case core.PodPending:
+		r.scheduled(pod)
+		reason := r.transient(pod)
+		if reason != "" {
+			r.retry(client, pod, reason)
+		}
 	case core.PodRunning:
+		if r.State != Running {
+			r.scheduled(pod)
+			r.Event(Running, "")
+		}
 		r.State = Running
 	case core.PodSucceeded:
 		r.State = Succeeded
 		r.Terminated = &mark
+		r.Event(Succeeded, "")
 	case core.PodFailed:
+		reason := r.transient(pod)
+		if reason != "" && r.retry(client, pod, reason) {
+			break
+		}
+		if reason == "" {
+			reason = status.Reason
+			if len(status.ContainerStatuses) > 0 {
+				state := status.ContainerStatuses[0].State
+				if state.Terminated != nil {
+					reason = state.Terminated.Reason
+				}
+			}
+		}
 		r.Error(
 			"Error",
 			"Pod failed: %s",
-			pod.Status.ContainerStatuses[0].State.Terminated.Reason)
-		switch pod.Status.ContainerStatuses[0].State.Terminated.ExitCode {
-		case 137: // Killed.
-			if r.Retries < Settings.Hub.Task.Retries {
-				_ = client.Delete(context.TODO(), pod)
-				r.Pod = ""
-				r.State = Ready
-				r.Errors = nil
-				r.Retries++
-			} else {
-				r.State = Failed
-				r.Terminated = &mark
+			reason)
+		r.Event(Failed, "Pod failed: %s", reason)
+		r.State = Failed
+		r.Terminated = &mark
+	}
+
+	return
+}
+
+// podStatus records the observed pod phase, container
+// statuses and the message of the last (transitioned) condition.
+func (r *Task) podStatus(pod *core.Pod) {
+	status := model.TaskPodStatus{
+		Phase:   string(pod.Status.Phase),
+		Message: pod.Status.Message,
+	}
+	var last *core.PodCondition
+	for i := range pod.Status.Conditions {
+		cnd := &pod.Status.Conditions[i]
+		if cnd.Message == "" {
+			continue
+		}
+		if last == nil || !cnd.LastTransitionTime.Before(&last.LastTransitionTime) {
+			last = cnd
+		}
+	}
+	if last != nil {
+		status.Message = last.Reason + ": " + last.Message
+	}
+	for _, container := range pod.Status.ContainerStatuses {
+		cs := model.TaskContainerStatus{
+			Name:     container.Name,
+			Ready:    container.Ready,
+			Restarts: container.RestartCount,
+		}
+		state := container.State
+		switch {
+		case state.Waiting != nil:
+			cs.State = "Waiting"
+			cs.Reason = state.Waiting.Reason
+			cs.Message = state.Waiting.Message
+		case state.Running != nil:
+			cs.State = "Running"
+		case state.Terminated != nil:
+			cs.State = "Terminated"
+			cs.Reason = state.Terminated.Reason
+			cs.Message = state.Terminated.Message
+			cs.ExitCode = state.Terminated.ExitCode
+		}
+		status.Containers = append(status.Containers, cs)
+	}
+	r.PodStatus, _ = json.Marshal(status)
+}
+
+// scheduled records the pod (scheduled) condition.
+func (r *Task) scheduled(pod *core.Pod) {
+	for _, cnd := range pod.Status.Conditions {
+		if cnd.Type != core.PodScheduled {
+			continue
+		}
+		if cnd.Status == core.ConditionTrue {
+			r.Event(EventPodScheduled, "Node: %s", pod.Spec.NodeName)
+		} else {
+			r.Event(EventPodScheduled, "%s: %s", cnd.Reason, cnd.Message)
+		}
+	}
+}
+
+// transient returns the reason the pod failed (or cannot start)
+// due to a transient (infrastructure) error. Returns "" when
+// not transient.
+func (r *Task) transient(pod *core.Pod) (reason string) {
+	status := pod.Status
+	if status.Reason == "Evicted" {
+		reason = status.Reason
+		return
+	}
+	for _, container := range status.ContainerStatuses {
+		state := container.State
+		switch {
+		case state.Waiting != nil:
+			switch state.Waiting.Reason {
+			case "ImagePullBackOff",
+				"ErrImagePull":
+				reason = state.Waiting.Reason
+				return
+			}
+		case state.Terminated != nil:
+			if state.Terminated.ExitCode == 137 { // Killed.
+				reason = state.Terminated.Reason
+				if reason == "" {
+					reason = "Killed"
+				}
+				return
 			}
-		default:
-			r.State = Failed
-			r.Terminated = &mark
 		}
 	}
+	return
+}
 
+// retry (resubmit) the task as permitted by the backoff limit.
+// The pod is deleted and the attempt is recorded in the events.
+// Returns false when the limit has been reached.
+func (r *Task) retry(client k8s.Client, pod *core.Pod, reason string) (retried bool) {
+	limit := Settings.Hub.Task.Retries
+	if r.BackoffLimit != nil {
+		limit = *r.BackoffLimit
+	}
+	if r.Retries >= limit {
+		return
+	}
+	_ = client.Delete(context.TODO(), pod)
+	r.Pod = ""
+	r.State = Ready
+	r.Errors = nil
+	r.Retries++
+	r.Event(
+		EventRetry,
+		"Pod failed: %s (attempt %d of %d).",
+		reason,
+		r.Retries,
+		limit)
+	Log.Info(
+		"Task retried.",
+		"id",
+		r.ID,
+		"reason",
+		reason)
+	retried = true
 	return
 }
 
@@ -398,6 +928,62 @@ func (r *Task) Delete(client k8s.Client) (err error) {
 	return
 }
 
+// timedOut returns true when the (pending or running) task has
+// exceeded the timeout. The task timeout (seconds) takes precedence
+// over the default defined by settings.
+func (r *Task) timedOut() (exceeded bool) {
+	switch r.State {
+	case Pending,
+		Running:
+	default:
+		return
+	}
+	if r.Started == nil {
+		return
+	}
+	timeout := Settings.Hub.Task.Timeout
+	if r.Timeout > 0 {
+		timeout = r.Timeout
+	}
+	if timeout < 1 {
+		return
+	}
+	d := time.Duration(timeout) * time.Second
+	exceeded = time.Since(*r.Started) > d
+	return
+}
+
+// Kill the (timed out) task.
+// The pod is deleted and the task is failed.
+func (r *Task) Kill(client k8s.Client) (err error) {
+	err = r.Delete(client)
+	if err != nil {
+		return
+	}
+	r.State = Failed
+	r.Error("Error", "Timeout: exceeded %s.", time.Since(*r.Started).Round(time.Second))
+	r.Event(EventTimeout, "Pod killed.")
+	Log.Info(
+		"Task timed out.",
+		"id",
+		r.ID)
+	return
+}
+
+// Preempt the task.
+// The pod is deleted and the task is rescheduled.
+func (r *Task) Preempt(client k8s.Client) (err error) {
+	err = r.Delete(client)
+	if err != nil {
+		return
+	}
+	r.State = Ready
+	r.Started = nil
+	r.Terminated = nil
+	r.Event(EventPreempted, "")
+	return
+}
+
 // Cancel the task.
 func (r *Task) Cancel(client k8s.Client) (err error) {
 	err = r.Delete(client)
@@ -406,6 +992,7 @@ func (r *Task) Cancel(client k8s.Client) (err error) {
 	}
 	r.State = Canceled
 	r.SetBucket(nil)
+	r.Event(Canceled, "")
 	Log.Info(
 		"Task canceled.",
 		"id",
@@ -495,6 +1082,8 @@ func (r *Task) specification(addon *crd.Addon, secret *core.Secret) (specificati
 	specification = core.PodSpec{
 		ServiceAccountName: Settings.Hub.Task.SA,
 		RestartPolicy:      core.RestartPolicyNever,
+		NodeSelector:       r.nodeSelector(addon),
+		Tolerations:        r.tolerations(addon),
 		Containers: []core.Container{
 			r.container(addon, secret),
 		},
@@ -506,6 +1095,66 @@ func (r *Task) specification(addon *crd.Addon, secret *core.Secret) (specificati
 	return
 }
 
+// nodeSelector returns the pod node selector.
+// The task node selector is merged over the addon.
+func (r *Task) nodeSelector(addon *crd.Addon) (selector map[string]string) {
+	selected := map[string]string{}
+	if r.NodeSelector != nil {
+		_ = json.Unmarshal(r.NodeSelector, &selected)
+	}
+	if len(addon.Spec.NodeSelector) == 0 && len(selected) == 0 {
+		return
+	}
+	selector = map[string]string{}
+	for k, v := range addon.Spec.NodeSelector {
+		selector[k] = v
+	}
+	for k, v := range selected {
+		selector[k] = v
+	}
+	return
+}
+
+// tolerations returns the pod tolerations.
+// The task tolerations are appended to the addon.
+func (r *Task) tolerations(addon *crd.Addon) (tolerations []core.Toleration) {
+	tolerations = append(tolerations, addon.Spec.Tolerations...)
+	if r.Tolerations != nil {
+		var list []core.Toleration
+		_ = json.Unmarshal(r.Tolerations, &list)
+		tolerations = append(tolerations, list...)
+	}
+	return
+}
+
+// resources returns the container resource requirements.
+// The task (named) limits and requests are merged over the addon.
+func (r *Task) resources(addon *crd.Addon) (resources core.ResourceRequirements) {
+	addon.Spec.Resources.DeepCopyInto(&resources)
+	if r.Resources == nil {
+		return
+	}
+	requested := core.ResourceRequirements{}
+	err := json.Unmarshal(r.Resources, &requested)
+	if err != nil {
+		Log.Error(err, "", "task", r.ID)
+		return
+	}
+	for name, q := range requested.Limits {
+		if resources.Limits == nil {
+			resources.Limits = core.ResourceList{}
+		}
+		resources.Limits[name] = q
+	}
+	for name, q := range requested.Requests {
+		if resources.Requests == nil {
+			resources.Requests = core.ResourceList{}
+		}
+		resources.Requests[name] = q
+	}
+	return
+}
+
 // container builds the pod container.
 func (r *Task) container(addon *crd.Addon, secret *core.Secret) (container core.Container) {
 	userid := int64(0)
@@ -514,10 +1163,10 @@ func (r *Task) container(addon *crd.Addon, secret *core.Secret) (container core.
 		policy = addon.Spec.ImagePullPolicy
 	}
 	container = core.Container{
-		Name:            "main",
+		Name:            MainContainer,
 		Image:           r.Image,
 		ImagePullPolicy: policy,
-		Resources:       addon.Spec.Resources,
+		Resources:       r.resources(addon),
 		Env: []core.EnvVar{
 			{
 				Name:  settings.EnvHubBaseURL,
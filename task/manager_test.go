@@ -0,0 +1,343 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/database"
+	crd "github.com/konveyor/tackle2-hub/k8s/api/tackle/v1alpha1"
+	v13 "github.com/konveyor/tackle2-hub/migration/v13"
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+	"gorm.io/gorm"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newDB returns a (migrated) test DB.
+func newDB(t *testing.T) (db *gorm.DB) {
+	g := gomega.NewGomegaWithT(t)
+	Settings.DB.Path = path.Join(t.TempDir(), "hub.db")
+	db, err := database.Open(true)
+	g.Expect(err).To(gomega.BeNil())
+	err = v13.Migration{}.Apply(db)
+	g.Expect(err).To(gomega.BeNil())
+	t.Cleanup(func() {
+		_ = database.Close(db)
+	})
+	return
+}
+
+func TestOrphaned(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	list := []model.Task{
+		{Name: "export", Addon: HubAddon, State: Running},
+		{Name: "done", Addon: HubAddon, State: Succeeded},
+		{Name: "analysis", Addon: "analyzer", State: Running},
+	}
+	for i := range list {
+		g.Expect(db.Create(&list[i]).Error).To(gomega.BeNil())
+	}
+	m := Manager{DB: db}
+	m.orphaned()
+	for i, state := range []string{Failed, Succeeded, Running} {
+		task := &model.Task{}
+		g.Expect(db.First(task, list[i].ID).Error).To(gomega.BeNil())
+		g.Expect(task.State).To(gomega.Equal(state), task.Name)
+	}
+}
+
+func TestPreemptable(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	ready := &model.Task{Priority: 10, State: Ready}
+	ready.ID = 9
+	list := []model.Task{
+		{Model: model.Model{ID: 1}, Priority: 5, State: Running},
+		{Model: model.Model{ID: 2}, Priority: 0, State: Running},
+		{Model: model.Model{ID: 3}, Priority: 0, State: Pending},
+		{Model: model.Model{ID: 4}, Priority: 0, State: Ready},
+		{Model: model.Model{ID: 5}, Priority: 10, State: Running},
+	}
+	victim := Preemptable(ready, list)
+	g.Expect(victim).ToNot(gomega.BeNil())
+	g.Expect(victim.ID).To(gomega.Equal(uint(3)))
+
+	// Equal priority not preempted.
+	ready.Priority = 0
+	g.Expect(Preemptable(ready, list)).To(gomega.BeNil())
+}
+
+func TestPreemptInFlight(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := newDB(t)
+	pod := func(name string) *core.Pod {
+		return &core.Pod{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace:  "konveyor",
+				Name:       name,
+				Finalizers: []string{"test"},
+			},
+		}
+	}
+	list := []model.Task{
+		{Name: "a", Priority: 0, State: Running, Pod: "konveyor/task-1"},
+		{Name: "b", Priority: 0, State: Running, Pod: "konveyor/task-2"},
+	}
+	for i := range list {
+		g.Expect(db.Create(&list[i]).Error).To(gomega.BeNil())
+	}
+	ready := &model.Task{Name: "c", Priority: 10, State: Ready}
+	g.Expect(db.Create(ready).Error).To(gomega.BeNil())
+	client := fake.NewClientBuilder().WithObjects(pod("task-1"), pod("task-2")).Build()
+	m := Manager{DB: db, Client: client}
+	// First pass.
+	m.preempt(ready, list)
+	g.Expect(list[1].State).To(gomega.Equal(Ready))
+	g.Expect(list[0].State).To(gomega.Equal(Running))
+	// Second pass: victim pod terminating.
+	m.preempt(ready, list)
+	g.Expect(list[0].State).To(gomega.Equal(Running))
+	// Victim pod deleted.
+	terminated := &core.Pod{}
+	key := k8s.ObjectKey{Namespace: "konveyor", Name: "task-2"}
+	g.Expect(client.Get(context.TODO(), key, terminated)).To(gomega.BeNil())
+	terminated.Finalizers = nil
+	g.Expect(client.Update(context.TODO(), terminated)).To(gomega.BeNil())
+	m.preempt(ready, list)
+	g.Expect(list[0].State).To(gomega.Equal(Ready))
+}
+
+func TestPodSpecification(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	addon := &crd.Addon{
+		Spec: crd.AddonSpec{
+			Resources: core.ResourceRequirements{
+				Limits: core.ResourceList{
+					core.ResourceCPU:    resource.MustParse("1"),
+					core.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+			NodeSelector: map[string]string{"zone": "a", "tier": "batch"},
+			Tolerations:  []core.Toleration{{Key: "addon", Operator: core.TolerationOpExists}},
+		},
+	}
+	task := &Task{
+		Task: &model.Task{
+			Resources:    []byte(`{"limits":{"memory":"4Gi"},"requests":{"memory":"2Gi"}}`),
+			NodeSelector: []byte(`{"zone":"b"}`),
+			Tolerations:  []byte(`[{"key":"big","operator":"Equal","value":"true","effect":"NoSchedule"}]`),
+		},
+	}
+	spec := task.specification(addon, &core.Secret{})
+	g.Expect(spec.NodeSelector).To(gomega.Equal(map[string]string{"zone": "b", "tier": "batch"}))
+	g.Expect(spec.Tolerations).To(gomega.HaveLen(2))
+	g.Expect(spec.Tolerations[1].Key).To(gomega.Equal("big"))
+	resources := spec.Containers[0].Resources
+	g.Expect(resources.Limits.Cpu().String()).To(gomega.Equal("1"))
+	g.Expect(resources.Limits.Memory().String()).To(gomega.Equal("4Gi"))
+	g.Expect(resources.Requests.Memory().String()).To(gomega.Equal("2Gi"))
+	// Addon not modified.
+	g.Expect(addon.Spec.Resources.Limits.Memory().String()).To(gomega.Equal("1Gi"))
+
+	// Addon defaults.
+	task = &Task{Task: &model.Task{}}
+	spec = task.specification(addon, &core.Secret{})
+	g.Expect(spec.NodeSelector).To(gomega.Equal(addon.Spec.NodeSelector))
+	g.Expect(spec.Containers[0].Resources).To(gomega.Equal(addon.Spec.Resources))
+}
+
+func TestReflectRetry(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	pod := func(status core.PodStatus) *core.Pod {
+		return &core.Pod{
+			ObjectMeta: meta.ObjectMeta{Namespace: "konveyor", Name: "task-1-abc"},
+			Status:     status,
+		}
+	}
+	evicted := core.PodStatus{Phase: core.PodFailed, Reason: "Evicted"}
+	limit := 1
+	task := &Task{
+		Task: &model.Task{
+			Pod:          "konveyor/task-1-abc",
+			State:        Running,
+			BackoffLimit: &limit,
+		},
+	}
+	client := fake.NewClientBuilder().WithObjects(pod(evicted)).Build()
+	err := task.Reflect(client)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(task.State).To(gomega.Equal(Ready))
+	g.Expect(task.Retries).To(gomega.Equal(1))
+	g.Expect(task.Pod).To(gomega.BeEmpty())
+	events := []model.TaskEvent{}
+	_ = json.Unmarshal(task.Events, &events)
+	g.Expect(events).To(gomega.HaveLen(1))
+	g.Expect(events[0].Kind).To(gomega.Equal("Retry"))
+	g.Expect(events[0].Reason).To(gomega.Equal("Pod failed: Evicted (attempt 1 of 1)."))
+	err = client.Get(context.TODO(), k8s.ObjectKey{Namespace: "konveyor", Name: "task-1-abc"}, &core.Pod{})
+	g.Expect(err).ToNot(gomega.BeNil())
+
+	// Limit reached.
+	task.Pod = "konveyor/task-1-abc"
+	client = fake.NewClientBuilder().WithObjects(pod(evicted)).Build()
+	err = task.Reflect(client)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(task.State).To(gomega.Equal(Failed))
+	g.Expect(task.Terminated).ToNot(gomega.BeNil())
+
+	// Image pull (default limit).
+	Settings.Hub.Task.Retries = 1
+	task = &Task{Task: &model.Task{Pod: "konveyor/task-1-abc", State: Pending}}
+	pulling := core.PodStatus{
+		Phase: core.PodPending,
+		ContainerStatuses: []core.ContainerStatus{
+			{State: core.ContainerState{Waiting: &core.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+		},
+	}
+	client = fake.NewClientBuilder().WithObjects(pod(pulling)).Build()
+	err = task.Reflect(client)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(task.State).To(gomega.Equal(Ready))
+
+	// Not transient.
+	task = &Task{Task: &model.Task{Pod: "konveyor/task-1-abc", State: Running}}
+	failed := core.PodStatus{
+		Phase: core.PodFailed,
+		ContainerStatuses: []core.ContainerStatus{
+			{State: core.ContainerState{Terminated: &core.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}}},
+		},
+	}
+	client = fake.NewClientBuilder().WithObjects(pod(failed)).Build()
+	err = task.Reflect(client)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(task.State).To(gomega.Equal(Failed))
+	g.Expect(task.Retries).To(gomega.BeZero())
+}
+
+func TestTimeout(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	started := time.Now().Add(-2 * time.Minute)
+	task := &Task{
+		Task: &model.Task{
+			Pod:     "konveyor/task-1-abc",
+			State:   Running,
+			Started: &started,
+			Timeout: 180,
+		},
+	}
+	g.Expect(task.timedOut()).To(gomega.BeFalse())
+	task.Timeout = 60
+	g.Expect(task.timedOut()).To(gomega.BeTrue())
+	pod := &core.Pod{ObjectMeta: meta.ObjectMeta{Namespace: "konveyor", Name: "task-1-abc"}}
+	client := fake.NewClientBuilder().WithObjects(pod).Build()
+	err := task.Kill(client)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(task.State).To(gomega.Equal(Failed))
+	g.Expect(task.Pod).To(gomega.BeEmpty())
+	g.Expect(task.Terminated).ToNot(gomega.BeNil())
+	err = client.Get(context.TODO(), k8s.ObjectKeyFromObject(pod), &core.Pod{})
+	g.Expect(err).ToNot(gomega.BeNil())
+	// Terminated.
+	g.Expect(task.timedOut()).To(gomega.BeFalse())
+	// Default.
+	task = &Task{Task: &model.Task{State: Pending, Started: &started}}
+	g.Expect(task.timedOut()).To(gomega.BeFalse())
+	Settings.Hub.Task.Timeout = 60
+	defer func() {
+		Settings.Hub.Task.Timeout = 0
+	}()
+	g.Expect(task.timedOut()).To(gomega.BeTrue())
+}
+
+func TestReflectEvents(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	pod := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{Namespace: "konveyor", Name: "task-1-abc"},
+		Status: core.PodStatus{
+			Phase: core.PodPending,
+			Conditions: []core.PodCondition{
+				{
+					Type:    core.PodScheduled,
+					Status:  core.ConditionFalse,
+					Reason:  "Unschedulable",
+					Message: "0/3 nodes are available.",
+				},
+			},
+		},
+	}
+	task := &Task{Task: &model.Task{Pod: "konveyor/task-1-abc", State: Pending}}
+	client := fake.NewClientBuilder().WithObjects(pod).Build()
+	for i := 0; i < 2; i++ {
+		err := task.Reflect(client)
+		g.Expect(err).To(gomega.BeNil())
+	}
+	pod.Spec.NodeName = "worker-1"
+	pod.Status.Phase = core.PodRunning
+	pod.Status.Conditions[0].Status = core.ConditionTrue
+	client = fake.NewClientBuilder().WithObjects(pod).Build()
+	for i := 0; i < 2; i++ {
+		err := task.Reflect(client)
+		g.Expect(err).To(gomega.BeNil())
+	}
+	events := []model.TaskEvent{}
+	_ = json.Unmarshal(task.Events, &events)
+	g.Expect(events).To(gomega.HaveLen(3))
+	g.Expect(events[0].Kind).To(gomega.Equal(EventPodScheduled))
+	g.Expect(events[0].Reason).To(gomega.Equal("Unschedulable: 0/3 nodes are available."))
+	g.Expect(events[1].Reason).To(gomega.Equal("Node: worker-1"))
+	g.Expect(events[2].Kind).To(gomega.Equal(Running))
+}
+
+func TestReflectPodStatus(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	earlier := meta.NewTime(time.Now().Add(-time.Minute))
+	pod := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{Namespace: "konveyor", Name: "task-1-abc"},
+		Status: core.PodStatus{
+			Phase: core.PodPending,
+			Conditions: []core.PodCondition{
+				{
+					Type:               core.PodInitialized,
+					Status:             core.ConditionTrue,
+					Reason:             "Initialized",
+					Message:            "init done.",
+					LastTransitionTime: earlier,
+				},
+				{
+					Type:               core.PodScheduled,
+					Status:             core.ConditionFalse,
+					Reason:             "Unschedulable",
+					Message:            "0/3 nodes are available.",
+					LastTransitionTime: meta.Now(),
+				},
+			},
+			ContainerStatuses: []core.ContainerStatus{
+				{
+					Name: MainContainer,
+					State: core.ContainerState{
+						Waiting: &core.ContainerStateWaiting{Reason: "ContainerCreating"},
+					},
+				},
+			},
+		},
+	}
+	task := &Task{Task: &model.Task{Pod: "konveyor/task-1-abc", State: Pending}}
+	client := fake.NewClientBuilder().WithObjects(pod).Build()
+	err := task.Reflect(client)
+	g.Expect(err).To(gomega.BeNil())
+	status := model.TaskPodStatus{}
+	_ = json.Unmarshal(task.PodStatus, &status)
+	g.Expect(status.Phase).To(gomega.Equal("Pending"))
+	g.Expect(status.Message).To(gomega.Equal("Unschedulable: 0/3 nodes are available."))
+	g.Expect(status.Containers).To(gomega.Equal(
+		[]model.TaskContainerStatus{
+			{Name: MainContainer, State: "Waiting", Reason: "ContainerCreating"},
+		}))
+}
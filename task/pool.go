@@ -0,0 +1,288 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	liberr "github.com/jortel/go-utils/error"
+	"github.com/konveyor/tackle2-hub/auth"
+	crd "github.com/konveyor/tackle2-hub/k8s/api/tackle/v1alpha1"
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/konveyor/tackle2-hub/settings"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PoolRole the (role) label of pool pods.
+const PoolRole = "pool"
+
+// Pool of warm (idle) addon pods.
+// When enabled (size > 0), idle pods are maintained for each addon
+// and ready tasks are dispatched to them rather than creating a pod.
+// Pool pods are started without a task. The addon (pool mode indicated
+// by the TASK_POOL environment variable) finds the task dispatched to
+// the pod using: GET /tasks?pod=<namespace>/<name>.
+// Tasks with specific resources, node selector or tolerations are
+// not dispatched to the pool.
+type Pool struct {
+	// k8s client.
+	Client k8s.Client
+}
+
+// Fill the pool.
+// Idle pods are created for each addon as needed. Idle pods are
+// counted against the (pods) quota with the active (pending and
+// running) tasks. Filling stops when the namespace quota is exceeded.
+func (p *Pool) Fill(active int) (err error) {
+	size := Settings.Hub.Task.Pool
+	if size < 1 {
+		return
+	}
+	available := -1
+	if Settings.Hub.Task.Quota.Pods > 0 {
+		var idle []core.Pod
+		idle, err = p.idle("")
+		if err != nil {
+			return
+		}
+		available = Settings.Hub.Task.Quota.Pods - active - len(idle)
+		if available <= 0 {
+			return
+		}
+	}
+	list := crd.AddonList{}
+	err = p.Client.List(
+		context.TODO(),
+		&list,
+		&k8s.ListOptions{Namespace: Settings.Hub.Namespace})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	rt := Task{&model.Task{}}
+	owner, err := rt.findTackle(p.Client)
+	if err != nil {
+		return
+	}
+	for i := range list.Items {
+		addon := &list.Items[i]
+		idle, err := p.idle(addon.Name)
+		if err != nil {
+			return err
+		}
+		for n := len(idle); n < size; n++ {
+			if available == 0 {
+				return nil
+			}
+			err = p.create(addon, owner)
+			if err != nil {
+				if errors.Is(err, &QuotaExceeded{}) {
+					Log.Info("Pool quota exceeded.", "addon", addon.Name)
+					return nil
+				}
+				return err
+			}
+			if available > 0 {
+				available--
+			}
+		}
+	}
+	return
+}
+
+// Release an idle pod.
+// Used when a ready task cannot be started (quota exceeded) so
+// that idle pods do not cause running tasks to be preempted.
+func (p *Pool) Release() (released bool, err error) {
+	idle, err := p.idle("")
+	if err != nil {
+		return
+	}
+	for i := range idle {
+		pod := &idle[i]
+		err = p.Client.Delete(context.TODO(), pod)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err)
+			return
+		}
+		Log.Info(
+			"Pool pod released.",
+			"pod",
+			pod.Name)
+		released = true
+		return
+	}
+	return
+}
+
+// Claim an idle (running) pool pod for the task.
+// The pod is labeled with the task and the task is pending.
+// Returns false when no pod is available.
+func (p *Pool) Claim(task *model.Task) (claimed bool, err error) {
+	if task.Resources != nil ||
+		task.NodeSelector != nil ||
+		task.Tolerations != nil {
+		return
+	}
+	idle, err := p.idle(task.Addon)
+	if err != nil {
+		return
+	}
+	for i := range idle {
+		pod := &idle[i]
+		if pod.Status.Phase != core.PodRunning {
+			continue
+		}
+		pod.Labels["task"] = strconv.Itoa(int(task.ID))
+		err = p.Client.Update(context.TODO(), pod)
+		if err != nil {
+			if k8serr.IsConflict(err) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err)
+			return
+		}
+		mark := time.Now()
+		task.Image = pod.Spec.Containers[0].Image
+		task.Started = &mark
+		task.State = Pending
+		task.Pod = path.Join(
+			pod.Namespace,
+			pod.Name)
+		task.Event(EventPodCreated, "Pod: %s (pool)", task.Pod)
+		claimed = true
+		return
+	}
+	return
+}
+
+// idle returns the idle pool pods for the addon.
+// All addons when not specified.
+// Terminated (idle) pods are deleted.
+func (p *Pool) idle(addon string) (idle []core.Pod, err error) {
+	labels := p.labels(addon)
+	if addon == "" {
+		delete(labels, "addon")
+	}
+	list := core.PodList{}
+	err = p.Client.List(
+		context.TODO(),
+		&list,
+		&k8s.ListOptions{Namespace: Settings.Hub.Namespace},
+		k8s.MatchingLabels(labels))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list.Items {
+		pod := &list.Items[i]
+		if _, found := pod.Labels["task"]; found {
+			continue
+		}
+		switch pod.Status.Phase {
+		case core.PodSucceeded,
+			core.PodFailed:
+			err = p.Client.Delete(context.TODO(), pod)
+			if err != nil && !k8serr.IsNotFound(err) {
+				err = liberr.Wrap(err)
+				return
+			}
+			err = nil
+		default:
+			idle = append(idle, *pod)
+		}
+	}
+	return
+}
+
+// create an idle pod (and secret) for the addon.
+func (p *Pool) create(addon *crd.Addon, owner *crd.Tackle) (err error) {
+	name := fmt.Sprintf("pool-%s-%s", addon.Name, rand.String(5))
+	token, _ := auth.Hub.NewToken(
+		"addon:"+addon.Name,
+		auth.AddonRole,
+		jwt.MapClaims{
+			"pod": path.Join(Settings.Hub.Namespace, name),
+		})
+	secret := core.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: Settings.Hub.Namespace,
+			Name:      name,
+			Labels:    p.labels(addon.Name),
+		},
+		Data: map[string][]byte{
+			settings.EnvHubToken: []byte(token),
+		},
+	}
+	err = p.Client.Create(context.TODO(), &secret)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	rt := Task{&model.Task{Image: addon.Spec.Image}}
+	pod := rt.pod(addon, owner, &secret)
+	pod.GenerateName = ""
+	pod.Name = name
+	pod.Labels = p.labels(addon.Name)
+	container := &pod.Spec.Containers[0]
+	for i := range container.Env {
+		env := &container.Env[i]
+		if env.Name == settings.EnvTask {
+			env.Name = settings.EnvTaskPool
+			env.Value = "true"
+		}
+	}
+	err = p.Client.Create(context.TODO(), &pod)
+	if err != nil {
+		_ = p.Client.Delete(context.TODO(), &secret)
+		if k8serr.IsForbidden(err) && strings.Contains(err.Error(), "exceeded quota") {
+			err = &QuotaExceeded{Reason: err.Error()}
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	secret.OwnerReferences = append(
+		secret.OwnerReferences,
+		meta.OwnerReference{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Name:       pod.Name,
+			UID:        pod.UID,
+		})
+	err = p.Client.Update(context.TODO(), &secret)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	Log.Info(
+		"Pool pod created.",
+		"addon",
+		addon.Name,
+		"pod",
+		pod.Name)
+	return
+}
+
+// labels builds k8s labels.
+func (p *Pool) labels(addon string) map[string]string {
+	return map[string]string{
+		"addon": addon,
+		"app":   "tackle",
+		"role":  PoolRole,
+	}
+}
@@ -0,0 +1,99 @@
+package task
+
+import (
+	"context"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPoolClaim(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	Settings.Hub.Namespace = "konveyor"
+	pod := func(name string, phase core.PodPhase) *core.Pod {
+		p := &Pool{}
+		return &core.Pod{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: "konveyor",
+				Name:      name,
+				Labels:    p.labels("analyzer"),
+			},
+			Spec: core.PodSpec{
+				Containers: []core.Container{{Name: MainContainer, Image: "analyzer:latest"}},
+			},
+			Status: core.PodStatus{Phase: phase},
+		}
+	}
+	client := fake.NewClientBuilder().WithObjects(
+		pod("pool-analyzer-a", core.PodFailed),
+		pod("pool-analyzer-b", core.PodPending),
+		pod("pool-analyzer-c", core.PodRunning)).Build()
+	pool := &Pool{Client: client}
+	// Not eligible.
+	task := &model.Task{Addon: "analyzer", State: Ready, NodeSelector: []byte(`{"zone":"east"}`)}
+	task.ID = 4
+	claimed, err := pool.Claim(task)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(claimed).To(gomega.BeFalse())
+	// Claimed.
+	task.NodeSelector = nil
+	claimed, err = pool.Claim(task)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(claimed).To(gomega.BeTrue())
+	g.Expect(task.State).To(gomega.Equal(Pending))
+	g.Expect(task.Pod).To(gomega.Equal("konveyor/pool-analyzer-c"))
+	g.Expect(task.Image).To(gomega.Equal("analyzer:latest"))
+	g.Expect(task.Started).ToNot(gomega.BeNil())
+	claimedPod := &core.Pod{}
+	err = client.Get(context.TODO(), k8s.ObjectKey{Namespace: "konveyor", Name: "pool-analyzer-c"}, claimedPod)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(claimedPod.Labels["task"]).To(gomega.Equal("4"))
+	// Terminated (idle) pod deleted.
+	err = client.Get(context.TODO(), k8s.ObjectKey{Namespace: "konveyor", Name: "pool-analyzer-a"}, &core.Pod{})
+	g.Expect(err).ToNot(gomega.BeNil())
+	// None running.
+	other := &model.Task{Addon: "analyzer", State: Ready}
+	claimed, err = pool.Claim(other)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(claimed).To(gomega.BeFalse())
+}
+
+func TestPoolQuota(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	Settings.Hub.Namespace = "konveyor"
+	Settings.Hub.Task.Pool = 2
+	Settings.Hub.Task.Quota.Pods = 2
+	t.Cleanup(func() {
+		Settings.Hub.Task.Pool = 0
+		Settings.Hub.Task.Quota.Pods = 0
+	})
+	p := &Pool{}
+	idle := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "konveyor",
+			Name:      "pool-analyzer-a",
+			Labels:    p.labels("analyzer"),
+		},
+		Status: core.PodStatus{Phase: core.PodRunning},
+	}
+	client := fake.NewClientBuilder().WithObjects(idle).Build()
+	pool := &Pool{Client: client}
+	// Quota reached (1 active, 1 idle).
+	err := pool.Fill(1)
+	g.Expect(err).To(gomega.BeNil())
+	list := core.PodList{}
+	g.Expect(client.List(context.TODO(), &list)).To(gomega.BeNil())
+	g.Expect(list.Items).To(gomega.HaveLen(1))
+	// Released.
+	released, err := pool.Release()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(released).To(gomega.BeTrue())
+	released, err = pool.Release()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(released).To(gomega.BeFalse())
+}
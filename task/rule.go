@@ -1,6 +1,7 @@
 package task
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/konveyor/tackle2-hub/model"
@@ -9,6 +10,7 @@ import (
 // Rule defines postpone rules.
 type Rule interface {
 	Match(candidate, other *model.Task) bool
+	Reason(other *model.Task) string
 }
 
 // RuleUnique running tasks must be unique by:
@@ -40,6 +42,11 @@ func (r *RuleUnique) Match(candidate, other *model.Task) (matched bool) {
 	return
 }
 
+// Reason the candidate is postponed.
+func (r *RuleUnique) Reason(other *model.Task) string {
+	return fmt.Sprintf("Task (id=%d) running for the application and addon.", other.ID)
+}
+
 // RuleIsolated policy.
 type RuleIsolated struct {
 }
@@ -59,6 +66,11 @@ func (r *RuleIsolated) Match(candidate, other *model.Task) (matched bool) {
 	return
 }
 
+// Reason the candidate is postponed.
+func (r *RuleIsolated) Reason(other *model.Task) string {
+	return fmt.Sprintf("Isolated by task (id=%d).", other.ID)
+}
+
 // Returns true if the task policy includes: isolated
 func (r *RuleIsolated) hasPolicy(task *model.Task, name string) (matched bool) {
 	for _, p := range strings.Split(task.Policy, ";") {
@@ -72,3 +84,58 @@ func (r *RuleIsolated) hasPolicy(task *model.Task, name string) (matched bool) {
 
 	return
 }
+
+// Quota concurrent (pending and running) task limits.
+// A limit of 0 is unlimited.
+type Quota struct {
+	// Pods the (global) limit.
+	Pods int
+	// Application the limit for each application.
+	Application int
+}
+
+// Exceeded determines whether starting the candidate would exceed the quota.
+func (r *Quota) Exceeded(candidate *model.Task, list []model.Task) (exceeded bool, reason string) {
+	pods := 0
+	application := 0
+	for i := range list {
+		other := &list[i]
+		if candidate.ID == other.ID {
+			continue
+		}
+		switch other.State {
+		case Running,
+			Pending:
+		default:
+			continue
+		}
+		pods++
+		if candidate.ApplicationID != nil &&
+			other.ApplicationID != nil &&
+			*candidate.ApplicationID == *other.ApplicationID {
+			application++
+		}
+	}
+	if r.Pods > 0 && pods >= r.Pods {
+		exceeded = true
+		reason = fmt.Sprintf("Quota: %d tasks running.", pods)
+		Log.Info(
+			"Quota:Pods exceeded.",
+			"candidate",
+			candidate.ID,
+			"running",
+			pods)
+		return
+	}
+	if r.Application > 0 && application >= r.Application {
+		exceeded = true
+		reason = fmt.Sprintf("Quota: %d tasks running for the application.", application)
+		Log.Info(
+			"Quota:Application exceeded.",
+			"candidate",
+			candidate.ID,
+			"running",
+			application)
+	}
+	return
+}
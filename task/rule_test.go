@@ -0,0 +1,36 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestQuota(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	app1 := uint(1)
+	app2 := uint(2)
+	candidate := &model.Task{Model: model.Model{ID: 9}, State: Ready, ApplicationID: &app1}
+	list := []model.Task{
+		{Model: model.Model{ID: 1}, State: Running, ApplicationID: &app1},
+		{Model: model.Model{ID: 2}, State: Pending, ApplicationID: &app2},
+		{Model: model.Model{ID: 3}, State: Ready, ApplicationID: &app1},
+		{Model: model.Model{ID: 4}, State: Succeeded, ApplicationID: &app1},
+		*candidate,
+	}
+	exceeded := func(quota Quota) bool {
+		b, _ := quota.Exceeded(candidate, list)
+		return b
+	}
+	g.Expect(exceeded(Quota{})).To(gomega.BeFalse())
+	g.Expect(exceeded(Quota{Pods: 3})).To(gomega.BeFalse())
+	g.Expect(exceeded(Quota{Pods: 2})).To(gomega.BeTrue())
+	g.Expect(exceeded(Quota{Application: 2})).To(gomega.BeFalse())
+	g.Expect(exceeded(Quota{Application: 1})).To(gomega.BeTrue())
+	quota := Quota{Application: 1}
+	_, reason := quota.Exceeded(candidate, list)
+	g.Expect(reason).To(gomega.Equal("Quota: 1 tasks running for the application."))
+	candidate.ApplicationID = nil
+	g.Expect(exceeded(Quota{Application: 1})).To(gomega.BeFalse())
+}
@@ -0,0 +1,153 @@
+package tracker
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/api"
+	"github.com/konveyor/tackle2-hub/binding"
+	"github.com/konveyor/tackle2-hub/test/assert"
+	"github.com/konveyor/tackle2-hub/tracker"
+)
+
+func TestTrackerValidate(t *testing.T) {
+	r := Samples[0]
+	identity := api.Identity{
+		Kind: tracker.BasicAuth,
+		Name: r.Identity.Name,
+	}
+	assert.Must(t, Identity.Create(&identity))
+	defer func() {
+		assert.Must(t, Identity.Delete(identity.ID))
+	}()
+	r.Identity.ID = identity.ID
+
+	// Valid (not connected).
+	report, err := Tracker.Validate(&r)
+	assert.Must(t, err)
+	if !report.Binding.Valid || !report.Identity.Valid || !report.Metadata.Valid {
+		t.Errorf("Expected valid binding, identity and metadata: %+v", report)
+	}
+	if report.Connectivity.Valid {
+		t.Errorf("Expected not connected: %+v", report)
+	}
+
+	// Metadata not valid.
+	r.Metadata = api.Metadata{tracker.HealthPath: "https://example.com"}
+	report, err = Tracker.Validate(&r)
+	assert.Must(t, err)
+	if report.Metadata.Valid || report.Connectivity.Message != "skipped." {
+		t.Errorf("Expected metadata not valid: %+v", report)
+	}
+
+	// Nothing created.
+	list, err := Tracker.List()
+	assert.Must(t, err)
+	for _, m := range list {
+		if m.Name == r.Name {
+			t.Errorf("Tracker created by validate: %v", m)
+		}
+	}
+}
+
+func TestTrackerFieldErrors(t *testing.T) {
+	r := Samples[0]
+	r.Identity = api.Ref{}
+	err := Tracker.Create(&r)
+	restErr := &binding.RestError{}
+	if !errors.As(err, &restErr) {
+		t.Fatalf("Expected REST error: %v", err)
+	}
+	if restErr.Status != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422, got: %d", restErr.Status)
+	}
+	if !strings.Contains(restErr.Body, "identity") {
+		t.Errorf("Expected the identity field reported: %s", restErr.Body)
+	}
+}
+
+func TestTrackerBackfill(t *testing.T) {
+	backfill := api.Backfill{}
+	assert.Must(t, RichClient.Client.Get(api.TrackerBackfillRoot, &backfill))
+	switch backfill.State {
+	case tracker.BackfillPending,
+		tracker.BackfillRunning,
+		tracker.BackfillSucceeded:
+	default:
+		t.Errorf("Unexpected backfill state: %+v", backfill)
+	}
+}
+
+func TestTrackerRateLimit(t *testing.T) {
+	r := Samples[0]
+	identity := api.Identity{
+		Kind: tracker.BasicAuth,
+		Name: r.Identity.Name,
+	}
+	assert.Must(t, Identity.Create(&identity))
+	defer func() {
+		assert.Must(t, Identity.Delete(identity.ID))
+	}()
+	r.Identity.ID = identity.ID
+	assert.Must(t, Tracker.Create(&r))
+	defer func() {
+		assert.Must(t, Tracker.Delete(r.ID))
+	}()
+
+	// Not reported.
+	rateLimit, err := Tracker.RateLimit(r.ID)
+	assert.Must(t, err)
+	if rateLimit.Limit != 0 || rateLimit.Throttled {
+		t.Errorf("Expected rate-limit not reported: %+v", rateLimit)
+	}
+}
+
+func TestTrackerHistory(t *testing.T) {
+	r := Samples[0]
+	identity := api.Identity{
+		Kind: tracker.BasicAuth,
+		Name: r.Identity.Name,
+	}
+	assert.Must(t, Identity.Create(&identity))
+	defer func() {
+		assert.Must(t, Identity.Delete(identity.ID))
+	}()
+	r.Identity.ID = identity.ID
+	assert.Must(t, Tracker.Create(&r))
+	defer func() {
+		assert.Must(t, Tracker.Delete(r.ID))
+	}()
+	_, err := Tracker.History(r.ID)
+	assert.Must(t, err)
+}
+
+func TestTrackerDebugLogging(t *testing.T) {
+	r := Samples[0]
+	identity := api.Identity{
+		Kind: tracker.BasicAuth,
+		Name: r.Identity.Name,
+	}
+	assert.Must(t, Identity.Create(&identity))
+	defer func() {
+		assert.Must(t, Identity.Delete(identity.ID))
+	}()
+	r.Identity.ID = identity.ID
+
+	// Beyond the maximum.
+	expiry := time.Now().Add(tracker.MaxDebugLogging * 2)
+	r.Metadata = api.Metadata{tracker.DebugLogging: expiry.Format(time.RFC3339)}
+	err := Tracker.Create(&r)
+	restErr := &binding.RestError{}
+	if !errors.As(err, &restErr) || restErr.Status != http.StatusBadRequest {
+		t.Errorf("Expected 400: %v", err)
+	}
+
+	// Enabled.
+	expiry = time.Now().Add(time.Hour)
+	r.Metadata = api.Metadata{tracker.DebugLogging: expiry.Format(time.RFC3339)}
+	assert.Must(t, Tracker.Create(&r))
+	assert.Must(t, Tracker.Delete(r.ID))
+}
@@ -0,0 +1,420 @@
+package tracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/metrics"
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+const (
+	AzureDevOpsEndpointProjects      = "_apis/projects"
+	AzureDevOpsEndpointWorkItems     = "_apis/wit/workitems"
+	AzureDevOpsEndpointWorkItemTypes = "_apis/wit/workitemtypes"
+)
+
+// AzureDevOpsAPIVersion the (REST) API version.
+const AzureDevOpsAPIVersion = "7.0"
+
+// AzureDevOpsPageSize the (max) page size.
+const AzureDevOpsPageSize = 100
+
+// Azure DevOps (work item) fields.
+const (
+	AzureDevOpsTitle       = "System.Title"
+	AzureDevOpsDescription = "System.Description"
+	AzureDevOpsState       = "System.State"
+	AzureDevOpsAssignedTo  = "System.AssignedTo"
+)
+
+// AzureDevOpsConnector for the Azure DevOps (boards) API.
+// The tracker URL is the organization (https://dev.azure.com/org).
+// Authenticated using a personal access token as the password of
+// a basic-auth identity (the user is ignored) or a bearer identity.
+// Tickets are created as work items.
+type AzureDevOpsConnector struct {
+	tracker *model.Tracker
+}
+
+// AzureDevOpsVersion the azure-devops connector version.
+// Incremented when the connector behavior changes.
+const AzureDevOpsVersion = "1.0.0"
+
+// Version returns the connector version.
+func (r *AzureDevOpsConnector) Version() string {
+	return AzureDevOpsVersion
+}
+
+// With updates the connector with the Tracker model.
+func (r *AzureDevOpsConnector) With(t *model.Tracker) {
+	r.tracker = t
+	_ = r.tracker.Identity.Decrypt()
+	if r.tracker.TunnelIdentity != nil {
+		_ = r.tracker.TunnelIdentity.Decrypt()
+	}
+}
+
+// Create the ticket (work item) in Azure DevOps.
+// The ticket fields are keyed by (work item) field reference name.
+func (r *AzureDevOpsConnector) Create(t *model.Ticket) (err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	fields := map[string]interface{}{}
+	_ = json.Unmarshal(t.Fields, &fields)
+	fields[AzureDevOpsTitle] = summary(t)
+	fields[AzureDevOpsDescription] = description(t)
+	var keys []string
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	patch := []azureDevOpsPatch{}
+	for _, k := range keys {
+		patch = append(
+			patch,
+			azureDevOpsPatch{
+				Op:    "add",
+				Path:  "/fields/" + k,
+				Value: fields[k],
+			})
+	}
+	client.contentType = "application/json-patch+json"
+	created := azureDevOpsWorkItem{}
+	err = client.do(
+		http.MethodPost,
+		r.path(
+			fmt.Sprintf(
+				"%s/%s/$%s",
+				url.PathEscape(t.Parent),
+				AzureDevOpsEndpointWorkItems,
+				url.PathEscape(t.Kind))),
+		patch,
+		&created)
+	if err != nil {
+		t.Error = true
+		t.Message = err.Error()
+		t.LastUpdated = time.Now()
+		err = nil
+		return
+	}
+	t.Created = true
+	t.Error = false
+	t.Message = ""
+	t.Reference = strconv.Itoa(created.ID)
+	t.Link = created.Links.HTML.Href
+	t.LastUpdated = time.Now()
+	metrics.IssuesExported.Inc()
+	return
+}
+
+// RefreshAll retrieves fresh status information for all the tracker's tickets.
+func (r *AzureDevOpsConnector) RefreshAll() (tickets map[*model.Ticket]bool, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	tickets = make(map[*model.Ticket]bool)
+	for i := range r.tracker.Tickets {
+		t := &r.tracker.Tickets[i]
+		if t.Reference == "" {
+			continue
+		}
+		tickets[t] = false
+		item := azureDevOpsWorkItem{}
+		err = client.do(
+			http.MethodGet,
+			r.path(fmt.Sprintf("%s/%s", AzureDevOpsEndpointWorkItems, t.Reference)),
+			nil,
+			&item)
+		if err != nil {
+			if NotFound(err) {
+				err = nil
+				continue
+			}
+			return
+		}
+		t.Status = item.status()
+		t.RemoteStatus = item.state()
+		t.LastUpdated = time.Now()
+		tickets[t] = true
+	}
+	return
+}
+
+// Projects returns a list of Projects.
+func (r *AzureDevOpsConnector) Projects() (projects []Project, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	for skip := 0; ; skip += AzureDevOpsPageSize {
+		list := struct {
+			Value []azureDevOpsProject `json:"value"`
+		}{}
+		err = client.do(
+			http.MethodGet,
+			r.path(
+				fmt.Sprintf(
+					"%s?$top=%d&$skip=%d",
+					AzureDevOpsEndpointProjects,
+					AzureDevOpsPageSize,
+					skip)),
+			nil,
+			&list)
+		if err != nil {
+			return
+		}
+		for _, p := range list.Value {
+			projects = append(projects, p.project())
+		}
+		if len(list.Value) < AzureDevOpsPageSize {
+			break
+		}
+	}
+	return
+}
+
+// ProjectSearch returns a page of Projects.
+// The (full) project list is filtered and paginated locally.
+func (r *AzureDevOpsConnector) ProjectSearch(filter ProjectFilter) (page ProjectPage, err error) {
+	projects, err := r.Projects()
+	if err != nil {
+		return
+	}
+	page.With(projects, filter)
+	return
+}
+
+// Project returns a Project.
+func (r *AzureDevOpsConnector) Project(id string) (project Project, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	p := azureDevOpsProject{}
+	err = client.do(
+		http.MethodGet,
+		r.path(fmt.Sprintf("%s/%s", AzureDevOpsEndpointProjects, url.PathEscape(id))),
+		nil,
+		&p)
+	if err != nil {
+		return
+	}
+	project = p.project()
+	return
+}
+
+// IssueTypes returns a list of IssueTypes (work item types) for a Project.
+// Disabled types are excluded.
+func (r *AzureDevOpsConnector) IssueTypes(id string) (issueTypes []IssueType, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	list := struct {
+		Value []struct {
+			Name       string `json:"name"`
+			IsDisabled bool   `json:"isDisabled"`
+		} `json:"value"`
+	}{}
+	err = client.do(
+		http.MethodGet,
+		r.path(fmt.Sprintf("%s/%s", url.PathEscape(id), AzureDevOpsEndpointWorkItemTypes)),
+		nil,
+		&list)
+	if err != nil {
+		return
+	}
+	for _, t := range list.Value {
+		if t.IsDisabled {
+			continue
+		}
+		issueTypes = append(
+			issueTypes,
+			IssueType{
+				ID:   t.Name,
+				Name: t.Name,
+			})
+	}
+	return
+}
+
+// RequiredFields returns the fields required to create an issue (work item).
+// Fields supplied by the connector are excluded.
+func (r *AzureDevOpsConnector) RequiredFields(project, kind string) (fields []string, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	list := struct {
+		Value []struct {
+			ReferenceName  string `json:"referenceName"`
+			AlwaysRequired bool   `json:"alwaysRequired"`
+		} `json:"value"`
+	}{}
+	err = client.do(
+		http.MethodGet,
+		r.path(
+			fmt.Sprintf(
+				"%s/%s/%s/fields?$expand=all",
+				url.PathEscape(project),
+				AzureDevOpsEndpointWorkItemTypes,
+				url.PathEscape(kind))),
+		nil,
+		&list)
+	if err != nil {
+		return
+	}
+	for _, f := range list.Value {
+		if !f.AlwaysRequired {
+			continue
+		}
+		switch f.ReferenceName {
+		case AzureDevOpsTitle, AzureDevOpsDescription:
+			continue
+		}
+		fields = append(fields, f.ReferenceName)
+	}
+	sort.Strings(fields)
+	return
+}
+
+// TestConnection to Azure DevOps.
+// The health checks may be replaced by a single probe
+// using the `healthPath` metadata.
+func (r *AzureDevOpsConnector) TestConnection() (connected bool, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	md := Metadata{}
+	md.With(r.tracker)
+	check := HealthCheck{
+		Step: StepUser,
+		Path: r.path(AzureDevOpsEndpointProjects + "?$top=1"),
+	}
+	path := md.String(HealthPath)
+	if path != "" {
+		check = HealthCheck{
+			Step: HealthPath,
+			Path: path,
+		}
+	}
+	err = client.healthCheck(check)
+	if err != nil {
+		return
+	}
+	connected = true
+	return
+}
+
+// path returns the path with the api-version.
+func (r *AzureDevOpsConnector) path(p string) string {
+	sep := "?"
+	if strings.Contains(p, "?") {
+		sep = "&"
+	}
+	return p + sep + "api-version=" + AzureDevOpsAPIVersion
+}
+
+// client returns an azure-devops client.
+func (r *AzureDevOpsConnector) client() (client *restClient, err error) {
+	client, err = newRestClient(r.tracker, r.tracker.URL)
+	if err != nil {
+		return
+	}
+	client.failed = func(status int, body []byte) error {
+		aErr := &azureDevOpsError{Status: status}
+		_ = json.Unmarshal(body, aErr)
+		return aErr
+	}
+	return
+}
+
+// azureDevOpsPatch azure-devops (json-patch) operation.
+type azureDevOpsPatch struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// azureDevOpsProject azure-devops project.
+type azureDevOpsProject struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// project returns the (tracker) project.
+func (r *azureDevOpsProject) project() (p Project) {
+	p = Project{
+		ID:   r.ID,
+		Name: r.Name,
+	}
+	return
+}
+
+// azureDevOpsWorkItem azure-devops work item.
+type azureDevOpsWorkItem struct {
+	ID     int                    `json:"id"`
+	Fields map[string]interface{} `json:"fields"`
+	Links  struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"_links"`
+}
+
+// state returns the (remote) state.
+func (r *azureDevOpsWorkItem) state() (s string) {
+	s, _ = r.Fields[AzureDevOpsState].(string)
+	return
+}
+
+// status returns a normalized status.
+// The states defined by the standard (agile, scrum, basic and cmmi)
+// processes are mapped. New items are in progress once assigned.
+func (r *azureDevOpsWorkItem) status() (s string) {
+	switch strings.ToLower(r.state()) {
+	case "new", "to do", "proposed", "approved":
+		s = New
+		if _, assigned := r.Fields[AzureDevOpsAssignedTo]; assigned {
+			s = InProgress
+		}
+	case "active", "committed", "doing", "in progress":
+		s = InProgress
+	case "resolved", "closed", "done", "removed":
+		s = Done
+	default:
+		s = Unknown
+	}
+	return
+}
+
+// azureDevOpsError reports an error returned by the Azure DevOps API.
+type azureDevOpsError struct {
+	Status  int    `json:"-"`
+	Message string `json:"message"`
+}
+
+// StatusCode returns the (http) status.
+func (r *azureDevOpsError) StatusCode() int {
+	return r.Status
+}
+
+// Error reports the consolidated error message.
+func (r *azureDevOpsError) Error() (s string) {
+	s = fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status))
+	if r.Message != "" {
+		s += ": " + r.Message
+	}
+	return
+}
@@ -0,0 +1,123 @@
+package tracker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestAzureDevOps(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	var patch []azureDevOpsPatch
+	contentType := ""
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_, pat, _ := r.BasicAuth()
+				if pat != "token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				if r.URL.Query().Get("api-version") != AzureDevOpsAPIVersion {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				switch r.Method + " " + r.URL.Path {
+				case "GET /org/_apis/projects":
+					_, _ = w.Write([]byte(`{"count":1,"value":[{"id":"p1","name":"Acme"}]}`))
+				case "GET /org/_apis/projects/p1":
+					_, _ = w.Write([]byte(`{"id":"p1","name":"Acme"}`))
+				case "GET /org/p1/_apis/wit/workitemtypes":
+					_, _ = w.Write([]byte(`{"value":[{"name":"Bug"},{"name":"Epic","isDisabled":true},{"name":"Task"}]}`))
+				case "GET /org/p1/_apis/wit/workitemtypes/Task/fields":
+					_, _ = w.Write([]byte(`{"value":[{"referenceName":"System.Title","alwaysRequired":true},{"referenceName":"Custom.Team","alwaysRequired":true},{"referenceName":"System.Tags"}]}`))
+				case "POST /org/p1/_apis/wit/workitems/$Task":
+					contentType = r.Header.Get("Content-Type")
+					_ = json.NewDecoder(r.Body).Decode(&patch)
+					_, _ = w.Write([]byte(`{"id":7,"_links":{"html":{"href":"https://dev.azure.com/org/p1/_workitems/edit/7"}}}`))
+				case "GET /org/_apis/wit/workitems/7":
+					_, _ = w.Write([]byte(`{"id":7,"fields":{"System.State":"Active"}}`))
+				case "POST /org/p2/_apis/wit/workitems/$Task":
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"message":"TF401320: Rule Error for field Team."}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"message":"not found."}`))
+				}
+			}))
+	defer server.Close()
+	identity := &model.Identity{
+		Kind:     BasicAuth,
+		Password: "token",
+	}
+	err := identity.Encrypt(&model.Identity{})
+	g.Expect(err).To(gomega.BeNil())
+	tracker := &model.Tracker{
+		Kind:     AzureDevOps,
+		URL:      server.URL + "/org",
+		Identity: identity,
+	}
+	conn, err := NewConnector(tracker)
+	g.Expect(err).To(gomega.BeNil())
+
+	connected, err := conn.TestConnection()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(connected).To(gomega.BeTrue())
+
+	projects, err := conn.Projects()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(projects).To(gomega.Equal([]Project{{ID: "p1", Name: "Acme"}}))
+	project, err := conn.Project("p1")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(project.Name).To(gomega.Equal("Acme"))
+
+	issueTypes, err := conn.IssueTypes("p1")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(issueTypes).To(gomega.Equal([]IssueType{{ID: "Bug", Name: "Bug"}, {ID: "Task", Name: "Task"}}))
+
+	fields, err := conn.RequiredFields("p1", "Task")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(fields).To(gomega.Equal([]string{"Custom.Team"}))
+
+	ticket := model.Ticket{
+		Kind:        "Task",
+		Parent:      "p1",
+		Fields:      []byte(`{"Custom.Team":"red"}`),
+		Application: &model.Application{Name: "app"},
+	}
+	err = conn.Create(&ticket)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ticket.Error).To(gomega.BeFalse(), ticket.Message)
+	g.Expect(ticket.Reference).To(gomega.Equal("7"))
+	g.Expect(ticket.Link).To(gomega.Equal("https://dev.azure.com/org/p1/_workitems/edit/7"))
+	g.Expect(contentType).To(gomega.Equal("application/json-patch+json"))
+	g.Expect(len(patch)).To(gomega.Equal(3))
+	g.Expect(patch[0].Path).To(gomega.Equal("/fields/Custom.Team"))
+	g.Expect(patch[2].Value).To(gomega.Equal("Migrate app"))
+
+	// Rejected.
+	rejected := model.Ticket{Kind: "Task", Parent: "p2", Application: &model.Application{Name: "app"}}
+	err = conn.Create(&rejected)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(rejected.Error).To(gomega.BeTrue())
+	g.Expect(rejected.Message).To(gomega.Equal("400 Bad Request: TF401320: Rule Error for field Team."))
+
+	tracker.Tickets = []model.Ticket{ticket}
+	tickets, err := conn.RefreshAll()
+	g.Expect(err).To(gomega.BeNil())
+	for t, found := range tickets {
+		g.Expect(found).To(gomega.BeTrue())
+		g.Expect(t.Status).To(gomega.Equal(InProgress))
+		g.Expect(t.RemoteStatus).To(gomega.Equal("Active"))
+	}
+
+	// Not authorized.
+	tracker.Identity.Password = "invalid"
+	_, err = conn.TestConnection()
+	category, _ := Categorize(err)
+	g.Expect(category).To(gomega.Equal(ErrorAuth))
+}
@@ -0,0 +1,135 @@
+package tracker
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/gorm"
+)
+
+// BackfillKey is the setting used to record that
+// the status backfill has completed.
+const BackfillKey = ".tracker.backfill"
+
+// Backfill states.
+const (
+	BackfillPending   = "Pending"
+	BackfillRunning   = "Running"
+	BackfillSucceeded = "Succeeded"
+	BackfillFailed    = "Failed"
+)
+
+// BackfillStatus reports the progress of the backfill of
+// structured status fields from the (legacy) Message.
+type BackfillStatus struct {
+	State     string
+	Total     int
+	Completed int
+	Updated   int
+	Error     string
+	Started   *time.Time
+	Finished  *time.Time
+}
+
+// backfillStatus is the current backfill status.
+var backfillStatus = struct {
+	mutex  sync.Mutex
+	status BackfillStatus
+}{
+	status: BackfillStatus{State: BackfillPending},
+}
+
+// Backfilled returns the backfill status.
+func Backfilled() (status BackfillStatus) {
+	backfillStatus.mutex.Lock()
+	defer backfillStatus.mutex.Unlock()
+	status = backfillStatus.status
+	return
+}
+
+// updateBackfill updates the backfill status.
+func updateBackfill(fn func(status *BackfillStatus)) {
+	backfillStatus.mutex.Lock()
+	defer backfillStatus.mutex.Unlock()
+	fn(&backfillStatus.status)
+}
+
+// backfill the structured status fields (ErrorCategory and
+// StatusReason) of trackers using the (legacy) Message.
+// Performed once.
+func (m *Manager) backfill() {
+	setting := &model.Setting{}
+	err := m.DB.First(setting, model.Setting{Key: BackfillKey}).Error
+	if err == nil {
+		updateBackfill(func(status *BackfillStatus) {
+			status.State = BackfillSucceeded
+		})
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		m.backfillFailed(err)
+		return
+	}
+	now := time.Now()
+	var list []model.Tracker
+	db := m.DB.Where("message != ''")
+	db = db.Where("errorCategory IS NULL OR errorCategory = ''")
+	err = db.Find(&list).Error
+	if err != nil {
+		m.backfillFailed(err)
+		return
+	}
+	updateBackfill(func(status *BackfillStatus) {
+		status.State = BackfillRunning
+		status.Total = len(list)
+		status.Started = &now
+	})
+	Log.Info("Backfill started.", "trackers", len(list))
+	for i := range list {
+		tracker := &list[i]
+		category := CategoryOf(tracker.Message)
+		result := m.DB.Model(tracker).Updates(
+			map[string]interface{}{
+				"ErrorCategory": category,
+				"StatusReason":  tracker.Message,
+			})
+		if result.Error != nil {
+			m.backfillFailed(result.Error)
+			return
+		}
+		updateBackfill(func(status *BackfillStatus) {
+			status.Completed++
+			if category != ErrorUnknown {
+				status.Updated++
+			}
+		})
+	}
+	setting.Key = BackfillKey
+	err = setting.With(true)
+	if err == nil {
+		err = m.DB.Create(setting).Error
+	}
+	if err != nil {
+		m.backfillFailed(err)
+		return
+	}
+	now = time.Now()
+	updateBackfill(func(status *BackfillStatus) {
+		status.State = BackfillSucceeded
+		status.Finished = &now
+	})
+	Log.Info("Backfill succeeded.")
+}
+
+// backfillFailed reports the backfill failed.
+func (m *Manager) backfillFailed(err error) {
+	Log.Error(err, "Backfill failed.")
+	now := time.Now()
+	updateBackfill(func(status *BackfillStatus) {
+		status.State = BackfillFailed
+		status.Error = err.Error()
+		status.Finished = &now
+	})
+}
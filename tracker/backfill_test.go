@@ -0,0 +1,62 @@
+package tracker
+
+import (
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+func TestBackfill(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db, err := gorm.Open(
+		sqlite.Open("file::memory:"),
+		&gorm.Config{
+			NamingStrategy: &schema.NamingStrategy{
+				SingularTable: true,
+				NoLowerCase:   true,
+			},
+		})
+	g.Expect(err).To(gomega.BeNil())
+	err = db.AutoMigrate(&model.Tracker{}, &model.Setting{})
+	g.Expect(err).To(gomega.BeNil())
+	trackers := []model.Tracker{
+		{Name: "a", Message: "401 Unauthorized"},
+		{Name: "b", Message: "something broke"},
+		{Name: "c", Message: "dial tcp: i/o timeout", ErrorCategory: ErrorTLS},
+		{Name: "d"},
+	}
+	err = db.Omit("Identity").Create(&trackers).Error
+	g.Expect(err).To(gomega.BeNil())
+
+	m := Manager{DB: db}
+	m.backfill()
+	status := Backfilled()
+	g.Expect(status.State).To(gomega.Equal(BackfillSucceeded))
+	g.Expect(status.Total).To(gomega.Equal(2))
+	g.Expect(status.Completed).To(gomega.Equal(2))
+	g.Expect(status.Updated).To(gomega.Equal(1))
+	expected := map[string]string{
+		"a": ErrorAuth,
+		"b": ErrorUnknown,
+		"c": ErrorTLS,
+		"d": "",
+	}
+	var list []model.Tracker
+	g.Expect(db.Find(&list).Error).To(gomega.BeNil())
+	for _, tracker := range list {
+		g.Expect(tracker.ErrorCategory).To(gomega.Equal(expected[tracker.Name]), tracker.Name)
+	}
+
+	// Performed once.
+	err = db.Model(&list[0]).Update("ErrorCategory", "").Error
+	g.Expect(err).To(gomega.BeNil())
+	m.backfill()
+	found := &model.Tracker{}
+	g.Expect(db.First(found, list[0].ID).Error).To(gomega.BeNil())
+	g.Expect(found.ErrorCategory).To(gomega.BeEmpty())
+	g.Expect(Backfilled().State).To(gomega.Equal(BackfillSucceeded))
+}
@@ -0,0 +1,157 @@
+package tracker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+// ProjectCacheTTL is how long pages of projects are cached.
+const ProjectCacheTTL = time.Second * 30
+
+// projectCache caches pages of projects.
+var projectCache = &pageCache{
+	entries: make(map[string]cachedPage),
+}
+
+// SearchProjects returns a page of the tracker's projects.
+// Pages are cached briefly to keep paging through large
+// project lists responsive. The key includes the identity
+// (and when it was updated) so that credential changes are
+// not masked by the cache.
+func SearchProjects(t *model.Tracker, filter ProjectFilter) (page ProjectPage, err error) {
+	var updated int64
+	if t.Identity != nil {
+		updated = t.Identity.UpdateTime.UnixNano()
+	}
+	key := fmt.Sprintf(
+		"%d|%s|%s|%d|%d|%s|%d|%d",
+		t.ID,
+		t.URL,
+		t.Kind,
+		t.IdentityID,
+		updated,
+		filter.Query,
+		filter.Offset,
+		filter.Limit)
+	page, found := projectCache.get(key)
+	if found {
+		return
+	}
+	conn, err := NewConnector(t)
+	if err != nil {
+		return
+	}
+	page, err = conn.ProjectSearch(filter)
+	if err != nil {
+		return
+	}
+	projectCache.put(key, t.ID, t.IdentityID, page)
+	return
+}
+
+// ProjectMetadata a project and its issue types.
+type ProjectMetadata struct {
+	Project
+	IssueTypes []IssueType
+}
+
+// RefreshProjects fetches the tracker's projects and their issue
+// types from the remote. The cached pages of projects are dropped
+// so that new projects are listed.
+func RefreshProjects(t *model.Tracker) (projects []ProjectMetadata, err error) {
+	projectCache.evictTracker(t.ID)
+	conn, err := NewConnector(t)
+	if err != nil {
+		return
+	}
+	list, err := conn.Projects()
+	if err != nil {
+		return
+	}
+	for _, p := range list {
+		md := ProjectMetadata{Project: p}
+		md.IssueTypes, err = conn.IssueTypes(p.ID)
+		if err != nil {
+			return
+		}
+		projects = append(projects, md)
+	}
+	return
+}
+
+// cachedPage is a cached page of projects.
+type cachedPage struct {
+	page ProjectPage
+	// tracker (ID) of the page.
+	tracker uint
+	// identity (ID) used to fetch the page.
+	identity uint
+	expires  time.Time
+}
+
+// pageCache is a (TTL) cache of project pages.
+type pageCache struct {
+	mutex   sync.Mutex
+	entries map[string]cachedPage
+}
+
+// get a cached page.
+func (c *pageCache) get(key string) (page ProjectPage, found bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, found := c.entries[key]
+	if !found {
+		return
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		found = false
+		return
+	}
+	page = entry.page
+	return
+}
+
+// put a page in the cache.
+// Expired entries are purged.
+func (c *pageCache) put(key string, tracker, identity uint, page ProjectPage) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cachedPage{
+		page:     page,
+		tracker:  tracker,
+		identity: identity,
+		expires:  now.Add(ProjectCacheTTL),
+	}
+}
+
+// evictTracker evicts the pages of the tracker.
+func (c *pageCache) evictTracker(tracker uint) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for k, entry := range c.entries {
+		if entry.tracker == tracker {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// evict the pages fetched using the identity.
+func (c *pageCache) evict(identity uint) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for k, entry := range c.entries {
+		if entry.identity == identity {
+			delete(c.entries, k)
+		}
+	}
+}
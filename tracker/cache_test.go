@@ -0,0 +1,80 @@
+package tracker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestSearchProjects(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	requested := 0
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				requested++
+				switch r.URL.Path {
+				case "/" + JiraEndpointProject:
+					_, _ = w.Write([]byte(`[{"id":"1","name":"p1"}]`))
+				case "/" + JiraEndpointProject + "/1":
+					_, _ = w.Write([]byte(`{"id":"1","issueTypes":[{"id":"10","name":"Task"}]}`))
+				default:
+					_, _ = w.Write([]byte(`{"values":[{"id":"1","name":"p1"}],"total":1,"isLast":true}`))
+				}
+			}))
+	defer server.Close()
+	identity := &model.Identity{Kind: BasicAuth, User: "elmer", Password: "secret"}
+	g.Expect(identity.Encrypt(&model.Identity{})).To(gomega.BeNil())
+	identity.ID = 4
+	identity.UpdateTime = time.Now()
+	tracker := &model.Tracker{
+		Kind:       JiraCloud,
+		URL:        server.URL,
+		IdentityID: identity.ID,
+		Identity:   identity,
+	}
+	tracker.ID = 11
+	filter := ProjectFilter{Limit: 10}
+
+	// Fetched.
+	page, err := SearchProjects(tracker, filter)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(page.Total).To(gomega.Equal(1))
+	g.Expect(requested).To(gomega.Equal(1))
+
+	// Cached.
+	_, err = SearchProjects(tracker, filter)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(requested).To(gomega.Equal(1))
+
+	// Identity updated.
+	tracker.Identity.UpdateTime = tracker.Identity.UpdateTime.Add(time.Second)
+	_, err = SearchProjects(tracker, filter)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(requested).To(gomega.Equal(2))
+
+	// Identity replaced.
+	tracker.IdentityID = 5
+	tracker.Identity.ID = 5
+	_, err = SearchProjects(tracker, filter)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(requested).To(gomega.Equal(3))
+
+	// Refreshed.
+	projects, err := RefreshProjects(tracker)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(requested).To(gomega.Equal(5))
+	g.Expect(projects).To(gomega.Equal([]ProjectMetadata{
+		{
+			Project:    Project{ID: "1", Name: "p1"},
+			IssueTypes: []IssueType{{ID: "10", Name: "Task"}},
+		},
+	}))
+	_, err = SearchProjects(tracker, filter)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(requested).To(gomega.Equal(6))
+}
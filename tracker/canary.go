@@ -0,0 +1,103 @@
+package tracker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+// CanaryTag is the tag used to select canary trackers.
+const CanaryTag = "canary"
+
+// CanaryReports is the number of divergence reports retained.
+const CanaryReports = 100
+
+// Canaries canary (new) connectors by tracker kind.
+// When canary mode is enabled, the canary connector is used
+// for trackers tagged `canary` and the result is compared against
+// the (stable) connector.
+var Canaries = map[string]func() Connector{}
+
+// divergences reported by canary reconciles.
+var divergences = struct {
+	mutex   sync.Mutex
+	reports []Divergence
+}{}
+
+// Outcome of a connection test.
+type Outcome struct {
+	Connected bool
+	Category  string
+	Reason    string
+}
+
+// With updates the outcome with the result of the connection test.
+func (r *Outcome) With(connected bool, err error) {
+	r.Connected = connected
+	if err != nil {
+		r.Category, r.Reason = Categorize(err)
+	}
+}
+
+// Divergence reports a canary connector result that
+// does not match the stable connector.
+type Divergence struct {
+	Tracker uint
+	Time    time.Time
+	Stable  Outcome
+	Canary  Outcome
+}
+
+// Divergences returns the (retained) divergence reports.
+func Divergences() (reports []Divergence) {
+	divergences.mutex.Lock()
+	defer divergences.mutex.Unlock()
+	reports = append(reports, divergences.reports...)
+	return
+}
+
+// diverged records a divergence.
+func diverged(d Divergence) {
+	divergences.mutex.Lock()
+	defer divergences.mutex.Unlock()
+	divergences.reports = append(divergences.reports, d)
+	n := len(divergences.reports)
+	if n > CanaryReports {
+		divergences.reports = divergences.reports[n-CanaryReports:]
+	}
+}
+
+// canary returns the canary connector for the tracker.
+// Returns nil when canary mode is not enabled, the tracker is
+// not tagged `canary` or no canary connector is defined for the kind.
+// The connector is built using a copy of the tracker (and identities)
+// so that the (encrypted) identities are decrypted independently of
+// the stable connector. Must be called before the stable connector
+// is built using the tracker.
+func canary(t *model.Tracker) (conn Connector) {
+	if !Settings.Hub.Tracker.Canary {
+		return
+	}
+	fn, found := Canaries[t.Kind]
+	if !found {
+		return
+	}
+	for _, tag := range t.Tags {
+		if tag.Name == CanaryTag {
+			copied := *t
+			if t.Identity != nil {
+				identity := *t.Identity
+				copied.Identity = &identity
+			}
+			if t.TunnelIdentity != nil {
+				identity := *t.TunnelIdentity
+				copied.TunnelIdentity = &identity
+			}
+			conn = fn()
+			conn.With(&copied)
+			return
+		}
+	}
+	return
+}
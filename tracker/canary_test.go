@@ -0,0 +1,117 @@
+package tracker
+
+import (
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fakeConnector is a canary connector with a fixed outcome.
+type fakeConnector struct {
+	Connector
+	tracker   *model.Tracker
+	connected bool
+	err       error
+}
+
+func (r *fakeConnector) With(t *model.Tracker) {
+	r.tracker = t
+	_ = r.tracker.Identity.Decrypt()
+}
+
+func (r *fakeConnector) TestConnection() (connected bool, err error) {
+	connected = r.connected
+	err = r.err
+	return
+}
+
+func withCanary(fake *fakeConnector) (restore func()) {
+	enabled := Settings.Hub.Tracker.Canary
+	Settings.Hub.Tracker.Canary = true
+	Canaries[JiraCloud] = func() Connector { return fake }
+	restore = func() {
+		Settings.Hub.Tracker.Canary = enabled
+		delete(Canaries, JiraCloud)
+	}
+	return
+}
+
+func canaryTracker(g *gomega.WithT) (t *model.Tracker) {
+	identity := &model.Identity{
+		Kind:     BasicAuth,
+		User:     "elmer",
+		Password: "secret",
+	}
+	err := identity.Encrypt(&model.Identity{})
+	g.Expect(err).To(gomega.BeNil())
+	t = &model.Tracker{
+		Kind:     JiraCloud,
+		URL:      "https://jira.example.com",
+		Identity: identity,
+		Tags: []model.Tag{
+			{Name: CanaryTag},
+		},
+	}
+	t.ID = 1
+	return
+}
+
+func TestCanary(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	fake := &fakeConnector{}
+	defer withCanary(fake)()
+
+	// Not tagged.
+	tracker := canaryTracker(g)
+	tracker.Tags = nil
+	g.Expect(canary(tracker)).To(gomega.BeNil())
+
+	// Identities decrypted independently of the stable connector.
+	tracker = canaryTracker(g)
+	conn := canary(tracker)
+	g.Expect(conn).To(gomega.Equal(fake))
+	_, err := NewConnector(tracker)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(tracker.Identity.Password).To(gomega.Equal("secret"))
+	g.Expect(fake.tracker.Identity.Password).To(gomega.Equal("secret"))
+	g.Expect(fake.tracker.Identity).ToNot(gomega.BeIdenticalTo(tracker.Identity))
+}
+
+func TestCanaryDiverged(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	g.Expect(err).To(gomega.BeNil())
+	err = db.AutoMigrate(&model.Tracker{})
+	g.Expect(err).To(gomega.BeNil())
+	m := Manager{DB: db}
+	tracker := canaryTracker(g)
+	stable := Outcome{Connected: true}
+
+	// Matched.
+	fake := &fakeConnector{connected: true}
+	defer withCanary(fake)()
+	reported := len(Divergences())
+	matched := m.canary(tracker, canary(tracker), stable)
+	g.Expect(matched).To(gomega.BeTrue())
+	g.Expect(len(Divergences())).To(gomega.Equal(reported))
+
+	// Diverged.
+	fake.connected = false
+	fake.err = &ConnectionError{Category: ErrorAuth, Reason: "denied"}
+	matched = m.canary(tracker, canary(tracker), stable)
+	g.Expect(matched).To(gomega.BeFalse())
+	reports := Divergences()
+	g.Expect(len(reports)).To(gomega.Equal(reported + 1))
+	last := reports[len(reports)-1]
+	g.Expect(last.Tracker).To(gomega.Equal(uint(1)))
+	g.Expect(last.Stable).To(gomega.Equal(stable))
+	g.Expect(last.Canary.Connected).To(gomega.BeFalse())
+	g.Expect(last.Canary.Category).To(gomega.Equal(ErrorAuth))
+
+	// No canary connector.
+	matched = m.canary(tracker, nil, stable)
+	g.Expect(matched).To(gomega.BeTrue())
+}
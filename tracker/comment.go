@@ -0,0 +1,57 @@
+package tracker
+
+import (
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/gorm"
+)
+
+// SyncComments replaces the (stored) comments on the ticket
+// with the comments on the remote issue.
+func SyncComments(db *gorm.DB, conn CommentConnector, t *model.Ticket) (comments []model.TicketComment, err error) {
+	remote, err := conn.Comments(t)
+	if err != nil {
+		return
+	}
+	comments = []model.TicketComment{}
+	for _, c := range remote {
+		comments = append(comments, commentModel(t, &c))
+	}
+	err = db.Transaction(func(tx *gorm.DB) (err error) {
+		err = tx.Where("TicketID", t.ID).Delete(&model.TicketComment{}).Error
+		if err != nil {
+			return
+		}
+		if len(comments) > 0 {
+			err = tx.Create(&comments).Error
+		}
+		return
+	})
+	return
+}
+
+// AddComment adds a comment to the remote issue and stores it.
+func AddComment(db *gorm.DB, conn CommentConnector, t *model.Ticket, comment *Comment) (m *model.TicketComment, err error) {
+	err = conn.AddComment(t, comment)
+	if err != nil {
+		return
+	}
+	added := commentModel(t, comment)
+	err = db.Create(&added).Error
+	if err != nil {
+		return
+	}
+	m = &added
+	return
+}
+
+// commentModel builds a comment model.
+func commentModel(t *model.Ticket, c *Comment) (m model.TicketComment) {
+	m = model.TicketComment{
+		TicketID:  t.ID,
+		Reference: c.ID,
+		Author:    c.Author,
+		Body:      c.Body,
+		Created:   c.Created,
+	}
+	return
+}
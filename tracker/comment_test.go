@@ -0,0 +1,73 @@
+package tracker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+func TestComments(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db, err := gorm.Open(
+		sqlite.Open("file::memory:"),
+		&gorm.Config{
+			NamingStrategy: &schema.NamingStrategy{
+				SingularTable: true,
+				NoLowerCase:   true,
+			},
+		})
+	g.Expect(err).To(gomega.BeNil())
+	err = db.AutoMigrate(&model.TicketComment{})
+	g.Expect(err).To(gomega.BeNil())
+	comments := []jiraComment{
+		{ID: "1", Body: "first", Created: "2024-01-02T03:04:05.000+0000"},
+	}
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				g.Expect(r.URL.Path).To(gomega.Equal("/" + JiraEndpointIssue + "/MIG-1/comment"))
+				if r.Method == http.MethodPost {
+					c := jiraComment{}
+					_ = json.NewDecoder(r.Body).Decode(&c)
+					c.ID = "2"
+					comments = append(comments, c)
+					_ = json.NewEncoder(w).Encode(c)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"comments": comments})
+			}))
+	defer server.Close()
+	tracker := canaryTracker(g)
+	tracker.URL = server.URL
+	conn, err := NewConnector(tracker)
+	g.Expect(err).To(gomega.BeNil())
+	cConn := conn.(CommentConnector)
+	ticket := &model.Ticket{Reference: "MIG-1"}
+	ticket.ID = 3
+
+	// Synchronized.
+	stored, err := SyncComments(db, cConn, ticket)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(len(stored)).To(gomega.Equal(1))
+	g.Expect(stored[0].Body).To(gomega.Equal("first"))
+	g.Expect(stored[0].Created.Year()).To(gomega.Equal(2024))
+
+	// Added.
+	added, err := AddComment(db, cConn, ticket, &Comment{Body: "second"})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(added.Reference).To(gomega.Equal("2"))
+	stored, err = SyncComments(db, cConn, ticket)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(len(stored)).To(gomega.Equal(2))
+	var count int64
+	err = db.Model(&model.TicketComment{}).Where("TicketID", ticket.ID).Count(&count).Error
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(count).To(gomega.Equal(int64(2)))
+}
@@ -0,0 +1,227 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+// Metadata keys.
+const (
+	// DebugLogging (RFC3339) time when debug logging expires.
+	DebugLogging = "debugLogging"
+)
+
+// Debug logging limits.
+const (
+	// MaxDebugLogging the maximum debug logging duration.
+	MaxDebugLogging = time.Hour * 24
+	// MaxDebugBody the maximum (logged) body length.
+	MaxDebugBody = 4096
+)
+
+// Redacted replaces sensitive values in the debug log.
+const Redacted = "<redacted>"
+
+// sensitive (lowercase) words in header, parameter
+// and field names of which the values are redacted.
+var sensitive = []string{
+	"api-key",
+	"api_key",
+	"apikey",
+	"authorization",
+	"cookie",
+	"credential",
+	"password",
+	"secret",
+	"session",
+	"token",
+}
+
+// debugSchema validates the debug logging metadata.
+func debugSchema(v interface{}) (err error) {
+	s, cast := v.(string)
+	if !cast {
+		err = errors.New("must be a string")
+		return
+	}
+	expiry, pErr := time.Parse(time.RFC3339, s)
+	if pErr != nil {
+		err = errors.New("must be an RFC3339 timestamp")
+		return
+	}
+	if expiry.After(time.Now().Add(MaxDebugLogging)) {
+		err = errors.New("must expire within " + MaxDebugLogging.String())
+		return
+	}
+	return
+}
+
+// Debugging returns true when debug logging
+// is enabled (and not expired) for the tracker.
+func Debugging(t *model.Tracker) (enabled bool) {
+	md := Metadata{}
+	md.With(t)
+	s := md.String(DebugLogging)
+	if s == "" {
+		return
+	}
+	expiry, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return
+	}
+	enabled = time.Now().Before(expiry)
+	return
+}
+
+// debugLog logs redacted requests and responses.
+type debugLog struct {
+	tracker *model.Tracker
+}
+
+// Request logs the request.
+func (r *debugLog) Request(req *http.Request) {
+	var body []byte
+	if req.GetBody != nil {
+		reader, err := req.GetBody()
+		if err == nil {
+			body, _ = io.ReadAll(reader)
+			_ = reader.Close()
+		}
+	}
+	Log.Info(
+		"Request.",
+		"tracker",
+		r.tracker.ID,
+		"method",
+		req.Method,
+		"url",
+		r.url(req.URL),
+		"header",
+		r.header(req.Header),
+		"body",
+		r.body(body))
+}
+
+// Response logs the response.
+// The body is read and replaced.
+func (r *debugLog) Response(resp *http.Response, err error, duration time.Duration) {
+	if err != nil {
+		Log.Info(
+			"Request failed.",
+			"tracker",
+			r.tracker.ID,
+			"duration",
+			duration.String(),
+			"error",
+			err.Error())
+		return
+	}
+	var body []byte
+	if resp.Body != nil {
+		body, _ = io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	Log.Info(
+		"Response.",
+		"tracker",
+		r.tracker.ID,
+		"status",
+		resp.StatusCode,
+		"duration",
+		duration.String(),
+		"header",
+		r.header(resp.Header),
+		"body",
+		r.body(body))
+}
+
+// url returns the URL with credentials and
+// sensitive parameters redacted.
+func (r *debugLog) url(u *url.URL) (s string) {
+	redacted := *u
+	q := redacted.Query()
+	for k := range q {
+		if r.sensitive(k) {
+			q.Set(k, Redacted)
+		}
+	}
+	redacted.RawQuery = q.Encode()
+	s = redacted.Redacted()
+	return
+}
+
+// header returns the header with sensitive values redacted.
+func (r *debugLog) header(h http.Header) (redacted map[string]string) {
+	redacted = make(map[string]string)
+	for k := range h {
+		if r.sensitive(k) {
+			redacted[k] = Redacted
+		} else {
+			redacted[k] = h.Get(k)
+		}
+	}
+	return
+}
+
+// body returns the (json) body with sensitive fields redacted.
+// Non-json bodies are not logged.
+func (r *debugLog) body(b []byte) (s string) {
+	if len(b) == 0 {
+		return
+	}
+	var v interface{}
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		s = Redacted
+		return
+	}
+	bfr := bytes.Buffer{}
+	encoder := json.NewEncoder(&bfr)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(r.redact(v))
+	s = strings.TrimSpace(bfr.String())
+	if len(s) > MaxDebugBody {
+		s = s[:MaxDebugBody] + "..."
+	}
+	return
+}
+
+// redact sensitive fields.
+func (r *debugLog) redact(v interface{}) interface{} {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range node {
+			if r.sensitive(k) {
+				node[k] = Redacted
+			} else {
+				node[k] = r.redact(fv)
+			}
+		}
+	case []interface{}:
+		for i := range node {
+			node[i] = r.redact(node[i])
+		}
+	}
+	return v
+}
+
+// sensitive returns true when the name is sensitive.
+func (r *debugLog) sensitive(name string) (matched bool) {
+	name = strings.ToLower(name)
+	for _, word := range sensitive {
+		if strings.Contains(name, word) {
+			matched = true
+			break
+		}
+	}
+	return
+}
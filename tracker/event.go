@@ -0,0 +1,120 @@
+package tracker
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	liberr "github.com/jortel/go-utils/error"
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EventConnector is implemented by connectors for trackers
+// that deliver (issue) events to a webhook.
+type EventConnector interface {
+	// Event parses the (delivered) event.
+	// Returns nil when the event is not relevant.
+	Event(body []byte) (event *Event, err error)
+}
+
+// Event an (issue) event delivered by the remote.
+type Event struct {
+	// Reference of the (remote) issue.
+	Reference string
+	// Deleted the issue has been deleted.
+	Deleted bool
+	// Status (normalized) of the issue.
+	Status string
+	// RemoteStatus (unmapped) of the issue.
+	RemoteStatus string
+}
+
+// SignatureHeader the (HMAC) signature header.
+const SignatureHeader = "X-Hub-Signature"
+
+// Verify the event was delivered by the remote using the shared secret.
+// The (sha256) HMAC signature is verified when reported. Otherwise, the
+// token (eg: URL query) must match the secret.
+func Verify(secret string, body []byte, signature, token string) (verified bool) {
+	if secret == "" {
+		return
+	}
+	if signature != "" {
+		digest, found := strings.CutPrefix(signature, "sha256=")
+		if !found {
+			return
+		}
+		reported, err := hex.DecodeString(digest)
+		if err != nil {
+			return
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		_, _ = mac.Write(body)
+		verified = hmac.Equal(reported, mac.Sum(nil))
+		return
+	}
+	verified = subtle.ConstantTimeCompare([]byte(secret), []byte(token)) == 1
+	return
+}
+
+// WebhookSecret returns the (decrypted) shared secret
+// of the tracker webhook identity.
+func WebhookSecret(t *model.Tracker) (secret string) {
+	if t.WebhookIdentity == nil {
+		return
+	}
+	identity := *t.WebhookIdentity
+	err := identity.Decrypt()
+	if err != nil {
+		Log.Error(err, "Decrypt webhook identity failed.", "tracker", t.ID)
+		return
+	}
+	secret = identity.Key
+	if secret == "" {
+		secret = identity.Password
+	}
+	return
+}
+
+// ApplyEvent updates the ticket referenced by the event.
+// The ticket is deleted when the issue has been deleted.
+// Returns nil when the referenced ticket is not found.
+func ApplyEvent(db *gorm.DB, t *model.Tracker, event *Event) (ticket *model.Ticket, err error) {
+	ticket = &model.Ticket{}
+	q := db.Where("TrackerID", t.ID)
+	q = q.Where("Reference", event.Reference)
+	err = q.First(ticket).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		ticket = nil
+		return
+	}
+	if event.Deleted {
+		err = db.Delete(ticket).Error
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	ticket.Status = event.Status
+	ticket.RemoteStatus = event.RemoteStatus
+	ticket.LastUpdated = time.Now()
+	mapping := StatusMapping{}
+	mapping.With(t)
+	mapping.Apply(ticket)
+	err = db.Omit(clause.Associations).Save(ticket).Error
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
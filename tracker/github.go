@@ -0,0 +1,380 @@
+package tracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/metrics"
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+const (
+	GitHubEndpointUser       = "user"
+	GitHubEndpointUserRepos  = "user/repos"
+	GitHubEndpointRepos      = "repos"
+	GitHubEndpointOrgs       = "orgs"
+	GitHubEndpointUsers      = "users"
+	GitHubEndpointIssueTypes = "issue-types"
+)
+
+// GitHub metadata keys.
+const (
+	// GitHubOwner (optional) the organization (or user) owning
+	// the repositories (projects). Defaults to the repositories
+	// of the authenticated user.
+	GitHubOwner = "owner"
+)
+
+// GitHubIssue the (default) issue type used when the
+// owner does not define issue types.
+const GitHubIssue = "Issue"
+
+// GitHubPageSize the (max) page size.
+const GitHubPageSize = 100
+
+// GitHubConnector for the GitHub (issues) API.
+// Projects are repositories identified by full name (owner/repo).
+type GitHubConnector struct {
+	tracker *model.Tracker
+}
+
+// GitHubVersion the github connector version.
+// Incremented when the connector behavior changes.
+const GitHubVersion = "1.0.0"
+
+// Version returns the connector version.
+func (r *GitHubConnector) Version() string {
+	return GitHubVersion
+}
+
+// With updates the connector with the Tracker model.
+func (r *GitHubConnector) With(t *model.Tracker) {
+	r.tracker = t
+	_ = r.tracker.Identity.Decrypt()
+	if r.tracker.TunnelIdentity != nil {
+		_ = r.tracker.TunnelIdentity.Decrypt()
+	}
+}
+
+// Create the ticket (issue) in GitHub.
+// The issue type is applied unless it is the default.
+func (r *GitHubConnector) Create(t *model.Ticket) (err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	issue := map[string]interface{}{}
+	_ = json.Unmarshal(t.Fields, &issue)
+	issue["title"] = summary(t)
+	issue["body"] = description(t)
+	if t.Kind != "" && t.Kind != GitHubIssue {
+		issue["type"] = t.Kind
+	}
+	created := githubIssue{}
+	err = client.do(
+		http.MethodPost,
+		fmt.Sprintf("%s/%s/issues", GitHubEndpointRepos, t.Parent),
+		issue,
+		&created)
+	if err != nil {
+		t.Error = true
+		t.Message = err.Error()
+		t.LastUpdated = time.Now()
+		err = nil
+		return
+	}
+	t.Created = true
+	t.Error = false
+	t.Message = ""
+	t.Reference = strconv.Itoa(created.Number)
+	t.Link = created.HTMLURL
+	t.LastUpdated = time.Now()
+	metrics.IssuesExported.Inc()
+	return
+}
+
+// RefreshAll retrieves fresh status information for all the tracker's tickets.
+func (r *GitHubConnector) RefreshAll() (tickets map[*model.Ticket]bool, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	tickets = make(map[*model.Ticket]bool)
+	for i := range r.tracker.Tickets {
+		t := &r.tracker.Tickets[i]
+		if t.Reference == "" {
+			continue
+		}
+		tickets[t] = false
+		issue := githubIssue{}
+		err = client.do(
+			http.MethodGet,
+			fmt.Sprintf("%s/%s/issues/%s", GitHubEndpointRepos, t.Parent, t.Reference),
+			nil,
+			&issue)
+		if err != nil {
+			if NotFound(err) {
+				err = nil
+				continue
+			}
+			return
+		}
+		t.Status = issue.status()
+		t.RemoteStatus = issue.State
+		t.LastUpdated = time.Now()
+		tickets[t] = true
+	}
+	return
+}
+
+// Projects returns a list of Projects (repositories).
+func (r *GitHubConnector) Projects() (projects []Project, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	md := Metadata{}
+	md.With(r.tracker)
+	owner := md.String(GitHubOwner)
+	path := GitHubEndpointUserRepos
+	if owner != "" {
+		path = fmt.Sprintf("%s/%s/repos", GitHubEndpointOrgs, owner)
+	}
+	for n := 1; ; n++ {
+		var list []githubRepo
+		err = client.do(
+			http.MethodGet,
+			fmt.Sprintf("%s?per_page=%d&page=%d", path, GitHubPageSize, n),
+			nil,
+			&list)
+		if err != nil {
+			// Owned by a user (not an organization).
+			if n == 1 && owner != "" && NotFound(err) {
+				path = fmt.Sprintf("%s/%s/repos", GitHubEndpointUsers, owner)
+				n = 0
+				err = nil
+				continue
+			}
+			return
+		}
+		for _, repo := range list {
+			if !repo.HasIssues {
+				continue
+			}
+			projects = append(projects, repo.project())
+		}
+		if len(list) < GitHubPageSize {
+			break
+		}
+	}
+	return
+}
+
+// ProjectSearch returns a page of Projects.
+// The (full) repository list is filtered and paginated locally.
+func (r *GitHubConnector) ProjectSearch(filter ProjectFilter) (page ProjectPage, err error) {
+	projects, err := r.Projects()
+	if err != nil {
+		return
+	}
+	page.With(projects, filter)
+	return
+}
+
+// Project returns a Project (repository).
+func (r *GitHubConnector) Project(id string) (project Project, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	repo := githubRepo{}
+	err = client.do(
+		http.MethodGet,
+		fmt.Sprintf("%s/%s", GitHubEndpointRepos, id),
+		nil,
+		&repo)
+	if err != nil {
+		return
+	}
+	project = repo.project()
+	return
+}
+
+// IssueTypes returns a list of IssueTypes for a Project (repository).
+// The issue types are defined by the owning organization. The
+// default type is returned when the owner does not define issue types.
+func (r *GitHubConnector) IssueTypes(id string) (issueTypes []IssueType, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	owner, _, _ := strings.Cut(id, "/")
+	var list []struct {
+		Name      string `json:"name"`
+		IsEnabled bool   `json:"is_enabled"`
+	}
+	err = client.do(
+		http.MethodGet,
+		fmt.Sprintf("%s/%s/%s", GitHubEndpointOrgs, owner, GitHubEndpointIssueTypes),
+		nil,
+		&list)
+	if err != nil {
+		if !NotFound(err) {
+			return
+		}
+		err = nil
+	}
+	for _, t := range list {
+		if !t.IsEnabled {
+			continue
+		}
+		issueTypes = append(
+			issueTypes,
+			IssueType{
+				ID:   t.Name,
+				Name: t.Name,
+			})
+	}
+	if len(issueTypes) == 0 {
+		issueTypes = append(
+			issueTypes,
+			IssueType{
+				ID:   GitHubIssue,
+				Name: GitHubIssue,
+			})
+	}
+	return
+}
+
+// RequiredFields returns the fields required to create an issue.
+// GitHub issues do not have required (custom) fields.
+func (r *GitHubConnector) RequiredFields(project, kind string) (fields []string, err error) {
+	return
+}
+
+// TestConnection to GitHub.
+// The health checks may be replaced by a single probe
+// using the `healthPath` metadata.
+func (r *GitHubConnector) TestConnection() (connected bool, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	md := Metadata{}
+	md.With(r.tracker)
+	check := HealthCheck{
+		Step: StepUser,
+		Path: GitHubEndpointUser,
+	}
+	path := md.String(HealthPath)
+	if path != "" {
+		check = HealthCheck{
+			Step: HealthPath,
+			Path: path,
+		}
+	}
+	err = client.healthCheck(check)
+	if err != nil {
+		return
+	}
+	connected = true
+	return
+}
+
+// client returns a github client.
+func (r *GitHubConnector) client() (client *restClient, err error) {
+	client, err = newRestClient(r.tracker, r.tracker.URL)
+	if err != nil {
+		return
+	}
+	client.header.Set("X-GitHub-Api-Version", "2022-11-28")
+	client.failed = func(status int, body []byte) error {
+		gErr := &githubError{Status: status}
+		_ = json.Unmarshal(body, gErr)
+		return gErr
+	}
+	return
+}
+
+// githubRepo github repository.
+type githubRepo struct {
+	ID        int    `json:"id"`
+	FullName  string `json:"full_name"`
+	HasIssues bool   `json:"has_issues"`
+}
+
+// project returns the (tracker) project.
+func (r *githubRepo) project() (p Project) {
+	p = Project{
+		ID:   r.FullName,
+		Name: r.FullName,
+	}
+	return
+}
+
+// githubIssue github issue.
+type githubIssue struct {
+	Number    int    `json:"number"`
+	HTMLURL   string `json:"html_url"`
+	State     string `json:"state"`
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+}
+
+// status returns a normalized status.
+// Open issues are in progress once assigned.
+func (r *githubIssue) status() (s string) {
+	switch r.State {
+	case "open":
+		s = New
+		if len(r.Assignees) > 0 {
+			s = InProgress
+		}
+	case "closed":
+		s = Done
+	default:
+		s = Unknown
+	}
+	return
+}
+
+// githubError reports an error returned by the GitHub API.
+type githubError struct {
+	Status  int    `json:"-"`
+	Message string `json:"message"`
+	Errors  []struct {
+		Field   string `json:"field"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// StatusCode returns the (http) status.
+func (r *githubError) StatusCode() int {
+	return r.Status
+}
+
+// Error reports the consolidated error message.
+func (r *githubError) Error() (s string) {
+	s = fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status))
+	if r.Message != "" {
+		s += ": " + r.Message
+	}
+	var details []string
+	for _, e := range r.Errors {
+		if e.Message != "" {
+			details = append(details, e.Message)
+		} else {
+			details = append(details, e.Field+": "+e.Code)
+		}
+	}
+	if len(details) > 0 {
+		s += " (" + strings.Join(details, ", ") + ")"
+	}
+	return
+}
@@ -0,0 +1,111 @@
+package tracker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestGitHub(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	var created map[string]interface{}
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					_, _ = w.Write([]byte(`{"message":"Bad credentials"}`))
+					return
+				}
+				switch r.Method + " " + r.URL.Path {
+				case "GET /user":
+					_, _ = w.Write([]byte(`{"login":"elmer"}`))
+				case "GET /orgs/acme/repos":
+					_, _ = w.Write([]byte(`[
+						{"full_name":"acme/app","has_issues":true},
+						{"full_name":"acme/docs","has_issues":false}]`))
+				case "GET /orgs/acme/issue-types":
+					_, _ = w.Write([]byte(`[
+						{"name":"Task","is_enabled":true},
+						{"name":"Bug","is_enabled":false}]`))
+				case "POST /repos/acme/app/issues":
+					_ = json.NewDecoder(r.Body).Decode(&created)
+					_, _ = w.Write([]byte(`{"number":7,"html_url":"https://github.com/acme/app/issues/7"}`))
+				case "GET /repos/acme/app/issues/7":
+					_, _ = w.Write([]byte(`{"number":7,"state":"open","assignees":[{"login":"elmer"}]}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+				}
+			}))
+	defer server.Close()
+	identity := &model.Identity{
+		Kind: BearerAuth,
+		Key:  "token",
+	}
+	err := identity.Encrypt(&model.Identity{})
+	g.Expect(err).To(gomega.BeNil())
+	tracker := &model.Tracker{
+		Kind:     GitHub,
+		URL:      server.URL,
+		Metadata: []byte(`{"owner":"acme"}`),
+		Identity: identity,
+	}
+	conn, err := NewConnector(tracker)
+	g.Expect(err).To(gomega.BeNil())
+
+	connected, err := conn.TestConnection()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(connected).To(gomega.BeTrue())
+
+	projects, err := conn.Projects()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(projects).To(gomega.Equal([]Project{{ID: "acme/app", Name: "acme/app"}}))
+
+	issueTypes, err := conn.IssueTypes("acme/app")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(issueTypes).To(gomega.Equal([]IssueType{{ID: "Task", Name: "Task"}}))
+
+	// Issue types not defined.
+	issueTypes, err = conn.IssueTypes("elmer/app")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(issueTypes).To(gomega.Equal([]IssueType{{ID: GitHubIssue, Name: GitHubIssue}}))
+
+	ticket := model.Ticket{
+		Kind:        "Task",
+		Parent:      "acme/app",
+		Fields:      []byte(`{"labels":["migration"]}`),
+		Application: &model.Application{Name: "app"},
+	}
+	err = conn.Create(&ticket)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ticket.Error).To(gomega.BeFalse(), ticket.Message)
+	g.Expect(ticket.Reference).To(gomega.Equal("7"))
+	g.Expect(ticket.Link).To(gomega.Equal("https://github.com/acme/app/issues/7"))
+	g.Expect(created["type"]).To(gomega.Equal("Task"))
+	g.Expect(created["title"]).To(gomega.Equal("Migrate app"))
+	g.Expect(created["labels"]).To(gomega.Equal([]interface{}{"migration"}))
+
+	missing := model.Ticket{Parent: "acme/app", Reference: "8"}
+	tracker.Tickets = []model.Ticket{ticket, missing}
+	tickets, err := conn.RefreshAll()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(len(tickets)).To(gomega.Equal(2))
+	for t, found := range tickets {
+		g.Expect(found).To(gomega.Equal(t.Reference == "7"))
+		if found {
+			g.Expect(t.Status).To(gomega.Equal(InProgress))
+		}
+	}
+
+	// Not authorized.
+	tracker.Identity.Key = "invalid"
+	_, err = conn.TestConnection()
+	category, reason := Categorize(err)
+	g.Expect(category).To(gomega.Equal(ErrorAuth))
+	g.Expect(reason).To(gomega.Equal("user: 401 Unauthorized: Bad credentials"))
+}
@@ -0,0 +1,337 @@
+package tracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/metrics"
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+const (
+	GitLabEndpointBase     = "api/v4"
+	GitLabEndpointUser     = "user"
+	GitLabEndpointProjects = "projects"
+)
+
+// GitLab issue types.
+const (
+	GitLabIssue    = "issue"
+	GitLabIncident = "incident"
+	GitLabTask     = "task"
+)
+
+// GitLabPageSize the (max) page size.
+const GitLabPageSize = 100
+
+// GitLabConnector for the GitLab (issues) API.
+// Authenticated using a personal access token (bearer) identity.
+// Projects are identified by the (numeric) project ID.
+type GitLabConnector struct {
+	tracker *model.Tracker
+}
+
+// GitLabVersion the gitlab connector version.
+// Incremented when the connector behavior changes.
+const GitLabVersion = "1.0.0"
+
+// Version returns the connector version.
+func (r *GitLabConnector) Version() string {
+	return GitLabVersion
+}
+
+// With updates the connector with the Tracker model.
+func (r *GitLabConnector) With(t *model.Tracker) {
+	r.tracker = t
+	_ = r.tracker.Identity.Decrypt()
+	if r.tracker.TunnelIdentity != nil {
+		_ = r.tracker.TunnelIdentity.Decrypt()
+	}
+}
+
+// Create the ticket (issue) in GitLab.
+func (r *GitLabConnector) Create(t *model.Ticket) (err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	issue := map[string]interface{}{}
+	_ = json.Unmarshal(t.Fields, &issue)
+	issue["title"] = summary(t)
+	issue["description"] = description(t)
+	if t.Kind != "" {
+		issue["issue_type"] = t.Kind
+	}
+	created := gitlabIssue{}
+	err = client.do(
+		http.MethodPost,
+		fmt.Sprintf("%s/%s/issues", GitLabEndpointProjects, url.PathEscape(t.Parent)),
+		issue,
+		&created)
+	if err != nil {
+		t.Error = true
+		t.Message = err.Error()
+		t.LastUpdated = time.Now()
+		err = nil
+		return
+	}
+	t.Created = true
+	t.Error = false
+	t.Message = ""
+	t.Reference = strconv.Itoa(created.IID)
+	t.Link = created.WebURL
+	t.LastUpdated = time.Now()
+	metrics.IssuesExported.Inc()
+	return
+}
+
+// RefreshAll retrieves fresh status information for all the tracker's tickets.
+func (r *GitLabConnector) RefreshAll() (tickets map[*model.Ticket]bool, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	tickets = make(map[*model.Ticket]bool)
+	for i := range r.tracker.Tickets {
+		t := &r.tracker.Tickets[i]
+		if t.Reference == "" {
+			continue
+		}
+		tickets[t] = false
+		issue := gitlabIssue{}
+		err = client.do(
+			http.MethodGet,
+			fmt.Sprintf(
+				"%s/%s/issues/%s",
+				GitLabEndpointProjects,
+				url.PathEscape(t.Parent),
+				t.Reference),
+			nil,
+			&issue)
+		if err != nil {
+			if NotFound(err) {
+				err = nil
+				continue
+			}
+			return
+		}
+		t.Status = issue.status()
+		t.RemoteStatus = issue.State
+		t.LastUpdated = time.Now()
+		tickets[t] = true
+	}
+	return
+}
+
+// Projects returns a list of Projects.
+// Projects of which the identity is a member and
+// for which issues are enabled.
+func (r *GitLabConnector) Projects() (projects []Project, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	for n := 1; ; n++ {
+		var list []gitlabProject
+		err = client.do(
+			http.MethodGet,
+			fmt.Sprintf(
+				"%s?membership=true&with_issues_enabled=true&per_page=%d&page=%d",
+				GitLabEndpointProjects,
+				GitLabPageSize,
+				n),
+			nil,
+			&list)
+		if err != nil {
+			return
+		}
+		for _, p := range list {
+			projects = append(projects, p.project())
+		}
+		if len(list) < GitLabPageSize {
+			break
+		}
+	}
+	return
+}
+
+// ProjectSearch returns a page of Projects.
+// The (full) project list is filtered and paginated locally.
+func (r *GitLabConnector) ProjectSearch(filter ProjectFilter) (page ProjectPage, err error) {
+	projects, err := r.Projects()
+	if err != nil {
+		return
+	}
+	page.With(projects, filter)
+	return
+}
+
+// Project returns a Project.
+func (r *GitLabConnector) Project(id string) (project Project, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	p := gitlabProject{}
+	err = client.do(
+		http.MethodGet,
+		fmt.Sprintf("%s/%s", GitLabEndpointProjects, url.PathEscape(id)),
+		nil,
+		&p)
+	if err != nil {
+		return
+	}
+	project = p.project()
+	return
+}
+
+// IssueTypes returns a list of IssueTypes for a Project.
+// GitLab issue types are not defined by the project.
+func (r *GitLabConnector) IssueTypes(id string) (issueTypes []IssueType, err error) {
+	_, err = r.Project(id)
+	if err != nil {
+		return
+	}
+	for _, name := range []string{GitLabIssue, GitLabIncident, GitLabTask} {
+		issueTypes = append(
+			issueTypes,
+			IssueType{
+				ID:   name,
+				Name: strings.ToUpper(name[:1]) + name[1:],
+			})
+	}
+	return
+}
+
+// RequiredFields returns the fields required to create an issue.
+// GitLab issues do not have required (custom) fields.
+func (r *GitLabConnector) RequiredFields(project, kind string) (fields []string, err error) {
+	return
+}
+
+// TestConnection to GitLab.
+// The health checks may be replaced by a single probe
+// using the `healthPath` metadata.
+func (r *GitLabConnector) TestConnection() (connected bool, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	md := Metadata{}
+	md.With(r.tracker)
+	check := HealthCheck{
+		Step: StepUser,
+		Path: GitLabEndpointUser,
+	}
+	path := md.String(HealthPath)
+	if path != "" {
+		check = HealthCheck{
+			Step: HealthPath,
+			Path: path,
+		}
+	}
+	err = client.healthCheck(check)
+	if err != nil {
+		return
+	}
+	connected = true
+	return
+}
+
+// client returns a gitlab client.
+func (r *GitLabConnector) client() (client *restClient, err error) {
+	baseURL := strings.TrimSuffix(r.tracker.URL, "/") + "/" + GitLabEndpointBase
+	client, err = newRestClient(r.tracker, baseURL)
+	if err != nil {
+		return
+	}
+	client.failed = func(status int, body []byte) error {
+		gErr := &gitlabError{Status: status}
+		_ = json.Unmarshal(body, gErr)
+		return gErr
+	}
+	return
+}
+
+// gitlabProject gitlab project.
+type gitlabProject struct {
+	ID                int    `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+}
+
+// project returns the (tracker) project.
+func (r *gitlabProject) project() (p Project) {
+	p = Project{
+		ID:   strconv.Itoa(r.ID),
+		Name: r.PathWithNamespace,
+	}
+	return
+}
+
+// gitlabIssue gitlab issue.
+type gitlabIssue struct {
+	IID       int    `json:"iid"`
+	WebURL    string `json:"web_url"`
+	State     string `json:"state"`
+	Assignees []struct {
+		Username string `json:"username"`
+	} `json:"assignees"`
+}
+
+// status returns a normalized status.
+// Open issues are in progress once assigned.
+func (r *gitlabIssue) status() (s string) {
+	switch r.State {
+	case "opened":
+		s = New
+		if len(r.Assignees) > 0 {
+			s = InProgress
+		}
+	case "closed":
+		s = Done
+	default:
+		s = Unknown
+	}
+	return
+}
+
+// gitlabError reports an error returned by the GitLab API.
+// The message may be a string or (validation) errors by field.
+type gitlabError struct {
+	Status      int         `json:"-"`
+	Message     interface{} `json:"message"`
+	Description string      `json:"error_description"`
+}
+
+// StatusCode returns the (http) status.
+func (r *gitlabError) StatusCode() int {
+	return r.Status
+}
+
+// Error reports the consolidated error message.
+func (r *gitlabError) Error() (s string) {
+	s = fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status))
+	switch m := r.Message.(type) {
+	case string:
+		if !strings.HasPrefix(m, strconv.Itoa(r.Status)) {
+			s += ": " + m
+		}
+	case map[string]interface{}:
+		var details []string
+		for k, v := range m {
+			details = append(details, fmt.Sprintf("%s: %v", k, v))
+		}
+		sort.Strings(details)
+		s += ": " + strings.Join(details, ", ")
+	}
+	if r.Description != "" {
+		s += ": " + r.Description
+	}
+	return
+}
@@ -0,0 +1,106 @@
+package tracker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestGitLab(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	var created map[string]interface{}
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					_, _ = w.Write([]byte(`{"message":"401 Unauthorized"}`))
+					return
+				}
+				switch r.Method + " " + r.URL.Path {
+				case "GET /api/v4/user":
+					_, _ = w.Write([]byte(`{"username":"elmer"}`))
+				case "GET /api/v4/projects":
+					_, _ = w.Write([]byte(`[{"id":4,"path_with_namespace":"acme/app"}]`))
+				case "GET /api/v4/projects/4":
+					_, _ = w.Write([]byte(`{"id":4,"path_with_namespace":"acme/app"}`))
+				case "POST /api/v4/projects/4/issues":
+					_ = json.NewDecoder(r.Body).Decode(&created)
+					_, _ = w.Write([]byte(`{"iid":3,"web_url":"https://gitlab.com/acme/app/-/issues/3"}`))
+				case "GET /api/v4/projects/4/issues/3":
+					_, _ = w.Write([]byte(`{"iid":3,"state":"closed"}`))
+				case "POST /api/v4/projects/5/issues":
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"message":{"title":["is too long"]}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"message":"404 Project Not Found"}`))
+				}
+			}))
+	defer server.Close()
+	identity := &model.Identity{
+		Kind: BearerAuth,
+		Key:  "token",
+	}
+	err := identity.Encrypt(&model.Identity{})
+	g.Expect(err).To(gomega.BeNil())
+	tracker := &model.Tracker{
+		Kind:     GitLab,
+		URL:      server.URL + "/",
+		Identity: identity,
+	}
+	conn, err := NewConnector(tracker)
+	g.Expect(err).To(gomega.BeNil())
+
+	connected, err := conn.TestConnection()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(connected).To(gomega.BeTrue())
+
+	projects, err := conn.Projects()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(projects).To(gomega.Equal([]Project{{ID: "4", Name: "acme/app"}}))
+
+	issueTypes, err := conn.IssueTypes("4")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(len(issueTypes)).To(gomega.Equal(3))
+	g.Expect(issueTypes[0]).To(gomega.Equal(IssueType{ID: GitLabIssue, Name: "Issue"}))
+	_, err = conn.IssueTypes("9")
+	g.Expect(NotFound(err)).To(gomega.BeTrue())
+
+	ticket := model.Ticket{
+		Kind:        GitLabIssue,
+		Parent:      "4",
+		Application: &model.Application{Name: "app"},
+	}
+	err = conn.Create(&ticket)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ticket.Error).To(gomega.BeFalse(), ticket.Message)
+	g.Expect(ticket.Reference).To(gomega.Equal("3"))
+	g.Expect(created["issue_type"]).To(gomega.Equal(GitLabIssue))
+
+	// Rejected.
+	rejected := model.Ticket{Parent: "5", Application: &model.Application{Name: "app"}}
+	err = conn.Create(&rejected)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(rejected.Error).To(gomega.BeTrue())
+	g.Expect(rejected.Message).To(gomega.Equal("400 Bad Request: title: [is too long]"))
+
+	tracker.Tickets = []model.Ticket{ticket}
+	tickets, err := conn.RefreshAll()
+	g.Expect(err).To(gomega.BeNil())
+	for t, found := range tickets {
+		g.Expect(found).To(gomega.BeTrue())
+		g.Expect(t.Status).To(gomega.Equal(Done))
+	}
+
+	// Not authorized.
+	tracker.Identity.Key = "invalid"
+	_, err = conn.TestConnection()
+	category, reason := Categorize(err)
+	g.Expect(category).To(gomega.Equal(ErrorAuth))
+	g.Expect(reason).To(gomega.Equal("user: 401 Unauthorized"))
+}
@@ -1,12 +1,14 @@
 package tracker
 
 import (
-	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,11 +21,14 @@ import (
 const IssueTypeEpic = "Epic"
 
 const (
-	JiraEndpointBase    = "rest/api/2"
-	JiraEndpointProject = JiraEndpointBase + "/project"
-	JiraEndpointIssue   = JiraEndpointBase + "/issue"
-	JiraEndpointSearch  = JiraEndpointBase + "/search"
-	JiraEndpointMyself  = JiraEndpointBase + "/myself"
+	JiraEndpointBase          = "rest/api/2"
+	JiraEndpointProject       = JiraEndpointBase + "/project"
+	JiraEndpointProjectSearch = JiraEndpointProject + "/search"
+	JiraEndpointIssue         = JiraEndpointBase + "/issue"
+	JiraEndpointCreateMeta    = JiraEndpointIssue + "/createmeta"
+	JiraEndpointSearch        = JiraEndpointBase + "/search"
+	JiraEndpointMyself        = JiraEndpointBase + "/myself"
+	JiraEndpointServerInfo    = JiraEndpointBase + "/serverInfo"
 )
 
 // JiraConnector for the Jira Cloud API
@@ -31,10 +36,22 @@ type JiraConnector struct {
 	tracker *model.Tracker
 }
 
+// JiraVersion the jira connector version.
+// Incremented when the connector behavior changes.
+const JiraVersion = "1.1.0"
+
+// Version returns the connector version.
+func (r *JiraConnector) Version() string {
+	return JiraVersion
+}
+
 // With updates the connector with the Tracker model.
 func (r *JiraConnector) With(t *model.Tracker) {
 	r.tracker = t
 	_ = r.tracker.Identity.Decrypt()
+	if r.tracker.TunnelIdentity != nil {
+		_ = r.tracker.TunnelIdentity.Decrypt()
+	}
 }
 
 // Create the ticket in Jira.
@@ -46,12 +63,17 @@ func (r *JiraConnector) Create(t *model.Ticket) (err error) {
 
 	i := jira.Issue{
 		Fields: &jira.IssueFields{
-			Summary:     fmt.Sprintf("Migrate %s", t.Application.Name),
-			Description: "Created by Konveyor.",
+			Summary:     summary(t),
+			Description: description(t),
 			Type:        jira.IssueType{ID: t.Kind},
 			Project:     jira.Project{ID: t.Parent},
 		},
 	}
+	fields := map[string]interface{}{}
+	_ = json.Unmarshal(t.Fields, &fields)
+	if len(fields) > 0 {
+		i.Fields.Unknowns = fields
+	}
 
 	req, err := client.NewRequest(http.MethodPost, JiraEndpointIssue, &i)
 	if err != nil {
@@ -134,6 +156,9 @@ func (r *JiraConnector) RefreshAll() (tickets map[*model.Ticket]bool, err error)
 		}
 		t.LastUpdated = lastUpdated
 		t.Status = status(issue)
+		if issue.Fields != nil && issue.Fields.Status != nil {
+			t.RemoteStatus = issue.Fields.Status.Name
+		}
 		tickets[t] = true
 	}
 	return
@@ -167,6 +192,66 @@ func (r *JiraConnector) Projects() (projects []Project, err error) {
 	return
 }
 
+// ProjectSearch returns a page of Projects.
+// Jira Cloud supports paginated search. Otherwise, the (full)
+// project list is filtered and paginated locally.
+func (r *JiraConnector) ProjectSearch(filter ProjectFilter) (page ProjectPage, err error) {
+	if r.tracker.Kind != JiraCloud {
+		var projects []Project
+		projects, err = r.Projects()
+		if err != nil {
+			return
+		}
+		page.With(projects, filter)
+		return
+	}
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+
+	query := url.Values{}
+	query.Add("startAt", strconv.Itoa(filter.Offset))
+	if filter.Limit > 0 {
+		query.Add("maxResults", strconv.Itoa(filter.Limit))
+	}
+	if filter.Query != "" {
+		query.Add("query", filter.Query)
+	}
+	req, err := client.NewRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s?%s", JiraEndpointProjectSearch, query.Encode()),
+		nil)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	results := struct {
+		Values     []jira.Project `json:"values"`
+		StartAt    int            `json:"startAt"`
+		MaxResults int            `json:"maxResults"`
+		Total      int            `json:"total"`
+		IsLast     bool           `json:"isLast"`
+	}{}
+	response, err := client.Do(req, &results)
+	err = handleJiraError(response, err)
+	if err != nil {
+		return
+	}
+	page.Total = results.Total
+	for _, p := range results.Values {
+		project := Project{
+			ID:   p.ID,
+			Name: p.Name,
+		}
+		page.Projects = append(page.Projects, project)
+	}
+	if !results.IsLast {
+		page.Next = results.StartAt + len(results.Values)
+	}
+	return
+}
+
 // Project returns a Project.
 func (r *JiraConnector) Project(id string) (project Project, err error) {
 	client, err := r.client()
@@ -224,11 +309,165 @@ func (r *JiraConnector) IssueTypes(id string) (issueTypes []IssueType, err error
 	return
 }
 
+// AddWorklog logs work on the ticket (issue).
+func (r *JiraConnector) AddWorklog(t *model.Ticket, worklog *Worklog) (err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+
+	record := jira.WorklogRecord{
+		TimeSpentSeconds: worklog.TimeSpent,
+		Comment:          worklog.Note,
+	}
+	req, err := client.NewRequest(
+		http.MethodPost,
+		fmt.Sprintf("%s/%s/worklog", JiraEndpointIssue, t.Reference),
+		&record)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	response, err := client.Do(req, &record)
+	err = handleJiraError(response, err)
+	if err != nil {
+		return
+	}
+	worklog.ID = record.ID
+	return
+}
+
+// jiraTime the jira (timestamp) format.
+const jiraTime = "2006-01-02T15:04:05.000-0700"
+
+// jiraComment jira comment.
+type jiraComment struct {
+	ID     string `json:"id,omitempty"`
+	Body   string `json:"body"`
+	Author *struct {
+		DisplayName string `json:"displayName"`
+	} `json:"author,omitempty"`
+	Created string `json:"created,omitempty"`
+}
+
+// comment returns the (tracker) comment.
+func (r *jiraComment) comment() (c Comment) {
+	c.ID = r.ID
+	c.Body = r.Body
+	if r.Author != nil {
+		c.Author = r.Author.DisplayName
+	}
+	c.Created, _ = time.Parse(jiraTime, r.Created)
+	return
+}
+
+// Comments lists the comments on the ticket (issue).
+func (r *JiraConnector) Comments(t *model.Ticket) (comments []Comment, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	req, err := client.NewRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/%s/comment", JiraEndpointIssue, t.Reference),
+		nil)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	results := struct {
+		Comments []jiraComment `json:"comments"`
+	}{}
+	response, err := client.Do(req, &results)
+	err = handleJiraError(response, err)
+	if err != nil {
+		return
+	}
+	for i := range results.Comments {
+		comments = append(comments, results.Comments[i].comment())
+	}
+	return
+}
+
+// AddComment adds a comment to the ticket (issue).
+func (r *JiraConnector) AddComment(t *model.Ticket, comment *Comment) (err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	added := jiraComment{Body: comment.Body}
+	req, err := client.NewRequest(
+		http.MethodPost,
+		fmt.Sprintf("%s/%s/comment", JiraEndpointIssue, t.Reference),
+		&added)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	response, err := client.Do(req, &added)
+	err = handleJiraError(response, err)
+	if err != nil {
+		return
+	}
+	*comment = added.comment()
+	return
+}
+
+// RequiredFields returns the (keys of) fields required to create
+// an issue of the specified type in the project.
+func (r *JiraConnector) RequiredFields(project, kind string) (fields []string, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+
+	query := url.Values{}
+	query.Add("projectIds", project)
+	query.Add("issuetypeIds", kind)
+	query.Add("expand", "projects.issuetypes.fields")
+	req, err := client.NewRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s?%s", JiraEndpointCreateMeta, query.Encode()),
+		nil)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	meta := jira.CreateMetaInfo{}
+	response, err := client.Do(req, &meta)
+	err = handleJiraError(response, err)
+	if err != nil {
+		return
+	}
+	for _, p := range meta.Projects {
+		for _, t := range p.IssueTypes {
+			required, mErr := t.GetMandatoryFields()
+			if mErr != nil {
+				err = liberr.Wrap(mErr)
+				return
+			}
+			for _, key := range required {
+				fields = append(fields, key)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return
+}
+
 // client builds a Jira API client for the tracker.
 func (r *JiraConnector) client() (client *jira.Client, err error) {
-	transport := http.DefaultTransport.(*http.Transport).Clone()
-	if r.tracker.Insecure {
-		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	var transport *http.Transport
+	md := Metadata{}
+	md.With(r.tracker)
+	tunnel := TunnelConfig{}
+	if tunnel.With(md) {
+		transport, err = tunnelTransport(r.tracker, tunnel)
+		if err != nil {
+			return
+		}
+	} else {
+		transport = transports.get(r.tracker)
 	}
 
 	var httpclient *http.Client
@@ -250,7 +489,7 @@ func (r *JiraConnector) client() (client *jira.Client, err error) {
 		err = liberr.New("unsupported identity kind", "kind", r.tracker.Identity.Kind)
 		return
 	}
-	wrapped := clientWrapper{client: httpclient}
+	wrapped := clientWrapper{client: httpclient, tracker: r.tracker}
 	client, err = jira.NewClient(&wrapped, r.tracker.URL)
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -259,27 +498,179 @@ func (r *JiraConnector) client() (client *jira.Client, err error) {
 	return
 }
 
-// TestConnection to Jira Cloud.
+// Health check steps.
+const (
+	StepServerInfo = "serverInfo"
+	StepMyself     = "myself"
+)
+
+// jiraHealthChecks health checks by tracker kind.
+var jiraHealthChecks = map[string][]HealthCheck{
+	JiraCloud: {
+		{
+			Step:   StepServerInfo,
+			Path:   JiraEndpointServerInfo,
+			Verify: deployment("Cloud"),
+		},
+		{
+			Step: StepMyself,
+			Path: JiraEndpointMyself,
+		},
+	},
+	JiraOnPrem: {
+		{
+			Step:   StepServerInfo,
+			Path:   JiraEndpointServerInfo,
+			Verify: deployment("Server", "DataCenter"),
+		},
+		{
+			Step: StepMyself,
+			Path: JiraEndpointMyself,
+		},
+	},
+}
+
+// TestConnection to Jira.
+// The health checks for the kind are run in order.
+// The health checks may be replaced by a single probe
+// using the `healthPath` metadata.
 func (r *JiraConnector) TestConnection() (connected bool, err error) {
 	client, err := r.client()
 	if err != nil {
 		return
 	}
 
-	req, err := client.NewRequest(http.MethodGet, JiraEndpointMyself, nil)
+	md := Metadata{}
+	md.With(r.tracker)
+	checks := jiraHealthChecks[r.tracker.Kind]
+	path := md.String(HealthPath)
+	if path != "" {
+		checks = []HealthCheck{
+			{
+				Step: HealthPath,
+				Path: path,
+			},
+		}
+	}
+	for _, check := range checks {
+		err = r.healthCheck(client, check)
+		if err != nil {
+			return
+		}
+	}
+
+	connected = true
+	return
+}
+
+// healthCheck runs a health check step.
+func (r *JiraConnector) healthCheck(client *jira.Client, check HealthCheck) (err error) {
+	req, err := client.NewRequest(http.MethodGet, check.Path, nil)
 	if err != nil {
 		err = liberr.Wrap(err)
 		return
 	}
-
-	var user jira.User
-	resp, err := client.Do(req, &user)
+	var v interface{}
+	decoded := map[string]interface{}{}
+	if check.Verify != nil {
+		v = &decoded
+	}
+	resp, err := client.Do(req, v)
 	if err != nil {
+		inner := &ConnectionError{}
+		if errors.As(err, &inner) {
+			cErr := *inner
+			cErr.Step = check.Step
+			err = &cErr
+			return
+		}
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
 		err = handleJiraError(resp, err)
+		cErr := &ConnectionError{
+			Category: CategoryOf(err.Error()),
+			Reason:   err.Error(),
+			Step:     check.Step,
+		}
+		switch status {
+		case http.StatusUnauthorized,
+			http.StatusForbidden:
+			cErr.Category = ErrorAuth
+		case http.StatusNotFound:
+			cErr.Category = ErrorNotFound
+			cErr.Reason = fmt.Sprintf("health probe (%s) not found.", check.Path)
+		}
+		err = cErr
 		return
 	}
+	if check.Verify != nil {
+		err = check.Verify(decoded)
+		if err != nil {
+			err = &ConnectionError{
+				Category: ErrorUnknown,
+				Reason:   err.Error(),
+				Step:     check.Step,
+			}
+			return
+		}
+	}
+	return
+}
 
-	connected = true
+// deployment returns a health check that verifies
+// the (serverInfo) deployment type.
+func deployment(types ...string) func(v map[string]interface{}) error {
+	return func(v map[string]interface{}) (err error) {
+		found, _ := v["deploymentType"].(string)
+		for _, t := range types {
+			if found == t {
+				return
+			}
+		}
+		err = fmt.Errorf(
+			"deployment type (%s) not in: %s.",
+			found,
+			strings.Join(types, ", "))
+		return
+	}
+}
+
+// Jira (webhook) events.
+const (
+	JiraEventIssueUpdated = "jira:issue_updated"
+	JiraEventIssueDeleted = "jira:issue_deleted"
+)
+
+// Event parses an (issue) event delivered to the webhook.
+// Events other than issue updated and deleted are not relevant.
+func (r *JiraConnector) Event(body []byte) (event *Event, err error) {
+	delivered := struct {
+		WebhookEvent string     `json:"webhookEvent"`
+		Issue        jira.Issue `json:"issue"`
+	}{}
+	err = json.Unmarshal(body, &delivered)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	switch delivered.WebhookEvent {
+	case JiraEventIssueUpdated:
+		issue := &delivered.Issue
+		event = &Event{
+			Reference: issue.Key,
+			Status:    status(issue),
+		}
+		if issue.Fields != nil && issue.Fields.Status != nil {
+			event.RemoteStatus = issue.Fields.Status.Name
+		}
+	case JiraEventIssueDeleted:
+		event = &Event{
+			Reference: delivered.Issue.Key,
+			Deleted:   true,
+		}
+	}
 	return
 }
 
@@ -334,14 +725,32 @@ func handleJiraError(response *jira.Response, in error) (out error) {
 
 // clientWrapper wraps the http client used by the jira client.
 type clientWrapper struct {
-	client *http.Client
+	client  *http.Client
+	tracker *model.Tracker
 }
 
 // Do applies an Accept header before performing the request.
-func (r *clientWrapper) Do(req *http.Request) (*http.Response, error) {
+// The rate-limit reported by the remote is captured.
+// The (redacted) request and response are logged when
+// debug logging is enabled for the tracker.
+func (r *clientWrapper) Do(req *http.Request) (resp *http.Response, err error) {
 	req.Header.Add("Accept", "application/json")
-	resp, err := r.client.Do(req)
-	return resp, err
+	if Debugging(r.tracker) {
+		d := debugLog{tracker: r.tracker}
+		d.Request(req)
+		started := time.Now()
+		defer func() {
+			d.Response(resp, err, time.Since(started))
+		}()
+	}
+	resp, err = r.client.Do(req)
+	if err == nil {
+		rateLimit := RateLimit{}
+		if rateLimit.With(resp.Header) {
+			rateLimit.Store(r.tracker)
+		}
+	}
+	return
 }
 
 // jiraError is a catch-all structure for the various ways that the
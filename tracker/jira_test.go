@@ -0,0 +1,90 @@
+package tracker
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestHealthCheck(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/" + JiraEndpointServerInfo:
+					_, _ = w.Write([]byte(`{"deploymentType":"Server"}`))
+				case "/" + JiraEndpointMyself:
+					w.WriteHeader(http.StatusUnauthorized)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+	defer server.Close()
+	address, hostKey, stop := sshServer(g)
+	defer stop()
+
+	cases := []struct {
+		kind     string
+		metadata map[string]interface{}
+		category string
+		step     string
+	}{
+		{
+			kind:     JiraCloud,
+			category: ErrorUnknown,
+			step:     StepServerInfo,
+		},
+		{
+			kind:     JiraOnPrem,
+			category: ErrorAuth,
+			step:     StepMyself,
+		},
+		{
+			kind:     JiraOnPrem,
+			metadata: map[string]interface{}{HealthPath: "/missing"},
+			category: ErrorNotFound,
+			step:     HealthPath,
+		},
+		{
+			kind: JiraOnPrem,
+			metadata: map[string]interface{}{
+				Tunnel: map[string]interface{}{
+					TunnelHost:    address,
+					TunnelHostKey: hostKey,
+				},
+			},
+			category: ErrorTunnel,
+			step:     StepServerInfo,
+		},
+	}
+	identityID := uint(3)
+	for _, c := range cases {
+		tracker := canaryTracker(g)
+		tracker.Tags = nil
+		tracker.Kind = c.kind
+		tracker.URL = server.URL
+		tracker.Metadata, _ = json.Marshal(c.metadata)
+		tracker.TunnelIdentity = &model.Identity{
+			User:     "elmer",
+			Password: "secret",
+		}
+		err := tracker.TunnelIdentity.Encrypt(&model.Identity{})
+		g.Expect(err).To(gomega.BeNil())
+		tracker.TunnelIdentityID = &identityID
+		conn, err := NewConnector(tracker)
+		g.Expect(err).To(gomega.BeNil())
+		connected, err := conn.TestConnection()
+		g.Expect(connected).To(gomega.BeFalse())
+		cErr := &ConnectionError{}
+		g.Expect(errors.As(err, &cErr)).To(gomega.BeTrue(), c.step)
+		g.Expect(cErr.Category).To(gomega.Equal(c.category), c.step)
+		g.Expect(cErr.Step).To(gomega.Equal(c.step))
+	}
+	tunnels.evict(identityID)
+}
@@ -2,34 +2,159 @@ package tracker
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jortel/go-utils/logr"
 	"github.com/konveyor/tackle2-hub/model"
+	"github.com/konveyor/tackle2-hub/settings"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 var (
-	Log = logr.WithName("tickets")
+	Settings = &settings.Settings
+	Log      = logr.WithName("tickets")
 )
 
+// reconciled is set after the manager has
+// completed its first reconcile pass.
+var reconciled atomic.Bool
+
+// Ready returns true when the manager has completed at least
+// one reconcile pass or reconciliation has been paused.
+func Ready() (ready bool) {
+	ready = Settings.Hub.Tracker.Paused || reconciled.Load()
+	return
+}
+
+// retest trackers to be tested on the next pass.
+var retest = struct {
+	mutex sync.Mutex
+	ids   map[uint]bool
+}{
+	ids: make(map[uint]bool),
+}
+
+// Retest requests that the trackers be tested (connection)
+// on the next pass rather than when next scheduled.
+func Retest(ids ...uint) {
+	retest.mutex.Lock()
+	defer retest.mutex.Unlock()
+	for _, id := range ids {
+		retest.ids[id] = true
+	}
+}
+
+// retested returns (and clears) the trackers to be retested.
+func retested() (ids map[uint]bool) {
+	retest.mutex.Lock()
+	defer retest.mutex.Unlock()
+	ids = retest.ids
+	retest.ids = make(map[uint]bool)
+	return
+}
+
+// Evict the connection state derived from the identity.
+// Established tunnels and cached project pages are dropped
+// so that the (updated) identity is used on the next connection.
+func Evict(identity uint) {
+	tunnels.evict(identity)
+	projectCache.evict(identity)
+}
+
+// TestNow tests the connection to the tracker immediately
+// (rather than on the next pass) and updates the tracker status.
+func TestNow(db *gorm.DB, tracker *model.Tracker) (err error) {
+	m := Manager{DB: db}
+	err = m.testConnection(tracker)
+	if err != nil {
+		return
+	}
+	m.saveRateLimit(tracker)
+	return
+}
+
+// CreateNow (re)tries creating the ticket immediately
+// (rather than on the next pass). The ticket application must
+// be loaded (with tags, business service and migration wave).
+func CreateNow(db *gorm.DB, ticket *model.Ticket) (err error) {
+	m := Manager{DB: db}
+	tracker := &model.Tracker{}
+	err = db.Preload(clause.Associations).First(tracker, ticket.TrackerID).Error
+	if err != nil {
+		return
+	}
+	conn, err := NewConnector(tracker)
+	if err != nil {
+		return
+	}
+	err = m.create(conn, tracker, ticket)
+	if err != nil {
+		return
+	}
+	m.saveRateLimit(tracker)
+	return
+}
+
 // Intervals
 const (
 	IntervalCreateRetry  = time.Second * 30
+	IntervalCreateMax    = time.Hour
 	IntervalRefresh      = time.Second * 30
 	IntervalConnected    = time.Second * 60
 	IntervalDisconnected = time.Second * 10
+	IntervalPrune        = time.Minute
+)
+
+// Backoff returns the (exponential) delay before retrying
+// to create a ticket after the number of failed attempts.
+func Backoff(attempts int) (d time.Duration) {
+	d = IntervalCreateRetry
+	for n := 1; n < attempts && d < IntervalCreateMax; n++ {
+		d *= 2
+	}
+	if d > IntervalCreateMax {
+		d = IntervalCreateMax
+	}
+	return
+}
+
+// Interval returns the polling interval set on the tracker
+// or the default. Disconnected trackers are retried using
+// the (global) disconnected interval.
+func Interval(tracker *model.Tracker, d time.Duration) (interval time.Duration) {
+	interval = d
+	if tracker.Interval > 0 {
+		interval = time.Duration(tracker.Interval) * time.Second
+	}
+	return
+}
+
+// Event outcomes.
+const (
+	EventConnected = "Connected"
+	EventFailed    = "Failed"
 )
 
 // Manager provides ticket management.
 type Manager struct {
 	// DB
 	DB *gorm.DB
+	// pruned last time events were pruned.
+	pruned time.Time
+	// queue reconcile queue (gauges).
+	queue queue
 }
 
 // Run the manager.
 func (m *Manager) Run(ctx context.Context) {
+	go m.backfill()
+	if Settings.Hub.Tracker.Paused {
+		Log.Info("Paused.")
+		return
+	}
 	go func() {
 		Log.Info("Started.")
 		defer Log.Info("Died.")
@@ -39,9 +164,13 @@ func (m *Manager) Run(ctx context.Context) {
 				return
 			default:
 				time.Sleep(time.Second)
+				m.queue.Reset()
 				m.testConnections()
 				m.refreshTickets()
 				m.createPending()
+				m.queue.Report()
+				m.pruneEvents()
+				reconciled.Store(true)
 			}
 		}
 	}()
@@ -55,20 +184,39 @@ func (m *Manager) testConnections() {
 		Log.Error(result.Error, "Failed to query trackers.")
 		return
 	}
+	forced := retested()
+	var due []*model.Tracker
+	var dueAt []time.Time
 	for i := range list {
 		tracker := &list[i]
+		if Throttled(tracker) {
+			continue
+		}
 		var ago time.Time
 		if tracker.Connected {
-			ago = tracker.LastUpdated.Add(IntervalConnected)
+			ago = tracker.LastUpdated.Add(Interval(tracker, IntervalConnected))
 		} else {
 			ago = tracker.LastUpdated.Add(IntervalDisconnected)
 		}
+		if forced[tracker.ID] {
+			due = append(due, tracker)
+			dueAt = append(dueAt, time.Now())
+			continue
+		}
 		if ago.Before(time.Now()) {
-			err := m.testConnection(tracker)
-			if err != nil {
-				Log.Error(err, "Failed to update tracker", "tracker", tracker.ID)
-			}
+			due = append(due, tracker)
+			dueAt = append(dueAt, ago)
+		}
+	}
+	m.queue.Add(len(due))
+	for i, tracker := range due {
+		m.queue.Started(dueAt[i])
+		err := m.testConnection(tracker)
+		if err != nil {
+			Log.Error(err, "Failed to update tracker", "tracker", tracker.ID)
 		}
+		m.saveRateLimit(tracker)
+		m.queue.Done()
 	}
 
 	return
@@ -76,24 +224,59 @@ func (m *Manager) testConnections() {
 
 // testConnection to the external tracker.
 func (m *Manager) testConnection(tracker *model.Tracker) (err error) {
+	canaryConn := canary(tracker)
 	conn, err := NewConnector(tracker)
 	if err != nil {
 		return
 	}
+	started := time.Now()
 	connected, err := conn.TestConnection()
+	event := &model.TrackerEvent{
+		TrackerID: tracker.ID,
+		Time:      started,
+		Outcome:   EventConnected,
+		Duration:  time.Since(started).Milliseconds(),
+	}
 	if err != nil {
 		Log.Error(err, "Connection test failed.", "tracker", tracker.ID)
 		tracker.Message = err.Error()
+		tracker.ErrorCategory, tracker.StatusReason = Categorize(err)
+		event.Category = tracker.ErrorCategory
+		event.Reason = tracker.StatusReason
 		err = nil
 	}
+	if !connected {
+		event.Outcome = EventFailed
+	}
+	m.record(event)
+	stable := Outcome{
+		Connected: connected,
+		Category:  event.Category,
+		Reason:    event.Reason,
+	}
+	if !m.canary(tracker, canaryConn, stable) {
+		return
+	}
 
 	if connected {
 		tracker.Message = ""
+		tracker.ErrorCategory = ""
+		tracker.StatusReason = ""
 	}
 	tracker.Connected = connected
 	tracker.LastUpdated = time.Now()
+	tracker.ConnectorVersion = Version(conn)
 
-	result := m.DB.Save(tracker)
+	db := m.DB.Model(tracker)
+	db = db.Select(
+		"Connected",
+		"Message",
+		"StatusReason",
+		"ErrorCategory",
+		"ConnectorVersion",
+		"LastUpdated",
+		"UpdateTime")
+	result := db.Updates(tracker)
 	if result.Error != nil {
 		err = result.Error
 		return
@@ -101,6 +284,68 @@ func (m *Manager) testConnection(tracker *model.Tracker) (err error) {
 	return
 }
 
+// canary tests the connection using the canary connector (when
+// defined) and compares the outcome. Divergences are reported and
+// the tracker status is not updated. Returns true when matched.
+func (m *Manager) canary(tracker *model.Tracker, conn Connector, stable Outcome) (matched bool) {
+	matched = true
+	if conn == nil {
+		return
+	}
+	candidate := Outcome{}
+	candidate.With(conn.TestConnection())
+	if candidate.Connected == stable.Connected &&
+		candidate.Category == stable.Category {
+		return
+	}
+	matched = false
+	Log.Info(
+		"Canary diverged.",
+		"tracker",
+		tracker.ID,
+		"stable",
+		stable,
+		"canary",
+		candidate)
+	diverged(Divergence{
+		Tracker: tracker.ID,
+		Time:    time.Now(),
+		Stable:  stable,
+		Canary:  candidate,
+	})
+	result := m.DB.Model(tracker).UpdateColumn("LastUpdated", time.Now())
+	if result.Error != nil {
+		Log.Error(result.Error, "Failed to update tracker.", "tracker", tracker.ID)
+	}
+	return
+}
+
+// record a reconcile event.
+func (m *Manager) record(event *model.TrackerEvent) {
+	result := m.DB.Create(event)
+	if result.Error != nil {
+		Log.Error(result.Error, "Failed to record event.", "tracker", event.TrackerID)
+	}
+}
+
+// pruneEvents deletes events older than the retention.
+func (m *Manager) pruneEvents() {
+	if time.Since(m.pruned) < IntervalPrune {
+		return
+	}
+	m.pruned = time.Now()
+	retention := time.Duration(Settings.Hub.Tracker.Retention) * time.Minute
+	cutoff := m.pruned.Add(-retention)
+	result := m.DB.Where("Time < ?", cutoff).Delete(&model.TrackerEvent{})
+	if result.Error != nil {
+		Log.Error(result.Error, "Failed to prune events.")
+		return
+	}
+	if result.RowsAffected > 0 {
+		Log.V(1).Info("Events pruned.", "count", result.RowsAffected)
+	}
+}
+
 func (m *Manager) refreshTickets() {
 	var list []model.Tracker
 	result := m.DB.Preload(clause.Associations).Where("connected = ?", true).Find(&list)
@@ -108,15 +353,26 @@ func (m *Manager) refreshTickets() {
 		Log.Error(result.Error, "Failed to query trackers.")
 		return
 	}
+	var due []*model.Tracker
 	for i := range list {
 		tracker := &list[i]
-		ago := tracker.LastUpdated.Add(IntervalRefresh)
+		if Throttled(tracker) {
+			continue
+		}
+		ago := tracker.LastUpdated.Add(Interval(tracker, IntervalRefresh))
 		if ago.Before(time.Now()) {
-			err := m.refresh(tracker)
-			if err != nil {
-				Log.Error(err, "Failed to refresh tracker.", "tracker", tracker.ID)
-			}
+			due = append(due, tracker)
+		}
+	}
+	m.queue.Add(len(due))
+	for _, tracker := range due {
+		m.queue.Started(tracker.LastUpdated.Add(Interval(tracker, IntervalRefresh)))
+		err := m.refresh(tracker)
+		if err != nil {
+			Log.Error(err, "Failed to refresh tracker.", "tracker", tracker.ID)
 		}
+		m.saveRateLimit(tracker)
+		m.queue.Done()
 	}
 }
 
@@ -131,8 +387,11 @@ func (m *Manager) refresh(tracker *model.Tracker) (err error) {
 	if err != nil {
 		return
 	}
+	mapping := StatusMapping{}
+	mapping.With(tracker)
 	for t, found := range tickets {
 		if found {
+			mapping.Apply(t)
 			result := m.DB.Save(t)
 			if result.Error != nil {
 				Log.Error(result.Error, "Failed to save ticket.", "ticket", t.ID)
@@ -153,41 +412,96 @@ func (m *Manager) refresh(tracker *model.Tracker) (err error) {
 // Create pending tickets.
 func (m *Manager) createPending() {
 	var list []model.Tracker
-	result := m.DB.Preload(clause.Associations).Preload("Tickets.Application").Where("connected = ?", true).Find(&list)
+	db := m.DB.Preload(clause.Associations)
+	db = db.Preload("Tickets.Application.Tags")
+	db = db.Preload("Tickets.Application.BusinessService")
+	db = db.Preload("Tickets.Application.MigrationWave")
+	result := db.Where("connected = ?", true).Find(&list)
 	if result.Error != nil {
 		Log.Error(result.Error, "Failed to query trackers.")
 		return
 	}
 	for i := range list {
 		tracker := &list[i]
+		if Throttled(tracker) {
+			continue
+		}
 		conn, err := NewConnector(tracker)
 		if err != nil {
 			Log.Error(err, "Unable to build connector for tracker.", "tracker", tracker.ID)
 			continue
 		}
+		var due []*model.Ticket
+		var dueAt []time.Time
 		for j := range tracker.Tickets {
 			t := &tracker.Tickets[j]
-			ago := t.LastUpdated.Add(IntervalCreateRetry)
 			// if the ticket has already been created, or if there was previously an error
-			// creating it and the retry window has not yet passed, skip this ticket.
-			if t.Created || (t.Error && !ago.Before(time.Now())) {
+			// creating it and the next attempt (backoff) is not yet due, skip this ticket.
+			if t.Created || (t.Error && !t.NextAttempt.Before(time.Now())) {
 				continue
 			}
-			err = m.create(conn, t)
+			due = append(due, t)
+			if t.Error {
+				dueAt = append(dueAt, t.NextAttempt)
+			} else {
+				dueAt = append(dueAt, t.CreateTime)
+			}
+		}
+		// items not processed (throttled) remain queued.
+		m.queue.Add(len(due))
+		for j, t := range due {
+			m.queue.Started(dueAt[j])
+			err = m.create(conn, tracker, t)
 			if err != nil {
 				Log.Error(err, "Failed to create ticket.", "ticket", t.ID)
 			}
+			m.queue.Done()
+			if Throttled(tracker) {
+				break
+			}
 		}
+		m.saveRateLimit(tracker)
+	}
+}
+
+// saveRateLimit saves the rate-limit reported by the remote.
+func (m *Manager) saveRateLimit(tracker *model.Tracker) {
+	if len(tracker.RateLimit) == 0 {
+		return
+	}
+	db := m.DB.Model(&model.Tracker{})
+	db = db.Where("ID", tracker.ID)
+	err := db.Update("RateLimit", tracker.RateLimit).Error
+	if err != nil {
+		Log.Error(err, "Failed to save rate-limit.", "tracker", tracker.ID)
 	}
 }
 
 // Create the ticket in its tracker.
-func (m *Manager) create(conn Connector, ticket *model.Ticket) (err error) {
-	err = conn.Create(ticket)
+// The ticket is rendered using the (tracker and wave) templates.
+// The attempt is recorded and the next attempt is scheduled
+// (backoff) when the attempt failed.
+func (m *Manager) create(conn Connector, tracker *model.Tracker, ticket *model.Ticket) (err error) {
+	ticket.Attempts++
+	ticket.LastAttempt = time.Now()
+	ticket.NextAttempt = time.Time{}
+	err = RenderTicket(tracker, ticket)
 	if err != nil {
-		return
+		ticket.Error = true
+		ticket.Message = err.Error()
+		ticket.LastUpdated = time.Now()
+	} else {
+		err = conn.Create(ticket)
+		if err != nil {
+			ticket.Error = true
+			ticket.Message = err.Error()
+			ticket.LastUpdated = time.Now()
+		}
+	}
+	if ticket.Error {
+		ticket.NextAttempt = ticket.LastAttempt.Add(Backoff(ticket.Attempts))
 	}
-	result := m.DB.Save(ticket)
+	result := m.DB.Omit(clause.Associations).Save(ticket)
 	if result.Error != nil {
 		err = result.Error
 		return
@@ -0,0 +1,183 @@
+package tracker
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestTestConnection(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	g.Expect(err).To(gomega.BeNil())
+	err = db.AutoMigrate(&model.Tracker{}, &model.TrackerEvent{})
+	g.Expect(err).To(gomega.BeNil())
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			}))
+	defer server.Close()
+	tracker := canaryTracker(g)
+	tracker.Tags = nil
+	tracker.Name = "jira"
+	tracker.URL = server.URL
+	tracker.Connected = true
+	err = db.Omit("Identity").Create(tracker).Error
+	g.Expect(err).To(gomega.BeNil())
+
+	// Edited while testing.
+	err = db.Model(&model.Tracker{}).Where("ID", tracker.ID).Update("Name", "edited").Error
+	g.Expect(err).To(gomega.BeNil())
+
+	m := Manager{DB: db}
+	err = m.testConnection(tracker)
+	g.Expect(err).To(gomega.BeNil())
+	saved := &model.Tracker{}
+	err = db.First(saved, tracker.ID).Error
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(saved.Name).To(gomega.Equal("edited"))
+	g.Expect(saved.Connected).To(gomega.BeFalse())
+	g.Expect(saved.ErrorCategory).To(gomega.Equal(ErrorAuth))
+	g.Expect(saved.Message).ToNot(gomega.BeEmpty())
+	g.Expect(saved.ConnectorVersion).To(gomega.Equal(JiraVersion))
+	g.Expect(saved.UpdateTime.After(tracker.CreateTime)).To(gomega.BeTrue())
+}
+
+func TestSaveRateLimit(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	g.Expect(err).To(gomega.BeNil())
+	err = db.AutoMigrate(&model.Tracker{})
+	g.Expect(err).To(gomega.BeNil())
+	tracker := &model.Tracker{
+		Name:     "jira",
+		Metadata: model.JSON(`{"tunnel":{"host":"bastion"}}`),
+	}
+	err = db.Omit("Identity").Create(tracker).Error
+	g.Expect(err).To(gomega.BeNil())
+	created := tracker.UpdateTime
+
+	// Edited while reconciling.
+	err = db.Model(&model.Tracker{}).Where("ID", tracker.ID).Update("Metadata", model.JSON(`{}`)).Error
+	g.Expect(err).To(gomega.BeNil())
+
+	time.Sleep(time.Millisecond)
+	rateLimit := RateLimit{Limit: 10, RetryAfter: 30}
+	rateLimit.Store(tracker)
+	m := Manager{DB: db}
+	m.saveRateLimit(tracker)
+	saved := &model.Tracker{}
+	err = db.First(saved, tracker.ID).Error
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(string(saved.Metadata)).To(gomega.Equal(`{}`))
+	g.Expect(saved.UpdateTime.After(created)).To(gomega.BeTrue())
+	loaded := RateLimit{}
+	g.Expect(loaded.Load(saved)).To(gomega.BeTrue())
+	g.Expect(loaded.Limit).To(gomega.Equal(10))
+	g.Expect(loaded.RetryAfter).To(gomega.Equal(30))
+}
+
+func TestInterval(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	tracker := &model.Tracker{}
+	g.Expect(Interval(tracker, IntervalConnected)).To(gomega.Equal(IntervalConnected))
+	tracker.Interval = 900
+	g.Expect(Interval(tracker, IntervalConnected)).To(gomega.Equal(15 * time.Minute))
+}
+
+func TestBackoff(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	g.Expect(Backoff(0)).To(gomega.Equal(IntervalCreateRetry))
+	g.Expect(Backoff(1)).To(gomega.Equal(IntervalCreateRetry))
+	g.Expect(Backoff(3)).To(gomega.Equal(IntervalCreateRetry * 4))
+	g.Expect(Backoff(100)).To(gomega.Equal(IntervalCreateMax))
+}
+
+func TestCreate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	g.Expect(err).To(gomega.BeNil())
+	err = db.AutoMigrate(&model.Ticket{})
+	g.Expect(err).To(gomega.BeNil())
+	rejected := true
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				if rejected {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"errorMessages":["project not valid."]}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"key":"MIG-1"}`))
+			}))
+	defer server.Close()
+	tracker := canaryTracker(g)
+	tracker.Tags = nil
+	tracker.URL = server.URL
+	conn, err := NewConnector(tracker)
+	g.Expect(err).To(gomega.BeNil())
+	ticket := &model.Ticket{
+		Parent:      "1",
+		Application: &model.Application{Name: "app"},
+	}
+	m := Manager{DB: db}
+
+	// Failed.
+	for n := 1; n <= 2; n++ {
+		err = m.create(conn, tracker, ticket)
+		g.Expect(err).To(gomega.BeNil())
+		g.Expect(ticket.Error).To(gomega.BeTrue())
+		g.Expect(ticket.Attempts).To(gomega.Equal(n))
+		g.Expect(ticket.NextAttempt).To(gomega.Equal(ticket.LastAttempt.Add(Backoff(n))))
+	}
+
+	// Succeeded.
+	rejected = false
+	err = m.create(conn, tracker, ticket)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ticket.Error).To(gomega.BeFalse())
+	g.Expect(ticket.Created).To(gomega.BeTrue())
+	g.Expect(ticket.Attempts).To(gomega.Equal(3))
+	g.Expect(ticket.NextAttempt.IsZero()).To(gomega.BeTrue())
+	saved := &model.Ticket{}
+	err = db.First(saved, ticket.ID).Error
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(saved.Reference).To(gomega.Equal("MIG-1"))
+	g.Expect(saved.Attempts).To(gomega.Equal(3))
+
+	// Not created (error).
+	ticket = &model.Ticket{
+		Parent:        "1",
+		ApplicationID: 2,
+		Application:   &model.Application{Name: "other"},
+	}
+	conn = &failedConnector{conn}
+	err = m.create(conn, tracker, ticket)
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(ticket.Error).To(gomega.BeTrue())
+	g.Expect(ticket.Message).ToNot(gomega.BeEmpty())
+	g.Expect(ticket.NextAttempt).To(gomega.Equal(ticket.LastAttempt.Add(Backoff(1))))
+	saved = &model.Ticket{}
+	err = db.First(saved, ticket.ID).Error
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(saved.Attempts).To(gomega.Equal(1))
+	g.Expect(saved.Error).To(gomega.BeTrue())
+}
+
+// failedConnector fails to create tickets.
+type failedConnector struct {
+	Connector
+}
+
+func (r *failedConnector) Create(t *model.Ticket) (err error) {
+	err = errors.New("client not built.")
+	return
+}
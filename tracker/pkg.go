@@ -1,14 +1,26 @@
 package tracker
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
 	liberr "github.com/jortel/go-utils/error"
 	"github.com/konveyor/tackle2-hub/model"
 )
 
 // Tracker types
 const (
-	JiraCloud  = "jira-cloud"
-	JiraOnPrem = "jira-onprem"
+	JiraCloud   = "jira-cloud"
+	JiraOnPrem  = "jira-onprem"
+	GitHub      = "github"
+	GitLab      = "gitlab"
+	AzureDevOps = "azure-devops"
+	ServiceNow  = "servicenow"
 )
 
 // Ticket status
@@ -19,12 +31,54 @@ const (
 	Unknown    = "Unknown"
 )
 
+// StatusMapping maps the remote (ticket) status to the hub status.
+// Matched case-insensitive.
+type StatusMapping map[string]string
+
+// With parses the tracker status mapping.
+func (m *StatusMapping) With(t *model.Tracker) {
+	*m = StatusMapping{}
+	mapping := map[string]string{}
+	if len(t.StatusMapping) > 0 {
+		_ = json.Unmarshal(t.StatusMapping, &mapping)
+	}
+	for k, v := range mapping {
+		(*m)[strings.ToLower(k)] = v
+	}
+}
+
+// Apply the mapping to the ticket.
+// The (normalized) status set by the connector is
+// retained when the remote status is not mapped.
+func (m StatusMapping) Apply(t *model.Ticket) {
+	status, found := m[strings.ToLower(t.RemoteStatus)]
+	if found {
+		t.Status = status
+	}
+}
+
 // Auth kinds
 const (
 	BearerAuth = "bearer"
 	BasicAuth  = "basic-auth"
 )
 
+// Metadata keys.
+const (
+	HealthPath = "healthPath"
+)
+
+// Connection error categories.
+const (
+	ErrorAuth     = "AUTH"
+	ErrorNotFound = "NOT_FOUND"
+	ErrorNetwork  = "NETWORK"
+	ErrorTLS      = "TLS"
+	ErrorIdentity = "IDENTITY"
+	ErrorTunnel   = "TUNNEL"
+	ErrorUnknown  = "UNKNOWN"
+)
+
 // Connector is a connector for an external ticket tracker.
 type Connector interface {
 	// With updates the connector with the tracker model.
@@ -37,10 +91,72 @@ type Connector interface {
 	TestConnection() (bool, error)
 	// Projects lists the tracker's projects.
 	Projects() ([]Project, error)
+	// ProjectSearch lists a page of the tracker's projects.
+	ProjectSearch(filter ProjectFilter) (ProjectPage, error)
 	// Project gets a project from the tracker.
 	Project(id string) (Project, error)
 	// IssueTypes gets the issue types for a project.
 	IssueTypes(id string) ([]IssueType, error)
+	// RequiredFields gets the fields required to create
+	// an issue of the specified type in a project.
+	RequiredFields(project, kind string) ([]string, error)
+}
+
+// VersionedConnector is implemented by connectors
+// that report the connector (implementation) version.
+type VersionedConnector interface {
+	// Version returns the connector version.
+	Version() string
+}
+
+// Version returns the connector version.
+// Returns "" when not reported.
+func Version(conn Connector) (v string) {
+	if versioned, cast := conn.(VersionedConnector); cast {
+		v = versioned.Version()
+	}
+	return
+}
+
+// WorklogConnector is implemented by connectors for trackers
+// that support logging work on tickets.
+type WorklogConnector interface {
+	// AddWorklog logs work on a ticket.
+	AddWorklog(t *model.Ticket, worklog *Worklog) error
+}
+
+// CommentConnector is implemented by connectors for trackers
+// that support comments on tickets.
+type CommentConnector interface {
+	// Comments lists the comments on a ticket.
+	Comments(t *model.Ticket) ([]Comment, error)
+	// AddComment adds a comment to a ticket.
+	AddComment(t *model.Ticket, comment *Comment) error
+}
+
+// Comment on a ticket.
+type Comment struct {
+	ID      string
+	Author  string
+	Body    string
+	Created time.Time
+}
+
+// Worklog work logged on a ticket.
+type Worklog struct {
+	// ID (reference) assigned by the tracker.
+	ID string
+	// TimeSpent (seconds).
+	TimeSpent int
+	Note      string
+}
+
+// SuppliedFields are ticket fields supplied by the hub.
+var SuppliedFields = []string{
+	"summary",
+	"description",
+	"issuetype",
+	"project",
 }
 
 // NewConnector instantiates a connector for an external ticket tracker.
@@ -49,12 +165,35 @@ func NewConnector(t *model.Tracker) (conn Connector, err error) {
 	case JiraCloud, JiraOnPrem:
 		conn = &JiraConnector{}
 		conn.With(t)
+	case GitHub:
+		conn = &GitHubConnector{}
+		conn.With(t)
+	case GitLab:
+		conn = &GitLabConnector{}
+		conn.With(t)
+	case AzureDevOps:
+		conn = &AzureDevOpsConnector{}
+		conn.With(t)
+	case ServiceNow:
+		conn = &ServiceNowConnector{}
+		conn.With(t)
 	default:
 		err = liberr.New("not implemented")
 	}
 	return
 }
 
+// HealthCheck is a step in a (multi-step) connection test.
+// Steps are run in order and the first to fail is reported.
+type HealthCheck struct {
+	// Step name.
+	Step string
+	// Path (relative) of the probed endpoint.
+	Path string
+	// Verify (optional) the decoded (json) response.
+	Verify func(v map[string]interface{}) error
+}
+
 // Project represents an external ticket tracker's project
 // in which an issue can be created.
 type Project struct {
@@ -62,9 +201,314 @@ type Project struct {
 	Name string
 }
 
+// ProjectFilter filters (and paginates) projects.
+type ProjectFilter struct {
+	// Query (substring) matched against the project name.
+	Query  string
+	Offset int
+	Limit  int
+}
+
+// ProjectPage is a page of projects.
+type ProjectPage struct {
+	Projects []Project
+	// Total number of (matched) projects.
+	Total int
+	// Next page offset.
+	// Zero(0) when no more pages.
+	Next int
+}
+
+// With builds the page by filtering and paginating the list.
+func (r *ProjectPage) With(projects []Project, filter ProjectFilter) {
+	var matched []Project
+	q := strings.ToLower(filter.Query)
+	for _, p := range projects {
+		if q == "" || strings.Contains(strings.ToLower(p.Name), q) {
+			matched = append(matched, p)
+		}
+	}
+	r.Total = len(matched)
+	begin := filter.Offset
+	if begin > len(matched) {
+		begin = len(matched)
+	}
+	end := len(matched)
+	if filter.Limit > 0 && begin+filter.Limit < end {
+		end = begin + filter.Limit
+		r.Next = end
+	}
+	r.Projects = matched[begin:end]
+}
+
 // IssueType represents a type of issue that can be created on
 // an external issue tracker.
 type IssueType struct {
 	ID   string
 	Name string
 }
+
+// Metadata is tracker (connector) metadata.
+type Metadata map[string]interface{}
+
+// With parses the tracker metadata.
+func (m *Metadata) With(t *model.Tracker) {
+	*m = Metadata{}
+	if len(t.Metadata) > 0 {
+		_ = json.Unmarshal(t.Metadata, m)
+	}
+}
+
+// String returns the value of the key as a string.
+func (m Metadata) String(key string) (s string) {
+	if v, found := m[key]; found {
+		s, _ = v.(string)
+	}
+	return
+}
+
+// Validate the metadata against the schema for the tracker kind.
+func (m Metadata) Validate(kind string) (err error) {
+	schema, found := Schemas[kind]
+	if !found {
+		err = &SchemaError{
+			Kind:    kind,
+			Reasons: []string{"kind not supported."},
+		}
+		return
+	}
+	err = schema.Validate(kind, m)
+	return
+}
+
+// Schemas metadata schemas by tracker kind.
+var Schemas = map[string]Schema{
+	JiraCloud:   jiraSchema,
+	JiraOnPrem:  jiraSchema,
+	GitHub:      githubSchema,
+	GitLab:      gitlabSchema,
+	AzureDevOps: azureDevOpsSchema,
+	ServiceNow:  serviceNowSchema,
+}
+
+// jiraSchema Jira metadata schema.
+var jiraSchema = Schema{
+	HealthPath:   relativePath,
+	Tunnel:       tunnelSchema,
+	DebugLogging: debugSchema,
+}
+
+// githubSchema GitHub metadata schema.
+var githubSchema = Schema{
+	HealthPath:   relativePath,
+	Tunnel:       tunnelSchema,
+	DebugLogging: debugSchema,
+	GitHubOwner:  name,
+}
+
+// gitlabSchema GitLab metadata schema.
+var gitlabSchema = Schema{
+	HealthPath:   relativePath,
+	Tunnel:       tunnelSchema,
+	DebugLogging: debugSchema,
+}
+
+// azureDevOpsSchema Azure DevOps metadata schema.
+var azureDevOpsSchema = Schema{
+	HealthPath:   relativePath,
+	Tunnel:       tunnelSchema,
+	DebugLogging: debugSchema,
+}
+
+// serviceNowSchema ServiceNow metadata schema.
+var serviceNowSchema = Schema{
+	HealthPath:   relativePath,
+	Tunnel:       tunnelSchema,
+	DebugLogging: debugSchema,
+}
+
+// Schema maps metadata keys to validators.
+// Keys not defined by the schema are not validated.
+type Schema map[string]func(v interface{}) error
+
+// Validate the metadata.
+func (s Schema) Validate(kind string, m Metadata) (err error) {
+	var reasons []string
+	for key, fn := range s {
+		v, found := m[key]
+		if !found {
+			continue
+		}
+		vErr := fn(v)
+		if vErr != nil {
+			reasons = append(reasons, key+": "+vErr.Error())
+		}
+	}
+	if len(reasons) > 0 {
+		sort.Strings(reasons)
+		err = &SchemaError{
+			Kind:    kind,
+			Reasons: reasons,
+		}
+	}
+	return
+}
+
+// name validates a (non-empty) name.
+func name(v interface{}) (err error) {
+	s, cast := v.(string)
+	if !cast {
+		err = errors.New("must be a string")
+		return
+	}
+	if s == "" || strings.ContainsAny(s, "/?# ") {
+		err = errors.New("must be a name")
+		return
+	}
+	return
+}
+
+// relativePath validates a relative (URL) path.
+func relativePath(v interface{}) (err error) {
+	p, cast := v.(string)
+	if !cast {
+		err = errors.New("must be a string")
+		return
+	}
+	u, pErr := url.Parse(p)
+	if pErr != nil ||
+		u.IsAbs() ||
+		u.Host != "" ||
+		strings.HasPrefix(p, "//") ||
+		strings.Contains(p, "..") {
+		err = errors.New("must be a relative path")
+		return
+	}
+	return
+}
+
+// SchemaError reports metadata not valid for the tracker kind.
+type SchemaError struct {
+	Kind    string
+	Reasons []string
+}
+
+func (e *SchemaError) Error() (s string) {
+	return fmt.Sprintf(
+		"metadata not valid for kind: %s: %s",
+		e.Kind,
+		strings.Join(e.Reasons, "; "))
+}
+
+func (e *SchemaError) Is(err error) (matched bool) {
+	_, matched = err.(*SchemaError)
+	return
+}
+
+// ConnectionError reports a failed connection test.
+type ConnectionError struct {
+	Category string
+	Reason   string
+	// Step (health check) failed.
+	Step string
+}
+
+func (e *ConnectionError) Error() (s string) {
+	return fmt.Sprintf("%s: %s", e.Category, e.reason())
+}
+
+// reason returns the reason prefixed by the step.
+func (e *ConnectionError) reason() (s string) {
+	s = e.Reason
+	if e.Step != "" {
+		s = e.Step + ": " + s
+	}
+	return
+}
+
+func (e *ConnectionError) Is(err error) (matched bool) {
+	_, matched = err.(*ConnectionError)
+	return
+}
+
+// Categorize the error.
+// Returns the category and reason.
+func Categorize(err error) (category, reason string) {
+	if err == nil {
+		return
+	}
+	cErr := &ConnectionError{}
+	if errors.As(err, &cErr) {
+		category = cErr.Category
+		reason = cErr.reason()
+		return
+	}
+	reason = err.Error()
+	category = CategoryOf(reason)
+	return
+}
+
+// patterns maps message patterns to error categories.
+// Ordered by precedence.
+var patterns = []struct {
+	category string
+	matched  []string
+}{
+	{
+		category: ErrorIdentity,
+		matched:  []string{"unsupported identity kind"},
+	},
+	{
+		category: ErrorTLS,
+		matched:  []string{"x509", "certificate", "tls:"},
+	},
+	{
+		category: ErrorAuth,
+		matched:  []string{"401", "403", "unauthorized", "forbidden", "authentication", "login"},
+	},
+	{
+		category: ErrorNotFound,
+		matched:  []string{"404", "not found"},
+	},
+	{
+		category: ErrorNetwork,
+		matched: []string{
+			"no such host",
+			"connection refused",
+			"connection reset",
+			"i/o timeout",
+			"dial tcp",
+			"timeout",
+		},
+	},
+}
+
+// CategoryOf returns the category of a (freeform) error message.
+// Messages reported using ConnectionError are prefixed with
+// the category.
+func CategoryOf(message string) (category string) {
+	category = ErrorUnknown
+	for _, known := range []string{
+		ErrorAuth,
+		ErrorNotFound,
+		ErrorNetwork,
+		ErrorTLS,
+		ErrorIdentity,
+		ErrorTunnel,
+	} {
+		if strings.HasPrefix(message, known+": ") {
+			category = known
+			return
+		}
+	}
+	lower := strings.ToLower(message)
+	for _, p := range patterns {
+		for _, s := range p.matched {
+			if strings.Contains(lower, s) {
+				category = p.category
+				return
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,147 @@
+package tracker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestMetadataValidate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	expiry := time.Now().Add(time.Hour).Format(time.RFC3339)
+	cases := []struct {
+		kind  string
+		md    Metadata
+		valid bool
+	}{
+		{kind: JiraCloud, md: Metadata{}, valid: true},
+		{kind: JiraCloud, md: Metadata{"other": 1}, valid: true},
+		{kind: "unknown", md: Metadata{}, valid: false},
+		{kind: JiraCloud, md: Metadata{HealthPath: "rest/api/2/myself"}, valid: true},
+		{kind: JiraOnPrem, md: Metadata{HealthPath: "/status?full=1"}, valid: true},
+		{kind: JiraCloud, md: Metadata{HealthPath: 1}, valid: false},
+		{kind: JiraCloud, md: Metadata{HealthPath: "https://evil.com/status"}, valid: false},
+		{kind: JiraCloud, md: Metadata{HealthPath: "//evil.com/status"}, valid: false},
+		{kind: JiraCloud, md: Metadata{HealthPath: "../../admin"}, valid: false},
+		{kind: JiraCloud, md: Metadata{HealthPath: "%zz"}, valid: false},
+		{kind: JiraCloud, md: Metadata{DebugLogging: expiry}, valid: true},
+		{kind: JiraCloud, md: Metadata{DebugLogging: "tomorrow"}, valid: false},
+		{
+			kind:  JiraCloud,
+			md:    Metadata{DebugLogging: time.Now().Add(MaxDebugLogging * 2).Format(time.RFC3339)},
+			valid: false,
+		},
+		{kind: JiraCloud, md: Metadata{Tunnel: "bastion"}, valid: false},
+	}
+	for _, c := range cases {
+		err := c.md.Validate(c.kind)
+		g.Expect(err == nil).To(gomega.Equal(c.valid), "%s: %v", c.kind, c.md)
+		if err != nil {
+			g.Expect(errors.Is(err, &SchemaError{})).To(gomega.BeTrue())
+		}
+	}
+
+	// Reasons are sorted and prefixed by key.
+	md := Metadata{HealthPath: 1, DebugLogging: 1}
+	err := md.Validate(JiraCloud)
+	sErr := &SchemaError{}
+	g.Expect(errors.As(err, &sErr)).To(gomega.BeTrue())
+	g.Expect(sErr.Reasons).To(gomega.Equal([]string{
+		DebugLogging + ": must be a string",
+		HealthPath + ": must be a string",
+	}))
+}
+
+func TestCategorize(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	cases := []struct {
+		message  string
+		category string
+	}{
+		{message: "", category: ErrorUnknown},
+		{message: "something broke", category: ErrorUnknown},
+		{message: "AUTH: denied", category: ErrorAuth},
+		{message: "TUNNEL: handshake failed", category: ErrorTunnel},
+		{message: "unsupported identity kind", category: ErrorIdentity},
+		{message: "x509: certificate signed by unknown authority", category: ErrorTLS},
+		{message: "401 Unauthorized", category: ErrorAuth},
+		{message: "Forbidden", category: ErrorAuth},
+		{message: "404 page not found", category: ErrorNotFound},
+		{message: "dial tcp: lookup jira: no such host", category: ErrorNetwork},
+		{message: "read: connection reset by peer", category: ErrorNetwork},
+		{message: "context deadline exceeded (Client.Timeout exceeded)", category: ErrorNetwork},
+		// Precedence: TLS before network.
+		{message: "dial tcp: tls: handshake failure", category: ErrorTLS},
+		// Prefix (category) before patterns.
+		{message: "NETWORK: 401", category: ErrorNetwork},
+	}
+	for _, c := range cases {
+		g.Expect(CategoryOf(c.message)).To(gomega.Equal(c.category), c.message)
+	}
+
+	category, reason := Categorize(nil)
+	g.Expect(category).To(gomega.BeEmpty())
+	g.Expect(reason).To(gomega.BeEmpty())
+	category, reason = Categorize(errors.New("401 Unauthorized"))
+	g.Expect(category).To(gomega.Equal(ErrorAuth))
+	g.Expect(reason).To(gomega.Equal("401 Unauthorized"))
+	category, reason = Categorize(
+		&ConnectionError{
+			Category: ErrorNotFound,
+			Reason:   "gone.",
+			Step:     StepMyself,
+		})
+	g.Expect(category).To(gomega.Equal(ErrorNotFound))
+	g.Expect(reason).To(gomega.Equal(StepMyself + ": gone."))
+}
+
+func TestProjectPageWith(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	projects := []Project{
+		{ID: "1", Name: "Alpha"},
+		{ID: "2", Name: "Beta"},
+		{ID: "3", Name: "alphabet"},
+		{ID: "4", Name: "Gamma"},
+	}
+	cases := []struct {
+		filter ProjectFilter
+		ids    []string
+		total  int
+		next   int
+	}{
+		{filter: ProjectFilter{}, ids: []string{"1", "2", "3", "4"}, total: 4},
+		{filter: ProjectFilter{Query: "ALPHA"}, ids: []string{"1", "3"}, total: 2},
+		{filter: ProjectFilter{Limit: 2}, ids: []string{"1", "2"}, total: 4, next: 2},
+		{filter: ProjectFilter{Offset: 2, Limit: 2}, ids: []string{"3", "4"}, total: 4},
+		{filter: ProjectFilter{Offset: 1, Limit: 2}, ids: []string{"2", "3"}, total: 4, next: 3},
+		{filter: ProjectFilter{Offset: 9, Limit: 2}, ids: nil, total: 4},
+		{filter: ProjectFilter{Query: "none"}, ids: nil, total: 0},
+	}
+	for _, c := range cases {
+		page := ProjectPage{}
+		page.With(projects, c.filter)
+		var ids []string
+		for _, p := range page.Projects {
+			ids = append(ids, p.ID)
+		}
+		g.Expect(ids).To(gomega.Equal(c.ids), "%+v", c.filter)
+		g.Expect(page.Total).To(gomega.Equal(c.total), "%+v", c.filter)
+		g.Expect(page.Next).To(gomega.Equal(c.next), "%+v", c.filter)
+	}
+}
+
+func TestStatusMapping(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	tracker := &model.Tracker{StatusMapping: []byte(`{"Closed":"Done","Won't Do":"Unknown"}`)}
+	mapping := StatusMapping{}
+	mapping.With(tracker)
+	ticket := &model.Ticket{Status: InProgress, RemoteStatus: "closed"}
+	mapping.Apply(ticket)
+	g.Expect(ticket.Status).To(gomega.Equal(Done))
+	ticket = &model.Ticket{Status: InProgress, RemoteStatus: "In Review"}
+	mapping.Apply(ticket)
+	g.Expect(ticket.Status).To(gomega.Equal(InProgress))
+}
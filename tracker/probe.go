@@ -0,0 +1,157 @@
+package tracker
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+// Probe steps.
+const (
+	StepDNS        = "dns"
+	StepTCP        = "tcp"
+	StepTLS        = "tls"
+	StepAuth       = "auth"
+	StepPermission = "permission"
+)
+
+// ProbeTimeout (network) probe step timeout.
+const ProbeTimeout = time.Second * 10
+
+// ProbeStep the result of a probe step.
+type ProbeStep struct {
+	Step     string
+	Passed   bool
+	Skipped  bool
+	Category string
+	Reason   string
+	Duration time.Duration
+}
+
+// Probe tests the connection to the tracker step by step.
+// Each step is reported (as completed) to the callback and steps
+// following a failed step are not run. The network steps are skipped
+// when the tracker is reached through a tunnel. The tracker status
+// is not updated.
+func Probe(t *model.Tracker, report func(step ProbeStep)) (connected bool, err error) {
+	conn, err := NewConnector(t)
+	if err != nil {
+		return
+	}
+	u, err := url.Parse(t.URL)
+	if err != nil {
+		return
+	}
+	host := u.Hostname()
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+	address := net.JoinHostPort(host, port)
+	md := Metadata{}
+	md.With(t)
+	tunnel := TunnelConfig{}
+	tunneled := tunnel.With(md)
+	steps := []struct {
+		name string
+		fn   func() error
+	}{
+		{
+			name: StepDNS,
+			fn: func() (err error) {
+				ctx, cancel := context.WithTimeout(context.Background(), ProbeTimeout)
+				defer cancel()
+				_, err = net.DefaultResolver.LookupHost(ctx, host)
+				return
+			},
+		},
+		{
+			name: StepTCP,
+			fn: func() (err error) {
+				c, err := net.DialTimeout("tcp", address, ProbeTimeout)
+				if err == nil {
+					_ = c.Close()
+				}
+				return
+			},
+		},
+		{
+			name: StepTLS,
+			fn: func() (err error) {
+				dialer := &net.Dialer{Timeout: ProbeTimeout}
+				c, err := tls.DialWithDialer(
+					dialer,
+					"tcp",
+					address,
+					&tls.Config{
+						ServerName:         host,
+						InsecureSkipVerify: t.Insecure,
+					})
+				if err == nil {
+					_ = c.Close()
+				}
+				return
+			},
+		},
+		{
+			name: StepAuth,
+			fn: func() (err error) {
+				_, err = conn.TestConnection()
+				return
+			},
+		},
+		{
+			name: StepPermission,
+			fn: func() (err error) {
+				page, err := conn.ProjectSearch(ProjectFilter{Limit: 1})
+				if err != nil {
+					return
+				}
+				if page.Total == 0 && len(page.Projects) == 0 {
+					err = &ConnectionError{
+						Category: ErrorAuth,
+						Reason:   "no projects visible to the identity.",
+					}
+				}
+				return
+			},
+		},
+	}
+	for _, step := range steps {
+		result := ProbeStep{Step: step.name}
+		switch {
+		case tunneled && step.name != StepAuth && step.name != StepPermission:
+			result.Skipped = true
+			result.Reason = "reached through tunnel: " + tunnel.Host
+		case step.name == StepTLS && u.Scheme != "https":
+			result.Skipped = true
+			result.Reason = "not https."
+		default:
+			mark := time.Now()
+			err = step.fn()
+			result.Duration = time.Since(mark)
+			if err != nil {
+				result.Category, result.Reason = Categorize(err)
+				if step.name == StepDNS || step.name == StepTCP {
+					result.Category = ErrorNetwork
+				}
+				if step.name == StepTLS {
+					result.Category = ErrorTLS
+				}
+				report(result)
+				return
+			}
+			result.Passed = true
+		}
+		report(result)
+	}
+	connected = true
+	return
+}
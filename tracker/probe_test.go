@@ -0,0 +1,47 @@
+package tracker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestProbe(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	server := httptest.NewTLSServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			}))
+	defer server.Close()
+	tracker := canaryTracker(g)
+	tracker.Tags = nil
+	tracker.URL = server.URL
+	tracker.Insecure = true
+
+	var steps []ProbeStep
+	report := func(step ProbeStep) {
+		steps = append(steps, step)
+	}
+	connected, err := Probe(tracker, report)
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(connected).To(gomega.BeFalse())
+	g.Expect(len(steps)).To(gomega.Equal(4))
+	for _, step := range steps[:3] {
+		g.Expect(step.Passed).To(gomega.BeTrue(), step.Step)
+	}
+	g.Expect(steps[3].Step).To(gomega.Equal(StepAuth))
+	g.Expect(steps[3].Passed).To(gomega.BeFalse())
+	g.Expect(steps[3].Category).To(gomega.Equal(ErrorAuth))
+
+	// Not trusted.
+	tracker.Insecure = false
+	steps = nil
+	_, err = Probe(tracker, report)
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(len(steps)).To(gomega.Equal(3))
+	g.Expect(steps[2].Step).To(gomega.Equal(StepTLS))
+	g.Expect(steps[2].Category).To(gomega.Equal(ErrorTLS))
+}
@@ -0,0 +1,60 @@
+package tracker
+
+import (
+	"time"
+
+	"github.com/konveyor/tackle2-hub/metrics"
+)
+
+// queue gauges the reconcile queue.
+// Items are queued when due and dequeued when
+// processed by a worker.
+type queue struct {
+	// depth items queued.
+	depth int
+	// waited total time items waited (this pass).
+	waited time.Duration
+	// started items started (this pass).
+	started int
+}
+
+// Reset the queue at the start of a pass.
+func (q *queue) Reset() {
+	q.depth = 0
+	q.waited = 0
+	q.started = 0
+	metrics.TrackerQueueDepth.Set(0)
+}
+
+// Add due items to the queue.
+func (q *queue) Add(n int) {
+	q.depth += n
+	metrics.TrackerQueueDepth.Set(float64(q.depth))
+}
+
+// Started dequeues an item (due at the specified time)
+// and a worker is active.
+func (q *queue) Started(due time.Time) {
+	q.depth--
+	q.started++
+	wait := time.Since(due)
+	if wait > 0 {
+		q.waited += wait
+	}
+	metrics.TrackerQueueDepth.Set(float64(q.depth))
+	metrics.TrackerWorkersActive.Inc()
+}
+
+// Done the worker is no longer active.
+func (q *queue) Done() {
+	metrics.TrackerWorkersActive.Dec()
+}
+
+// Report the average wait at the end of a pass.
+func (q *queue) Report() {
+	wait := time.Duration(0)
+	if q.started > 0 {
+		wait = q.waited / time.Duration(q.started)
+	}
+	metrics.TrackerQueueWait.Set(wait.Seconds())
+}
@@ -0,0 +1,122 @@
+package tracker
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+// Rate-limit headers.
+const (
+	HeaderRateLimit     = "X-RateLimit-Limit"
+	HeaderRateRemaining = "X-RateLimit-Remaining"
+	HeaderRateReset     = "X-RateLimit-Reset"
+	HeaderRetryAfter    = "Retry-After"
+)
+
+// RateLimit the remote's view of the rate limit.
+// Captured from the response headers and stored
+// in the tracker (rate-limit) column.
+type RateLimit struct {
+	Limit     int        `json:"limit,omitempty"`
+	Remaining *int       `json:"remaining,omitempty"`
+	Reset     *time.Time `json:"reset,omitempty"`
+	// RetryAfter (seconds).
+	RetryAfter int       `json:"retryAfter,omitempty"`
+	Updated    time.Time `json:"updated"`
+}
+
+// With parses the rate-limit headers.
+// Returns false when not reported.
+func (r *RateLimit) With(header http.Header) (reported bool) {
+	s := header.Get(HeaderRateLimit)
+	if s != "" {
+		r.Limit, _ = strconv.Atoi(s)
+		reported = true
+	}
+	s = header.Get(HeaderRateRemaining)
+	if s != "" {
+		n, _ := strconv.Atoi(s)
+		r.Remaining = &n
+		reported = true
+	}
+	s = header.Get(HeaderRateReset)
+	if s != "" {
+		r.Reset = r.parseTime(s)
+		reported = true
+	}
+	s = header.Get(HeaderRetryAfter)
+	if s != "" {
+		r.RetryAfter, _ = strconv.Atoi(s)
+		reported = true
+	}
+	if reported {
+		r.Updated = time.Now()
+	}
+	return
+}
+
+// Load the rate-limit from the tracker.
+// Returns false when not found.
+func (r *RateLimit) Load(t *model.Tracker) (found bool) {
+	if len(t.RateLimit) == 0 {
+		return
+	}
+	err := json.Unmarshal(t.RateLimit, r)
+	found = err == nil
+	return
+}
+
+// Store the rate-limit in the tracker.
+func (r *RateLimit) Store(t *model.Tracker) {
+	t.RateLimit, _ = json.Marshal(r)
+}
+
+// Until returns when throttling (by the remote) ends.
+func (r *RateLimit) Until() (until time.Time) {
+	if r.RetryAfter > 0 {
+		until = r.Updated.Add(time.Duration(r.RetryAfter) * time.Second)
+	}
+	if r.Remaining != nil && *r.Remaining == 0 && r.Reset != nil {
+		if r.Reset.After(until) {
+			until = *r.Reset
+		}
+	}
+	return
+}
+
+// Throttled returns true when throttled by the remote.
+func (r *RateLimit) Throttled() (throttled bool) {
+	throttled = time.Now().Before(r.Until())
+	return
+}
+
+// parseTime parses the reset time.
+// Either RFC3339 (ISO 8601) or unix (seconds).
+func (r *RateLimit) parseTime(s string) (t *time.Time) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00"} {
+		parsed, err := time.Parse(layout, s)
+		if err == nil {
+			t = &parsed
+			return
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err == nil {
+		parsed := time.Unix(n, 0)
+		t = &parsed
+	}
+	return
+}
+
+// Throttled returns true when the tracker is throttled by the remote.
+func Throttled(t *model.Tracker) (throttled bool) {
+	rateLimit := RateLimit{}
+	if rateLimit.Load(t) {
+		throttled = rateLimit.Throttled()
+	}
+	return
+}
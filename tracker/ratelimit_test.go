@@ -0,0 +1,120 @@
+package tracker
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+// header builds a (canonical) header.
+func header(kv ...string) (h http.Header) {
+	h = http.Header{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		h.Set(kv[i], kv[i+1])
+	}
+	return
+}
+
+func TestRateLimitWith(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	reset := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	cases := []struct {
+		header     http.Header
+		reported   bool
+		limit      int
+		remaining  *int
+		reset      *time.Time
+		retryAfter int
+	}{
+		{header: header()},
+		{
+			header:   header(HeaderRateLimit, "100"),
+			reported: true,
+			limit:    100,
+		},
+		{
+			header: header(
+				HeaderRateLimit, "100",
+				HeaderRateRemaining, "0",
+				HeaderRateReset, reset.Format(time.RFC3339)),
+			reported:  true,
+			limit:     100,
+			remaining: new(int),
+			reset:     &reset,
+		},
+		{
+			header:     header(HeaderRetryAfter, "30"),
+			reported:   true,
+			retryAfter: 30,
+		},
+		{
+			header:   header(HeaderRateReset, "invalid"),
+			reported: true,
+		},
+	}
+	for _, c := range cases {
+		r := RateLimit{}
+		reported := r.With(c.header)
+		g.Expect(reported).To(gomega.Equal(c.reported), "%v", c.header)
+		g.Expect(r.Limit).To(gomega.Equal(c.limit))
+		g.Expect(r.Remaining).To(gomega.Equal(c.remaining))
+		g.Expect(r.RetryAfter).To(gomega.Equal(c.retryAfter))
+		if c.reset != nil {
+			g.Expect(r.Reset.Equal(*c.reset)).To(gomega.BeTrue())
+		} else {
+			g.Expect(r.Reset).To(gomega.BeNil())
+		}
+		g.Expect(r.Updated.IsZero()).To(gomega.Equal(!c.reported))
+	}
+}
+
+func TestRateLimitParseTime(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	expected := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+	cases := []struct {
+		s      string
+		parsed bool
+	}{
+		{s: "2026-01-02T03:04:00Z", parsed: true},
+		{s: "2026-01-01T22:04:00-05:00", parsed: true},
+		{s: "2026-01-02T03:04Z", parsed: true},
+		{s: "1767323040", parsed: true},
+		{s: "", parsed: false},
+		{s: "tomorrow", parsed: false},
+	}
+	r := RateLimit{}
+	for _, c := range cases {
+		parsed := r.parseTime(c.s)
+		if !c.parsed {
+			g.Expect(parsed).To(gomega.BeNil(), c.s)
+			continue
+		}
+		g.Expect(parsed).ToNot(gomega.BeNil(), c.s)
+		g.Expect(parsed.Equal(expected)).To(gomega.BeTrue(), c.s)
+	}
+}
+
+func TestRateLimitThrottled(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	now := time.Now()
+	zero := 0
+	some := 5
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+	cases := []struct {
+		r         RateLimit
+		throttled bool
+	}{
+		{r: RateLimit{}, throttled: false},
+		{r: RateLimit{RetryAfter: 60, Updated: now}, throttled: true},
+		{r: RateLimit{RetryAfter: 60, Updated: now.Add(-time.Hour)}, throttled: false},
+		{r: RateLimit{Remaining: &zero, Reset: &future, Updated: now}, throttled: true},
+		{r: RateLimit{Remaining: &zero, Reset: &past, Updated: now}, throttled: false},
+		{r: RateLimit{Remaining: &some, Reset: &future, Updated: now}, throttled: false},
+	}
+	for i, c := range cases {
+		g.Expect(c.r.Throttled()).To(gomega.Equal(c.throttled), "case: %d", i)
+	}
+}
@@ -0,0 +1,172 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	liberr "github.com/jortel/go-utils/error"
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+// Health check steps.
+const (
+	StepUser = "user"
+)
+
+// StatusError is an error reported by the remote
+// with an (http) status.
+type StatusError interface {
+	error
+	// StatusCode returns the (http) status.
+	StatusCode() int
+}
+
+// NotFound returns true when the remote reported (404) not found.
+func NotFound(err error) (notFound bool) {
+	var sErr StatusError
+	if errors.As(err, &sErr) {
+		notFound = sErr.StatusCode() == http.StatusNotFound
+	}
+	return
+}
+
+// restClient (json) REST client used by connectors
+// without a client library.
+type restClient struct {
+	baseURL  string
+	identity *model.Identity
+	header   http.Header
+	wrapper  clientWrapper
+	// contentType of the (encoded) request body.
+	contentType string
+	// failed returns the error reported by the
+	// (failed) response.
+	failed func(status int, body []byte) error
+}
+
+// newRestClient returns a REST client for the tracker.
+// The tunnel is used when configured.
+func newRestClient(t *model.Tracker, baseURL string) (client *restClient, err error) {
+	switch t.Identity.Kind {
+	case BearerAuth, BasicAuth:
+	default:
+		err = liberr.New("unsupported identity kind", "kind", t.Identity.Kind)
+		return
+	}
+	var transport *http.Transport
+	md := Metadata{}
+	md.With(t)
+	tunnel := TunnelConfig{}
+	if tunnel.With(md) {
+		transport, err = tunnelTransport(t, tunnel)
+		if err != nil {
+			return
+		}
+	} else {
+		transport = transports.get(t)
+	}
+	client = &restClient{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		identity:    t.Identity,
+		header:      http.Header{},
+		contentType: "application/json",
+		wrapper: clientWrapper{
+			client:  &http.Client{Transport: transport},
+			tracker: t,
+		},
+	}
+	return
+}
+
+// do sends the request and decodes the (json) response.
+// The input (optional) is encoded as the request body.
+// The output (optional) is decoded from the response body.
+func (r *restClient) do(method, path string, in, out interface{}) (err error) {
+	var body io.Reader
+	if in != nil {
+		var b []byte
+		b, err = json.Marshal(in)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, r.baseURL+"/"+path, body)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+	switch r.identity.Kind {
+	case BearerAuth:
+		req.Header.Set("Authorization", "Bearer "+r.identity.Key)
+	case BasicAuth:
+		req.SetBasicAuth(r.identity.User, r.identity.Password)
+	}
+	resp, err := r.wrapper.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		err = r.failed(resp.StatusCode, b)
+		return
+	}
+	if out != nil {
+		err = json.Unmarshal(b, out)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	return
+}
+
+// healthCheck runs a health check step.
+func (r *restClient) healthCheck(check HealthCheck) (err error) {
+	err = r.do(http.MethodGet, strings.TrimPrefix(check.Path, "/"), nil, nil)
+	if err == nil {
+		return
+	}
+	inner := &ConnectionError{}
+	if errors.As(err, &inner) {
+		cErr := *inner
+		cErr.Step = check.Step
+		err = &cErr
+		return
+	}
+	cErr := &ConnectionError{
+		Category: CategoryOf(err.Error()),
+		Reason:   err.Error(),
+		Step:     check.Step,
+	}
+	var sErr StatusError
+	if errors.As(err, &sErr) {
+		switch sErr.StatusCode() {
+		case http.StatusUnauthorized,
+			http.StatusForbidden:
+			cErr.Category = ErrorAuth
+		case http.StatusNotFound:
+			cErr.Category = ErrorNotFound
+			cErr.Reason = fmt.Sprintf("health probe (%s) not found.", check.Path)
+		}
+	}
+	err = cErr
+	return
+}
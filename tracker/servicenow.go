@@ -0,0 +1,383 @@
+package tracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/metrics"
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+const (
+	ServiceNowEndpointTable = "api/now/table"
+)
+
+// ServiceNow tables.
+const (
+	ServiceNowTableGroup    = "sys_user_group"
+	ServiceNowTableDBObject = "sys_db_object"
+	ServiceNowTableChange   = "change_request"
+	ServiceNowTableStory    = "rm_story"
+)
+
+// ServiceNowDisplayValue requests (state) display values.
+const ServiceNowDisplayValue = "sysparm_display_value=true"
+
+// ServiceNowPageSize the (max) page size.
+const ServiceNowPageSize = 100
+
+// ServiceNowTables the (record) tables in which
+// tickets may be created.
+var ServiceNowTables = []string{
+	ServiceNowTableChange,
+	ServiceNowTableStory,
+}
+
+// ServiceNowConnector for the ServiceNow (table) API.
+// The tracker URL is the instance (https://acme.service-now.com).
+// Projects are (active) assignment groups and the issue types are
+// the record tables (change_request, rm_story) defined by the instance.
+// Tickets are created as records assigned to the group.
+type ServiceNowConnector struct {
+	tracker *model.Tracker
+}
+
+// ServiceNowVersion the servicenow connector version.
+// Incremented when the connector behavior changes.
+const ServiceNowVersion = "1.0.0"
+
+// Version returns the connector version.
+func (r *ServiceNowConnector) Version() string {
+	return ServiceNowVersion
+}
+
+// With updates the connector with the Tracker model.
+func (r *ServiceNowConnector) With(t *model.Tracker) {
+	r.tracker = t
+	_ = r.tracker.Identity.Decrypt()
+	if r.tracker.TunnelIdentity != nil {
+		_ = r.tracker.TunnelIdentity.Decrypt()
+	}
+}
+
+// Create the ticket (record) in ServiceNow.
+// The record is created in the table (kind) and
+// assigned to the group (parent).
+func (r *ServiceNowConnector) Create(t *model.Ticket) (err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	record := map[string]interface{}{}
+	_ = json.Unmarshal(t.Fields, &record)
+	record["short_description"] = summary(t)
+	record["description"] = description(t)
+	record["assignment_group"] = t.Parent
+	created := struct {
+		Result serviceNowRecord `json:"result"`
+	}{}
+	err = client.do(
+		http.MethodPost,
+		fmt.Sprintf("%s/%s?%s", ServiceNowEndpointTable, url.PathEscape(t.Kind), ServiceNowDisplayValue),
+		record,
+		&created)
+	if err != nil {
+		t.Error = true
+		t.Message = err.Error()
+		t.LastUpdated = time.Now()
+		err = nil
+		return
+	}
+	t.Created = true
+	t.Error = false
+	t.Message = ""
+	t.Reference = created.Result.SysID
+	t.Link = fmt.Sprintf(
+		"%s/nav_to.do?uri=%s.do?sys_id=%s",
+		strings.TrimSuffix(r.tracker.URL, "/"),
+		t.Kind,
+		created.Result.SysID)
+	t.LastUpdated = time.Now()
+	metrics.IssuesExported.Inc()
+	return
+}
+
+// RefreshAll retrieves fresh status information for all the tracker's tickets.
+func (r *ServiceNowConnector) RefreshAll() (tickets map[*model.Ticket]bool, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	tickets = make(map[*model.Ticket]bool)
+	for i := range r.tracker.Tickets {
+		t := &r.tracker.Tickets[i]
+		if t.Reference == "" {
+			continue
+		}
+		tickets[t] = false
+		record := struct {
+			Result serviceNowRecord `json:"result"`
+		}{}
+		err = client.do(
+			http.MethodGet,
+			fmt.Sprintf(
+				"%s/%s/%s?%s",
+				ServiceNowEndpointTable,
+				url.PathEscape(t.Kind),
+				url.PathEscape(t.Reference),
+				ServiceNowDisplayValue),
+			nil,
+			&record)
+		if err != nil {
+			if NotFound(err) {
+				err = nil
+				continue
+			}
+			return
+		}
+		t.Status = record.Result.status()
+		t.RemoteStatus = record.Result.State
+		t.LastUpdated = time.Now()
+		tickets[t] = true
+	}
+	return
+}
+
+// Projects returns a list of Projects (active assignment groups).
+func (r *ServiceNowConnector) Projects() (projects []Project, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	for offset := 0; ; offset += ServiceNowPageSize {
+		list := struct {
+			Result []serviceNowGroup `json:"result"`
+		}{}
+		query := url.Values{}
+		query.Set("sysparm_query", "active=true^ORDERBYname")
+		query.Set("sysparm_fields", "sys_id,name")
+		query.Set("sysparm_limit", strconv.Itoa(ServiceNowPageSize))
+		query.Set("sysparm_offset", strconv.Itoa(offset))
+		err = client.do(
+			http.MethodGet,
+			fmt.Sprintf("%s/%s?%s", ServiceNowEndpointTable, ServiceNowTableGroup, query.Encode()),
+			nil,
+			&list)
+		if err != nil {
+			return
+		}
+		for _, g := range list.Result {
+			projects = append(projects, g.project())
+		}
+		if len(list.Result) < ServiceNowPageSize {
+			break
+		}
+	}
+	return
+}
+
+// ProjectSearch returns a page of Projects.
+// The (full) group list is filtered and paginated locally.
+func (r *ServiceNowConnector) ProjectSearch(filter ProjectFilter) (page ProjectPage, err error) {
+	projects, err := r.Projects()
+	if err != nil {
+		return
+	}
+	page.With(projects, filter)
+	return
+}
+
+// Project returns a Project (assignment group).
+func (r *ServiceNowConnector) Project(id string) (project Project, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	group := struct {
+		Result serviceNowGroup `json:"result"`
+	}{}
+	err = client.do(
+		http.MethodGet,
+		fmt.Sprintf(
+			"%s/%s/%s?sysparm_fields=sys_id,name",
+			ServiceNowEndpointTable,
+			ServiceNowTableGroup,
+			url.PathEscape(id)),
+		nil,
+		&group)
+	if err != nil {
+		return
+	}
+	project = group.Result.project()
+	return
+}
+
+// IssueTypes returns a list of IssueTypes (record tables).
+// Only the tables defined by the instance are returned.
+// The tables are not defined by the group (project).
+func (r *ServiceNowConnector) IssueTypes(id string) (issueTypes []IssueType, err error) {
+	_, err = r.Project(id)
+	if err != nil {
+		return
+	}
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	list := struct {
+		Result []struct {
+			Name  string `json:"name"`
+			Label string `json:"label"`
+		} `json:"result"`
+	}{}
+	query := url.Values{}
+	query.Set("sysparm_query", "nameIN"+strings.Join(ServiceNowTables, ","))
+	query.Set("sysparm_fields", "name,label")
+	err = client.do(
+		http.MethodGet,
+		fmt.Sprintf("%s/%s?%s", ServiceNowEndpointTable, ServiceNowTableDBObject, query.Encode()),
+		nil,
+		&list)
+	if err != nil {
+		return
+	}
+	defined := map[string]string{}
+	for _, t := range list.Result {
+		defined[t.Name] = t.Label
+	}
+	for _, name := range ServiceNowTables {
+		label, found := defined[name]
+		if !found {
+			continue
+		}
+		issueTypes = append(
+			issueTypes,
+			IssueType{
+				ID:   name,
+				Name: label,
+			})
+	}
+	return
+}
+
+// RequiredFields returns the fields required to create an issue.
+// Mandatory fields are enforced by the instance (policies) and
+// not reported by the table API.
+func (r *ServiceNowConnector) RequiredFields(project, kind string) (fields []string, err error) {
+	return
+}
+
+// TestConnection to ServiceNow.
+// The health checks may be replaced by a single probe
+// using the `healthPath` metadata.
+func (r *ServiceNowConnector) TestConnection() (connected bool, err error) {
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	md := Metadata{}
+	md.With(r.tracker)
+	check := HealthCheck{
+		Step: StepUser,
+		Path: fmt.Sprintf("%s/%s?sysparm_limit=1&sysparm_fields=sys_id", ServiceNowEndpointTable, ServiceNowTableGroup),
+	}
+	path := md.String(HealthPath)
+	if path != "" {
+		check = HealthCheck{
+			Step: HealthPath,
+			Path: path,
+		}
+	}
+	err = client.healthCheck(check)
+	if err != nil {
+		return
+	}
+	connected = true
+	return
+}
+
+// client returns a servicenow client.
+func (r *ServiceNowConnector) client() (client *restClient, err error) {
+	client, err = newRestClient(r.tracker, r.tracker.URL)
+	if err != nil {
+		return
+	}
+	client.header.Set("Accept", "application/json")
+	client.failed = func(status int, body []byte) error {
+		sErr := &serviceNowError{Status: status}
+		_ = json.Unmarshal(body, sErr)
+		return sErr
+	}
+	return
+}
+
+// serviceNowGroup servicenow (assignment) group.
+type serviceNowGroup struct {
+	SysID string `json:"sys_id"`
+	Name  string `json:"name"`
+}
+
+// project returns the (tracker) project.
+func (r *serviceNowGroup) project() (p Project) {
+	p = Project{
+		ID:   r.SysID,
+		Name: r.Name,
+	}
+	return
+}
+
+// serviceNowRecord servicenow (change, story) record.
+// Fields are the display values.
+type serviceNowRecord struct {
+	SysID  string `json:"sys_id"`
+	Number string `json:"number"`
+	State  string `json:"state"`
+}
+
+// status returns a normalized status.
+// The states defined by the (change_request, rm_story) tables are mapped.
+func (r *serviceNowRecord) status() (s string) {
+	switch strings.ToLower(r.State) {
+	case "new", "draft", "ready":
+		s = New
+	case "assess", "authorize", "scheduled", "implement", "review",
+		"work in progress", "testing":
+		s = InProgress
+	case "closed", "complete", "canceled", "cancelled":
+		s = Done
+	default:
+		s = Unknown
+	}
+	return
+}
+
+// serviceNowError reports an error returned by the ServiceNow API.
+type serviceNowError struct {
+	Status   int `json:"-"`
+	Reported struct {
+		Message string `json:"message"`
+		Detail  string `json:"detail"`
+	} `json:"error"`
+}
+
+// StatusCode returns the (http) status.
+func (r *serviceNowError) StatusCode() int {
+	return r.Status
+}
+
+// Error reports the consolidated error message.
+func (r *serviceNowError) Error() (s string) {
+	s = fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status))
+	if r.Reported.Message != "" {
+		s += ": " + r.Reported.Message
+	}
+	if r.Reported.Detail != "" {
+		s += " (" + r.Reported.Detail + ")"
+	}
+	return
+}
@@ -0,0 +1,112 @@
+package tracker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestServiceNow(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	var created map[string]interface{}
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				user, password, _ := r.BasicAuth()
+				if user != "admin" || password != "secret" {
+					w.WriteHeader(http.StatusUnauthorized)
+					_, _ = w.Write([]byte(`{"error":{"message":"User Not Authenticated","detail":"Required to provide Auth information"},"status":"failure"}`))
+					return
+				}
+				switch r.Method + " " + r.URL.Path {
+				case "GET /api/now/table/sys_user_group":
+					_, _ = w.Write([]byte(`{"result":[{"sys_id":"g1","name":"Platform"}]}`))
+				case "GET /api/now/table/sys_user_group/g1":
+					_, _ = w.Write([]byte(`{"result":{"sys_id":"g1","name":"Platform"}}`))
+				case "GET /api/now/table/sys_db_object":
+					_, _ = w.Write([]byte(`{"result":[{"name":"change_request","label":"Change Request"}]}`))
+				case "POST /api/now/table/change_request":
+					_ = json.NewDecoder(r.Body).Decode(&created)
+					_, _ = w.Write([]byte(`{"result":{"sys_id":"c9","number":"CHG0001","state":"New"}}`))
+				case "GET /api/now/table/change_request/c9":
+					_, _ = w.Write([]byte(`{"result":{"sys_id":"c9","number":"CHG0001","state":"Implement"}}`))
+				case "POST /api/now/table/rm_story":
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"error":{"message":"Invalid table rm_story"},"status":"failure"}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"error":{"message":"No Record found"},"status":"failure"}`))
+				}
+			}))
+	defer server.Close()
+	identity := &model.Identity{
+		Kind:     BasicAuth,
+		User:     "admin",
+		Password: "secret",
+	}
+	err := identity.Encrypt(&model.Identity{})
+	g.Expect(err).To(gomega.BeNil())
+	tracker := &model.Tracker{
+		Kind:     ServiceNow,
+		URL:      server.URL,
+		Identity: identity,
+	}
+	conn, err := NewConnector(tracker)
+	g.Expect(err).To(gomega.BeNil())
+
+	connected, err := conn.TestConnection()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(connected).To(gomega.BeTrue())
+
+	projects, err := conn.Projects()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(projects).To(gomega.Equal([]Project{{ID: "g1", Name: "Platform"}}))
+
+	issueTypes, err := conn.IssueTypes("g1")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(issueTypes).To(gomega.Equal([]IssueType{{ID: ServiceNowTableChange, Name: "Change Request"}}))
+	_, err = conn.IssueTypes("g2")
+	g.Expect(NotFound(err)).To(gomega.BeTrue())
+
+	ticket := model.Ticket{
+		Kind:        ServiceNowTableChange,
+		Parent:      "g1",
+		Fields:      []byte(`{"risk":"low"}`),
+		Application: &model.Application{Name: "app"},
+	}
+	err = conn.Create(&ticket)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ticket.Error).To(gomega.BeFalse(), ticket.Message)
+	g.Expect(ticket.Reference).To(gomega.Equal("c9"))
+	g.Expect(ticket.Link).To(gomega.Equal(server.URL + "/nav_to.do?uri=change_request.do?sys_id=c9"))
+	g.Expect(created["short_description"]).To(gomega.Equal("Migrate app"))
+	g.Expect(created["assignment_group"]).To(gomega.Equal("g1"))
+	g.Expect(created["risk"]).To(gomega.Equal("low"))
+
+	// Rejected.
+	rejected := model.Ticket{Kind: ServiceNowTableStory, Parent: "g1", Application: &model.Application{Name: "app"}}
+	err = conn.Create(&rejected)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(rejected.Error).To(gomega.BeTrue())
+	g.Expect(rejected.Message).To(gomega.Equal("400 Bad Request: Invalid table rm_story"))
+
+	tracker.Tickets = []model.Ticket{ticket}
+	tickets, err := conn.RefreshAll()
+	g.Expect(err).To(gomega.BeNil())
+	for t, found := range tickets {
+		g.Expect(found).To(gomega.BeTrue())
+		g.Expect(t.Status).To(gomega.Equal(InProgress))
+		g.Expect(t.RemoteStatus).To(gomega.Equal("Implement"))
+	}
+
+	// Not authorized.
+	tracker.Identity.Password = "invalid"
+	_, err = conn.TestConnection()
+	category, reason := Categorize(err)
+	g.Expect(category).To(gomega.Equal(ErrorAuth))
+	g.Expect(reason).To(gomega.Equal("user: 401 Unauthorized: User Not Authenticated (Required to provide Auth information)"))
+}
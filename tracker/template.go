@@ -0,0 +1,252 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	liberr "github.com/jortel/go-utils/error"
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+// Default (ticket) summary and description.
+const (
+	DefaultSummary     = "Migrate {{.Application.Name}}"
+	DefaultDescription = "Created by Konveyor."
+)
+
+// Template (ticket) applied by the connector when creating
+// the issue. The summary, description and (string) field values
+// are text templates rendered with the TemplateData.
+type Template struct {
+	Summary     string                 `json:"summary,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Fields      map[string]interface{} `json:"fields,omitempty"`
+}
+
+// TemplateData the data available to the template.
+type TemplateData struct {
+	Application struct {
+		ID              uint
+		Name            string
+		Description     string
+		BusinessService string
+		Tags            []string
+	}
+	Wave struct {
+		Name string
+	}
+}
+
+// With parses the (json) template.
+func (r *Template) With(j model.JSON) (err error) {
+	if len(j) == 0 {
+		return
+	}
+	err = json.Unmarshal(j, r)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Merge the other template.
+// The defined summary, description and fields of
+// the other template take precedence.
+func (r *Template) Merge(other *Template) {
+	if other.Summary != "" {
+		r.Summary = other.Summary
+	}
+	if other.Description != "" {
+		r.Description = other.Description
+	}
+	for k, v := range other.Fields {
+		if r.Fields == nil {
+			r.Fields = map[string]interface{}{}
+		}
+		r.Fields[k] = v
+	}
+}
+
+// Validate the template.
+// Rendered using sample data to detect references
+// to data that is not defined.
+func (r *Template) Validate() (err error) {
+	d := &TemplateData{}
+	d.Application.Tags = []string{""}
+	_, err = r.render(d)
+	return
+}
+
+// Render the template for the ticket.
+// Sets the ticket summary and description. The rendered fields are
+// merged into the ticket fields which take precedence.
+func (r *Template) Render(t *model.Ticket) (err error) {
+	d := &TemplateData{}
+	if app := t.Application; app != nil {
+		d.Application.ID = app.ID
+		d.Application.Name = app.Name
+		d.Application.Description = app.Description
+		if app.BusinessService != nil {
+			d.Application.BusinessService = app.BusinessService.Name
+		}
+		for _, tag := range app.Tags {
+			d.Application.Tags = append(d.Application.Tags, tag.Name)
+		}
+		if app.MigrationWave != nil {
+			d.Wave.Name = app.MigrationWave.Name
+		}
+	}
+	rendered, err := r.render(d)
+	if err != nil {
+		return
+	}
+	fields := rendered.Fields
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	if len(t.Fields) > 0 {
+		err = json.Unmarshal(t.Fields, &fields)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	if len(fields) > 0 {
+		t.Fields, _ = json.Marshal(fields)
+	}
+	t.Summary = rendered.Summary
+	t.Description = rendered.Description
+	return
+}
+
+// render returns the rendered template.
+// The default summary and description are used when not defined.
+func (r *Template) render(d *TemplateData) (rendered *Template, err error) {
+	rendered = &Template{
+		Summary:     DefaultSummary,
+		Description: DefaultDescription,
+	}
+	rendered.Merge(r)
+	rendered.Summary, err = r.text("summary", rendered.Summary, d)
+	if err != nil {
+		return
+	}
+	rendered.Description, err = r.text("description", rendered.Description, d)
+	if err != nil {
+		return
+	}
+	fields := map[string]interface{}{}
+	for k, v := range rendered.Fields {
+		fields[k], err = r.value("fields."+k, v, d)
+		if err != nil {
+			return
+		}
+	}
+	rendered.Fields = fields
+	return
+}
+
+// value renders the strings contained in a (json) value.
+func (r *Template) value(path string, v interface{}, d *TemplateData) (rendered interface{}, err error) {
+	switch x := v.(type) {
+	case string:
+		rendered, err = r.text(path, x, d)
+	case []interface{}:
+		list := make([]interface{}, len(x))
+		for i := range x {
+			list[i], err = r.value(fmt.Sprintf("%s[%d]", path, i), x[i], d)
+			if err != nil {
+				return
+			}
+		}
+		rendered = list
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for k := range x {
+			m[k], err = r.value(path+"."+k, x[k], d)
+			if err != nil {
+				return
+			}
+		}
+		rendered = m
+	default:
+		rendered = v
+	}
+	return
+}
+
+// text renders a text template.
+func (r *Template) text(path, s string, d *TemplateData) (rendered string, err error) {
+	tmpl, err := template.New(path).
+		Funcs(template.FuncMap{"join": strings.Join}).
+		Option("missingkey=error").
+		Parse(s)
+	if err != nil {
+		err = &TemplateError{Path: path, Reason: err.Error()}
+		return
+	}
+	b := bytes.Buffer{}
+	err = tmpl.Execute(&b, d)
+	if err != nil {
+		err = &TemplateError{Path: path, Reason: err.Error()}
+		return
+	}
+	rendered = b.String()
+	return
+}
+
+// summary returns the (rendered) ticket summary.
+func summary(t *model.Ticket) (s string) {
+	s = t.Summary
+	if s == "" {
+		s = fmt.Sprintf("Migrate %s", t.Application.Name)
+	}
+	return
+}
+
+// description returns the (rendered) ticket description.
+func description(t *model.Ticket) (s string) {
+	s = t.Description
+	if s == "" {
+		s = DefaultDescription
+	}
+	return
+}
+
+// RenderTicket renders the ticket using the tracker template
+// merged with the template of the application's migration wave.
+func RenderTicket(tracker *model.Tracker, t *model.Ticket) (err error) {
+	tmpl := &Template{}
+	err = tmpl.With(tracker.Template)
+	if err != nil {
+		return
+	}
+	if t.Application != nil && t.Application.MigrationWave != nil {
+		wave := &Template{}
+		err = wave.With(t.Application.MigrationWave.TicketTemplate)
+		if err != nil {
+			return
+		}
+		tmpl.Merge(wave)
+	}
+	err = tmpl.Render(t)
+	return
+}
+
+// TemplateError reports an invalid template.
+type TemplateError struct {
+	Path   string
+	Reason string
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("template (%s) not valid: %s", e.Path, e.Reason)
+}
+
+func (e *TemplateError) Is(err error) (matched bool) {
+	_, matched = err.(*TemplateError)
+	return
+}
@@ -0,0 +1,66 @@
+package tracker
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+)
+
+func TestTemplate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	tracker := &model.Tracker{
+		Template: []byte(`{
+			"summary": "Migrate {{.Application.Name}} ({{.Wave.Name}})",
+			"fields": {
+				"labels": ["konveyor", "{{join .Application.Tags \"-\"}}"],
+				"components": [{"name": "{{.Application.BusinessService}}"}],
+				"priority": {"name": "Low"},
+				"customfield_1": 10
+			}
+		}`),
+	}
+	ticket := &model.Ticket{
+		Fields: []byte(`{"customfield_1": 20}`),
+		Application: &model.Application{
+			Name:            "app",
+			BusinessService: &model.BusinessService{Name: "billing"},
+			Tags:            []model.Tag{{Name: "java"}, {Name: "spring"}},
+			MigrationWave: &model.MigrationWave{
+				Name:           "wave-1",
+				TicketTemplate: []byte(`{"description":"Team: {{.Application.BusinessService}}","fields":{"priority":{"name":"High"}}}`),
+			},
+		},
+	}
+	err := RenderTicket(tracker, ticket)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ticket.Summary).To(gomega.Equal("Migrate app (wave-1)"))
+	g.Expect(ticket.Description).To(gomega.Equal("Team: billing"))
+	fields := map[string]interface{}{}
+	_ = json.Unmarshal(ticket.Fields, &fields)
+	g.Expect(fields["labels"]).To(gomega.Equal([]interface{}{"konveyor", "java-spring"}))
+	g.Expect(fields["components"]).To(gomega.Equal([]interface{}{map[string]interface{}{"name": "billing"}}))
+	g.Expect(fields["priority"]).To(gomega.Equal(map[string]interface{}{"name": "High"}))
+	g.Expect(fields["customfield_1"]).To(gomega.Equal(float64(20)))
+
+	// Defaults.
+	ticket = &model.Ticket{Application: &model.Application{Name: "app"}}
+	err = RenderTicket(&model.Tracker{}, ticket)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(summary(ticket)).To(gomega.Equal("Migrate app"))
+	g.Expect(description(ticket)).To(gomega.Equal(DefaultDescription))
+	g.Expect(ticket.Fields).To(gomega.BeNil())
+
+	// Not valid.
+	tmpl := Template{Fields: map[string]interface{}{"labels": []interface{}{"{{.Application.Name"}}}
+	err = tmpl.Validate()
+	g.Expect(errors.Is(err, &TemplateError{})).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("fields.labels[0]"))
+	tmpl = Template{Summary: "{{.Application.Owner}}"}
+	err = tmpl.Validate()
+	g.Expect(errors.Is(err, &TemplateError{})).To(gomega.BeTrue())
+	tmpl = Template{Summary: "{{index .Application.Tags 0}}"}
+	g.Expect(tmpl.Validate()).To(gomega.BeNil())
+}
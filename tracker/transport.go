@@ -0,0 +1,56 @@
+package tracker
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/model"
+)
+
+// transports pooled (shared) transports.
+var transports = &transportPool{
+	transports: make(map[string]*http.Transport),
+}
+
+// transportPool is a pool of transports keyed by host
+// and TLS configuration. Sharing the transport reuses
+// (keep-alive) connections across reconciles.
+type transportPool struct {
+	mutex      sync.Mutex
+	transports map[string]*http.Transport
+}
+
+// get returns the shared transport for the tracker.
+func (p *transportPool) get(t *model.Tracker) (transport *http.Transport) {
+	host := t.URL
+	u, err := url.Parse(t.URL)
+	if err == nil {
+		host = u.Host
+	}
+	key := fmt.Sprintf("%s|%t", host, t.Insecure)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	transport, found := p.transports[key]
+	if found {
+		return
+	}
+	transport = newTransport(t)
+	p.transports[key] = transport
+	return
+}
+
+// newTransport returns a new transport for the tracker.
+func newTransport(t *model.Tracker) (transport *http.Transport) {
+	transport = http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = Settings.Hub.Tracker.Transport.MaxIdle
+	transport.MaxIdleConnsPerHost = Settings.Hub.Tracker.Transport.MaxIdlePerHost
+	transport.IdleConnTimeout = time.Duration(Settings.Hub.Tracker.Transport.IdleTimeout) * time.Second
+	if t.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return
+}
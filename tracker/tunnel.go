@@ -0,0 +1,287 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"golang.org/x/crypto/ssh"
+)
+
+// Metadata keys.
+const (
+	Tunnel        = "tunnel"
+	TunnelHost    = "host"
+	TunnelHostKey = "hostKey"
+)
+
+// TunnelTimeout SSH (tunnel) connection timeout.
+const TunnelTimeout = time.Second * 10
+
+// tunnels established SSH tunnels.
+var tunnels = &tunnelPool{
+	tunnels: make(map[string]*tunnel),
+	dialing: make(map[string]*dialing),
+}
+
+// TunnelConfig SSH tunnel (bastion) configuration.
+// Defined by the `tunnel` tracker metadata.
+type TunnelConfig struct {
+	// Host (bastion) host:port.
+	Host string
+	// HostKey authorized_keys formatted public key.
+	HostKey string
+}
+
+// With parses the tunnel configuration from the metadata.
+// Returns false when not configured.
+func (r *TunnelConfig) With(m Metadata) (configured bool) {
+	d, cast := m[Tunnel].(map[string]interface{})
+	if !cast {
+		return
+	}
+	r.Host, _ = d[TunnelHost].(string)
+	r.HostKey, _ = d[TunnelHostKey].(string)
+	configured = r.Host != ""
+	return
+}
+
+// address returns the host:port.
+// The port defaults to 22.
+func (r *TunnelConfig) address() (address string) {
+	address = r.Host
+	_, _, err := net.SplitHostPort(address)
+	if err != nil {
+		address = net.JoinHostPort(address, "22")
+	}
+	return
+}
+
+// fingerprint returns the (SHA256) fingerprint of the host key.
+// The (trimmed) key is returned when not valid.
+func (r *TunnelConfig) fingerprint() (fingerprint string) {
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(r.HostKey))
+	if err != nil {
+		fingerprint = strings.TrimSpace(r.HostKey)
+		return
+	}
+	fingerprint = ssh.FingerprintSHA256(pub)
+	return
+}
+
+// tunnelSchema validates the tunnel metadata.
+func tunnelSchema(v interface{}) (err error) {
+	d, cast := v.(map[string]interface{})
+	if !cast {
+		err = fmt.Errorf("must be an object")
+		return
+	}
+	host, _ := d[TunnelHost].(string)
+	if host == "" {
+		err = fmt.Errorf("%s: required", TunnelHost)
+		return
+	}
+	key, _ := d[TunnelHostKey].(string)
+	if key == "" {
+		err = fmt.Errorf("%s: required", TunnelHostKey)
+		return
+	}
+	_, _, _, _, pErr := ssh.ParseAuthorizedKey([]byte(key))
+	if pErr != nil {
+		err = fmt.Errorf("%s: not valid", TunnelHostKey)
+		return
+	}
+	return
+}
+
+// tunnelTransport returns the (pooled) transport that connects
+// through the SSH tunnel. The tunnel is established (or reused) before
+// returning so that setup failures are reported immediately.
+func tunnelTransport(t *model.Tracker, config TunnelConfig) (transport *http.Transport, err error) {
+	if t.TunnelIdentity == nil || t.TunnelIdentityID == nil {
+		err = &ConnectionError{
+			Category: ErrorTunnel,
+			Reason:   "tunnel identity not specified.",
+		}
+		return
+	}
+	key := fmt.Sprintf(
+		"%d|%s|%s|%d|%t",
+		t.ID,
+		config.address(),
+		config.fingerprint(),
+		*t.TunnelIdentityID,
+		t.Insecure)
+	transport, err = tunnels.get(key, t, config)
+	return
+}
+
+// tunnel an established SSH tunnel.
+type tunnel struct {
+	client *ssh.Client
+	// transport connects through the tunnel.
+	transport *http.Transport
+	// identity (ID) used to establish the tunnel.
+	identity uint
+}
+
+// close the tunnel and the transport (idle) connections.
+func (t *tunnel) close() {
+	t.transport.CloseIdleConnections()
+	_ = t.client.Close()
+}
+
+// dialing a tunnel being established.
+// Waiters are released when done is closed.
+type dialing struct {
+	done      chan struct{}
+	transport *http.Transport
+	err       error
+}
+
+// tunnelPool is a pool of established SSH tunnels.
+// The mutex is not held while (keepalive) requests are sent
+// or tunnels dialed so an unreachable host does not block
+// other trackers. Tunnels are dialed once (per key) and
+// concurrent requests wait for the result.
+type tunnelPool struct {
+	mutex   sync.Mutex
+	tunnels map[string]*tunnel
+	dialing map[string]*dialing
+}
+
+// get returns the transport for an established tunnel.
+func (p *tunnelPool) get(key string, t *model.Tracker, config TunnelConfig) (transport *http.Transport, err error) {
+	p.mutex.Lock()
+	entry, found := p.tunnels[key]
+	p.mutex.Unlock()
+	if found {
+		_, _, err = entry.client.SendRequest("keepalive@openssh.com", true, nil)
+		if err == nil {
+			transport = entry.transport
+			return
+		}
+		err = nil
+		p.drop(key, entry.client)
+	}
+	p.mutex.Lock()
+	if entry, found := p.tunnels[key]; found {
+		p.mutex.Unlock()
+		transport = entry.transport
+		return
+	}
+	if d, found := p.dialing[key]; found {
+		p.mutex.Unlock()
+		<-d.done
+		transport = d.transport
+		err = d.err
+		return
+	}
+	d := &dialing{done: make(chan struct{})}
+	p.dialing[key] = d
+	p.mutex.Unlock()
+	defer func() {
+		d.transport = transport
+		d.err = err
+		p.mutex.Lock()
+		delete(p.dialing, key)
+		p.mutex.Unlock()
+		close(d.done)
+	}()
+	client, err := p.dial(t.TunnelIdentity, config)
+	if err != nil {
+		err = &ConnectionError{
+			Category: ErrorTunnel,
+			Reason:   err.Error(),
+		}
+		return
+	}
+	transport = newTransport(t)
+	transport.DialContext = func(ctx context.Context, network, address string) (conn net.Conn, err error) {
+		conn, err = client.Dial(network, address)
+		if err != nil {
+			p.drop(key, client)
+			err = &ConnectionError{
+				Category: ErrorTunnel,
+				Reason:   err.Error(),
+			}
+		}
+		return
+	}
+	p.mutex.Lock()
+	p.tunnels[key] = &tunnel{
+		client:    client,
+		transport: transport,
+		identity:  *t.TunnelIdentityID,
+	}
+	p.mutex.Unlock()
+	return
+}
+
+// drop (closes) a tunnel.
+func (p *tunnelPool) drop(key string, client *ssh.Client) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if entry, found := p.tunnels[key]; found && entry.client == client {
+		delete(p.tunnels, key)
+		entry.close()
+		return
+	}
+	_ = client.Close()
+}
+
+// evict (closes) the tunnels established using the identity.
+func (p *tunnelPool) evict(identity uint) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for key, entry := range p.tunnels {
+		if entry.identity == identity {
+			entry.close()
+			delete(p.tunnels, key)
+		}
+	}
+}
+
+// dial the bastion.
+func (p *tunnelPool) dial(identity *model.Identity, config TunnelConfig) (client *ssh.Client, err error) {
+	var auth []ssh.AuthMethod
+	if identity.Key != "" {
+		var signer ssh.Signer
+		if identity.Password != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(
+				[]byte(identity.Key),
+				[]byte(identity.Password))
+		} else {
+			signer, err = ssh.ParsePrivateKey([]byte(identity.Key))
+		}
+		if err != nil {
+			return
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	} else if identity.Password != "" {
+		auth = append(auth, ssh.Password(identity.Password))
+	}
+	if config.HostKey == "" {
+		err = fmt.Errorf("%s: required", TunnelHostKey)
+		return
+	}
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(config.HostKey))
+	if err != nil {
+		return
+	}
+	client, err = ssh.Dial(
+		"tcp",
+		config.address(),
+		&ssh.ClientConfig{
+			User:            identity.User,
+			Auth:            auth,
+			HostKeyCallback: ssh.FixedHostKey(pub),
+			Timeout:         TunnelTimeout,
+		})
+	return
+}
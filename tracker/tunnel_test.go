@@ -0,0 +1,181 @@
+package tracker
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+)
+
+// sshServer starts an SSH (bastion) server that accepts
+// the password `secret`. Returns the address and host key.
+func sshServer(g *gomega.WithT) (address, hostKey string, stop func()) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	g.Expect(err).To(gomega.BeNil())
+	signer, err := ssh.NewSignerFromKey(key)
+	g.Expect(err).To(gomega.BeNil())
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(_ ssh.ConnMetadata, password []byte) (p *ssh.Permissions, err error) {
+			if string(password) != "secret" {
+				err = errors.New("denied")
+			}
+			return
+		},
+	}
+	config.AddHostKey(signer)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).To(gomega.BeNil())
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, channels, requests, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(requests)
+				for ch := range channels {
+					_ = ch.Reject(ssh.Prohibited, "not supported")
+				}
+			}()
+		}
+	}()
+	address = listener.Addr().String()
+	hostKey = string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+	stop = func() {
+		_ = listener.Close()
+	}
+	return
+}
+
+func TestTunnelSchema(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	_, hostKey, stop := sshServer(g)
+	defer stop()
+	cases := []struct {
+		value interface{}
+		valid bool
+	}{
+		{value: "bastion", valid: false},
+		{value: map[string]interface{}{}, valid: false},
+		{value: map[string]interface{}{TunnelHost: "bastion"}, valid: false},
+		{value: map[string]interface{}{TunnelHost: "bastion", TunnelHostKey: "invalid"}, valid: false},
+		{value: map[string]interface{}{TunnelHostKey: hostKey}, valid: false},
+		{value: map[string]interface{}{TunnelHost: "bastion", TunnelHostKey: hostKey}, valid: true},
+	}
+	for _, c := range cases {
+		err := tunnelSchema(c.value)
+		g.Expect(err == nil).To(gomega.Equal(c.valid), "%v", c.value)
+	}
+}
+
+func TestTunnelTransport(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	address, hostKey, stop := sshServer(g)
+	defer stop()
+	_, otherKey, stopOther := sshServer(g)
+	defer stopOther()
+	identityID := uint(7)
+	tracker := &model.Tracker{
+		TunnelIdentity: &model.Identity{
+			User:     "elmer",
+			Password: "secret",
+		},
+		TunnelIdentityID: &identityID,
+	}
+	tracker.ID = 1
+	config := TunnelConfig{Host: address, HostKey: hostKey}
+
+	// Pooled.
+	transport, err := tunnelTransport(tracker, config)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(transport.DialContext).ToNot(gomega.BeNil())
+	reused, err := tunnelTransport(tracker, config)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(reused).To(gomega.BeIdenticalTo(transport))
+
+	// Host key changed.
+	_, err = tunnelTransport(tracker, TunnelConfig{Host: address, HostKey: otherKey})
+	g.Expect(err).ToNot(gomega.BeNil())
+
+	// Evicted.
+	tunnels.evict(identityID)
+	renewed, err := tunnelTransport(tracker, config)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(renewed).ToNot(gomega.BeIdenticalTo(transport))
+	tunnels.evict(identityID)
+
+	// Host key not matched.
+	_, err = tunnelTransport(tracker, TunnelConfig{Host: address, HostKey: otherKey})
+	cErr := &ConnectionError{}
+	g.Expect(errors.As(err, &cErr)).To(gomega.BeTrue())
+	g.Expect(cErr.Category).To(gomega.Equal(ErrorTunnel))
+
+	// Host key required.
+	_, err = tunnelTransport(tracker, TunnelConfig{Host: address})
+	g.Expect(errors.As(err, &cErr)).To(gomega.BeTrue())
+	g.Expect(cErr.Category).To(gomega.Equal(ErrorTunnel))
+
+	// Identity not specified.
+	tracker.TunnelIdentity = nil
+	_, err = tunnelTransport(tracker, config)
+	g.Expect(errors.As(err, &cErr)).To(gomega.BeTrue())
+	g.Expect(cErr.Category).To(gomega.Equal(ErrorTunnel))
+}
+
+func TestTunnelNotBlocked(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	address, hostKey, stop := sshServer(g)
+	defer stop()
+	// Bastion accepts but never completes the handshake.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).To(gomega.BeNil())
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+	identityID := uint(8)
+	tracker := func(id uint) (m *model.Tracker) {
+		m = &model.Tracker{
+			TunnelIdentity: &model.Identity{
+				User:     "elmer",
+				Password: "secret",
+			},
+			TunnelIdentityID: &identityID,
+		}
+		m.ID = id
+		return
+	}
+	defer tunnels.evict(identityID)
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = tunnelTransport(tracker(1), TunnelConfig{Host: listener.Addr().String(), HostKey: hostKey})
+	}()
+	var conn net.Conn
+	g.Eventually(accepted, time.Second*5).Should(gomega.Receive(&conn))
+	// Not blocked by the (hung) dial.
+	done := make(chan error, 1)
+	go func() {
+		_, err := tunnelTransport(tracker(2), TunnelConfig{Host: address, HostKey: hostKey})
+		done <- err
+	}()
+	g.Eventually(done, time.Second*5).Should(gomega.Receive(gomega.BeNil()))
+	_ = conn.Close()
+	_ = listener.Close()
+	wg.Wait()
+}
@@ -0,0 +1,123 @@
+package tracker
+
+import (
+	"errors"
+	"fmt"
+
+	liberr "github.com/jortel/go-utils/error"
+	"github.com/konveyor/tackle2-hub/model"
+	"gorm.io/gorm"
+)
+
+// Uniqueness policies.
+//
+// none: no constraint. Trackers may be duplicated; useful when
+// trackers are provisioned by (idempotent) tooling that tracks
+// them by ID.
+//
+// name: the tracker name is unique (default). Names are
+// human-friendly references but the same remote may be
+// registered more than once under different names.
+//
+// url-kind: the URL and kind are unique. Each remote is registered
+// once; names may be reused (ambiguous when resolved by name).
+//
+// externalId: the (non-empty) external ID is unique. The identity is
+// owned by the organization; trackers without an external ID are
+// not constrained.
+const (
+	UniqueNone       = "none"
+	UniqueName       = "name"
+	UniqueURLKind    = "url-kind"
+	UniqueExternalID = "externalId"
+)
+
+// uniqueIndex unique index (DDL) by policy.
+var uniqueIndex = map[string]struct {
+	Name    string
+	Columns string
+	Where   string
+}{
+	UniqueName: {
+		Name:    "trackerUniqueName",
+		Columns: "Name",
+	},
+	UniqueURLKind: {
+		Name:    "trackerUniqueURLKind",
+		Columns: "URL,Kind",
+	},
+	UniqueExternalID: {
+		Name:    "trackerUniqueExternalID",
+		Columns: "ExternalID",
+		Where:   "ExternalID != ''",
+	},
+}
+
+// ApplyUnique creates the unique index for the policy and drops the
+// indexes of the other policies. Fails when the trackers already
+// violate the policy.
+func ApplyUnique(db *gorm.DB, policy string) (err error) {
+	if policy != UniqueNone {
+		if _, found := uniqueIndex[policy]; !found {
+			err = liberr.New("tracker: uniqueness policy '" + policy + "' not supported.")
+			return
+		}
+	}
+	for p, index := range uniqueIndex {
+		if p == policy {
+			continue
+		}
+		err = db.Exec("DROP INDEX IF EXISTS " + index.Name).Error
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	index, found := uniqueIndex[policy]
+	if !found {
+		return
+	}
+	ddl := fmt.Sprintf(
+		"CREATE UNIQUE INDEX IF NOT EXISTS %s ON Tracker (%s)",
+		index.Name,
+		index.Columns)
+	if index.Where != "" {
+		ddl += " WHERE " + index.Where
+	}
+	err = db.Exec(ddl).Error
+	if err != nil {
+		err = liberr.Wrap(err, "policy", policy)
+		return
+	}
+	return
+}
+
+// Conflict returns the tracker conflicting with the
+// specified tracker by the policy.
+func Conflict(db *gorm.DB, policy string, t *model.Tracker) (conflict *model.Tracker, err error) {
+	db = db.Model(&model.Tracker{})
+	db = db.Where("ID != ?", t.ID)
+	switch policy {
+	case UniqueName:
+		db = db.Where("Name", t.Name)
+	case UniqueURLKind:
+		db = db.Where("URL", t.URL).Where("Kind", t.Kind)
+	case UniqueExternalID:
+		if t.ExternalID == "" {
+			return
+		}
+		db = db.Where("ExternalID", t.ExternalID)
+	default:
+		return
+	}
+	found := &model.Tracker{}
+	err = db.First(found).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		}
+		return
+	}
+	conflict = found
+	return
+}
@@ -0,0 +1,66 @@
+package tracker
+
+import (
+	"testing"
+
+	"github.com/konveyor/tackle2-hub/model"
+	"github.com/onsi/gomega"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+func TestUnique(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db, err := gorm.Open(
+		sqlite.Open("file::memory:"),
+		&gorm.Config{
+			NamingStrategy: &schema.NamingStrategy{
+				SingularTable: true,
+				NoLowerCase:   true,
+			},
+		})
+	g.Expect(err).To(gomega.BeNil())
+	err = db.AutoMigrate(&model.Tracker{})
+	g.Expect(err).To(gomega.BeNil())
+	a := &model.Tracker{Name: "a", URL: "https://jira", Kind: JiraCloud, ExternalID: "x"}
+	err = db.Omit("Identity").Create(a).Error
+	g.Expect(err).To(gomega.BeNil())
+
+	cases := []struct {
+		policy   string
+		tracker  model.Tracker
+		conflict bool
+	}{
+		{policy: UniqueNone, tracker: model.Tracker{Name: "a", URL: "https://jira", Kind: JiraCloud}},
+		{policy: UniqueName, tracker: model.Tracker{Name: "a"}, conflict: true},
+		{policy: UniqueName, tracker: model.Tracker{Name: "b", URL: "https://jira", Kind: JiraCloud}},
+		{policy: UniqueURLKind, tracker: model.Tracker{Name: "b", URL: "https://jira", Kind: JiraCloud}, conflict: true},
+		{policy: UniqueURLKind, tracker: model.Tracker{Name: "a", URL: "https://jira", Kind: JiraOnPrem}},
+		{policy: UniqueExternalID, tracker: model.Tracker{Name: "b", ExternalID: "x"}, conflict: true},
+		{policy: UniqueExternalID, tracker: model.Tracker{Name: "a"}},
+	}
+	for _, c := range cases {
+		err = ApplyUnique(db, c.policy)
+		g.Expect(err).To(gomega.BeNil())
+		conflict, err := Conflict(db, c.policy, &c.tracker)
+		g.Expect(err).To(gomega.BeNil())
+		g.Expect(conflict != nil).To(gomega.Equal(c.conflict), c.policy)
+		m := c.tracker
+		err = db.Omit("Identity").Create(&m).Error
+		g.Expect(err != nil).To(gomega.Equal(c.conflict), c.policy)
+		if err == nil {
+			err = db.Delete(&m).Error
+			g.Expect(err).To(gomega.BeNil())
+		}
+	}
+
+	// Updated (self) not a conflict.
+	conflict, err := Conflict(db, UniqueName, a)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(conflict).To(gomega.BeNil())
+
+	// Not supported.
+	err = ApplyUnique(db, "invalid")
+	g.Expect(err).ToNot(gomega.BeNil())
+}
@@ -0,0 +1,111 @@
+package tracker
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebhookConnector is implemented by connectors for trackers
+// that support delivering (test) events to a webhook.
+type WebhookConnector interface {
+	// RegisterWebhook registers a webhook delivering to the URL.
+	// Returns the (remote) webhook ID.
+	RegisterWebhook(url string) (id string, err error)
+	// TriggerWebhook asks the remote to deliver a test event.
+	TriggerWebhook(id string) error
+	// DeleteWebhook deletes the webhook.
+	DeleteWebhook(id string) error
+}
+
+// WebhookPath the (hub) path on which probes are received.
+const WebhookPath = "/trackers/webhooks"
+
+// Probes pending webhook probes.
+var Probes = probes{pending: make(map[string]chan struct{})}
+
+// probes tracks webhook probes waiting to be received.
+type probes struct {
+	mutex   sync.Mutex
+	pending map[string]chan struct{}
+}
+
+// New returns a new probe token and the channel
+// closed when the probe is received.
+func (r *probes) New() (token string, received chan struct{}, err error) {
+	b := make([]byte, 16)
+	_, err = rand.Read(b)
+	if err != nil {
+		return
+	}
+	token = hex.EncodeToString(b)
+	received = make(chan struct{})
+	r.mutex.Lock()
+	r.pending[token] = received
+	r.mutex.Unlock()
+	return
+}
+
+// Receive the probe.
+// Returns false when the token is not pending.
+func (r *probes) Receive(token string) (found bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	received, found := r.pending[token]
+	if found {
+		close(received)
+		delete(r.pending, token)
+	}
+	return
+}
+
+// Delete the probe.
+func (r *probes) Delete(token string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.pending, token)
+}
+
+// WebhookTest the outcome of a webhook delivery test.
+type WebhookTest struct {
+	Delivered bool
+	Duration  time.Duration
+	Reason    string
+}
+
+// ProbeWebhook registers a temporary webhook delivering to the hub,
+// asks the remote to deliver a test event and waits (up to the
+// timeout) for it to be received. The webhook is always deleted.
+func ProbeWebhook(conn WebhookConnector, hubURL string, timeout time.Duration) (test WebhookTest, err error) {
+	token, received, err := Probes.New()
+	if err != nil {
+		return
+	}
+	defer Probes.Delete(token)
+	url := strings.TrimSuffix(hubURL, "/") + WebhookPath + "/" + token
+	id, err := conn.RegisterWebhook(url)
+	if err != nil {
+		return
+	}
+	defer func() {
+		dErr := conn.DeleteWebhook(id)
+		if dErr != nil {
+			Log.Error(dErr, "Delete webhook failed.", "id", id)
+		}
+	}()
+	mark := time.Now()
+	err = conn.TriggerWebhook(id)
+	if err != nil {
+		return
+	}
+	select {
+	case <-received:
+		test.Delivered = true
+	case <-time.After(timeout):
+		test.Reason = "Event not received within " + timeout.String() + "."
+	}
+	test.Duration = time.Since(mark)
+	return
+}
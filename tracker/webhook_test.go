@@ -0,0 +1,56 @@
+package tracker
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+// fakeWebhook delivers (or drops) the test event.
+type fakeWebhook struct {
+	url     string
+	deliver bool
+	deleted bool
+}
+
+func (r *fakeWebhook) RegisterWebhook(url string) (id string, err error) {
+	r.url = url
+	id = "1"
+	return
+}
+
+func (r *fakeWebhook) TriggerWebhook(id string) (err error) {
+	if r.deliver {
+		Probes.Receive(path.Base(r.url))
+	}
+	return
+}
+
+func (r *fakeWebhook) DeleteWebhook(id string) (err error) {
+	r.deleted = true
+	return
+}
+
+func TestWebhook(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Delivered.
+	conn := &fakeWebhook{deliver: true}
+	test, err := ProbeWebhook(conn, "https://hub.example.com/", time.Second)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(test.Delivered).To(gomega.BeTrue())
+	g.Expect(conn.url).To(gomega.HavePrefix("https://hub.example.com" + WebhookPath + "/"))
+	g.Expect(conn.deleted).To(gomega.BeTrue())
+	g.Expect(Probes.Receive(path.Base(conn.url))).To(gomega.BeFalse())
+
+	// Not delivered.
+	conn = &fakeWebhook{}
+	test, err = ProbeWebhook(conn, "https://hub.example.com", time.Millisecond)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(test.Delivered).To(gomega.BeFalse())
+	g.Expect(test.Reason).ToNot(gomega.BeEmpty())
+	g.Expect(conn.deleted).To(gomega.BeTrue())
+	g.Expect(Probes.Receive(path.Base(conn.url))).To(gomega.BeFalse())
+}